@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/adamkadda/arman/internal/cms/model"
+	"github.com/adamkadda/arman/internal/cms/service"
+	"github.com/adamkadda/arman/pkg/logging"
+)
+
+// runArchive exports and restores archives of every piece of content:
+//
+//	export [-o FILE]   write an archive to FILE, or to stdout
+//	restore FILE       restore an archive into a database holding no content
+//	                   yet; FILE may be - to read stdin
+//
+// Media files are not archived: copy the media directory alongside.
+func runArchive(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return errors.New("archive: missing command, want export or restore")
+	}
+
+	switch command := args[0]; command {
+	case "export":
+		return exportArchive(ctx, args[1:])
+	case "restore":
+		return restoreArchive(ctx, args[1:])
+	default:
+		return fmt.Errorf("archive: unknown command %q", command)
+	}
+}
+
+func exportArchive(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("archive export", flag.ContinueOnError)
+	output := flags.String("o", "-", "file the archive is written to, - for stdout")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	_, db, err := connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close(ctx)
+
+	var w io.Writer = os.Stdout
+	if *output != "-" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		w = f
+	}
+
+	buf := bufio.NewWriter(w)
+
+	if err := service.NewArchiveService(db.Pool).Export(ctx, buf); err != nil {
+		return err
+	}
+
+	if err := buf.Flush(); err != nil {
+		return err
+	}
+
+	if f, ok := w.(*os.File); ok && f != os.Stdout {
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+
+	logging.FromContext(ctx).Info(
+		"archive exported",
+		slog.String("file", *output),
+	)
+
+	return nil
+}
+
+func restoreArchive(ctx context.Context, args []string) error {
+	if len(args) != 1 {
+		return errors.New("archive restore: want exactly one file")
+	}
+
+	name := args[0]
+
+	var r io.Reader = os.Stdin
+	if name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		r = f
+	}
+
+	archive, err := model.DecodeArchive(bufio.NewReader(r))
+	if err != nil {
+		return fmt.Errorf("archive restore %s: %w", name, err)
+	}
+
+	_, db, err := connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close(ctx)
+
+	report, err := service.NewArchiveService(db.Pool).Restore(ctx, archive)
+	if err != nil {
+		return err
+	}
+
+	logging.FromContext(ctx).Info(
+		"archive restored",
+		slog.String("file", name),
+		slog.Time("created_at", archive.CreatedAt),
+		slog.Int("composers", report.Composers),
+		slog.Int("pieces", report.Pieces),
+		slog.Int("venues", report.Venues),
+		slog.Int("programmes", report.Programmes),
+		slog.Int("media", report.Media),
+		slog.Int("events", report.Events),
+		slog.Int("biographies", report.Biographies),
+		slog.Bool("contact", report.Contact),
+		slog.Int("unknown_users", report.UnknownUsers),
+	)
+
+	return nil
+}
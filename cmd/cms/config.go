@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/adamkadda/arman/internal/cms/handler"
+	"github.com/adamkadda/arman/pkg/blob"
+	"github.com/adamkadda/arman/pkg/database"
+)
+
+// runConfig checks the configuration before a deployment:
+//
+//	check   parse and validate the environment, connect to the database,
+//	        look for pending migrations and load the website theme
+func runConfig(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return errors.New("config: missing command, want check")
+	}
+
+	switch command := args[0]; command {
+	case "check":
+		return checkConfig(ctx, args[1:])
+	default:
+		return fmt.Errorf("config: unknown command %q", command)
+	}
+}
+
+// checkConfig runs every check, prints its outcome, and fails if any check
+// failed.
+func checkConfig(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("config check", flag.ContinueOnError)
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	defer w.Flush()
+
+	failed := false
+
+	report := func(check string, err error) {
+		if err != nil {
+			failed = true
+			fmt.Fprintf(w, "%s\tFAIL\t%s\n", check, err)
+			return
+		}
+
+		fmt.Fprintf(w, "%s\tok\t\n", check)
+	}
+
+	cfg, err := loadConfig()
+	report("environment", err)
+	if err != nil {
+		return errors.New("configuration check failed")
+	}
+
+	_, err = blob.NewFileStore(cfg.MediaDir)
+	report("media directory", err)
+
+	if cfg.SitePort != "" {
+		_, err = handler.NewSiteHandler(nil, nil, nil, cfg)
+		report("website theme", err)
+	}
+
+	db, err := database.NewWithConfig(ctx, cfg.DB)
+	if err == nil {
+		defer db.Close(ctx)
+		err = db.Pool.Ping(ctx)
+	}
+	report("database", err)
+
+	if err == nil {
+		err = checkMigrations(ctx, db)
+		report("migrations", err)
+	}
+
+	if failed {
+		return errors.New("configuration check failed")
+	}
+
+	return nil
+}
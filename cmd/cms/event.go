@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/adamkadda/arman/internal/cms/model"
+	"github.com/adamkadda/arman/internal/cms/service"
+	"github.com/adamkadda/arman/internal/content"
+	"github.com/adamkadda/arman/pkg/logging"
+)
+
+// runEvent lists events and changes their status:
+//
+//	list [-status STATUS] [-timeframe upcoming|past]   list events
+//	publish ID...                                      publish draft events
+//	archive ID...                                      archive events
+//
+// Status changes go through the same business rules as in the dashboard: an
+// incomplete event cannot be published, for instance.
+func runEvent(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return errors.New("event: missing command, want list, publish or archive")
+	}
+
+	switch command := args[0]; command {
+	case "list":
+		return listEvents(ctx, args[1:])
+	case "publish":
+		return transitionEvents(ctx, args[1:], content.StatusPublished)
+	case "archive":
+		return transitionEvents(ctx, args[1:], content.StatusArchived)
+	default:
+		return fmt.Errorf("event: unknown command %q", command)
+	}
+}
+
+func listEvents(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("event list", flag.ContinueOnError)
+	status := flags.String("status", "", "only list events in this status")
+	timeframe := flags.String("timeframe", "", "only list upcoming or past events")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var q model.EventQuery
+
+	if *status != "" {
+		s := content.Status(*status)
+		q.Status = &s
+	}
+
+	if *timeframe != "" {
+		t := content.Timeframe(*timeframe)
+		q.Timeframe = &t
+	}
+
+	_, db, err := connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close(ctx)
+
+	eventService := service.NewEventService(db.Pool)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "ID\tDATE\tSTATUS\tTITLE")
+
+	for {
+		page, err := eventService.List(ctx, q)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range page.Items {
+			date := "-"
+			if event.Date != nil {
+				date = event.Date.Format(time.DateTime)
+			}
+
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", event.ID, date, event.Status, event.Title)
+		}
+
+		if page.NextCursor == nil {
+			break
+		}
+
+		q.Cursor, err = model.DecodeEventCursor(*page.NextCursor)
+		if err != nil {
+			return err
+		}
+	}
+
+	return w.Flush()
+}
+
+// transitionEvents moves every event passed by id to status. It stops at the
+// first event that cannot be moved.
+func transitionEvents(ctx context.Context, args []string, status content.Status) error {
+	if len(args) == 0 {
+		return errors.New("event: missing event id")
+	}
+
+	ids := make([]int, len(args))
+	for i, arg := range args {
+		id, err := strconv.Atoi(arg)
+		if err != nil || id < 1 {
+			return fmt.Errorf("event: invalid event id %q", arg)
+		}
+
+		ids[i] = id
+	}
+
+	_, db, err := connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close(ctx)
+
+	eventService := service.NewEventService(db.Pool)
+	logger := logging.FromContext(ctx)
+
+	for _, id := range ids {
+		err := eventService.Transition(ctx, id, status, content.StatusChange{})
+		if err != nil {
+			return fmt.Errorf("event %d: %w", id, err)
+		}
+
+		logger.Info(
+			"event status changed",
+			slog.Int("id", id),
+			slog.String("status", string(status)),
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log/slog"
+
+	"github.com/adamkadda/arman/internal/cms/export"
+	"github.com/adamkadda/arman/pkg/logging"
+)
+
+// runExport exports the public website into a directory of static files. Run
+// it again to update the export: only changed files are rewritten.
+func runExport(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	dir := flags.String("dir", "site", "directory the website is exported to")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, db, err := connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close(ctx)
+
+	exporter, err := export.New(db.Pool, cfg)
+	if err != nil {
+		return err
+	}
+
+	report, err := exporter.Export(ctx, *dir)
+	if err != nil {
+		return err
+	}
+
+	logging.FromContext(ctx).Info(
+		"website exported",
+		slog.String("dir", *dir),
+		slog.Int("written", report.Written),
+		slog.Int("unchanged", report.Unchanged),
+		slog.Int("removed", report.Removed),
+	)
+
+	return nil
+}
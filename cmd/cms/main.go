@@ -2,18 +2,25 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"os"
 	"os/signal"
 	"runtime/debug"
 	"syscall"
+	"time"
 
 	"github.com/adamkadda/arman/internal/cms"
 	"github.com/adamkadda/arman/internal/cms/handler"
+	"github.com/adamkadda/arman/internal/cms/service"
+	"github.com/adamkadda/arman/pkg/blob"
 	"github.com/adamkadda/arman/pkg/database"
 	"github.com/adamkadda/arman/pkg/logging"
+	"github.com/adamkadda/arman/pkg/queue"
+	"github.com/adamkadda/arman/pkg/scheduler"
 	"github.com/adamkadda/arman/pkg/server"
 	"github.com/caarlos0/env/v11"
+	"golang.org/x/sync/errgroup"
 )
 
 func main() {
@@ -38,7 +45,7 @@ func main() {
 		}
 	}()
 
-	err := start(ctx)
+	err := run(ctx, os.Args[1:])
 	done()
 
 	if err != nil {
@@ -52,9 +59,88 @@ func main() {
 	logger.Info("successful shutdown")
 }
 
-func start(ctx context.Context) error {
+// usage describes the commands of the binary. Commands other than serve work
+// on the database directly, through the services, so that operators can script
+// maintenance. They are trusted internal callers, see service.WithSystemActor.
+const usage = `usage: cms [command] [arguments]
+
+commands:
+  serve                                  serve the CMS (the default)
+  export [-dir DIR]                      export the public website as static files
+  migrate up|down|status|baseline        apply or revert database migrations
+  user create|list                       manage admin users
+  event list|publish|archive             list events and change their status
+  piece import [-dry-run] FILE           import pieces from a CSV or JSON file
+  archive export|restore                 back up or restore all content as JSON
+  config check                           check the configuration and database
+  help                                   show this help
+`
+
+// run runs the command named by the first argument, see usage.
+func run(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return start(ctx)
+	}
+
+	switch args[0] {
+	case "serve":
+		return start(ctx)
+	case "export":
+		return runExport(ctx, args[1:])
+	case "migrate":
+		return runMigrate(ctx, args[1:])
+	case "user":
+		return runUser(service.WithSystemActor(ctx), args[1:])
+	case "event":
+		return runEvent(service.WithSystemActor(ctx), args[1:])
+	case "piece":
+		return runPiece(service.WithSystemActor(ctx), args[1:])
+	case "archive":
+		return runArchive(service.WithSystemActor(ctx), args[1:])
+	case "config":
+		return runConfig(ctx, args[1:])
+	case "help", "-h", "-help", "--help":
+		fmt.Print(usage)
+		return nil
+	default:
+		return fmt.Errorf("unknown command %q, run cms help", args[0])
+	}
+}
+
+// loadConfig parses the configuration from the environment.
+func loadConfig() (*cms.Config, error) {
 	var cfg cms.Config
 	if err := env.Parse(&cfg); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &cfg, nil
+}
+
+// connect loads the configuration and opens the database, for commands
+// working on the database without serving anything. Close the database once
+// done.
+func connect(ctx context.Context) (*cms.Config, *database.DB, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	db, err := database.NewWithConfig(ctx, cfg.DB)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return cfg, db, nil
+}
+
+func start(ctx context.Context) error {
+	cfg, err := loadConfig()
+	if err != nil {
 		return err
 	}
 
@@ -66,12 +152,119 @@ func start(ctx context.Context) error {
 	}
 	defer db.Close(ctx)
 
-	server, err := server.New(cfg.Port)
+	if cfg.AutoMigrate {
+		migrator, err := newMigrator(db)
+		if err != nil {
+			return err
+		}
+
+		if err := migrateUp(ctx, migrator); err != nil {
+			return err
+		}
+	}
+
+	srv, err := server.New(cfg.Port)
 	if err != nil {
 		return err
 	}
 
-	router := handler.RegisterRoutes(db.Pool)
+	blobs, err := blob.NewFileStore(cfg.MediaDir)
+	if err != nil {
+		return err
+	}
+
+	router := handler.RegisterRoutes(db.Pool, cfg, blobs)
+
+	sched := scheduler.New(db.Pool, tasks(cfg, service.NewEventService(db.Pool))...)
+
+	// If any server, the scheduler or the job workers fail, the group's context
+	// is cancelled and everything else is gracefully stopped as well.
+	g, gctx := errgroup.WithContext(ctx)
+
+	g.Go(func() error {
+		return srv.ServeHTTPHandler(gctx, router)
+	})
+
+	g.Go(func() error {
+		return sched.Run(gctx)
+	})
+
+	if cfg.JobWorkers > 0 {
+		workers := queue.NewWorkers(
+			db.Pool,
+			jobHandlers(),
+			cfg.JobWorkers,
+			cfg.JobPollInterval,
+			cfg.JobLease,
+		)
+
+		g.Go(func() error {
+			return workers.Run(gctx)
+		})
+	}
+
+	if cfg.PublicPort != "" {
+		publicSrv, err := server.New(cfg.PublicPort)
+		if err != nil {
+			return err
+		}
+
+		publicRouter := handler.RegisterPublicRoutes(db.Pool, cfg)
+
+		g.Go(func() error {
+			return publicSrv.ServeHTTPHandler(gctx, publicRouter)
+		})
+	}
+
+	if cfg.SitePort != "" {
+		siteRouter, err := handler.RegisterSiteRoutes(db.Pool, cfg)
+		if err != nil {
+			return err
+		}
+
+		siteSrv, err := server.New(cfg.SitePort)
+		if err != nil {
+			return err
+		}
+
+		g.Go(func() error {
+			return siteSrv.ServeHTTPHandler(gctx, siteRouter)
+		})
+	}
+
+	return g.Wait()
+}
+
+// jobHandlers returns the handlers of every kind of background job. Jobs are
+// enqueued with queue.Enqueue, typically from a service. No kind of job exists
+// yet, which is why JOB_WORKERS defaults to zero: jobs of a kind without a
+// handler are dead-lettered.
+func jobHandlers() queue.Handlers {
+	return queue.Handlers{}
+}
+
+// tasks returns the background tasks run by the scheduler. Tasks are trusted
+// internal callers, see service.WithSystemActor.
+func tasks(cfg *cms.Config, eventService *service.EventService) []scheduler.Task {
+	tasks := []scheduler.Task{
+		{
+			Name:     "event.publish_scheduled",
+			Interval: cfg.SchedulerInterval,
+			Run: func(ctx context.Context) error {
+				return eventService.PublishScheduled(service.WithSystemActor(ctx), time.Now())
+			},
+		},
+	}
+
+	if cfg.ArchiveAfter > 0 {
+		tasks = append(tasks, scheduler.Task{
+			Name:     "event.archive_expired",
+			Interval: cfg.SchedulerInterval,
+			Run: func(ctx context.Context) error {
+				return eventService.ArchiveExpired(service.WithSystemActor(ctx), time.Now().Add(-cfg.ArchiveAfter))
+			},
+		})
+	}
 
-	return server.ServeHTTPHandler(ctx, router)
+	return tasks
 }
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/adamkadda/arman/pkg/database"
+	"github.com/adamkadda/arman/pkg/logging"
+	"github.com/adamkadda/arman/pkg/migrate"
+	"github.com/adamkadda/arman/schema"
+)
+
+// runMigrate applies or reverts the database migrations embedded in the
+// binary:
+//
+//	up                 apply every pending migration
+//	down               revert the latest applied migration
+//	status             list migrations and whether they are applied
+//	baseline VERSION   record migrations up to VERSION as applied, without
+//	                   running them, for databases whose schema was applied
+//	                   by hand; one built from the former schema/schema.sql
+//	                   is at version 1
+func runMigrate(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() == 0 {
+		return errors.New("migrate: missing command, want up, down, status or baseline")
+	}
+
+	_, db, err := connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close(ctx)
+
+	migrator, err := newMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	logger := logging.FromContext(ctx)
+
+	switch command := flags.Arg(0); command {
+	case "up":
+		return migrateUp(ctx, migrator)
+
+	case "down":
+		migration, err := migrator.Down(ctx)
+		if err != nil {
+			return err
+		}
+
+		logger.Info(
+			"migration reverted",
+			slog.Int("version", migration.Version),
+			slog.String("name", migration.Name),
+		)
+
+		return nil
+
+	case "status":
+		states, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		return printStatus(states)
+
+	case "baseline":
+		version, err := strconv.Atoi(flags.Arg(1))
+		if err != nil {
+			return fmt.Errorf("migrate baseline: invalid version %q", flags.Arg(1))
+		}
+
+		recorded, err := migrator.Baseline(ctx, version)
+		if err != nil {
+			return err
+		}
+
+		logger.Info(
+			"migrations baselined",
+			slog.Int("version", version),
+			slog.Int("recorded", len(recorded)),
+		)
+
+		return nil
+
+	default:
+		return fmt.Errorf("migrate: unknown command %q", command)
+	}
+}
+
+// migrateUp applies every pending migration.
+func migrateUp(ctx context.Context, migrator *migrate.Migrator) error {
+	applied, err := migrator.Up(ctx)
+	if err != nil {
+		return err
+	}
+
+	logging.FromContext(ctx).Info(
+		"database migrated",
+		slog.Int("applied", len(applied)),
+	)
+
+	return nil
+}
+
+func newMigrator(db *database.DB) (*migrate.Migrator, error) {
+	migrations, err := schema.Migrations()
+	if err != nil {
+		return nil, err
+	}
+
+	return migrate.New(db.Pool, migrations), nil
+}
+
+func printStatus(states []migrate.State) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED")
+
+	for _, state := range states {
+		name := state.Name
+		if state.Unknown {
+			name = "(unknown)"
+		}
+
+		applied := "pending"
+		if state.AppliedAt != nil {
+			applied = state.AppliedAt.Format(time.DateTime)
+		}
+
+		fmt.Fprintf(w, "%04d\t%s\t%s\n", state.Version, name, applied)
+	}
+
+	return w.Flush()
+}
+
+// checkMigrations fails if any migration is pending.
+func checkMigrations(ctx context.Context, db *database.DB) error {
+	migrator, err := newMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	states, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	pending := 0
+	for _, state := range states {
+		if state.AppliedAt == nil {
+			pending++
+		}
+	}
+
+	if pending > 0 {
+		return fmt.Errorf("%d pending, run cms migrate up", pending)
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/adamkadda/arman/internal/cms/model"
+	"github.com/adamkadda/arman/internal/cms/service"
+	"github.com/adamkadda/arman/pkg/logging"
+)
+
+// runPiece manages the repertoire:
+//
+//	import [-dry-run] [-format csv|json] FILE   create pieces in bulk, along
+//	                                            with their composers; FILE may
+//	                                            be - to read stdin
+func runPiece(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return errors.New("piece: missing command, want import")
+	}
+
+	switch command := args[0]; command {
+	case "import":
+		return importPieces(ctx, args[1:])
+	default:
+		return fmt.Errorf("piece: unknown command %q", command)
+	}
+}
+
+func importPieces(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("piece import", flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "report what the import would do without committing it")
+	format := flags.String("format", "", "format of the file, csv or json; guessed from its extension by default")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		return errors.New("piece import: want exactly one file")
+	}
+
+	name := flags.Arg(0)
+
+	if *format == "" {
+		*format = strings.TrimPrefix(strings.ToLower(filepath.Ext(name)), ".")
+	}
+
+	var r io.Reader = os.Stdin
+	if name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		r = f
+	}
+
+	rows, err := model.DecodePieceImport(r, model.ImportFormat(*format))
+	if err != nil {
+		return fmt.Errorf("piece import %s: %w", name, err)
+	}
+
+	_, db, err := connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close(ctx)
+
+	report, err := service.NewPieceService(db.Pool).Import(ctx, rows, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	if err := printImportReport(report); err != nil {
+		return err
+	}
+
+	logging.FromContext(ctx).Info(
+		"pieces imported",
+		slog.Bool("dry_run", report.DryRun),
+		slog.Bool("committed", report.Committed),
+		slog.Int("created", report.Created),
+		slog.Int("skipped", report.Skipped),
+		slog.Int("failed", report.Failed),
+		slog.Int("composers_created", report.ComposersCreated),
+	)
+
+	if report.Failed > 0 {
+		return fmt.Errorf("piece import %s: %d invalid rows, nothing imported", name, report.Failed)
+	}
+
+	return nil
+}
+
+// printImportReport lists the rows that were not created, so that they can be
+// fixed or checked.
+func printImportReport(report *model.PieceImportReport) error {
+	if report.Skipped == 0 && report.Failed == 0 {
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "LINE\tSTATUS\tREASON")
+
+	for _, row := range report.Rows {
+		if row.Status == model.ImportCreated {
+			continue
+		}
+
+		fmt.Fprintf(w, "%d\t%s\t%s\n", row.Line, row.Status, row.Reason)
+	}
+
+	return w.Flush()
+}
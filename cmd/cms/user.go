@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/adamkadda/arman/internal/cms/service"
+	"github.com/adamkadda/arman/internal/content"
+	"github.com/adamkadda/arman/pkg/logging"
+)
+
+// runUser manages the admin users signing in to the CMS:
+//
+//	create -username NAME [-role ROLE]   create a user, reading the password
+//	                                     from the first line of stdin
+//	list                                 list users
+func runUser(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return errors.New("user: missing command, want create or list")
+	}
+
+	switch command := args[0]; command {
+	case "create":
+		return createUser(ctx, args[1:])
+	case "list":
+		return listUsers(ctx, args[1:])
+	default:
+		return fmt.Errorf("user: unknown command %q", command)
+	}
+}
+
+func createUser(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("user create", flag.ContinueOnError)
+	username := flags.String("username", "", "name the user signs in with")
+	role := flags.String("role", string(content.RoleOwner), "role of the user: viewer, editor or owner")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	// The password is read from stdin rather than a flag, to keep it out of
+	// the shell history and the process list.
+	password, err := readLine(os.Stdin)
+	if err != nil {
+		return fmt.Errorf("read password: %w", err)
+	}
+
+	_, db, err := connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close(ctx)
+
+	user, err := service.NewUserService(db.Pool).Create(ctx, *username, password, content.Role(*role))
+	if err != nil {
+		return err
+	}
+
+	logging.FromContext(ctx).Info(
+		"user created",
+		slog.Int("id", user.ID),
+		slog.String("username", user.Username),
+		slog.String("role", string(user.Role)),
+	)
+
+	return nil
+}
+
+func listUsers(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("user list", flag.ContinueOnError)
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	_, db, err := connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close(ctx)
+
+	users, err := service.NewUserService(db.Pool).List(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "ID\tUSERNAME\tROLE")
+
+	for _, user := range users {
+		fmt.Fprintf(w, "%d\t%s\t%s\n", user.ID, user.Username, user.Role)
+	}
+
+	return w.Flush()
+}
+
+// readLine reads the first line of r, without its line ending.
+func readLine(r io.Reader) (string, error) {
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
@@ -1,10 +1,113 @@
 package cms
 
-import "github.com/adamkadda/arman/pkg/database"
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/adamkadda/arman/internal/content"
+	"github.com/adamkadda/arman/pkg/database"
+)
 
 type Config struct {
-	Host  string `env:"HOST,required"`
-	Port  string `env:"PORT,required"`
+	Host string `env:"HOST,required"`
+	Port string `env:"PORT,required"`
+
+	// PublicPort optionally serves the public API on a port of its own. When
+	// empty, the public API is served alongside the admin API.
+	PublicPort string `env:"PUBLIC_PORT"`
+
+	// SiteURL is the address of the public artist website. It makes the links
+	// and identifiers published in feeds absolute.
+	SiteURL url.URL `env:"SITE_URL" envDefault:"http://localhost"`
+	// SitePort optionally serves the public artist website, rendered with the
+	// theme in SiteTheme. The website is disabled when empty.
+	SitePort  string `env:"SITE_PORT"`
+	SiteTheme string `env:"SITE_THEME" envDefault:"themes/default"`
+	// SiteMaxAge is how long clients and proxies may cache website pages.
+	SiteMaxAge time.Duration `env:"SITE_MAX_AGE" envDefault:"5m"`
+
+	// ArtistName is the performer named in the structured data describing
+	// events. It is left out when empty.
+	ArtistName string `env:"ARTIST_NAME"`
+
 	Stage string `env:"STAGE" envDefault:"dev"`
 	DB    *database.Config
+
+	// AutoMigrate applies pending database migrations on startup. Replicas
+	// starting together take turns, see pkg/migrate.
+	AutoMigrate bool `env:"AUTO_MIGRATE" envDefault:"false"`
+
+	SessionTTL time.Duration `env:"SESSION_TTL" envDefault:"24h"`
+
+	// MediaDir is the directory uploaded media files are stored in.
+	MediaDir string `env:"MEDIA_DIR" envDefault:"media"`
+	// MediaMaxSize is the largest media file accepted, in bytes.
+	MediaMaxSize int64 `env:"MEDIA_MAX_SIZE" envDefault:"20971520"`
+
+	// Languages are the languages the biography may be written in. The first
+	// language is the fallback, served when none of the languages a client
+	// accepts are available.
+	Languages []content.Language `env:"LANGUAGES" envSeparator:"," envDefault:"en"`
+
+	// SchedulerInterval is how often scheduled publications and expired events
+	// are looked for.
+	SchedulerInterval time.Duration `env:"SCHEDULER_INTERVAL" envDefault:"1m"`
+	// ArchiveAfter is how long after its date an event is archived. Zero, the
+	// default, disables automatic archiving.
+	ArchiveAfter time.Duration `env:"ARCHIVE_AFTER" envDefault:"0"`
+
+	// JobWorkers is how many background jobs run concurrently. Zero, the
+	// default, disables the workers, leaving jobs to other replicas.
+	JobWorkers int `env:"JOB_WORKERS" envDefault:"0"`
+	// JobPollInterval is how long an idle worker waits before looking for a job.
+	JobPollInterval time.Duration `env:"JOB_POLL_INTERVAL" envDefault:"5s"`
+	// JobLease is how long a job may run before it is handed to another worker.
+	JobLease time.Duration `env:"JOB_LEASE" envDefault:"10m"`
+}
+
+// Validate reports whether the values parsed into cfg are usable.
+func (cfg *Config) Validate() error {
+	if len(cfg.Languages) == 0 {
+		return errors.New("no languages configured")
+	}
+
+	for _, lang := range cfg.Languages {
+		if err := lang.Validate(); err != nil {
+			return fmt.Errorf("language %q: %w", lang, err)
+		}
+	}
+
+	if cfg.SiteURL.Scheme != "http" && cfg.SiteURL.Scheme != "https" ||
+		cfg.SiteURL.Host == "" {
+		return fmt.Errorf("site url %q must be an absolute http(s) url", cfg.SiteURL.String())
+	}
+
+	if cfg.SiteMaxAge < 0 {
+		return errors.New("site max age must not be negative")
+	}
+
+	if cfg.SchedulerInterval <= 0 {
+		return errors.New("scheduler interval must be positive")
+	}
+
+	if cfg.ArchiveAfter < 0 {
+		return errors.New("archive delay must not be negative")
+	}
+
+	if cfg.JobWorkers < 0 {
+		return errors.New("job workers must not be negative")
+	}
+
+	if cfg.JobPollInterval <= 0 || cfg.JobLease <= 0 {
+		return errors.New("job poll interval and lease must be positive")
+	}
+
+	return nil
+}
+
+// Development reports whether the CMS is running in the development stage.
+func (cfg *Config) Development() bool {
+	return cfg.Stage == "dev"
 }
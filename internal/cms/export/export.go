@@ -0,0 +1,464 @@
+// The export package renders the public website, along with its feeds and the
+// public JSON documents, into a directory of static files that can be served
+// by any static file host.
+//
+// Pages are rendered by the very handlers serving the website and the public
+// API, so an export looks exactly like the live website. The static files of
+// the theme are copied along.
+//
+// Every page is rendered on every run: listings, feeds and the calendar depend
+// on many events at once, so telling which pages an edit affects is not worth
+// the risk of a stale page. Only writing is incremental: a manifest kept in
+// the export directory records a hash of every file, and only the files whose
+// content changed since the last export are rewritten, so that their
+// modification times and the dates in the sitemap tell when content last
+// changed. Files of content that is no longer published are removed.
+package export
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/adamkadda/arman/internal/cms"
+	"github.com/adamkadda/arman/internal/cms/handler"
+	"github.com/adamkadda/arman/internal/cms/model"
+	"github.com/adamkadda/arman/internal/cms/service"
+	"github.com/adamkadda/arman/pkg/logging"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ManifestName is the name of the manifest kept in the export directory.
+const ManifestName = ".export-manifest.json"
+
+// notFoundPath is requested to render the page static file hosts serve for
+// missing files.
+const notFoundPath = "/404"
+
+// pages are exported on every run. Listings of concerts are followed page by
+// page, and every published concert is added to them.
+var pages = []string{
+	"/",
+	"/biography",
+	"/concerts",
+	"/concerts/past",
+	"/public/events.ics",
+	"/public/feed.atom",
+	"/public/feed.rss",
+	"/public/biography/full",
+	"/public/biography/short",
+	"/public/contact",
+}
+
+// Exporter renders the public website into static files.
+type Exporter struct {
+	site         http.Handler
+	static       fs.FS
+	eventService *service.EventService
+	siteURL      *url.URL
+}
+
+// New returns an Exporter rendering the website as configured in cfg. It
+// fails if the theme of the website cannot be loaded.
+func New(pool *pgxpool.Pool, cfg *cms.Config) (*Exporter, error) {
+	site, err := handler.RegisterSiteRoutes(pool, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	static, err := handler.StaticFiles(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Exporter{
+		site:         site,
+		static:       static,
+		eventService: service.NewEventService(pool),
+		siteURL:      &cfg.SiteURL,
+	}, nil
+}
+
+// Report tells how many files an export wrote, left untouched and removed.
+// Unchanged files were rendered all the same, see the package documentation.
+type Report struct {
+	Written   int
+	Unchanged int
+	Removed   int
+}
+
+// manifestEntry records the hash of an exported file, and when its content
+// last changed.
+type manifestEntry struct {
+	Hash     string    `json:"hash"`
+	Modified time.Time `json:"modified"`
+}
+
+type manifest map[string]manifestEntry
+
+// Export renders the website into dir, creating it if needed.
+func (e *Exporter) Export(ctx context.Context, dir string) (*Report, error) {
+	logger := logging.FromContext(ctx).With(
+		slog.String("operation", "site.export"),
+		slog.String("dir", dir),
+	)
+
+	logger.Info(
+		"export site",
+	)
+
+	previous, err := readManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	queue, err := e.concertPages(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	queue = append(slices.Clone(pages), queue...)
+
+	x := &export{
+		dir:      dir,
+		previous: previous,
+		current:  make(manifest),
+		now:      time.Now().UTC(),
+		report:   &Report{},
+	}
+
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+
+		res := e.get(ctx, p)
+
+		switch res.Code {
+		case http.StatusOK:
+		case http.StatusNotFound:
+			// Optional content, such as a short biography, may be missing.
+			logger.Warn(
+				"export page skipped",
+				slog.String("path", p),
+				slog.Int("status", res.Code),
+			)
+			continue
+		default:
+			return nil, fmt.Errorf("export %s: unexpected status %d", p, res.Code)
+		}
+
+		if next := nextPage(res.Header()); next != "" {
+			queue = append(queue, next)
+		}
+
+		if err := x.write(filename(p, res.Header().Get("Content-Type")), res.Body.Bytes()); err != nil {
+			return nil, err
+		}
+	}
+
+	res := e.get(ctx, notFoundPath)
+	if err := x.write("404.html", res.Body.Bytes()); err != nil {
+		return nil, err
+	}
+
+	if err := e.exportStatic(ctx, x); err != nil {
+		return nil, err
+	}
+
+	sitemap, err := x.sitemap(e.siteURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := x.write("sitemap.xml", sitemap); err != nil {
+		return nil, err
+	}
+
+	if err := x.removeStale(); err != nil {
+		return nil, err
+	}
+
+	if err := writeManifest(dir, x.current); err != nil {
+		return nil, err
+	}
+
+	return x.report, nil
+}
+
+// concertPages returns the pages of every published concert, both on the
+// website and in the public API.
+func (e *Exporter) concertPages(ctx context.Context) ([]string, error) {
+	var pages []string
+
+	q := model.EventQuery{
+		Limit: model.MaxLimit,
+	}
+
+	for {
+		page, err := e.eventService.ListPublished(ctx, q)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, event := range page.Items {
+			id := strconv.Itoa(event.Event.ID)
+			pages = append(pages, "/concerts/"+id, "/public/events/"+id)
+		}
+
+		if page.NextCursor == nil {
+			return pages, nil
+		}
+
+		cursor, err := model.DecodeEventCursor(*page.NextCursor)
+		if err != nil {
+			return nil, err
+		}
+
+		q.Cursor = cursor
+	}
+}
+
+// exportStatic exports the static files of the theme, as served by the
+// website. Themes without static files are fine.
+func (e *Exporter) exportStatic(ctx context.Context, x *export) error {
+	err := fs.WalkDir(e.static, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		p := "/static/" + name
+
+		res := e.get(ctx, p)
+		if res.Code != http.StatusOK {
+			return fmt.Errorf("export %s: unexpected status %d", p, res.Code)
+		}
+
+		return x.write(strings.TrimPrefix(p, "/"), res.Body.Bytes())
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	return err
+}
+
+// get renders a page in-process.
+func (e *Exporter) get(ctx context.Context, p string) *httptest.ResponseRecorder {
+	r := httptest.NewRequestWithContext(ctx, http.MethodGet, p, nil)
+	w := httptest.NewRecorder()
+
+	e.site.ServeHTTP(w, r)
+
+	return w
+}
+
+var nextLink = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// nextPage returns the path of the next page advertised by a Link header.
+func nextPage(h http.Header) string {
+	m := nextLink.FindStringSubmatch(h.Get("Link"))
+	if m == nil {
+		return ""
+	}
+
+	return m[1]
+}
+
+// filename maps the path of a page to the file it is exported to. HTML pages
+// are exported as the index of a directory and JSON documents are given an
+// extension, so that static file hosts serve them at the same address and
+// with the same content type as the live website.
+func filename(p string, contentType string) string {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+
+	switch {
+	case mediaType == "text/html":
+		p = path.Join(p, "index.html")
+	case mediaType == "application/json" && path.Ext(p) == "":
+		p += ".json"
+	}
+
+	return strings.TrimPrefix(p, "/")
+}
+
+// export is the state of a single run of Export.
+type export struct {
+	dir      string
+	previous manifest
+	current  manifest
+	now      time.Time
+	report   *Report
+}
+
+// write writes a file unless its content is unchanged since the last export.
+func (x *export) write(name string, body []byte) error {
+	sum := sha256.Sum256(body)
+	hash := hex.EncodeToString(sum[:])
+
+	target := filepath.Join(x.dir, filepath.FromSlash(name))
+
+	if prev, ok := x.previous[name]; ok && prev.Hash == hash {
+		if _, err := os.Stat(target); err == nil {
+			x.current[name] = prev
+			x.report.Unchanged++
+			return nil
+		}
+	}
+
+	if err := writeFile(target, body); err != nil {
+		return err
+	}
+
+	x.current[name] = manifestEntry{
+		Hash:     hash,
+		Modified: x.now,
+	}
+	x.report.Written++
+
+	return nil
+}
+
+// removeStale removes the files of the previous export that were not
+// exported again, along with the directories left empty.
+func (x *export) removeStale() error {
+	for name := range x.previous {
+		if _, ok := x.current[name]; ok {
+			continue
+		}
+
+		target := filepath.Join(x.dir, filepath.FromSlash(name))
+		if err := os.Remove(target); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("remove %s: %w", name, err)
+		}
+
+		x.report.Removed++
+
+		// Removing a directory fails as long as it holds anything else.
+		for d := filepath.Dir(target); d != filepath.Clean(x.dir); d = filepath.Dir(d) {
+			if os.Remove(d) != nil {
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod"`
+}
+
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+// sitemap lists every exported HTML page, dated by the last change of its
+// content.
+func (x *export) sitemap(site *url.URL) ([]byte, error) {
+	var set sitemapURLSet
+
+	for name, entry := range x.current {
+		if path.Base(name) != "index.html" {
+			continue
+		}
+
+		loc := site.ResolveReference(&url.URL{
+			Path: "/" + strings.TrimSuffix(name, "index.html"),
+		})
+
+		set.URLs = append(set.URLs, sitemapURL{
+			Loc:     loc.String(),
+			LastMod: entry.Modified.Format(time.DateOnly),
+		})
+	}
+
+	slices.SortFunc(set.URLs, func(a, b sitemapURL) int {
+		return strings.Compare(a.Loc, b.Loc)
+	})
+
+	body, err := xml.MarshalIndent(set, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), body...), nil
+}
+
+func readManifest(dir string) (manifest, error) {
+	body, err := os.ReadFile(filepath.Join(dir, ManifestName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return manifest{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read manifest: %w", err)
+	}
+
+	var m manifest
+	if err := json.Unmarshal(body, &m); err != nil {
+		return nil, fmt.Errorf("read manifest: %w", err)
+	}
+
+	return m, nil
+}
+
+func writeManifest(dir string, m manifest) error {
+	body, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeFile(filepath.Join(dir, ManifestName), body)
+}
+
+// writeFile replaces a file atomically, so that a host serving the export
+// directory never serves a partially written file.
+func writeFile(name string, body []byte) error {
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".export-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(body); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), name)
+}
@@ -0,0 +1,191 @@
+package export
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilename(t *testing.T) {
+	tests := []struct {
+		name        string
+		path        string
+		contentType string
+		expected    string
+	}{
+		{
+			name:        "home",
+			path:        "/",
+			contentType: "text/html; charset=utf-8",
+			expected:    "index.html",
+		},
+		{
+			name:        "page",
+			path:        "/concerts/1",
+			contentType: "text/html; charset=utf-8",
+			expected:    "concerts/1/index.html",
+		},
+		{
+			name:        "json document",
+			path:        "/public/events/1",
+			contentType: "application/json",
+			expected:    "public/events/1.json",
+		},
+		{
+			name:        "json document with an extension",
+			path:        "/public/foo.json",
+			contentType: "application/json",
+			expected:    "public/foo.json",
+		},
+		{
+			name:        "calendar",
+			path:        "/public/events.ics",
+			contentType: "text/calendar; charset=utf-8",
+			expected:    "public/events.ics",
+		},
+		{
+			name:        "feed",
+			path:        "/public/feed.atom",
+			contentType: "application/atom+xml; charset=utf-8",
+			expected:    "public/feed.atom",
+		},
+		{
+			name:        "no content type",
+			path:        "/foo",
+			contentType: "",
+			expected:    "foo",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.expected, filename(tt.path, tt.contentType))
+		})
+	}
+}
+
+// Unchanged files are left untouched and keep the date their content last
+// changed.
+func TestExport_Write(t *testing.T) {
+	dir := t.TempDir()
+
+	earlier := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := earlier.Add(24 * time.Hour)
+
+	first := &export{
+		dir:      dir,
+		previous: manifest{},
+		current:  manifest{},
+		now:      earlier,
+		report:   &Report{},
+	}
+	require.NoError(t, first.write("foo/index.html", []byte("foo")))
+	require.NoError(t, first.write("bar/index.html", []byte("bar")))
+
+	second := &export{
+		dir:      dir,
+		previous: first.current,
+		current:  manifest{},
+		now:      now,
+		report:   &Report{},
+	}
+	require.NoError(t, second.write("foo/index.html", []byte("foo")))
+	require.NoError(t, second.write("bar/index.html", []byte("baz")))
+
+	require.Equal(t, &Report{Written: 1, Unchanged: 1}, second.report)
+	require.Equal(t, earlier, second.current["foo/index.html"].Modified)
+	require.Equal(t, now, second.current["bar/index.html"].Modified)
+
+	body, err := os.ReadFile(filepath.Join(dir, "bar", "index.html"))
+	require.NoError(t, err)
+	require.Equal(t, "baz", string(body))
+}
+
+func TestExport_RemoveStale(t *testing.T) {
+	dir := t.TempDir()
+
+	files := []string{
+		"index.html",
+		"concerts/1/index.html",
+		"concerts/2/index.html",
+		"public/events/2.json",
+		"public/contact.json",
+	}
+
+	previous := manifest{}
+	for _, name := range files {
+		require.NoError(t, writeFile(filepath.Join(dir, filepath.FromSlash(name)), []byte(name)))
+		previous[name] = manifestEntry{}
+	}
+
+	// Listed by the previous export, but already gone.
+	previous["concerts/3/index.html"] = manifestEntry{}
+
+	x := &export{
+		dir:      dir,
+		previous: previous,
+		current: manifest{
+			"index.html":            {},
+			"concerts/1/index.html": {},
+			"public/contact.json":   {},
+		},
+		report: &Report{},
+	}
+
+	require.NoError(t, x.removeStale())
+	require.Equal(t, 3, x.report.Removed)
+
+	for _, name := range []string{"index.html", "concerts/1/index.html", "public/contact.json"} {
+		require.FileExists(t, filepath.Join(dir, filepath.FromSlash(name)))
+	}
+
+	// Directories left empty are removed, up to the export directory.
+	require.NoDirExists(t, filepath.Join(dir, "concerts", "2"))
+	require.NoDirExists(t, filepath.Join(dir, "public", "events"))
+	require.DirExists(t, filepath.Join(dir, "concerts"))
+	require.DirExists(t, filepath.Join(dir, "public"))
+	require.DirExists(t, dir)
+}
+
+func TestExport_Sitemap(t *testing.T) {
+	site, err := url.Parse("https://example.com/")
+	require.NoError(t, err)
+
+	x := &export{
+		current: manifest{
+			"index.html": {
+				Modified: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+			},
+			"concerts/1/index.html": {
+				Modified: time.Date(2026, 2, 1, 12, 0, 0, 0, time.UTC),
+			},
+			"404.html":             {},
+			"public/events/1.json": {},
+			"public/feed.atom":     {},
+			"static/style.css":     {},
+		},
+	}
+
+	sitemap, err := x.sitemap(site)
+	require.NoError(t, err)
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url>
+    <loc>https://example.com/</loc>
+    <lastmod>2026-03-01</lastmod>
+  </url>
+  <url>
+    <loc>https://example.com/concerts/1/</loc>
+    <lastmod>2026-02-01</lastmod>
+  </url>
+</urlset>`
+
+	require.Equal(t, expected, string(sitemap))
+}
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/adamkadda/arman/internal/cms/service"
+	"github.com/adamkadda/arman/internal/content"
+	"github.com/adamkadda/arman/pkg/logging"
+)
+
+type ArchiveHandler struct {
+	archiveService *service.ArchiveService
+}
+
+func NewArchiveHandler(
+	archiveService *service.ArchiveService,
+) *ArchiveHandler {
+	return &ArchiveHandler{
+		archiveService: archiveService,
+	}
+}
+
+// Register registers all archive-related HTTP routes on the provided ServeMux.
+// Archives are restored with the cms archive restore command only, as the
+// database restored into must be empty.
+func (h *ArchiveHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /archive", h.export)
+}
+
+// archiveWriter sends the headers of an archive download along with the first
+// bytes of the archive, so that failures before anything is written can still
+// be answered with an error status.
+type archiveWriter struct {
+	w       http.ResponseWriter
+	started bool
+}
+
+func (aw *archiveWriter) Write(p []byte) (int, error) {
+	if !aw.started {
+		aw.started = true
+
+		name := fmt.Sprintf("archive-%s.json", time.Now().UTC().Format("20060102-150405"))
+
+		aw.w.Header().Set("Content-Type", "application/json")
+		aw.w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+		aw.w.WriteHeader(http.StatusOK)
+	}
+
+	return aw.w.Write(p)
+}
+
+// export streams an archive of every piece of content, see
+// service.ArchiveService.Export.
+func (h *ArchiveHandler) export(w http.ResponseWriter, r *http.Request) {
+	aw := &archiveWriter{w: w}
+
+	err := h.archiveService.Export(r.Context(), aw)
+	if err == nil {
+		return
+	}
+
+	if aw.started {
+		// The status is sent already; the client is left with an unfinished
+		// archive, which fails to restore.
+		logging.FromContext(r.Context()).Error(
+			"archive download interrupted",
+			slog.Any("error", err),
+		)
+		return
+	}
+
+	switch {
+	case errors.Is(err, content.ErrPermissionDenied):
+		respondJSON(r.Context(), w,
+			http.StatusForbidden,
+			pair("error", "permission denied"),
+		)
+	default:
+		respondJSON(r.Context(), w,
+			http.StatusInternalServerError,
+			pair("error", "internal server error"),
+		)
+	}
+}
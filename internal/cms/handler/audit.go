@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/adamkadda/arman/internal/cms/model"
+	"github.com/adamkadda/arman/internal/cms/service"
+	"github.com/adamkadda/arman/internal/content"
+)
+
+type AuditHandler struct {
+	auditService *service.AuditService
+}
+
+func NewAuditHandler(
+	auditService *service.AuditService,
+) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+// Register registers all audit-related HTTP routes on the provided ServeMux.
+// Routes are registered at the root and assume JSON response bodies.
+func (h *AuditHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /audit", h.list)
+}
+
+type auditEntryResponse struct {
+	ID         int64             `json:"id"`
+	ActorID    *int              `json:"actor_id"`
+	Actor      *string           `json:"actor"`
+	Operation  string            `json:"operation"`
+	EntityType model.AuditEntity `json:"entity_type"`
+	EntityID   string            `json:"entity_id"`
+	Before     json.RawMessage   `json:"before"`
+	After      json.RawMessage   `json:"after"`
+	RequestID  *string           `json:"request_id"`
+	CreatedAt  time.Time         `json:"created_at"`
+}
+
+func newAuditEntryResponse(e *model.AuditEntry) auditEntryResponse {
+	return auditEntryResponse{
+		ID:         e.ID,
+		ActorID:    e.ActorID,
+		Actor:      e.Actor,
+		Operation:  e.Operation,
+		EntityType: e.EntityType,
+		EntityID:   e.EntityID,
+		Before:     e.Before,
+		After:      e.After,
+		RequestID:  e.RequestID,
+		CreatedAt:  e.CreatedAt,
+	}
+}
+
+// list accepts the filters of parseAuditFilter, along with the paging
+// parameters of parseListQuery.
+func (h *AuditHandler) list(w http.ResponseWriter, r *http.Request) {
+	f, ok := parseAuditFilter(w, r)
+	if !ok {
+		return
+	}
+
+	q, ok := parseListQuery(w, r)
+	if !ok {
+		return
+	}
+
+	page, err := h.auditService.List(r.Context(), f, q)
+	if err != nil {
+		switch {
+		case errors.Is(err, content.ErrInvalidResource):
+			respondJSON(r.Context(), w,
+				http.StatusBadRequest,
+				pair("error", err.Error()),
+			)
+			return
+		case errors.Is(err, content.ErrPermissionDenied):
+			respondJSON(r.Context(), w,
+				http.StatusForbidden,
+				pair("error", "permission denied"),
+			)
+			return
+		default:
+			respondJSON(r.Context(), w,
+				http.StatusInternalServerError,
+				pair("error", "internal server error"),
+			)
+			return
+		}
+	}
+
+	resp := newPageResponse(page, newAuditEntryResponse)
+	respondJSON(r.Context(), w,
+		http.StatusOK,
+		resp,
+	)
+}
+
+// parseAuditFilter reads the actor_id, operation, entity_type, entity_id,
+// request_id, from and to filters of an audit log listing from the query
+// string. Parameters that fail to parse are rejected here; how the filters
+// relate to each other is validated by the service.
+func parseAuditFilter(
+	w http.ResponseWriter,
+	r *http.Request,
+) (model.AuditFilter, bool) {
+	query := r.URL.Query()
+
+	f := model.AuditFilter{
+		Operation:  query.Get("operation"),
+		EntityType: model.AuditEntity(query.Get("entity_type")),
+		EntityID:   query.Get("entity_id"),
+		RequestID:  query.Get("request_id"),
+	}
+
+	if val := query.Get("actor_id"); val != "" {
+		id, err := strconv.Atoi(val)
+		if err != nil || id < 1 {
+			rejectParam(w, r, "actor_id", val)
+			return f, false
+		}
+		f.ActorID = &id
+	}
+
+	if val := query.Get("from"); val != "" {
+		t, err := parseTime(val)
+		if err != nil {
+			rejectParam(w, r, "from", val)
+			return f, false
+		}
+		f.From = &t
+	}
+
+	if val := query.Get("to"); val != "" {
+		t, err := parseTime(val)
+		if err != nil {
+			rejectParam(w, r, "to", val)
+			return f, false
+		}
+		f.To = &t
+	}
+
+	return f, true
+}
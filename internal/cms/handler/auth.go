@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/adamkadda/arman/internal/cms/service"
+	"github.com/adamkadda/arman/internal/content"
+	"github.com/adamkadda/arman/pkg/logging"
+	"github.com/adamkadda/arman/pkg/middleware"
+)
+
+// sessionCookie is the name of the cookie carrying the session token.
+const sessionCookie = "arman_session"
+
+// AuthHandler exposes HTTP endpoints for signing in and out of the CMS.
+// It is a thin HTTP-to-service adapter and contains no business logic.
+type AuthHandler struct {
+	authService   *service.AuthService
+	secureCookies bool
+}
+
+// NewAuthHandler creates an AuthHandler. Session cookies are only flagged as
+// Secure when secureCookies is set, which allows plain HTTP during development.
+func NewAuthHandler(
+	authService *service.AuthService,
+	secureCookies bool,
+) *AuthHandler {
+	return &AuthHandler{
+		authService:   authService,
+		secureCookies: secureCookies,
+	}
+}
+
+// Register registers all authentication-related HTTP routes on the provided
+// ServeMux. These routes are intentionally left unauthenticated.
+func (h *AuthHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("POST /login", h.login)
+	mux.HandleFunc("POST /logout", h.logout)
+}
+
+type loginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type loginResponse struct {
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (h *AuthHandler) login(w http.ResponseWriter, r *http.Request) {
+	req, ok := parseBody[loginRequest](w, r)
+	if !ok {
+		return
+	}
+
+	token, session, err := h.authService.Login(r.Context(), req.Username, req.Password)
+	if err != nil {
+		if errors.Is(err, content.ErrInvalidCredentials) {
+			respondJSON(r.Context(), w,
+				http.StatusUnauthorized,
+				pair("error", "invalid credentials"),
+			)
+			return
+		}
+
+		respondJSON(r.Context(), w,
+			http.StatusInternalServerError,
+			pair("error", "internal server error"),
+		)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Value:    token,
+		Path:     "/",
+		Expires:  session.ExpiresAt,
+		HttpOnly: true,
+		Secure:   h.secureCookies,
+		SameSite: http.SameSiteStrictMode,
+	})
+
+	respondJSON(r.Context(), w,
+		http.StatusOK,
+		loginResponse{
+			ExpiresAt: session.ExpiresAt,
+		},
+	)
+}
+
+func (h *AuthHandler) logout(w http.ResponseWriter, r *http.Request) {
+	cookie, err := r.Cookie(sessionCookie)
+	if err == nil {
+		if err := h.authService.Logout(r.Context(), cookie.Value); err != nil {
+			respondJSON(r.Context(), w,
+				http.StatusInternalServerError,
+				pair("error", "internal server error"),
+			)
+			return
+		}
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   h.secureCookies,
+		SameSite: http.SameSiteStrictMode,
+	})
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Middleware returns a Middleware that rejects requests without a valid
+// session before they reach any handler. Authenticated requests carry their
+// User in the request context, see service.UserFromContext.
+func (h *AuthHandler) Middleware() middleware.Middleware {
+	return h.authenticate(func(w http.ResponseWriter, r *http.Request) {
+		respondJSON(r.Context(), w,
+			http.StatusUnauthorized,
+			pair("error", "unauthenticated"),
+		)
+	})
+}
+
+// PageMiddleware is like Middleware, but meant for pages viewed in a browser:
+// requests without a valid session are redirected to the login page, which is
+// told where to send the user back to.
+func (h *AuthHandler) PageMiddleware(login string) middleware.Middleware {
+	return h.authenticate(func(w http.ResponseWriter, r *http.Request) {
+		target := login + "?" + url.Values{"next": {r.URL.RequestURI()}}.Encode()
+		http.Redirect(w, r, target, http.StatusSeeOther)
+	})
+}
+
+// authenticate returns a Middleware that hands requests without a valid
+// session to unauthenticated.
+func (h *AuthHandler) authenticate(unauthenticated http.HandlerFunc) middleware.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			cookie, err := r.Cookie(sessionCookie)
+			if err != nil {
+				logging.FromContext(r.Context()).Warn(
+					"session cookie missing",
+					slog.String("reason", "unauthenticated"),
+				)
+
+				unauthenticated(w, r)
+				return
+			}
+
+			user, err := h.authService.Authenticate(r.Context(), cookie.Value)
+			if err != nil {
+				if errors.Is(err, content.ErrUnauthenticated) {
+					unauthenticated(w, r)
+					return
+				}
+
+				respondJSON(r.Context(), w,
+					http.StatusInternalServerError,
+					pair("error", "internal server error"),
+				)
+				return
+			}
+
+			logger := logging.FromContext(r.Context()).With(
+				slog.Int("user_id", user.ID),
+			)
+
+			ctx := logging.WithLogger(r.Context(), logger)
+			ctx = service.WithUser(ctx, user)
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -3,9 +3,13 @@ package handler
 import (
 	"errors"
 	"net/http"
+	"strconv"
+	"time"
 
+	"github.com/adamkadda/arman/internal/cms/model"
 	"github.com/adamkadda/arman/internal/cms/service"
 	"github.com/adamkadda/arman/internal/content"
+	"golang.org/x/text/language"
 )
 
 type BiographyHandler struct {
@@ -22,9 +26,17 @@ func NewBiographyHandler(
 
 // Register registers all biography-related HTTP routes on the provided ServeMux.
 // Routes are registered at the root and assume JSON request and response bodies.
+//
+// Every route accepts a lang query parameter selecting the language of the
+// biography. Without it, GET /biography/{variant} negotiates the language from
+// the Accept-Language header, and every other route uses the fallback language.
 func (h *BiographyHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /biography/translations", h.translations)
 	mux.HandleFunc("GET /biography/{variant}", h.get)
 	mux.HandleFunc("PUT /biography/{variant}", h.update)
+	mux.HandleFunc("GET /biography/{variant}/revisions", h.listRevisions)
+	mux.HandleFunc("GET /biography/{variant}/revisions/diff", h.diff)
+	mux.HandleFunc("POST /biography/{variant}/revisions/{id}/restore", h.restore)
 }
 
 type biographyRequest struct {
@@ -33,22 +45,160 @@ type biographyRequest struct {
 
 func (r *biographyRequest) toDomain(
 	variant content.BiographyVariant,
+	lang content.Language,
 ) content.Biography {
 	return content.Biography{
-		Content: r.Content,
-		Variant: variant,
+		Content:  r.Content,
+		Variant:  variant,
+		Language: lang,
 	}
 }
 
 type biographyResponse struct {
-	Content string `json:"content"`
-	Variant string `json:"variant"`
+	Content  string `json:"content"`
+	Variant  string `json:"variant"`
+	Language string `json:"language"`
 }
 
 func newBiographyResponse(b *content.Biography) biographyResponse {
 	return biographyResponse{
-		Content: b.Content,
-		Variant: string(b.Variant),
+		Content:  b.Content,
+		Variant:  string(b.Variant),
+		Language: string(b.Language),
+	}
+}
+
+// preferredLanguages returns the languages a client asked for, most preferred
+// first. The lang query parameter takes precedence over the Accept-Language
+// header. A malformed header is treated as absent.
+func preferredLanguages(r *http.Request) []content.Language {
+	if lang := r.URL.Query().Get("lang"); lang != "" {
+		return []content.Language{content.Language(lang)}
+	}
+
+	tags, _, err := language.ParseAcceptLanguage(r.Header.Get("Accept-Language"))
+	if err != nil {
+		return nil
+	}
+
+	languages := make([]content.Language, len(tags))
+	for i, tag := range tags {
+		languages[i] = content.Language(tag.String())
+	}
+
+	return languages
+}
+
+// setLanguageHeaders describes the language of a negotiated biography.
+func setLanguageHeaders(w http.ResponseWriter, b *content.Biography) {
+	w.Header().Set("Content-Language", string(b.Language))
+	w.Header().Add("Vary", "Accept-Language")
+}
+
+type biographyTranslationsResponse struct {
+	Variant   string   `json:"variant"`
+	Languages []string `json:"languages"`
+	Missing   []string `json:"missing"`
+}
+
+func newBiographyTranslationsResponse(
+	t *model.BiographyTranslations,
+) biographyTranslationsResponse {
+	languages := make([]string, len(t.Languages))
+	for i, lang := range t.Languages {
+		languages[i] = string(lang)
+	}
+
+	missing := make([]string, len(t.Missing))
+	for i, lang := range t.Missing {
+		missing[i] = string(lang)
+	}
+
+	return biographyTranslationsResponse{
+		Variant:   string(t.Variant),
+		Languages: languages,
+		Missing:   missing,
+	}
+}
+
+type biographyRevisionResponse struct {
+	ID        int       `json:"revision_id"`
+	Variant   string    `json:"variant"`
+	Language  string    `json:"language"`
+	Content   string    `json:"content"`
+	AuthorID  *int      `json:"author_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func newBiographyRevisionResponse(
+	r *content.BiographyRevision,
+) biographyRevisionResponse {
+	return biographyRevisionResponse{
+		ID:        r.ID,
+		Variant:   string(r.Variant),
+		Language:  string(r.Language),
+		Content:   r.Content,
+		AuthorID:  r.AuthorID,
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+type diffLineResponse struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+type biographyDiffResponse struct {
+	Variant  string             `json:"variant"`
+	Language string             `json:"language"`
+	From     int                `json:"from"`
+	To       *int               `json:"to"`
+	Lines    []diffLineResponse `json:"lines"`
+}
+
+func newBiographyDiffResponse(d *model.BiographyDiff) biographyDiffResponse {
+	lines := make([]diffLineResponse, len(d.Lines))
+	for i, l := range d.Lines {
+		lines[i] = diffLineResponse{
+			Op:   string(l.Op),
+			Text: l.Text,
+		}
+	}
+
+	return biographyDiffResponse{
+		Variant:  string(d.Variant),
+		Language: string(d.Language),
+		From:     d.From,
+		To:       d.To,
+		Lines:    lines,
+	}
+}
+
+// respondBiographyError maps errors shared by the biography revision routes
+// to responses.
+func respondBiographyError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, content.ErrInvalidBiographyVariant),
+		errors.Is(err, content.ErrResourceNotFound):
+		respondJSON(r.Context(), w,
+			http.StatusNotFound,
+			pair("error", "biography revision not found"),
+		)
+	case errors.Is(err, content.ErrInvalidResource):
+		respondJSON(r.Context(), w,
+			http.StatusBadRequest,
+			pair("error", err.Error()),
+		)
+	case errors.Is(err, content.ErrPermissionDenied):
+		respondJSON(r.Context(), w,
+			http.StatusForbidden,
+			pair("error", "permission denied"),
+		)
+	default:
+		respondJSON(r.Context(), w,
+			http.StatusInternalServerError,
+			pair("error", "internal server error"),
+		)
 	}
 }
 
@@ -57,9 +207,10 @@ func (h *BiographyHandler) get(w http.ResponseWriter, r *http.Request) {
 
 	variant := content.BiographyVariant(varStr)
 
-	biography, err := h.biographyService.Get(r.Context(), variant)
+	biography, err := h.biographyService.Get(r.Context(), variant, preferredLanguages(r)...)
 	if err != nil {
-		if errors.Is(err, content.ErrInvalidBiographyVariant) {
+		if errors.Is(err, content.ErrInvalidBiographyVariant) ||
+			errors.Is(err, content.ErrResourceNotFound) {
 			respondJSON(r.Context(), w,
 				http.StatusNotFound,
 				pair("error", "biography not found"),
@@ -67,6 +218,14 @@ func (h *BiographyHandler) get(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if errors.Is(err, content.ErrPermissionDenied) {
+			respondJSON(r.Context(), w,
+				http.StatusForbidden,
+				pair("error", "permission denied"),
+			)
+			return
+		}
+
 		respondJSON(r.Context(), w,
 			http.StatusInternalServerError,
 			pair("error", "internal server error"),
@@ -74,6 +233,8 @@ func (h *BiographyHandler) get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	setLanguageHeaders(w, biography)
+
 	resp := newBiographyResponse(biography)
 	respondJSON(r.Context(), w,
 		http.StatusOK,
@@ -90,10 +251,12 @@ func (h *BiographyHandler) update(w http.ResponseWriter, r *http.Request) {
 	varStr := r.PathValue("variant")
 
 	variant := content.BiographyVariant(varStr)
+	lang := content.Language(r.URL.Query().Get("lang"))
 
-	biography, err := h.biographyService.Update(r.Context(), req.toDomain(variant))
+	biography, err := h.biographyService.Update(r.Context(), req.toDomain(variant, lang))
 	if err != nil {
-		if errors.Is(err, content.ErrInvalidBiographyVariant) {
+		if errors.Is(err, content.ErrInvalidBiographyVariant) ||
+			errors.Is(err, content.ErrInvalidResource) {
 			respondJSON(r.Context(), w,
 				http.StatusBadRequest,
 				pair("error", err.Error()),
@@ -101,6 +264,14 @@ func (h *BiographyHandler) update(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if errors.Is(err, content.ErrPermissionDenied) {
+			respondJSON(r.Context(), w,
+				http.StatusForbidden,
+				pair("error", "permission denied"),
+			)
+			return
+		}
+
 		respondJSON(r.Context(), w,
 			http.StatusInternalServerError,
 			pair("error", "internal server error"),
@@ -114,3 +285,105 @@ func (h *BiographyHandler) update(w http.ResponseWriter, r *http.Request) {
 		resp,
 	)
 }
+
+func (h *BiographyHandler) listRevisions(w http.ResponseWriter, r *http.Request) {
+	variant := content.BiographyVariant(r.PathValue("variant"))
+
+	lang := content.Language(r.URL.Query().Get("lang"))
+
+	revisions, err := h.biographyService.ListRevisions(r.Context(), variant, lang)
+	if err != nil {
+		respondBiographyError(w, r, err)
+		return
+	}
+
+	resp := convertAll(revisions, newBiographyRevisionResponse)
+	respondJSON(r.Context(), w,
+		http.StatusOK,
+		resp,
+	)
+}
+
+// diff compares the revision given by the from parameter against the revision
+// given by the to parameter, or against the current text when to is omitted.
+func (h *BiographyHandler) diff(w http.ResponseWriter, r *http.Request) {
+	variant := content.BiographyVariant(r.PathValue("variant"))
+	query := r.URL.Query()
+	lang := content.Language(query.Get("lang"))
+
+	val := query.Get("from")
+	from, err := strconv.Atoi(val)
+	if err != nil {
+		rejectParam(w, r, "from", val)
+		return
+	}
+
+	var to *int
+	if val := query.Get("to"); val != "" {
+		n, err := strconv.Atoi(val)
+		if err != nil {
+			rejectParam(w, r, "to", val)
+			return
+		}
+		to = &n
+	}
+
+	d, err := h.biographyService.Diff(r.Context(), variant, lang, from, to)
+	if err != nil {
+		respondBiographyError(w, r, err)
+		return
+	}
+
+	resp := newBiographyDiffResponse(d)
+	respondJSON(r.Context(), w,
+		http.StatusOK,
+		resp,
+	)
+}
+
+func (h *BiographyHandler) restore(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+
+	variant := content.BiographyVariant(r.PathValue("variant"))
+	lang := content.Language(r.URL.Query().Get("lang"))
+
+	biography, err := h.biographyService.Restore(r.Context(), variant, lang, id)
+	if err != nil {
+		respondBiographyError(w, r, err)
+		return
+	}
+
+	resp := newBiographyResponse(biography)
+	respondJSON(r.Context(), w,
+		http.StatusOK,
+		resp,
+	)
+}
+
+func (h *BiographyHandler) translations(w http.ResponseWriter, r *http.Request) {
+	translations, err := h.biographyService.Translations(r.Context())
+	if err != nil {
+		if errors.Is(err, content.ErrPermissionDenied) {
+			respondJSON(r.Context(), w,
+				http.StatusForbidden,
+				pair("error", "permission denied"),
+			)
+			return
+		}
+
+		respondJSON(r.Context(), w,
+			http.StatusInternalServerError,
+			pair("error", "internal server error"),
+		)
+		return
+	}
+
+	resp := convertAll(translations, newBiographyTranslationsResponse)
+	respondJSON(r.Context(), w,
+		http.StatusOK,
+		resp,
+	)
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/adamkadda/arman/internal/cms/model"
+	"github.com/adamkadda/arman/pkg/ical"
+	"github.com/adamkadda/arman/pkg/logging"
+)
+
+const calendarProdID = "-//Arman//Concert Calendar//EN"
+
+// calendarMaxAge is how long clients and proxies may cache the calendar.
+// Calendar clients refresh subscriptions hourly at most, so a few minutes of
+// staleness go unnoticed.
+const calendarMaxAge = 15 * time.Minute
+
+// getCalendar serves the public calendar as an iCalendar feed. Called off
+// events are kept in the feed as cancelled, so that subscribed calendars drop
+// them instead of silently keeping a stale copy.
+func (h *PublicHandler) getCalendar(w http.ResponseWriter, r *http.Request) {
+	events, err := h.eventService.Calendar(r.Context(), time.Now())
+	if err != nil {
+		respondJSON(r.Context(), w,
+			http.StatusInternalServerError,
+			pair("error", "internal server error"),
+		)
+		return
+	}
+
+	cal := ical.Calendar{
+		ProdID: calendarProdID,
+		Name:   "Concerts",
+		Events: make([]ical.Event, len(events)),
+	}
+
+	for i := range events {
+		cal.Events[i] = h.newCalendarEvent(&events[i])
+	}
+
+	w.Header().Set("Content-Type", ical.ContentType)
+	w.Header().Set("Cache-Control",
+		fmt.Sprintf("public, max-age=%d", int(calendarMaxAge.Seconds())),
+	)
+	w.WriteHeader(http.StatusOK)
+
+	if err := cal.Encode(w); err != nil {
+		logging.FromContext(r.Context()).Error(
+			"write calendar failed",
+			slog.Any("error", err),
+		)
+	}
+}
+
+func (h *PublicHandler) newCalendarEvent(e *model.PublishedEvent) ical.Event {
+	event := ical.Event{
+		UID:          fmt.Sprintf("event-%d@%s", e.Event.ID, h.site.Hostname()),
+		Sequence:     e.Sequence,
+		Stamp:        e.UpdatedAt,
+		LastModified: e.UpdatedAt,
+		// Event dates are the wall clock time at the venue.
+		Start:       *e.Event.Date,
+		Floating:    true,
+		Summary:     e.Event.Title,
+		Description: calendarDescription(e),
+		Status:      ical.StatusConfirmed,
+	}
+
+	if e.Venue != nil {
+		event.Location = e.Venue.FullAddress
+	}
+
+	if e.Event.TicketLink != nil {
+		event.URL = *e.Event.TicketLink
+	}
+
+	if e.Event.Status.CallsOff() {
+		event.Status = ical.StatusCancelled
+	}
+
+	return event
+}
+
+// calendarDescription describes an event by its programme, preceded by why the
+// event was called off, if it was.
+func calendarDescription(e *model.PublishedEvent) string {
+	var lines []string
+
+	if e.Event.Status.CallsOff() {
+		line := "This concert has been " + string(e.Event.Status) + "."
+		if e.Event.StatusReason != nil {
+			line += " " + *e.Event.StatusReason
+		}
+
+		lines = append(lines, line, "")
+	}
+
+	if e.Programme != nil {
+		lines = append(lines, e.Programme.Programme.Title)
+		lines = append(lines, programmeLines(e.Programme)...)
+	}
+
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// programmeLines renders the pieces of a programme as "Composer – Title"
+// lines, in programme order.
+func programmeLines(p *model.ProgrammeWithPieces) []string {
+	lines := make([]string, len(p.Pieces))
+	for i, pp := range p.Pieces {
+		lines[i] = pp.Composer.FullName + " – " + pp.Piece.Title
+	}
+
+	return lines
+}
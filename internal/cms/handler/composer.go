@@ -134,6 +134,14 @@ func (h *ComposerHandler) get(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if errors.Is(err, content.ErrPermissionDenied) {
+			respondJSON(r.Context(), w,
+				http.StatusForbidden,
+				pair("error", "permission denied"),
+			)
+			return
+		}
+
 		respondJSON(r.Context(), w,
 			http.StatusInternalServerError,
 			pair("error", "internal server error"),
@@ -149,20 +157,36 @@ func (h *ComposerHandler) get(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *ComposerHandler) list(w http.ResponseWriter, r *http.Request) {
-	composers, err := h.composerService.List(r.Context())
-	if err != nil {
-		respondJSON(r.Context(), w,
-			http.StatusInternalServerError,
-			pair("error", "internal server error"),
-		)
+	q, ok := parseListQuery(w, r)
+	if !ok {
 		return
 	}
 
-	resp := make([]composerWithDetailsResponse, len(composers))
-	for i := range composers {
-		resp[i] = newComposerWithDetailsResponse(&composers[i])
+	page, err := h.composerService.List(r.Context(), q)
+	if err != nil {
+		switch {
+		case errors.Is(err, content.ErrInvalidResource):
+			respondJSON(r.Context(), w,
+				http.StatusBadRequest,
+				pair("error", err.Error()),
+			)
+			return
+		case errors.Is(err, content.ErrPermissionDenied):
+			respondJSON(r.Context(), w,
+				http.StatusForbidden,
+				pair("error", "permission denied"),
+			)
+			return
+		default:
+			respondJSON(r.Context(), w,
+				http.StatusInternalServerError,
+				pair("error", "internal server error"),
+			)
+			return
+		}
 	}
 
+	resp := newPageResponse(page, newComposerWithDetailsResponse)
 	respondJSON(r.Context(), w,
 		http.StatusOK,
 		resp,
@@ -193,6 +217,14 @@ func (h *ComposerHandler) create(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if errors.Is(err, content.ErrPermissionDenied) {
+			respondJSON(r.Context(), w,
+				http.StatusForbidden,
+				pair("error", "permission denied"),
+			)
+			return
+		}
+
 		respondJSON(r.Context(), w,
 			http.StatusInternalServerError,
 			pair("error", "internal server error"),
@@ -238,6 +270,14 @@ func (h *ComposerHandler) update(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if errors.Is(err, content.ErrPermissionDenied) {
+			respondJSON(r.Context(), w,
+				http.StatusForbidden,
+				pair("error", "permission denied"),
+			)
+			return
+		}
+
 		respondJSON(r.Context(), w,
 			http.StatusInternalServerError,
 			pair("error", "internal server error"),
@@ -272,6 +312,12 @@ func (h *ComposerHandler) delete(w http.ResponseWriter, r *http.Request) {
 				pair("error", "composer in use"),
 			)
 			return
+		case errors.Is(err, content.ErrPermissionDenied):
+			respondJSON(r.Context(), w,
+				http.StatusForbidden,
+				pair("error", "permission denied"),
+			)
+			return
 		default:
 			respondJSON(r.Context(), w,
 				http.StatusInternalServerError,
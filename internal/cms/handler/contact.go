@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/adamkadda/arman/internal/cms/service"
+	"github.com/adamkadda/arman/internal/content"
+)
+
+type ContactHandler struct {
+	contactService *service.ContactService
+}
+
+func NewContactHandler(
+	contactService *service.ContactService,
+) *ContactHandler {
+	return &ContactHandler{
+		contactService: contactService,
+	}
+}
+
+// Register registers all contact-related HTTP routes on the provided ServeMux.
+// Routes are registered at the root and assume JSON request and response bodies.
+func (h *ContactHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /contact", h.get)
+	mux.HandleFunc("PUT /contact", h.update)
+}
+
+type socialLinkData struct {
+	Platform string `json:"platform"`
+	URL      string `json:"url"`
+}
+
+type contactData struct {
+	Email            string           `json:"email"`
+	Phone            string           `json:"phone"`
+	ManagementAgency string           `json:"management_agency"`
+	ManagementEmail  string           `json:"management_email"`
+	ManagementPhone  string           `json:"management_phone"`
+	PressContact     string           `json:"press_contact"`
+	PressEmail       string           `json:"press_email"`
+	PressPhone       string           `json:"press_phone"`
+	SocialLinks      []socialLinkData `json:"social_links"`
+}
+
+func (d *contactData) toDomain() content.Contact {
+	links := make([]content.SocialLink, len(d.SocialLinks))
+	for i, l := range d.SocialLinks {
+		links[i] = content.SocialLink{
+			Platform: l.Platform,
+			URL:      l.URL,
+		}
+	}
+
+	return content.Contact{
+		Email:            d.Email,
+		Phone:            d.Phone,
+		ManagementAgency: d.ManagementAgency,
+		ManagementEmail:  d.ManagementEmail,
+		ManagementPhone:  d.ManagementPhone,
+		PressContact:     d.PressContact,
+		PressEmail:       d.PressEmail,
+		PressPhone:       d.PressPhone,
+		SocialLinks:      links,
+	}
+}
+
+func newContactResponse(c *content.Contact) contactData {
+	links := make([]socialLinkData, len(c.SocialLinks))
+	for i, l := range c.SocialLinks {
+		links[i] = socialLinkData{
+			Platform: l.Platform,
+			URL:      l.URL,
+		}
+	}
+
+	return contactData{
+		Email:            c.Email,
+		Phone:            c.Phone,
+		ManagementAgency: c.ManagementAgency,
+		ManagementEmail:  c.ManagementEmail,
+		ManagementPhone:  c.ManagementPhone,
+		PressContact:     c.PressContact,
+		PressEmail:       c.PressEmail,
+		PressPhone:       c.PressPhone,
+		SocialLinks:      links,
+	}
+}
+
+func (h *ContactHandler) get(w http.ResponseWriter, r *http.Request) {
+	contact, err := h.contactService.Get(r.Context())
+	if err != nil {
+		if errors.Is(err, content.ErrResourceNotFound) {
+			respondJSON(r.Context(), w,
+				http.StatusNotFound,
+				pair("error", "contact not found"),
+			)
+			return
+		}
+
+		if errors.Is(err, content.ErrPermissionDenied) {
+			respondJSON(r.Context(), w,
+				http.StatusForbidden,
+				pair("error", "permission denied"),
+			)
+			return
+		}
+
+		respondJSON(r.Context(), w,
+			http.StatusInternalServerError,
+			pair("error", "internal server error"),
+		)
+		return
+	}
+
+	resp := newContactResponse(contact)
+	respondJSON(r.Context(), w,
+		http.StatusOK,
+		resp,
+	)
+}
+
+func (h *ContactHandler) update(w http.ResponseWriter, r *http.Request) {
+	req, ok := parseBody[contactData](w, r)
+	if !ok {
+		return
+	}
+
+	contact, err := h.contactService.Update(r.Context(), req.toDomain())
+	if err != nil {
+		if errors.Is(err, content.ErrInvalidResource) {
+			respondJSON(r.Context(), w,
+				http.StatusBadRequest,
+				pair("error", err.Error()),
+			)
+			return
+		}
+
+		if errors.Is(err, content.ErrPermissionDenied) {
+			respondJSON(r.Context(), w,
+				http.StatusForbidden,
+				pair("error", "permission denied"),
+			)
+			return
+		}
+
+		respondJSON(r.Context(), w,
+			http.StatusInternalServerError,
+			pair("error", "internal server error"),
+		)
+		return
+	}
+
+	resp := newContactResponse(contact)
+	respondJSON(r.Context(), w,
+		http.StatusOK,
+		resp,
+	)
+}
@@ -0,0 +1,781 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/adamkadda/arman/internal/cms/model"
+	"github.com/adamkadda/arman/internal/cms/service"
+	"github.com/adamkadda/arman/internal/content"
+	"github.com/adamkadda/arman/pkg/logging"
+)
+
+// dashboardFiles holds the templates and static files of the admin dashboard.
+//
+//go:embed dashboard
+var dashboardFiles embed.FS
+
+// dashboardLogin is the path of the login page of the dashboard.
+const dashboardLogin = "/admin/login"
+
+// dashboardPages maps every page of the dashboard to its template.
+var dashboardPages = []string{
+	"login", "error", "home",
+	"composers", "composer",
+	"pieces", "piece",
+	"venues", "venue",
+	"programmes", "programme",
+	"events", "event",
+	"biographies", "biography",
+}
+
+// DashboardHandler renders the admin dashboard as HTML.
+//
+// Pages are rendered from the services, while changes are submitted from the
+// browser to the JSON API, so that the dashboard is subject to the exact same
+// validation and business rules as any other client. Errors returned by the
+// API are shown next to the form or button that caused them.
+type DashboardHandler struct {
+	composerService  *service.ComposerService
+	pieceService     *service.PieceService
+	venueService     *service.VenueService
+	programmeService *service.ProgrammeService
+	eventService     *service.EventService
+	biographyService *service.BiographyService
+	pages            map[string]*template.Template
+	static           fs.FS
+}
+
+func NewDashboardHandler(
+	composerService *service.ComposerService,
+	pieceService *service.PieceService,
+	venueService *service.VenueService,
+	programmeService *service.ProgrammeService,
+	eventService *service.EventService,
+	biographyService *service.BiographyService,
+) *DashboardHandler {
+	funcs := template.FuncMap{
+		"datetime": formatDateTime,
+		"input":    formatInputTime,
+		"text":     derefString,
+		"id":       derefInt,
+		"action":   statusAction,
+	}
+
+	// The templates are embedded, so failing to parse them is a programming
+	// error rather than a runtime condition.
+	pages := make(map[string]*template.Template, len(dashboardPages))
+	for _, name := range dashboardPages {
+		pages[name] = template.Must(
+			template.New(name).Funcs(funcs).ParseFS(dashboardFiles,
+				"dashboard/templates/layout.html",
+				"dashboard/templates/"+name+".html",
+			),
+		)
+	}
+
+	static, err := fs.Sub(dashboardFiles, "dashboard/static")
+	if err != nil {
+		panic(err)
+	}
+
+	return &DashboardHandler{
+		composerService:  composerService,
+		pieceService:     pieceService,
+		venueService:     venueService,
+		programmeService: programmeService,
+		eventService:     eventService,
+		biographyService: biographyService,
+		pages:            pages,
+		static:           static,
+	}
+}
+
+// Register registers the dashboard under /admin on the provided ServeMux. The
+// login page and static files are public, every other page requires a session
+// and redirects to the login page without one.
+func (h *DashboardHandler) Register(mux *http.ServeMux, auth *AuthHandler) {
+	pages := http.NewServeMux()
+
+	pages.HandleFunc("GET /admin/{$}", h.home)
+	pages.HandleFunc("GET /admin/composers", h.composers)
+	pages.HandleFunc("GET /admin/composers/new", h.composer)
+	pages.HandleFunc("GET /admin/composers/{id}", h.composer)
+	pages.HandleFunc("GET /admin/pieces", h.pieces)
+	pages.HandleFunc("GET /admin/pieces/new", h.piece)
+	pages.HandleFunc("GET /admin/pieces/{id}", h.piece)
+	pages.HandleFunc("GET /admin/venues", h.venues)
+	pages.HandleFunc("GET /admin/venues/new", h.venue)
+	pages.HandleFunc("GET /admin/venues/{id}", h.venue)
+	pages.HandleFunc("GET /admin/programmes", h.programmes)
+	pages.HandleFunc("GET /admin/programmes/new", h.programme)
+	pages.HandleFunc("GET /admin/programmes/{id}", h.programme)
+	pages.HandleFunc("GET /admin/events", h.events)
+	pages.HandleFunc("GET /admin/events/new", h.event)
+	pages.HandleFunc("GET /admin/events/{id}", h.event)
+	pages.HandleFunc("GET /admin/biography", h.biographies)
+	pages.HandleFunc("GET /admin/biography/{variant}/{lang}", h.biography)
+	pages.HandleFunc("/admin/", h.notFound)
+
+	mux.HandleFunc("GET "+dashboardLogin, h.login)
+	mux.Handle("GET /admin/static/", http.StripPrefix("/admin/static/",
+		http.FileServerFS(h.static),
+	))
+	mux.Handle("/admin/", auth.PageMiddleware(dashboardLogin)(pages))
+}
+
+// dashboardPage is what every template is executed with. Data holds what is
+// specific to the page.
+type dashboardPage struct {
+	Title string
+	User  *content.User
+	Data  any
+}
+
+// listPage is a page of a listing. Next is the address of the next page, if
+// there is one.
+type listPage[T any] struct {
+	Items  []T
+	Filter string
+	Next   string
+}
+
+func (h *DashboardHandler) login(w http.ResponseWriter, r *http.Request) {
+	next := r.URL.Query().Get("next")
+	// Only ever send the user back into the dashboard.
+	if !strings.HasPrefix(next, "/admin/") {
+		next = "/admin/"
+	}
+
+	h.render(w, r, http.StatusOK, "login", "Sign in", next)
+}
+
+func (h *DashboardHandler) home(w http.ResponseWriter, r *http.Request) {
+	timeframe := content.TimeframeUpcoming
+
+	page, err := h.eventService.List(r.Context(), model.EventQuery{
+		Timeframe: &timeframe,
+		Limit:     10,
+	})
+	if err != nil {
+		h.renderError(w, r, err)
+		return
+	}
+
+	h.render(w, r, http.StatusOK, "home", "Dashboard", page.Items)
+}
+
+func (h *DashboardHandler) composers(w http.ResponseWriter, r *http.Request) {
+	q, err := dashboardListQuery(r, "full_name")
+	if err != nil {
+		h.renderError(w, r, err)
+		return
+	}
+
+	page, err := h.composerService.List(r.Context(), q)
+	if err != nil {
+		h.renderError(w, r, err)
+		return
+	}
+
+	h.render(w, r, http.StatusOK, "composers", "Composers", newListPage(r, q, page))
+}
+
+func (h *DashboardHandler) composer(w http.ResponseWriter, r *http.Request) {
+	composer := &content.Composer{}
+
+	if r.PathValue("id") != "" {
+		id, err := dashboardID(r)
+		if err != nil {
+			h.renderError(w, r, err)
+			return
+		}
+
+		composer, err = h.composerService.Get(r.Context(), id)
+		if err != nil {
+			h.renderError(w, r, err)
+			return
+		}
+	}
+
+	h.render(w, r, http.StatusOK, "composer", titleOf(composer.ID, "composer", composer.FullName), composer)
+}
+
+type pieceRow struct {
+	Piece          content.Piece
+	Composer       string
+	ProgrammeCount int
+}
+
+func (h *DashboardHandler) pieces(w http.ResponseWriter, r *http.Request) {
+	q, err := dashboardListQuery(r, "title")
+	if err != nil {
+		h.renderError(w, r, err)
+		return
+	}
+
+	page, err := h.pieceService.List(r.Context(), q)
+	if err != nil {
+		h.renderError(w, r, err)
+		return
+	}
+
+	composers, err := h.composerNames(r.Context())
+	if err != nil {
+		h.renderError(w, r, err)
+		return
+	}
+
+	rows := make([]pieceRow, len(page.Items))
+	for i, p := range page.Items {
+		rows[i] = pieceRow{
+			Piece:          p.Piece,
+			Composer:       composers[p.Piece.ComposerID],
+			ProgrammeCount: p.ProgrammeCount,
+		}
+	}
+
+	list := newListPage(r, q, page)
+
+	h.render(w, r, http.StatusOK, "pieces", "Pieces", listPage[pieceRow]{
+		Items:  rows,
+		Filter: list.Filter,
+		Next:   list.Next,
+	})
+}
+
+type piecePage struct {
+	Piece     *content.Piece
+	Composers []model.ComposerWithDetails
+}
+
+func (h *DashboardHandler) piece(w http.ResponseWriter, r *http.Request) {
+	piece := &content.Piece{}
+
+	if r.PathValue("id") != "" {
+		id, err := dashboardID(r)
+		if err != nil {
+			h.renderError(w, r, err)
+			return
+		}
+
+		piece, err = h.pieceService.Get(r.Context(), id)
+		if err != nil {
+			h.renderError(w, r, err)
+			return
+		}
+	}
+
+	composers, err := listAll(r.Context(), h.composerService.List, "full_name")
+	if err != nil {
+		h.renderError(w, r, err)
+		return
+	}
+
+	h.render(w, r, http.StatusOK, "piece", titleOf(piece.ID, "piece", piece.Title), piecePage{
+		Piece:     piece,
+		Composers: composers,
+	})
+}
+
+func (h *DashboardHandler) venues(w http.ResponseWriter, r *http.Request) {
+	q, err := dashboardListQuery(r, "name")
+	if err != nil {
+		h.renderError(w, r, err)
+		return
+	}
+
+	page, err := h.venueService.List(r.Context(), q)
+	if err != nil {
+		h.renderError(w, r, err)
+		return
+	}
+
+	h.render(w, r, http.StatusOK, "venues", "Venues", newListPage(r, q, page))
+}
+
+func (h *DashboardHandler) venue(w http.ResponseWriter, r *http.Request) {
+	venue := &content.Venue{}
+
+	if r.PathValue("id") != "" {
+		id, err := dashboardID(r)
+		if err != nil {
+			h.renderError(w, r, err)
+			return
+		}
+
+		venue, err = h.venueService.Get(r.Context(), id)
+		if err != nil {
+			h.renderError(w, r, err)
+			return
+		}
+	}
+
+	h.render(w, r, http.StatusOK, "venue", titleOf(venue.ID, "venue", venue.Name), venue)
+}
+
+func (h *DashboardHandler) programmes(w http.ResponseWriter, r *http.Request) {
+	q, err := dashboardListQuery(r, "title")
+	if err != nil {
+		h.renderError(w, r, err)
+		return
+	}
+
+	page, err := h.programmeService.List(r.Context(), q)
+	if err != nil {
+		h.renderError(w, r, err)
+		return
+	}
+
+	h.render(w, r, http.StatusOK, "programmes", "Programmes", newListPage(r, q, page))
+}
+
+// repertoireEntry is a piece offered by the programme builder.
+type repertoireEntry struct {
+	ID    int
+	Label string
+}
+
+type programmePage struct {
+	Programme  *model.ProgrammeWithPieces
+	Repertoire []repertoireEntry
+}
+
+func (h *DashboardHandler) programme(w http.ResponseWriter, r *http.Request) {
+	programme := &model.ProgrammeWithPieces{
+		Programme: &content.Programme{},
+	}
+
+	if r.PathValue("id") != "" {
+		id, err := dashboardID(r)
+		if err != nil {
+			h.renderError(w, r, err)
+			return
+		}
+
+		programme, err = h.programmeService.Get(r.Context(), id)
+		if err != nil {
+			h.renderError(w, r, err)
+			return
+		}
+	}
+
+	pieces, err := listAll(r.Context(), h.pieceService.List, "title")
+	if err != nil {
+		h.renderError(w, r, err)
+		return
+	}
+
+	composers, err := h.composerNames(r.Context())
+	if err != nil {
+		h.renderError(w, r, err)
+		return
+	}
+
+	repertoire := make([]repertoireEntry, len(pieces))
+	for i, p := range pieces {
+		repertoire[i] = repertoireEntry{
+			ID:    p.Piece.ID,
+			Label: composers[p.Piece.ComposerID] + " – " + p.Piece.Title,
+		}
+	}
+
+	h.render(w, r, http.StatusOK, "programme",
+		titleOf(programme.Programme.ID, "programme", programme.Programme.Title),
+		programmePage{
+			Programme:  programme,
+			Repertoire: repertoire,
+		},
+	)
+}
+
+type eventsPage struct {
+	Events    []content.Event
+	Statuses  []content.Status
+	Status    string
+	Timeframe string
+	Next      string
+}
+
+func (h *DashboardHandler) events(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	var q model.EventQuery
+
+	if val := query.Get("status"); val != "" {
+		status := content.Status(val)
+		q.Status = &status
+	}
+
+	if val := query.Get("timeframe"); val != "" {
+		timeframe := content.Timeframe(val)
+		q.Timeframe = &timeframe
+	}
+
+	if val := query.Get("cursor"); val != "" {
+		cursor, err := model.DecodeEventCursor(val)
+		if err != nil {
+			h.renderError(w, r, fmt.Errorf("%w: %s", content.ErrInvalidResource, err))
+			return
+		}
+
+		q.Cursor = cursor
+	}
+
+	page, err := h.eventService.List(r.Context(), q)
+	if err != nil {
+		h.renderError(w, r, err)
+		return
+	}
+
+	data := eventsPage{
+		Events:    page.Items,
+		Statuses:  eventStatuses,
+		Status:    query.Get("status"),
+		Timeframe: query.Get("timeframe"),
+	}
+
+	if page.NextCursor != nil {
+		query.Set("cursor", *page.NextCursor)
+		data.Next = "?" + query.Encode()
+	}
+
+	h.render(w, r, http.StatusOK, "events", "Events", data)
+}
+
+type eventPage struct {
+	Event       *model.EventWithProgramme
+	Venues      []model.VenueWithDetails
+	Programmes  []model.ProgrammeWithDetails
+	Transitions []content.Status
+	History     []content.StatusTransition
+}
+
+// eventStatuses lists the statuses an event may be moved to, in the order the
+// dashboard offers them.
+var eventStatuses = []content.Status{
+	content.StatusDraft,
+	content.StatusPublished,
+	content.StatusPostponed,
+	content.StatusCancelled,
+	content.StatusArchived,
+}
+
+func (h *DashboardHandler) event(w http.ResponseWriter, r *http.Request) {
+	data := eventPage{
+		Event: &model.EventWithProgramme{
+			Event: &content.Event{},
+		},
+	}
+
+	if r.PathValue("id") != "" {
+		id, err := dashboardID(r)
+		if err != nil {
+			h.renderError(w, r, err)
+			return
+		}
+
+		data.Event, err = h.eventService.Get(r.Context(), id)
+		if err != nil {
+			h.renderError(w, r, err)
+			return
+		}
+
+		data.History, err = h.eventService.History(r.Context(), id)
+		if err != nil {
+			h.renderError(w, r, err)
+			return
+		}
+
+		for _, status := range eventStatuses {
+			if data.Event.Event.Status.CanTransition(status) == nil {
+				data.Transitions = append(data.Transitions, status)
+			}
+		}
+	}
+
+	var err error
+
+	data.Venues, err = listAll(r.Context(), h.venueService.List, "name")
+	if err != nil {
+		h.renderError(w, r, err)
+		return
+	}
+
+	data.Programmes, err = listAll(r.Context(), h.programmeService.List, "title")
+	if err != nil {
+		h.renderError(w, r, err)
+		return
+	}
+
+	h.render(w, r, http.StatusOK, "event",
+		titleOf(data.Event.Event.ID, "event", data.Event.Event.Title),
+		data,
+	)
+}
+
+func (h *DashboardHandler) biographies(w http.ResponseWriter, r *http.Request) {
+	translations, err := h.biographyService.Translations(r.Context())
+	if err != nil {
+		h.renderError(w, r, err)
+		return
+	}
+
+	h.render(w, r, http.StatusOK, "biographies", "Biography", translations)
+}
+
+type biographyPage struct {
+	Variant  content.BiographyVariant
+	Language content.Language
+	Content  string
+}
+
+func (h *DashboardHandler) biography(w http.ResponseWriter, r *http.Request) {
+	data := biographyPage{
+		Variant:  content.BiographyVariant(r.PathValue("variant")),
+		Language: content.Language(r.PathValue("lang")),
+	}
+
+	biography, err := h.biographyService.Get(r.Context(), data.Variant, data.Language)
+	switch {
+	case errors.Is(err, content.ErrResourceNotFound):
+	case err != nil:
+		h.renderError(w, r, err)
+		return
+	// Get falls back to another language when the requested one is missing,
+	// which simply means the translation is yet to be written.
+	case biography.Language == data.Language:
+		data.Content = biography.Content
+	}
+
+	title := fmt.Sprintf("%s biography (%s)", data.Variant, data.Language)
+	h.render(w, r, http.StatusOK, "biography", title, data)
+}
+
+func (h *DashboardHandler) notFound(w http.ResponseWriter, r *http.Request) {
+	h.renderError(w, r, content.ErrResourceNotFound)
+}
+
+// render executes the named page and writes it. Pages are rendered before
+// anything is written, so that a failing template never leaves a partial page.
+func (h *DashboardHandler) render(
+	w http.ResponseWriter,
+	r *http.Request,
+	status int,
+	name string,
+	title string,
+	data any,
+) {
+	page := dashboardPage{
+		Title: title,
+		Data:  data,
+	}
+
+	if user, ok := service.UserFromContext(r.Context()); ok {
+		page.User = user
+	}
+
+	var buf bytes.Buffer
+	if err := h.pages[name].ExecuteTemplate(&buf, "layout", page); err != nil {
+		logging.FromContext(r.Context()).Error(
+			"render page failed",
+			slog.String("page", name),
+			slog.Any("error", err),
+		)
+
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	w.Write(buf.Bytes())
+}
+
+// renderError renders the error page matching err.
+func (h *DashboardHandler) renderError(w http.ResponseWriter, r *http.Request, err error) {
+	status, message := http.StatusInternalServerError, "internal server error"
+
+	switch {
+	case errors.Is(err, content.ErrResourceNotFound):
+		status, message = http.StatusNotFound, "not found"
+	case errors.Is(err, content.ErrPermissionDenied):
+		status, message = http.StatusForbidden, "permission denied"
+	case errors.Is(err, content.ErrInvalidResource),
+		errors.Is(err, content.ErrInvalidBiographyVariant):
+		status, message = http.StatusBadRequest, err.Error()
+	}
+
+	h.render(w, r, status, "error", http.StatusText(status), message)
+}
+
+// composerNames maps the id of every composer to their full name.
+func (h *DashboardHandler) composerNames(ctx context.Context) (map[int]string, error) {
+	composers, err := listAll(ctx, h.composerService.List, "full_name")
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[int]string, len(composers))
+	for _, c := range composers {
+		names[c.Composer.ID] = c.Composer.FullName
+	}
+
+	return names, nil
+}
+
+// listAll collects every item of a listing sorted by the passed field, a page
+// at a time. It feeds the select boxes of the dashboard.
+func listAll[T any](
+	ctx context.Context,
+	list func(context.Context, model.ListQuery) (*model.Page[T], error),
+	sort string,
+) ([]T, error) {
+	q := model.ListQuery{
+		Sort:  sort,
+		Limit: model.MaxLimit,
+	}
+
+	var items []T
+	for {
+		page, err := list(ctx, q)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, page.Items...)
+
+		if page.NextCursor == nil {
+			return items, nil
+		}
+
+		cursor, err := model.DecodeOffsetCursor(*page.NextCursor)
+		if err != nil {
+			return nil, err
+		}
+
+		q.Offset = cursor.Offset
+	}
+}
+
+// dashboardListQuery reads the filter and page of a listing from the query
+// string. Listings are sorted by the passed field.
+func dashboardListQuery(r *http.Request, sort string) (model.ListQuery, error) {
+	q := model.ListQuery{
+		Filter: r.URL.Query().Get("q"),
+		Sort:   sort,
+	}
+
+	if val := r.URL.Query().Get("cursor"); val != "" {
+		cursor, err := model.DecodeOffsetCursor(val)
+		if err != nil {
+			return q, fmt.Errorf("%w: %s", content.ErrInvalidResource, err)
+		}
+
+		q.Offset = cursor.Offset
+	}
+
+	return q, nil
+}
+
+func newListPage[T any](r *http.Request, q model.ListQuery, page *model.Page[T]) listPage[T] {
+	list := listPage[T]{
+		Items:  page.Items,
+		Filter: q.Filter,
+	}
+
+	if page.NextCursor != nil {
+		query := url.Values{"cursor": {*page.NextCursor}}
+		if q.Filter != "" {
+			query.Set("q", q.Filter)
+		}
+
+		list.Next = r.URL.Path + "?" + query.Encode()
+	}
+
+	return list
+}
+
+// dashboardID reads the id of a page from its path. Malformed ids are reported
+// as missing resources.
+func dashboardID(r *http.Request) (int, error) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id < 1 {
+		return 0, content.ErrResourceNotFound
+	}
+
+	return id, nil
+}
+
+// titleOf titles the page of a resource, or of a new one when id is zero.
+func titleOf(id int, kind string, name string) string {
+	if id == 0 {
+		return "New " + kind
+	}
+
+	return name
+}
+
+func formatDateTime(t *time.Time) string {
+	if t == nil {
+		return "—"
+	}
+
+	return t.Format("Mon 2 Jan 2006, 15:04")
+}
+
+// formatInputTime formats a time as the value of a datetime-local input. Event
+// dates are wall clock times stored as UTC, and are submitted back as such.
+func formatInputTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+
+	return t.UTC().Format("2006-01-02T15:04")
+}
+
+// statusAction labels the button moving an event to status s.
+func statusAction(s content.Status) string {
+	switch s {
+	case content.StatusDraft:
+		return "Return to draft"
+	case content.StatusPublished:
+		return "Publish"
+	case content.StatusPostponed:
+		return "Postpone"
+	case content.StatusCancelled:
+		return "Cancel"
+	case content.StatusArchived:
+		return "Archive"
+	default:
+		return string(s)
+	}
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
+
+func derefInt(n *int) int {
+	if n == nil {
+		return 0
+	}
+
+	return *n
+}
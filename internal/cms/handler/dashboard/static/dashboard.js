@@ -0,0 +1,255 @@
+// The dashboard renders pages on the server and submits every change to the
+// JSON API. Forms and buttons describe the request they make with data
+// attributes:
+//
+//   data-api="METHOD /path"      the request to make.
+//   data-redirect="/admin/..."   where to go once it succeeds. {key} is
+//                                replaced with the key of the response body.
+//                                The page is reloaded when it is missing.
+//   data-body='{...}'            the body sent by a button.
+//   data-confirm="..."           asks for confirmation first.
+//   data-prompt="..."            asks for a reason, sent as "reason".
+//
+// Form fields are named after the path of their value in the request body,
+// such as data.composer.id. Their data-type converts the value: "int" and
+// "datetime" send null when empty, "object" sends an empty object, and
+// data-nullable sends null for an empty text field.
+"use strict";
+
+(() => {
+  const anonymous = () => document.body.hasAttribute("data-anonymous");
+
+  function showError(scope, message) {
+    const el = scope && scope.querySelector(".error");
+    if (!el) {
+      alert(message);
+      return;
+    }
+
+    el.textContent = message;
+    el.hidden = false;
+  }
+
+  function clearError(scope) {
+    const el = scope && scope.querySelector(".error");
+    if (el) {
+      el.hidden = true;
+      el.textContent = "";
+    }
+  }
+
+  async function request(api, body) {
+    const [method, path] = api.split(" ", 2);
+
+    const init = { method, headers: {}, credentials: "same-origin" };
+    if (body !== undefined) {
+      init.headers["Content-Type"] = "application/json";
+      init.body = JSON.stringify(body);
+    }
+
+    const res = await fetch(path, init);
+
+    if (res.status === 401 && !anonymous()) {
+      const next = location.pathname + location.search;
+      location.assign("/admin/login?" + new URLSearchParams({ next }));
+      return new Promise(() => {});
+    }
+
+    let data = null;
+    if ((res.headers.get("Content-Type") || "").startsWith("application/json")) {
+      data = await res.json();
+    }
+
+    if (!res.ok) {
+      throw new Error((data && data.error) || res.statusText);
+    }
+
+    return data;
+  }
+
+  function done(el, data) {
+    const redirect = el.dataset.redirect;
+    if (!redirect) {
+      location.reload();
+      return;
+    }
+
+    location.assign(
+      redirect.replace(/\{(\w+)\}/g, (_, key) => encodeURIComponent(data[key])),
+    );
+  }
+
+  function fieldValue(field) {
+    const value = field.value;
+
+    switch (field.dataset.type) {
+      case "int":
+        return value === "" ? null : parseInt(value, 10);
+      case "datetime":
+        // Dates are the wall clock time at the venue, and are stored as such.
+        return value === "" ? null : value + ":00Z";
+      case "object":
+        return {};
+    }
+
+    if (value === "" && field.hasAttribute("data-nullable")) {
+      return null;
+    }
+
+    return value;
+  }
+
+  function formBody(form) {
+    const body = {};
+
+    for (const field of form.elements) {
+      if (!field.name) {
+        continue;
+      }
+
+      const keys = field.name.split(".");
+      const last = keys.pop();
+
+      let target = body;
+      for (const key of keys) {
+        target = target[key] = target[key] || {};
+      }
+
+      target[last] = fieldValue(field);
+    }
+
+    return body;
+  }
+
+  document.addEventListener("submit", async (event) => {
+    const form = event.target;
+    if (!form.dataset.api) {
+      return;
+    }
+
+    event.preventDefault();
+    clearError(form);
+
+    try {
+      done(form, await request(form.dataset.api, formBody(form)));
+    } catch (err) {
+      showError(form, err.message);
+    }
+  });
+
+  document.addEventListener("click", async (event) => {
+    const button = event.target.closest("button[data-api]");
+    if (!button) {
+      return;
+    }
+
+    const scope = button.closest("[data-actions]");
+    clearError(scope);
+
+    if (button.dataset.confirm && !confirm(button.dataset.confirm)) {
+      return;
+    }
+
+    let body = button.dataset.body ? JSON.parse(button.dataset.body) : undefined;
+
+    if (button.dataset.prompt) {
+      const reason = prompt(button.dataset.prompt);
+      if (reason === null) {
+        return;
+      }
+
+      body = Object.assign(body || {}, { reason });
+    }
+
+    button.disabled = true;
+    try {
+      done(button, await request(button.dataset.api, body));
+    } catch (err) {
+      showError(scope, err.message);
+    } finally {
+      button.disabled = false;
+    }
+  });
+
+  // The programme builder orders the pieces of a programme by drag and drop,
+  // and saves them all at once.
+  for (const builder of document.querySelectorAll("[data-builder]")) {
+    const list = builder.querySelector("ol");
+    let dragged = null;
+
+    list.addEventListener("dragstart", (event) => {
+      dragged = event.target.closest("li");
+      event.dataTransfer.effectAllowed = "move";
+      dragged.classList.add("dragging");
+    });
+
+    list.addEventListener("dragend", () => {
+      if (dragged) {
+        dragged.classList.remove("dragging");
+      }
+      dragged = null;
+    });
+
+    list.addEventListener("dragover", (event) => {
+      if (!dragged) {
+        return;
+      }
+
+      event.preventDefault();
+
+      const over = event.target.closest("li");
+      if (!over || over === dragged) {
+        return;
+      }
+
+      const box = over.getBoundingClientRect();
+      const after = event.clientY > box.top + box.height / 2;
+      list.insertBefore(dragged, after ? over.nextSibling : over);
+    });
+
+    list.addEventListener("click", (event) => {
+      if (event.target.closest("[data-remove]")) {
+        event.target.closest("li").remove();
+      }
+    });
+
+    builder.querySelector("[data-add]").addEventListener("change", (event) => {
+      const select = event.target;
+      if (select.value === "") {
+        return;
+      }
+
+      const item = document.createElement("li");
+      item.draggable = true;
+      item.dataset.piece = select.value;
+
+      const label = document.createElement("span");
+      label.textContent = select.selectedOptions[0].textContent;
+
+      const remove = document.createElement("button");
+      remove.type = "button";
+      remove.dataset.remove = "";
+      remove.textContent = "Remove";
+
+      item.append(label, " ", remove);
+      list.append(item);
+
+      select.value = "";
+    });
+
+    builder.querySelector("[data-save]").addEventListener("click", async () => {
+      clearError(builder);
+
+      const pieces = [...list.querySelectorAll("li")].map((li) =>
+        parseInt(li.dataset.piece, 10),
+      );
+
+      try {
+        await request(builder.dataset.api, pieces);
+        location.reload();
+      } catch (err) {
+        showError(builder, err.message);
+      }
+    });
+  }
+})();
@@ -1,29 +1,35 @@
 package handler
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
-	"log/slog"
 	"net/http"
 	"strconv"
 	"time"
 
+	"github.com/adamkadda/arman/internal/cms/jsonld"
 	"github.com/adamkadda/arman/internal/cms/model"
 	"github.com/adamkadda/arman/internal/cms/service"
 	"github.com/adamkadda/arman/internal/content"
-	"github.com/adamkadda/arman/pkg/logging"
 )
 
 // EventHandler exposes HTTP endpoints for managing events.
 // It is a thin HTTP-to-service adapter and contains no business logic.
 type EventHandler struct {
 	eventService *service.EventService
+	performer    string
 }
 
+// NewEventHandler returns an EventHandler. The performer is named in the
+// structured data describing events.
 func NewEventHandler(
 	eventService *service.EventService,
+	performer string,
 ) *EventHandler {
 	return &EventHandler{
 		eventService: eventService,
+		performer:    performer,
 	}
 }
 
@@ -38,6 +44,11 @@ func (h *EventHandler) Register(mux *http.ServeMux) {
 	mux.HandleFunc("PUT /events/{id}/draft", h.draft)
 	mux.HandleFunc("PUT /events/{id}/publish", h.publish)
 	mux.HandleFunc("PUT /events/{id}/archive", h.archive)
+	mux.HandleFunc("PUT /events/{id}/cancel", h.cancel)
+	mux.HandleFunc("PUT /events/{id}/postpone", h.postpone)
+	mux.HandleFunc("PUT /events/{id}/status", h.transition)
+	mux.HandleFunc("GET /events/{id}/history", h.history)
+	mux.HandleFunc("GET /events/{id}/jsonld", h.jsonLD)
 	mux.HandleFunc("DELETE /events/{id}", h.delete)
 }
 
@@ -47,6 +58,7 @@ type eventRequest struct {
 	TicketLink  *string    `json:"ticket_link"`
 	VenueID     *int       `json:"venue_id"`
 	ProgrammeID *int       `json:"programme_id"`
+	PublishAt   *time.Time `json:"publish_at"`
 }
 
 func (r *eventRequest) toDomain() content.Event {
@@ -56,6 +68,7 @@ func (r *eventRequest) toDomain() content.Event {
 		TicketLink:  r.TicketLink,
 		VenueID:     r.VenueID,
 		ProgrammeID: r.ProgrammeID,
+		PublishAt:   r.PublishAt,
 	}
 }
 
@@ -67,96 +80,138 @@ func (r *eventRequest) toDomainWithID(id int) content.Event {
 		TicketLink:  r.TicketLink,
 		VenueID:     r.VenueID,
 		ProgrammeID: r.ProgrammeID,
+		PublishAt:   r.PublishAt,
 	}
 }
 
 type eventResponse struct {
-	ID          int            `json:"id"`
-	Title       string         `json:"title"`
-	Date        *time.Time     `json:"date"`
-	TicketLink  *string        `json:"ticket_link"`
-	VenueID     *int           `json:"venue_id"`
-	ProgrammeID *int           `json:"programme_id"`
-	Status      content.Status `json:"status"`
-	Notes       *string        `json:"notes"`
+	ID            int            `json:"id"`
+	Title         string         `json:"title"`
+	Date          *time.Time     `json:"date"`
+	TicketLink    *string        `json:"ticket_link"`
+	VenueID       *int           `json:"venue_id"`
+	ProgrammeID   *int           `json:"programme_id"`
+	Status        content.Status `json:"status"`
+	Notes         *string        `json:"notes"`
+	PublishAt     *time.Time     `json:"publish_at"`
+	StatusReason  *string        `json:"status_reason"`
+	ReplacementID *int           `json:"replacement_id"`
 }
 
 func newEventResponse(e *content.Event) eventResponse {
 	return eventResponse{
-		ID:          e.ID,
-		Title:       e.Title,
-		Date:        e.Date,
-		TicketLink:  e.TicketLink,
-		VenueID:     e.VenueID,
-		ProgrammeID: e.ProgrammeID,
-		Status:      e.Status,
-		Notes:       e.Notes,
+		ID:            e.ID,
+		Title:         e.Title,
+		Date:          e.Date,
+		TicketLink:    e.TicketLink,
+		VenueID:       e.VenueID,
+		ProgrammeID:   e.ProgrammeID,
+		Status:        e.Status,
+		Notes:         e.Notes,
+		PublishAt:     e.PublishAt,
+		StatusReason:  e.StatusReason,
+		ReplacementID: e.ReplacementID,
 	}
 }
 
 type eventWithTimestampsResponse struct {
-	ID          int            `json:"id"`
-	Title       string         `json:"title"`
-	Date        *time.Time     `json:"date"`
-	TicketLink  *string        `json:"ticket_link"`
-	VenueID     *int           `json:"venue_id"`
-	ProgrammeID *int           `json:"programme_id"`
-	Status      content.Status `json:"status"`
-	Notes       *string        `json:"notes"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
+	ID            int            `json:"id"`
+	Title         string         `json:"title"`
+	Date          *time.Time     `json:"date"`
+	TicketLink    *string        `json:"ticket_link"`
+	VenueID       *int           `json:"venue_id"`
+	ProgrammeID   *int           `json:"programme_id"`
+	Status        content.Status `json:"status"`
+	Notes         *string        `json:"notes"`
+	PublishAt     *time.Time     `json:"publish_at"`
+	StatusReason  *string        `json:"status_reason"`
+	ReplacementID *int           `json:"replacement_id"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
 }
 
 func newEventWithTimestampsResponse(
 	e *model.EventWithTimestamps,
 ) eventWithTimestampsResponse {
 	return eventWithTimestampsResponse{
-		ID:          e.Event.ID,
-		Title:       e.Event.Title,
-		Date:        e.Event.Date,
-		TicketLink:  e.Event.TicketLink,
-		VenueID:     e.Event.VenueID,
-		ProgrammeID: e.Event.ProgrammeID,
-		Status:      e.Event.Status,
-		Notes:       e.Event.Notes,
-		CreatedAt:   e.CreatedAt,
-		UpdatedAt:   e.UpdatedAt,
+		ID:            e.Event.ID,
+		Title:         e.Event.Title,
+		Date:          e.Event.Date,
+		TicketLink:    e.Event.TicketLink,
+		VenueID:       e.Event.VenueID,
+		ProgrammeID:   e.Event.ProgrammeID,
+		Status:        e.Event.Status,
+		Notes:         e.Event.Notes,
+		PublishAt:     e.Event.PublishAt,
+		StatusReason:  e.Event.StatusReason,
+		ReplacementID: e.Event.ReplacementID,
+		CreatedAt:     e.CreatedAt,
+		UpdatedAt:     e.UpdatedAt,
 	}
 }
 
 type eventWithProgrammeResponse struct {
-	ID          int                          `json:"id"`
-	Title       string                       `json:"title"`
-	Date        *time.Time                   `json:"date"`
-	TicketLink  *string                      `json:"ticket_link"`
-	VenueID     *int                         `json:"venue_id"`
-	ProgrammeID *int                         `json:"programme_id"`
-	Status      content.Status               `json:"status"`
-	Notes       *string                      `json:"notes"`
-	Programme   *programmeWithPiecesResponse `json:"programme"`
+	ID            int                          `json:"id"`
+	Title         string                       `json:"title"`
+	Date          *time.Time                   `json:"date"`
+	TicketLink    *string                      `json:"ticket_link"`
+	VenueID       *int                         `json:"venue_id"`
+	ProgrammeID   *int                         `json:"programme_id"`
+	Status        content.Status               `json:"status"`
+	Notes         *string                      `json:"notes"`
+	PublishAt     *time.Time                   `json:"publish_at"`
+	StatusReason  *string                      `json:"status_reason"`
+	ReplacementID *int                         `json:"replacement_id"`
+	Venue         *venueResponse               `json:"venue"`
+	Programme     *programmeWithPiecesResponse `json:"programme"`
 }
 
 func newEventWithProgrammeResponse(
 	e *model.EventWithProgramme,
 ) eventWithProgrammeResponse {
-	programme := newProgrammeWithPiecesResponse(e.Programme)
-	return eventWithProgrammeResponse{
-		ID:          e.Event.ID,
-		Title:       e.Event.Title,
-		Date:        e.Event.Date,
-		TicketLink:  e.Event.TicketLink,
-		VenueID:     e.Event.VenueID,
-		ProgrammeID: e.Event.ProgrammeID,
-		Status:      e.Event.Status,
-		Notes:       e.Event.Notes,
-		Programme:   &programme,
+	resp := eventWithProgrammeResponse{
+		ID:            e.Event.ID,
+		Title:         e.Event.Title,
+		Date:          e.Event.Date,
+		TicketLink:    e.Event.TicketLink,
+		VenueID:       e.Event.VenueID,
+		ProgrammeID:   e.Event.ProgrammeID,
+		Status:        e.Event.Status,
+		Notes:         e.Event.Notes,
+		PublishAt:     e.Event.PublishAt,
+		StatusReason:  e.Event.StatusReason,
+		ReplacementID: e.Event.ReplacementID,
 	}
+
+	if e.Venue != nil {
+		venue := newVenueResponse(e.Venue)
+		resp.Venue = &venue
+	}
+
+	if e.Programme != nil {
+		programme := newProgrammeWithPiecesResponse(e.Programme)
+		resp.Programme = &programme
+	}
+
+	return resp
 }
 
 type notesRequest struct {
 	Notes string `json:"notes"`
 }
 
+type statusChangeRequest struct {
+	Reason        string `json:"reason"`
+	ReplacementID *int   `json:"replacement_id"`
+}
+
+func (r *statusChangeRequest) toDomain() content.StatusChange {
+	return content.StatusChange{
+		Reason:        r.Reason,
+		ReplacementID: r.ReplacementID,
+	}
+}
+
 func (h *EventHandler) get(w http.ResponseWriter, r *http.Request) {
 	id, ok := parseID(w, r)
 	if !ok {
@@ -173,6 +228,14 @@ func (h *EventHandler) get(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if errors.Is(err, content.ErrPermissionDenied) {
+			respondJSON(r.Context(), w,
+				http.StatusForbidden,
+				pair("error", "permission denied"),
+			)
+			return
+		}
+
 		respondJSON(r.Context(), w,
 			http.StatusInternalServerError,
 			pair("error", "internal server error"),
@@ -187,83 +250,183 @@ func (h *EventHandler) get(w http.ResponseWriter, r *http.Request) {
 	)
 }
 
-func (h *EventHandler) list(w http.ResponseWriter, r *http.Request) {
+// jsonLD describes an event as schema.org structured data, for previewing what
+// search engines are shown.
+func (h *EventHandler) jsonLD(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+
+	event, err := h.eventService.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, content.ErrResourceNotFound) {
+			respondJSON(r.Context(), w,
+				http.StatusNotFound,
+				pair("error", "event not found"),
+			)
+			return
+		}
+
+		if errors.Is(err, content.ErrPermissionDenied) {
+			respondJSON(r.Context(), w,
+				http.StatusForbidden,
+				pair("error", "permission denied"),
+			)
+			return
+		}
+
+		respondJSON(r.Context(), w,
+			http.StatusInternalServerError,
+			pair("error", "internal server error"),
+		)
+		return
+	}
+
+	body, err := json.Marshal(jsonld.NewMusicEvent(event, h.performer))
+	if err != nil {
+		respondJSON(r.Context(), w,
+			http.StatusInternalServerError,
+			pair("error", "internal server error"),
+		)
+		return
+	}
+
+	w.Header().Set("Content-Type", jsonld.ContentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+// parseEventQuery reads the filters and page of an event listing from the
+// query string. Parameters that fail to parse are rejected here; how the
+// filters relate to each other is validated by the service.
+func parseEventQuery(
+	w http.ResponseWriter,
+	r *http.Request,
+) (model.EventQuery, bool) {
 	query := r.URL.Query()
 
-	var status *content.Status
-	val, ok := query["status"]
-	if ok && len(val) > 0 && val[0] != "" {
-		s := content.Status(val[0])
-		status = &s
+	var q model.EventQuery
+
+	if val := query.Get("status"); val != "" {
+		s := content.Status(val)
+		if err := s.Validate(); err != nil {
+			rejectParam(w, r, "status", val)
+			return q, false
+		}
+		q.Status = &s
 	}
 
-	var timeframe *content.Timeframe
-	val, ok = query["timeframe"]
-	if ok && len(val) > 0 && val[0] != "" {
-		s := content.Timeframe(val[0])
-		timeframe = &s
+	if val := query.Get("timeframe"); val != "" {
+		t := content.Timeframe(val)
+		if err := t.Validate(); err != nil {
+			rejectParam(w, r, "timeframe", val)
+			return q, false
+		}
+		q.Timeframe = &t
 	}
 
-	detailed := false
-	val, ok = query["detailed"]
-	if ok && len(val) > 0 && val[0] != "" {
-		b, err := strconv.ParseBool(val[0])
+	if val := query.Get("from"); val != "" {
+		t, err := parseTime(val)
 		if err != nil {
-			logging.FromContext(r.Context()).Warn(
-				"invalid 'detailed' parameter",
-				slog.String("detailed", val[0]),
-			)
+			rejectParam(w, r, "from", val)
+			return q, false
+		}
+		q.From = &t
+	}
 
-			respondJSON(r.Context(), w,
-				http.StatusBadRequest,
-				pair("error", "invalid 'detailed' parameter"),
-			)
+	if val := query.Get("to"); val != "" {
+		t, err := parseTime(val)
+		if err != nil {
+			rejectParam(w, r, "to", val)
+			return q, false
+		}
+		q.To = &t
+	}
+
+	if val := query.Get("limit"); val != "" {
+		n, err := strconv.Atoi(val)
+		if err != nil || n < 1 || n > model.MaxLimit {
+			rejectParam(w, r, "limit", val)
+			return q, false
+		}
+		q.Limit = n
+	}
+
+	if val := query.Get("cursor"); val != "" {
+		c, err := model.DecodeEventCursor(val)
+		if err != nil {
+			rejectParam(w, r, "cursor", val)
+			return q, false
+		}
+		q.Cursor = c
+	}
+
+	return q, true
+}
+
+func (h *EventHandler) list(w http.ResponseWriter, r *http.Request) {
+	q, ok := parseEventQuery(w, r)
+	if !ok {
+		return
+	}
+
+	detailed := false
+	if val := r.URL.Query().Get("detailed"); val != "" {
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			rejectParam(w, r, "detailed", val)
 			return
 		}
 
 		detailed = b
 	}
 
-	var events any
+	var resp any
 	var err error
 
 	ctx := r.Context()
 
 	if detailed {
-		events, err = h.eventService.ListWithTimestamp(ctx, status, timeframe)
+		var page *model.Page[model.EventWithTimestamps]
+		page, err = h.eventService.ListWithTimestamp(ctx, q)
+		if err == nil {
+			resp = newPageResponse(page, newEventWithTimestampsResponse)
+		}
 	} else {
-		events, err = h.eventService.List(ctx, status, timeframe)
+		var page *model.Page[content.Event]
+		page, err = h.eventService.List(ctx, q)
+		if err == nil {
+			resp = newPageResponse(page, newEventResponse)
+		}
 	}
 
 	if err != nil {
-		respondJSON(r.Context(), w,
-			http.StatusInternalServerError,
-			pair("error", "internal server error"),
-		)
-		return
-	}
-
-	buildResponse := func(events any) any {
-		switch e := events.(type) {
-		case []content.Event:
-			resp := make([]eventResponse, len(e))
-			for i := range e {
-				resp[i] = newEventResponse(&e[i])
-			}
-			return resp
-		case []model.EventWithTimestamps:
-			resp := make([]eventWithTimestampsResponse, len(e))
-			for i := range e {
-				resp[i] = newEventWithTimestampsResponse(&e[i])
-			}
-			return resp
+		switch {
+		case errors.Is(err, content.ErrInvalidResource):
+			respondJSON(r.Context(), w,
+				http.StatusBadRequest,
+				pair("error", err.Error()),
+			)
+			return
+		case errors.Is(err, content.ErrPermissionDenied):
+			respondJSON(r.Context(), w,
+				http.StatusForbidden,
+				pair("error", "permission denied"),
+			)
+			return
+		default:
+			respondJSON(r.Context(), w,
+				http.StatusInternalServerError,
+				pair("error", "internal server error"),
+			)
+			return
 		}
-		return nil
 	}
 
 	respondJSON(ctx, w,
 		http.StatusOK,
-		buildResponse(events),
+		resp,
 	)
 }
 
@@ -283,6 +446,14 @@ func (h *EventHandler) create(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if errors.Is(err, content.ErrPermissionDenied) {
+			respondJSON(r.Context(), w,
+				http.StatusForbidden,
+				pair("error", "permission denied"),
+			)
+			return
+		}
+
 		respondJSON(r.Context(), w,
 			http.StatusInternalServerError,
 			pair("error", "internal server error"),
@@ -318,6 +489,14 @@ func (h *EventHandler) update(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if errors.Is(err, content.ErrPermissionDenied) {
+			respondJSON(r.Context(), w,
+				http.StatusForbidden,
+				pair("error", "permission denied"),
+			)
+			return
+		}
+
 		respondJSON(r.Context(), w,
 			http.StatusInternalServerError,
 			pair("error", "internal server error"),
@@ -345,6 +524,14 @@ func (h *EventHandler) updatesNotes(w http.ResponseWriter, r *http.Request) {
 
 	event, err := h.eventService.UpdateNotes(r.Context(), id, req.Notes)
 	if err != nil {
+		if errors.Is(err, content.ErrPermissionDenied) {
+			respondJSON(r.Context(), w,
+				http.StatusForbidden,
+				pair("error", "permission denied"),
+			)
+			return
+		}
+
 		respondJSON(r.Context(), w,
 			http.StatusInternalServerError,
 			pair("error", "internal server error"),
@@ -359,6 +546,44 @@ func (h *EventHandler) updatesNotes(w http.ResponseWriter, r *http.Request) {
 	)
 }
 
+// respondTransitionError maps errors returned by status transitions to
+// responses.
+func respondTransitionError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, content.ErrInvalidResource),
+		errors.Is(err, content.ErrProgrammeHasNoPieces):
+		respondJSON(r.Context(), w,
+			http.StatusBadRequest,
+			pair("error", err.Error()),
+		)
+	case errors.Is(err, content.ErrResourceNotFound):
+		respondJSON(r.Context(), w,
+			http.StatusNotFound,
+			pair("error", "event not found"),
+		)
+	case errors.Is(err, content.ErrInvalidStatusTransition):
+		respondJSON(r.Context(), w,
+			http.StatusConflict,
+			pair("error", err.Error()),
+		)
+	case errors.Is(err, content.ErrEventNotPublishable):
+		respondJSON(r.Context(), w,
+			http.StatusForbidden,
+			pair("error", err.Error()),
+		)
+	case errors.Is(err, content.ErrPermissionDenied):
+		respondJSON(r.Context(), w,
+			http.StatusForbidden,
+			pair("error", "permission denied"),
+		)
+	default:
+		respondJSON(r.Context(), w,
+			http.StatusInternalServerError,
+			pair("error", "internal server error"),
+		)
+	}
+}
+
 func (h *EventHandler) draft(w http.ResponseWriter, r *http.Request) {
 	id, ok := parseID(w, r)
 	if !ok {
@@ -366,10 +591,7 @@ func (h *EventHandler) draft(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.eventService.Draft(r.Context(), id); err != nil {
-		respondJSON(r.Context(), w,
-			http.StatusInternalServerError,
-			pair("error", "internal server error"),
-		)
+		respondTransitionError(w, r, err)
 		return
 	}
 
@@ -383,23 +605,51 @@ func (h *EventHandler) publish(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.eventService.Publish(r.Context(), id); err != nil {
+		respondTransitionError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *EventHandler) archive(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.eventService.Archive(r.Context(), id); err != nil {
+		respondTransitionError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *EventHandler) delete(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.eventService.Delete(r.Context(), id); err != nil {
 		switch {
-		case errors.Is(err, content.ErrInvalidResource):
+		case errors.Is(err, content.ErrResourceNotFound):
 			respondJSON(r.Context(), w,
-				http.StatusBadRequest,
-				pair("error", err.Error()),
+				http.StatusNotFound,
+				pair("error", "event not found"),
 			)
 			return
-		case errors.Is(err, content.ErrProgrammeHasNoPieces):
+		case errors.Is(err, content.ErrEventProtected):
 			respondJSON(r.Context(), w,
-				http.StatusBadRequest,
-				pair("error", err.Error()),
+				http.StatusForbidden,
+				pair("error", "published event protected"),
 			)
 			return
-		case errors.Is(err, content.ErrEventNotPublishable):
+		case errors.Is(err, content.ErrPermissionDenied):
 			respondJSON(r.Context(), w,
 				http.StatusForbidden,
-				pair("error", err.Error()),
+				pair("error", "permission denied"),
 			)
 			return
 		default:
@@ -414,36 +664,121 @@ func (h *EventHandler) publish(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
-func (h *EventHandler) archive(w http.ResponseWriter, r *http.Request) {
+func (h *EventHandler) cancel(w http.ResponseWriter, r *http.Request) {
+	h.callOff(w, r, h.eventService.Cancel)
+}
+
+func (h *EventHandler) postpone(w http.ResponseWriter, r *http.Request) {
+	h.callOff(w, r, h.eventService.Postpone)
+}
+
+// callOff serves the routes moving an event to the cancelled or postponed
+// status with the passed service method.
+func (h *EventHandler) callOff(
+	w http.ResponseWriter,
+	r *http.Request,
+	apply func(ctx context.Context, id int, change content.StatusChange) error,
+) {
 	id, ok := parseID(w, r)
 	if !ok {
 		return
 	}
 
-	if err := h.eventService.Archive(r.Context(), id); err != nil {
-		respondJSON(r.Context(), w,
-			http.StatusInternalServerError,
-			pair("error", "internal server error"),
-		)
+	req, ok := parseBody[statusChangeRequest](w, r)
+	if !ok {
+		return
+	}
+
+	if err := apply(r.Context(), id, req.toDomain()); err != nil {
+		respondTransitionError(w, r, err)
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
-func (h *EventHandler) delete(w http.ResponseWriter, r *http.Request) {
+type transitionRequest struct {
+	Status content.Status `json:"status"`
+	statusChangeRequest
+}
+
+// transition moves an event to any status allowed by the transition table,
+// with an optional reason.
+func (h *EventHandler) transition(w http.ResponseWriter, r *http.Request) {
 	id, ok := parseID(w, r)
 	if !ok {
 		return
 	}
 
-	if err := h.eventService.Delete(r.Context(), id); err != nil {
-		if errors.Is(err, content.ErrEventProtected) {
+	req, ok := parseBody[transitionRequest](w, r)
+	if !ok {
+		return
+	}
+
+	err := h.eventService.Transition(r.Context(), id, req.Status, req.toDomain())
+	if err != nil {
+		respondTransitionError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type statusTransitionResponse struct {
+	ID        int            `json:"id"`
+	From      content.Status `json:"from"`
+	To        content.Status `json:"to"`
+	Reason    *string        `json:"reason"`
+	ChangedBy *int           `json:"changed_by"`
+	ChangedAt time.Time      `json:"changed_at"`
+}
+
+func newStatusTransitionResponse(
+	t *content.StatusTransition,
+) statusTransitionResponse {
+	return statusTransitionResponse{
+		ID:        t.ID,
+		From:      t.From,
+		To:        t.To,
+		Reason:    t.Reason,
+		ChangedBy: t.ChangedBy,
+		ChangedAt: t.ChangedAt,
+	}
+}
+
+func (h *EventHandler) history(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+
+	transitions, err := h.eventService.History(r.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, content.ErrResourceNotFound):
+			respondJSON(r.Context(), w,
+				http.StatusNotFound,
+				pair("error", "event not found"),
+			)
+			return
+		case errors.Is(err, content.ErrPermissionDenied):
 			respondJSON(r.Context(), w,
 				http.StatusForbidden,
-				pair("error", "published event protected"))
+				pair("error", "permission denied"),
+			)
+			return
+		default:
+			respondJSON(r.Context(), w,
+				http.StatusInternalServerError,
+				pair("error", "internal server error"),
+			)
+			return
 		}
 	}
 
-	w.WriteHeader(http.StatusNoContent)
+	resp := convertAll(transitions, newStatusTransitionResponse)
+	respondJSON(r.Context(), w,
+		http.StatusOK,
+		resp,
+	)
 }
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/adamkadda/arman/internal/cms/model"
+	"github.com/adamkadda/arman/pkg/feed"
+	"github.com/adamkadda/arman/pkg/logging"
+)
+
+// feedSize is how many of the latest events the feeds list.
+const feedSize = 50
+
+// getAtomFeed serves the latest published events as an Atom feed.
+func (h *PublicHandler) getAtomFeed(w http.ResponseWriter, r *http.Request) {
+	h.serveFeed(w, r, feed.AtomContentType, (*feed.Feed).EncodeAtom)
+}
+
+// getRSSFeed serves the latest published events as an RSS feed.
+func (h *PublicHandler) getRSSFeed(w http.ResponseWriter, r *http.Request) {
+	h.serveFeed(w, r, feed.RSSContentType, (*feed.Feed).EncodeRSS)
+}
+
+func (h *PublicHandler) serveFeed(
+	w http.ResponseWriter,
+	r *http.Request,
+	contentType string,
+	encode func(*feed.Feed, io.Writer) error,
+) {
+	events, err := h.eventService.ListRecent(r.Context(), feedSize)
+	if err != nil {
+		respondJSON(r.Context(), w,
+			http.StatusInternalServerError,
+			pair("error", "internal server error"),
+		)
+		return
+	}
+
+	f := feed.Feed{
+		Title:       "Concerts",
+		Description: "Newly announced concerts",
+		Link:        h.site.String(),
+		// The public API is expected to be reachable through the site.
+		Self:    h.site.ResolveReference(&url.URL{Path: r.URL.Path}).String(),
+		Entries: make([]feed.Entry, len(events)),
+	}
+
+	for i := range events {
+		f.Entries[i] = h.newFeedEntry(&events[i])
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+
+	if err := encode(&f, w); err != nil {
+		logging.FromContext(r.Context()).Error(
+			"write feed failed",
+			slog.Any("error", err),
+		)
+	}
+}
+
+func (h *PublicHandler) newFeedEntry(e *model.PublishedEvent) feed.Entry {
+	link := h.eventURL(e.Event.ID)
+
+	return feed.Entry{
+		ID:        link,
+		Title:     e.Event.Title,
+		Link:      link,
+		Content:   feedContent(e),
+		Published: e.CreatedAt,
+		Updated:   e.UpdatedAt,
+	}
+}
+
+// eventURL returns the address of the page of an event on the public site.
+func (h *PublicHandler) eventURL(id int) string {
+	return h.site.ResolveReference(&url.URL{
+		Path: fmt.Sprintf("/concerts/%d", id),
+	}).String()
+}
+
+// feedContent describes an event by its date, venue and programme, one item
+// per line.
+func feedContent(e *model.PublishedEvent) string {
+	var lines []string
+
+	if e.Event.Date != nil {
+		lines = append(lines, e.Event.Date.Format("Monday 2 January 2006, 15:04"))
+	}
+
+	if e.Venue != nil {
+		lines = append(lines, e.Venue.Name+", "+e.Venue.FullAddress)
+	}
+
+	if e.Programme != nil {
+		lines = append(lines, "", e.Programme.Programme.Title)
+		lines = append(lines, programmeLines(e.Programme)...)
+	}
+
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
@@ -20,7 +20,9 @@ import (
 	"log/slog"
 	"net/http"
 	"strconv"
+	"time"
 
+	"github.com/adamkadda/arman/internal/cms/model"
 	"github.com/adamkadda/arman/pkg/logging"
 )
 
@@ -123,3 +125,106 @@ func parseBody[T any](
 
 	return req, true
 }
+
+// rejectParam logs and responds to a query parameter that failed to parse or
+// validate.
+func rejectParam(
+	w http.ResponseWriter,
+	r *http.Request,
+	name string,
+	value string,
+) {
+	logging.FromContext(r.Context()).Warn(
+		"invalid '"+name+"' parameter",
+		slog.String(name, value),
+	)
+
+	respondJSON(r.Context(), w,
+		http.StatusBadRequest,
+		pair("error", "invalid '"+name+"' parameter"),
+	)
+}
+
+// parseTime parses a query parameter holding either a full RFC 3339 timestamp
+// or a plain date. Plain dates are taken as midnight UTC.
+func parseTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	return time.Parse(time.DateOnly, value)
+}
+
+// pageResponse is the envelope of every paginated listing. Total is omitted
+// by listings that do not count their items.
+type pageResponse[T any] struct {
+	Items      []T     `json:"items"`
+	Total      *int    `json:"total,omitempty"`
+	NextCursor *string `json:"next_cursor"`
+}
+
+// newPageResponse converts each item of a page into its response type.
+func newPageResponse[T, R any](
+	page *model.Page[T],
+	convert func(*T) R,
+) pageResponse[R] {
+	return pageResponse[R]{
+		Items:      convertAll(page.Items, convert),
+		Total:      page.Total,
+		NextCursor: page.NextCursor,
+	}
+}
+
+// convertAll converts each item of a slice into its response type.
+func convertAll[T, R any](items []T, convert func(*T) R) []R {
+	resp := make([]R, len(items))
+	for i := range items {
+		resp[i] = convert(&items[i])
+	}
+
+	return resp
+}
+
+// parseListQuery reads a model.ListQuery from the query string. It accepts
+// the q, sort, direction, limit and cursor parameters. Sort fields are
+// validated by the service, which knows the fields of each resource.
+func parseListQuery(
+	w http.ResponseWriter,
+	r *http.Request,
+) (model.ListQuery, bool) {
+	query := r.URL.Query()
+
+	q := model.ListQuery{
+		Filter: query.Get("q"),
+		Sort:   query.Get("sort"),
+	}
+
+	if val := query.Get("direction"); val != "" {
+		d := model.Direction(val)
+		if err := d.Validate(); err != nil {
+			rejectParam(w, r, "direction", val)
+			return q, false
+		}
+		q.Direction = d
+	}
+
+	if val := query.Get("limit"); val != "" {
+		n, err := strconv.Atoi(val)
+		if err != nil || n < 1 || n > model.MaxLimit {
+			rejectParam(w, r, "limit", val)
+			return q, false
+		}
+		q.Limit = n
+	}
+
+	if val := query.Get("cursor"); val != "" {
+		c, err := model.DecodeOffsetCursor(val)
+		if err != nil {
+			rejectParam(w, r, "cursor", val)
+			return q, false
+		}
+		q.Offset = c.Offset
+	}
+
+	return q, true
+}
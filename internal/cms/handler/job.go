@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/adamkadda/arman/internal/cms/service"
+	"github.com/adamkadda/arman/internal/content"
+	"github.com/adamkadda/arman/pkg/queue"
+)
+
+type JobHandler struct {
+	jobService *service.JobService
+}
+
+func NewJobHandler(
+	jobService *service.JobService,
+) *JobHandler {
+	return &JobHandler{
+		jobService: jobService,
+	}
+}
+
+// Register registers all job-related HTTP routes on the provided ServeMux.
+// Routes are registered at the root and assume JSON response bodies.
+func (h *JobHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /jobs", h.list)
+	mux.HandleFunc("GET /jobs/{id}", h.get)
+	mux.HandleFunc("POST /jobs/{id}/retry", h.retry)
+}
+
+type jobResponse struct {
+	ID          int64           `json:"id"`
+	Kind        string          `json:"kind"`
+	Payload     json.RawMessage `json:"payload"`
+	Status      queue.Status    `json:"status"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"max_attempts"`
+	RunAt       time.Time       `json:"run_at"`
+	LastError   *string         `json:"last_error"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+func newJobResponse(j *queue.Job) jobResponse {
+	return jobResponse{
+		ID:          j.ID,
+		Kind:        j.Kind,
+		Payload:     j.Payload,
+		Status:      j.Status,
+		Attempts:    j.Attempts,
+		MaxAttempts: j.MaxAttempts,
+		RunAt:       j.RunAt,
+		LastError:   j.LastError,
+		CreatedAt:   j.CreatedAt,
+	}
+}
+
+func (h *JobHandler) get(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+
+	job, err := h.jobService.Get(r.Context(), int64(id))
+	if err != nil {
+		switch {
+		case errors.Is(err, content.ErrResourceNotFound):
+			respondJSON(r.Context(), w,
+				http.StatusNotFound,
+				pair("error", "job not found"),
+			)
+			return
+		case errors.Is(err, content.ErrPermissionDenied):
+			respondJSON(r.Context(), w,
+				http.StatusForbidden,
+				pair("error", "permission denied"),
+			)
+			return
+		default:
+			respondJSON(r.Context(), w,
+				http.StatusInternalServerError,
+				pair("error", "internal server error"),
+			)
+			return
+		}
+	}
+
+	resp := newJobResponse(job)
+	respondJSON(r.Context(), w,
+		http.StatusOK,
+		resp,
+	)
+}
+
+// list accepts a status query parameter, along with the paging parameters of
+// parseListQuery.
+func (h *JobHandler) list(w http.ResponseWriter, r *http.Request) {
+	q, ok := parseListQuery(w, r)
+	if !ok {
+		return
+	}
+
+	var status *queue.Status
+	if val := r.URL.Query().Get("status"); val != "" {
+		s := queue.Status(val)
+		if err := s.Validate(); err != nil {
+			rejectParam(w, r, "status", val)
+			return
+		}
+		status = &s
+	}
+
+	page, err := h.jobService.List(r.Context(), status, q)
+	if err != nil {
+		switch {
+		case errors.Is(err, content.ErrInvalidResource):
+			respondJSON(r.Context(), w,
+				http.StatusBadRequest,
+				pair("error", err.Error()),
+			)
+			return
+		case errors.Is(err, content.ErrPermissionDenied):
+			respondJSON(r.Context(), w,
+				http.StatusForbidden,
+				pair("error", "permission denied"),
+			)
+			return
+		default:
+			respondJSON(r.Context(), w,
+				http.StatusInternalServerError,
+				pair("error", "internal server error"),
+			)
+			return
+		}
+	}
+
+	resp := newPageResponse(page, newJobResponse)
+	respondJSON(r.Context(), w,
+		http.StatusOK,
+		resp,
+	)
+}
+
+func (h *JobHandler) retry(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.jobService.Retry(r.Context(), int64(id)); err != nil {
+		switch {
+		case errors.Is(err, content.ErrResourceNotFound):
+			respondJSON(r.Context(), w,
+				http.StatusNotFound,
+				pair("error", "job not found"),
+			)
+			return
+		case errors.Is(err, queue.ErrNotDead):
+			respondJSON(r.Context(), w,
+				http.StatusConflict,
+				pair("error", err.Error()),
+			)
+			return
+		case errors.Is(err, content.ErrPermissionDenied):
+			respondJSON(r.Context(), w,
+				http.StatusForbidden,
+				pair("error", "permission denied"),
+			)
+			return
+		default:
+			respondJSON(r.Context(), w,
+				http.StatusInternalServerError,
+				pair("error", "internal server error"),
+			)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
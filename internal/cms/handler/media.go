@@ -0,0 +1,369 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/adamkadda/arman/internal/cms/model"
+	"github.com/adamkadda/arman/internal/cms/service"
+	"github.com/adamkadda/arman/internal/content"
+	"github.com/adamkadda/arman/pkg/logging"
+)
+
+// multipartOverhead is the room left for the non-file parts and boundaries of
+// an upload request, on top of the maximum file size.
+const multipartOverhead = 1 << 20
+
+// MediaHandler exposes HTTP endpoints for the media library and for attaching
+// media to other resources.
+// It is a thin HTTP-to-service adapter and contains no business logic.
+type MediaHandler struct {
+	mediaService  *service.MediaService
+	maxUploadSize int64
+}
+
+func NewMediaHandler(
+	mediaService *service.MediaService,
+	maxUploadSize int64,
+) *MediaHandler {
+	return &MediaHandler{
+		mediaService:  mediaService,
+		maxUploadSize: maxUploadSize,
+	}
+}
+
+// Register registers all media-related HTTP routes on the provided ServeMux.
+// Uploads are multipart/form-data requests, every other route assumes JSON
+// request and response bodies.
+func (h *MediaHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /media/{id}", h.get)
+	mux.HandleFunc("GET /media/{id}/file", h.file)
+	mux.HandleFunc("GET /media", h.list)
+	mux.HandleFunc("POST /media", h.upload)
+	mux.HandleFunc("PUT /media/{id}", h.update)
+	mux.HandleFunc("DELETE /media/{id}", h.delete)
+
+	mux.HandleFunc("GET /events/{id}/media", h.listAttached(model.MediaOwnerEvent))
+	mux.HandleFunc("PUT /events/{id}/media", h.attach(model.MediaOwnerEvent))
+	mux.HandleFunc("GET /venues/{id}/media", h.listAttached(model.MediaOwnerVenue))
+	mux.HandleFunc("PUT /venues/{id}/media", h.attach(model.MediaOwnerVenue))
+	mux.HandleFunc("GET /composers/{id}/media", h.listAttached(model.MediaOwnerComposer))
+	mux.HandleFunc("PUT /composers/{id}/media", h.attach(model.MediaOwnerComposer))
+	mux.HandleFunc("GET /biography/{variant}/media", h.listAttached(model.MediaOwnerBiography))
+	mux.HandleFunc("PUT /biography/{variant}/media", h.attach(model.MediaOwnerBiography))
+}
+
+type mediaRequest struct {
+	AltText string `json:"alt_text"`
+	Credits string `json:"credits"`
+}
+
+type mediaResponse struct {
+	ID        int       `json:"media_id"`
+	Filename  string    `json:"filename"`
+	MIMEType  string    `json:"mime_type"`
+	Size      int64     `json:"size"`
+	Checksum  string    `json:"checksum"`
+	AltText   string    `json:"alt_text"`
+	Credits   string    `json:"credits"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func newMediaResponse(m *content.Media) mediaResponse {
+	return mediaResponse{
+		ID:        m.ID,
+		Filename:  m.Filename,
+		MIMEType:  m.MIMEType,
+		Size:      m.Size,
+		Checksum:  m.Checksum,
+		AltText:   m.AltText,
+		Credits:   m.Credits,
+		CreatedAt: m.CreatedAt,
+	}
+}
+
+type mediaWithDetailsResponse struct {
+	Media      mediaResponse `json:"media"`
+	EventCount int           `json:"event_count"`
+}
+
+func newMediaWithDetailsResponse(
+	m *model.MediaWithDetails,
+) mediaWithDetailsResponse {
+	return mediaWithDetailsResponse{
+		Media:      newMediaResponse(&m.Media),
+		EventCount: m.EventCount,
+	}
+}
+
+// respondMediaError maps errors shared by the media routes to responses.
+func respondMediaError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, content.ErrInvalidResource):
+		respondJSON(r.Context(), w,
+			http.StatusBadRequest,
+			pair("error", err.Error()),
+		)
+	case errors.Is(err, content.ErrMediaTooLarge):
+		respondJSON(r.Context(), w,
+			http.StatusRequestEntityTooLarge,
+			pair("error", "media too large"),
+		)
+	case errors.Is(err, content.ErrResourceNotFound):
+		respondJSON(r.Context(), w,
+			http.StatusNotFound,
+			pair("error", "media not found"),
+		)
+	case errors.Is(err, content.ErrMediaProtected):
+		respondJSON(r.Context(), w,
+			http.StatusForbidden,
+			pair("error", "media in use"),
+		)
+	case errors.Is(err, content.ErrEventImmutable):
+		respondJSON(r.Context(), w,
+			http.StatusForbidden,
+			pair("error", "event immutable"),
+		)
+	case errors.Is(err, content.ErrPermissionDenied):
+		respondJSON(r.Context(), w,
+			http.StatusForbidden,
+			pair("error", "permission denied"),
+		)
+	default:
+		respondJSON(r.Context(), w,
+			http.StatusInternalServerError,
+			pair("error", "internal server error"),
+		)
+	}
+}
+
+func (h *MediaHandler) get(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+
+	media, err := h.mediaService.Get(r.Context(), id)
+	if err != nil {
+		respondMediaError(w, r, err)
+		return
+	}
+
+	resp := newMediaWithDetailsResponse(media)
+	respondJSON(r.Context(), w,
+		http.StatusOK,
+		resp,
+	)
+}
+
+// file streams the file of a Media. It is the only media route that does not
+// respond with JSON on success.
+func (h *MediaHandler) file(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+
+	media, file, err := h.mediaService.Open(r.Context(), id)
+	if err != nil {
+		respondMediaError(w, r, err)
+		return
+	}
+	defer file.Close()
+
+	w.Header().Set("Content-Type", media.MIMEType)
+	w.Header().Set("Content-Length", strconv.FormatInt(media.Size, 10))
+	w.Header().Set("ETag", `"`+media.Checksum+`"`)
+
+	if _, err := io.Copy(w, file); err != nil {
+		logging.FromContext(r.Context()).Warn(
+			"stream media file failed",
+			slog.Any("error", err),
+		)
+	}
+}
+
+func (h *MediaHandler) list(w http.ResponseWriter, r *http.Request) {
+	q, ok := parseListQuery(w, r)
+	if !ok {
+		return
+	}
+
+	page, err := h.mediaService.List(r.Context(), q)
+	if err != nil {
+		respondMediaError(w, r, err)
+		return
+	}
+
+	resp := newPageResponse(page, newMediaWithDetailsResponse)
+	respondJSON(r.Context(), w,
+		http.StatusOK,
+		resp,
+	)
+}
+
+// upload expects a multipart/form-data body with the file in the "file" part,
+// and optional "alt_text" and "credits" parts.
+func (h *MediaHandler) upload(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxUploadSize+multipartOverhead)
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		logging.FromContext(r.Context()).Warn(
+			"read upload failed",
+			slog.Any("error", err),
+		)
+
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			respondMediaError(w, r, content.ErrMediaTooLarge)
+			return
+		}
+
+		respondJSON(r.Context(), w,
+			http.StatusBadRequest,
+			pair("error", "invalid upload"),
+		)
+		return
+	}
+	defer file.Close()
+
+	m := content.Media{
+		Filename: header.Filename,
+		AltText:  r.FormValue("alt_text"),
+		Credits:  r.FormValue("credits"),
+	}
+
+	media, err := h.mediaService.Upload(r.Context(), m, file)
+	if err != nil {
+		respondMediaError(w, r, err)
+		return
+	}
+
+	resp := newMediaResponse(media)
+	respondJSON(r.Context(), w,
+		http.StatusCreated,
+		resp,
+	)
+}
+
+func (h *MediaHandler) update(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+
+	req, ok := parseBody[mediaRequest](w, r)
+	if !ok {
+		return
+	}
+
+	media, err := h.mediaService.Update(r.Context(), content.Media{
+		ID:      id,
+		AltText: req.AltText,
+		Credits: req.Credits,
+	})
+	if err != nil {
+		respondMediaError(w, r, err)
+		return
+	}
+
+	resp := newMediaResponse(media)
+	respondJSON(r.Context(), w,
+		http.StatusOK,
+		resp,
+	)
+}
+
+func (h *MediaHandler) delete(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.mediaService.Delete(r.Context(), id); err != nil {
+		respondMediaError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseMediaOwner reads the owner of an attachment route from the path.
+func parseMediaOwner(
+	w http.ResponseWriter,
+	r *http.Request,
+	ownerType model.MediaOwnerType,
+) (model.MediaOwner, bool) {
+	owner := model.MediaOwner{Type: ownerType}
+
+	if ownerType == model.MediaOwnerBiography {
+		owner.Variant = content.BiographyVariant(r.PathValue("variant"))
+		return owner, true
+	}
+
+	id, ok := parseID(w, r)
+	if !ok {
+		return owner, false
+	}
+
+	owner.ID = id
+
+	return owner, true
+}
+
+func (h *MediaHandler) listAttached(
+	ownerType model.MediaOwnerType,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		owner, ok := parseMediaOwner(w, r, ownerType)
+		if !ok {
+			return
+		}
+
+		media, err := h.mediaService.ListAttached(r.Context(), owner)
+		if err != nil {
+			respondMediaError(w, r, err)
+			return
+		}
+
+		resp := convertAll(media, newMediaResponse)
+		respondJSON(r.Context(), w,
+			http.StatusOK,
+			resp,
+		)
+	}
+}
+
+// attach expects a JSON array of media ids, in display order.
+func (h *MediaHandler) attach(
+	ownerType model.MediaOwnerType,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		owner, ok := parseMediaOwner(w, r, ownerType)
+		if !ok {
+			return
+		}
+
+		req, ok := parseBody[[]int](w, r)
+		if !ok {
+			return
+		}
+
+		media, err := h.mediaService.Attach(r.Context(), owner, req)
+		if err != nil {
+			respondMediaError(w, r, err)
+			return
+		}
+
+		resp := convertAll(media, newMediaResponse)
+		respondJSON(r.Context(), w,
+			http.StatusOK,
+			resp,
+		)
+	}
+}
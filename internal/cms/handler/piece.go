@@ -2,7 +2,9 @@ package handler
 
 import (
 	"errors"
+	"mime"
 	"net/http"
+	"strconv"
 
 	"github.com/adamkadda/arman/internal/cms/model"
 	"github.com/adamkadda/arman/internal/cms/service"
@@ -29,6 +31,7 @@ func (h *PieceHandler) Register(mux *http.ServeMux) {
 	mux.HandleFunc("POST /pieces", h.create)
 	mux.HandleFunc("PUT /pieces/{id}", h.update)
 	mux.HandleFunc("DELETE /pieces/{id}", h.delete)
+	mux.HandleFunc("POST /pieces/import", h.importPieces)
 }
 
 type pieceRequest struct {
@@ -131,6 +134,14 @@ func (h *PieceHandler) get(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if errors.Is(err, content.ErrPermissionDenied) {
+			respondJSON(r.Context(), w,
+				http.StatusForbidden,
+				pair("error", "permission denied"),
+			)
+			return
+		}
+
 		respondJSON(r.Context(), w,
 			http.StatusInternalServerError,
 			pair("error", "internal server error"),
@@ -146,20 +157,36 @@ func (h *PieceHandler) get(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *PieceHandler) list(w http.ResponseWriter, r *http.Request) {
-	pieces, err := h.pieceService.List(r.Context())
-	if err != nil {
-		respondJSON(r.Context(), w,
-			http.StatusInternalServerError,
-			pair("error", "internal server error"),
-		)
+	q, ok := parseListQuery(w, r)
+	if !ok {
 		return
 	}
 
-	resp := make([]pieceWithDetailsResponse, len(pieces))
-	for i := range pieces {
-		resp[i] = newPieceWithDetailsResponse(&pieces[i])
+	page, err := h.pieceService.List(r.Context(), q)
+	if err != nil {
+		switch {
+		case errors.Is(err, content.ErrInvalidResource):
+			respondJSON(r.Context(), w,
+				http.StatusBadRequest,
+				pair("error", err.Error()),
+			)
+			return
+		case errors.Is(err, content.ErrPermissionDenied):
+			respondJSON(r.Context(), w,
+				http.StatusForbidden,
+				pair("error", "permission denied"),
+			)
+			return
+		default:
+			respondJSON(r.Context(), w,
+				http.StatusInternalServerError,
+				pair("error", "internal server error"),
+			)
+			return
+		}
 	}
 
+	resp := newPageResponse(page, newPieceWithDetailsResponse)
 	respondJSON(r.Context(), w,
 		http.StatusOK,
 		resp,
@@ -190,6 +217,14 @@ func (h *PieceHandler) create(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if errors.Is(err, content.ErrPermissionDenied) {
+			respondJSON(r.Context(), w,
+				http.StatusForbidden,
+				pair("error", "permission denied"),
+			)
+			return
+		}
+
 		respondJSON(r.Context(), w,
 			http.StatusInternalServerError,
 			pair("error", "internal server error"),
@@ -235,6 +270,14 @@ func (h *PieceHandler) update(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if errors.Is(err, content.ErrPermissionDenied) {
+			respondJSON(r.Context(), w,
+				http.StatusForbidden,
+				pair("error", "permission denied"),
+			)
+			return
+		}
+
 		respondJSON(r.Context(), w,
 			http.StatusInternalServerError,
 			pair("error", "internal server error"),
@@ -269,6 +312,12 @@ func (h *PieceHandler) delete(w http.ResponseWriter, r *http.Request) {
 				pair("error", "piece in use"),
 			)
 			return
+		case errors.Is(err, content.ErrPermissionDenied):
+			respondJSON(r.Context(), w,
+				http.StatusForbidden,
+				pair("error", "permission denied"),
+			)
+			return
 		default:
 			respondJSON(r.Context(), w,
 				http.StatusInternalServerError,
@@ -280,3 +329,134 @@ func (h *PieceHandler) delete(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// maxImportSize is the largest import body accepted, in bytes.
+const maxImportSize = 10 << 20
+
+type pieceImportResultResponse struct {
+	Line            int                `json:"line"`
+	Status          model.ImportStatus `json:"status"`
+	Reason          string             `json:"reason,omitempty"`
+	PieceID         int                `json:"piece_id,omitempty"`
+	ComposerID      int                `json:"composer_id,omitempty"`
+	ComposerCreated bool               `json:"composer_created"`
+}
+
+type pieceImportResponse struct {
+	Error            string                      `json:"error,omitempty"`
+	DryRun           bool                        `json:"dry_run"`
+	Committed        bool                        `json:"committed"`
+	Created          int                         `json:"created"`
+	Skipped          int                         `json:"skipped"`
+	Failed           int                         `json:"failed"`
+	ComposersCreated int                         `json:"composers_created"`
+	Rows             []pieceImportResultResponse `json:"rows"`
+}
+
+func newPieceImportResponse(r *model.PieceImportReport) pieceImportResponse {
+	resp := pieceImportResponse{
+		DryRun:           r.DryRun,
+		Committed:        r.Committed,
+		Created:          r.Created,
+		Skipped:          r.Skipped,
+		Failed:           r.Failed,
+		ComposersCreated: r.ComposersCreated,
+		Rows:             make([]pieceImportResultResponse, len(r.Rows)),
+	}
+
+	for i, row := range r.Rows {
+		resp.Rows[i] = pieceImportResultResponse{
+			Line:            row.Line,
+			Status:          row.Status,
+			Reason:          row.Reason,
+			PieceID:         row.PieceID,
+			ComposerID:      row.ComposerID,
+			ComposerCreated: row.ComposerCreated,
+		}
+	}
+
+	return resp
+}
+
+// importPieces creates pieces in bulk from a text/csv or application/json
+// body, see model.DecodePieceImport. Pass dry_run=true to report what the
+// import would do without committing it.
+//
+// An import with failing rows is rolled back and answered with 400, along
+// with the report locating the failures. Dry runs are always answered with
+// 200.
+func (h *PieceHandler) importPieces(w http.ResponseWriter, r *http.Request) {
+	dryRun := false
+	if val := r.URL.Query().Get("dry_run"); val != "" {
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			rejectParam(w, r, "dry_run", val)
+			return
+		}
+
+		dryRun = b
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+	var format model.ImportFormat
+	switch mediaType {
+	case "text/csv":
+		format = model.ImportCSV
+	case "application/json":
+		format = model.ImportJSON
+	default:
+		respondJSON(r.Context(), w,
+			http.StatusUnsupportedMediaType,
+			pair("error", "unsupported content type, want text/csv or application/json"),
+		)
+		return
+	}
+
+	rows, err := model.DecodePieceImport(http.MaxBytesReader(w, r.Body, maxImportSize), format)
+	if err != nil {
+		respondJSON(r.Context(), w,
+			http.StatusBadRequest,
+			pair("error", err.Error()),
+		)
+		return
+	}
+
+	report, err := h.pieceService.Import(r.Context(), rows, dryRun)
+	if err != nil {
+		if errors.Is(err, content.ErrInvalidResource) {
+			respondJSON(r.Context(), w,
+				http.StatusBadRequest,
+				pair("error", err.Error()),
+			)
+			return
+		}
+
+		if errors.Is(err, content.ErrPermissionDenied) {
+			respondJSON(r.Context(), w,
+				http.StatusForbidden,
+				pair("error", "permission denied"),
+			)
+			return
+		}
+
+		respondJSON(r.Context(), w,
+			http.StatusInternalServerError,
+			pair("error", "internal server error"),
+		)
+		return
+	}
+
+	resp := newPieceImportResponse(report)
+
+	status := http.StatusOK
+	if report.Failed > 0 && !dryRun {
+		status = http.StatusBadRequest
+		resp.Error = "import has invalid rows"
+	}
+
+	respondJSON(r.Context(), w,
+		status,
+		resp,
+	)
+}
@@ -29,7 +29,7 @@ func (h *ProgrammeHandler) Register(mux *http.ServeMux) {
 	mux.HandleFunc("GET /programmes/{id}", h.get)
 	mux.HandleFunc("GET /programmes", h.list)
 	mux.HandleFunc("POST /programmes", h.create)
-	mux.HandleFunc("PUT /programmes", h.update)
+	mux.HandleFunc("PUT /programmes/{id}", h.update)
 	mux.HandleFunc("PUT /programmes/{id}/pieces", h.updatePieces)
 	mux.HandleFunc("DELETE /programmes/{id}", h.delete)
 }
@@ -132,6 +132,14 @@ func (h *ProgrammeHandler) get(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if errors.Is(err, content.ErrPermissionDenied) {
+			respondJSON(r.Context(), w,
+				http.StatusForbidden,
+				pair("error", "permission denied"),
+			)
+			return
+		}
+
 		respondJSON(r.Context(), w,
 			http.StatusInternalServerError,
 			pair("error", "internal server error"),
@@ -147,20 +155,36 @@ func (h *ProgrammeHandler) get(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *ProgrammeHandler) list(w http.ResponseWriter, r *http.Request) {
-	programmes, err := h.programmeService.List(r.Context())
-	if err != nil {
-		respondJSON(r.Context(), w,
-			http.StatusInternalServerError,
-			pair("error", "internal server error"),
-		)
+	q, ok := parseListQuery(w, r)
+	if !ok {
 		return
 	}
 
-	resp := make([]programmeWithDetailsResponse, len(programmes))
-	for i := range programmes {
-		resp[i] = newProgrammeWithDetailsResponse(&programmes[i])
+	page, err := h.programmeService.List(r.Context(), q)
+	if err != nil {
+		switch {
+		case errors.Is(err, content.ErrInvalidResource):
+			respondJSON(r.Context(), w,
+				http.StatusBadRequest,
+				pair("error", err.Error()),
+			)
+			return
+		case errors.Is(err, content.ErrPermissionDenied):
+			respondJSON(r.Context(), w,
+				http.StatusForbidden,
+				pair("error", "permission denied"),
+			)
+			return
+		default:
+			respondJSON(r.Context(), w,
+				http.StatusInternalServerError,
+				pair("error", "internal server error"),
+			)
+			return
+		}
 	}
 
+	resp := newPageResponse(page, newProgrammeWithDetailsResponse)
 	respondJSON(r.Context(), w,
 		http.StatusOK,
 		resp,
@@ -183,6 +207,14 @@ func (h *ProgrammeHandler) create(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if errors.Is(err, content.ErrPermissionDenied) {
+			respondJSON(r.Context(), w,
+				http.StatusForbidden,
+				pair("error", "permission denied"),
+			)
+			return
+		}
+
 		respondJSON(r.Context(), w,
 			http.StatusInternalServerError,
 			pair("error", "internal server error"),
@@ -223,6 +255,12 @@ func (h *ProgrammeHandler) update(w http.ResponseWriter, r *http.Request) {
 				pair("error", "programme in use"),
 			)
 			return
+		case errors.Is(err, content.ErrPermissionDenied):
+			respondJSON(r.Context(), w,
+				http.StatusForbidden,
+				pair("error", "permission denied"),
+			)
+			return
 		default:
 			respondJSON(r.Context(), w,
 				http.StatusInternalServerError,
@@ -260,6 +298,14 @@ func (h *ProgrammeHandler) updatePieces(w http.ResponseWriter, r *http.Request)
 			return
 		}
 
+		if errors.Is(err, content.ErrPermissionDenied) {
+			respondJSON(r.Context(), w,
+				http.StatusForbidden,
+				pair("error", "permission denied"),
+			)
+			return
+		}
+
 		respondJSON(r.Context(), w,
 			http.StatusInternalServerError,
 			pair("error", "internal server error"),
@@ -294,6 +340,12 @@ func (h *ProgrammeHandler) delete(w http.ResponseWriter, r *http.Request) {
 				pair("error", "programme in use"),
 			)
 			return
+		case errors.Is(err, content.ErrPermissionDenied):
+			respondJSON(r.Context(), w,
+				http.StatusForbidden,
+				pair("error", "permission denied"),
+			)
+			return
 		default:
 			respondJSON(r.Context(), w,
 				http.StatusInternalServerError,
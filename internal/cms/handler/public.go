@@ -0,0 +1,276 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/adamkadda/arman/internal/cms/model"
+	"github.com/adamkadda/arman/internal/cms/service"
+	"github.com/adamkadda/arman/internal/content"
+)
+
+// PublicHandler exposes the read-only HTTP endpoints consumed by the artist
+// website. Every route it registers is unauthenticated, so responses are built
+// from dedicated public types that never carry private fields such as Notes.
+type PublicHandler struct {
+	eventService     *service.EventService
+	biographyService *service.BiographyService
+	contactService   *service.ContactService
+	site             *url.URL
+}
+
+func NewPublicHandler(
+	eventService *service.EventService,
+	biographyService *service.BiographyService,
+	contactService *service.ContactService,
+	site *url.URL,
+) *PublicHandler {
+	return &PublicHandler{
+		eventService:     eventService,
+		biographyService: biographyService,
+		contactService:   contactService,
+		site:             site,
+	}
+}
+
+// Register registers all public HTTP routes on the provided ServeMux. Routes
+// are registered under /public and assume JSON response bodies, except for the
+// calendar and syndication feeds.
+func (h *PublicHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /public/events", h.listEvents)
+	mux.HandleFunc("GET /public/events.ics", h.getCalendar)
+	mux.HandleFunc("GET /public/feed.atom", h.getAtomFeed)
+	mux.HandleFunc("GET /public/feed.rss", h.getRSSFeed)
+	mux.HandleFunc("GET /public/events/{id}", h.getEvent)
+	mux.HandleFunc("GET /public/biography/{variant}", h.getBiography)
+	mux.HandleFunc("GET /public/contact", h.getContact)
+}
+
+type publicComposerResponse struct {
+	FullName  string `json:"full_name"`
+	ShortName string `json:"short_name"`
+}
+
+type publicPieceResponse struct {
+	Title    string                 `json:"title"`
+	Composer publicComposerResponse `json:"composer"`
+	Sequence int                    `json:"sequence"`
+}
+
+type publicProgrammeResponse struct {
+	Title  string                `json:"title"`
+	Pieces []publicPieceResponse `json:"pieces"`
+}
+
+func newPublicProgrammeResponse(
+	p *model.ProgrammeWithPieces,
+) *publicProgrammeResponse {
+	if p == nil {
+		return nil
+	}
+
+	pieces := make([]publicPieceResponse, len(p.Pieces))
+	for i, pp := range p.Pieces {
+		pieces[i] = publicPieceResponse{
+			Title: pp.Piece.Title,
+			Composer: publicComposerResponse{
+				FullName:  pp.Composer.FullName,
+				ShortName: pp.Composer.ShortName,
+			},
+			Sequence: pp.Sequence,
+		}
+	}
+
+	return &publicProgrammeResponse{
+		Title:  p.Programme.Title,
+		Pieces: pieces,
+	}
+}
+
+type publicVenueResponse struct {
+	Name         string `json:"name"`
+	FullAddress  string `json:"full_address"`
+	ShortAddress string `json:"short_address"`
+}
+
+func newPublicVenueResponse(v *content.Venue) *publicVenueResponse {
+	if v == nil {
+		return nil
+	}
+
+	return &publicVenueResponse{
+		Name:         v.Name,
+		FullAddress:  v.FullAddress,
+		ShortAddress: v.ShortAddress,
+	}
+}
+
+type publicEventResponse struct {
+	ID           int                      `json:"id"`
+	Title        string                   `json:"title"`
+	Date         *time.Time               `json:"date"`
+	TicketLink   *string                  `json:"ticket_link"`
+	Status       content.Status           `json:"status"`
+	StatusReason *string                  `json:"status_reason"`
+	Venue        *publicVenueResponse     `json:"venue"`
+	Programme    *publicProgrammeResponse `json:"programme"`
+}
+
+func newPublicEventResponse(e *model.EventWithProgramme) publicEventResponse {
+	return publicEventResponse{
+		ID:           e.Event.ID,
+		Title:        e.Event.Title,
+		Date:         e.Event.Date,
+		TicketLink:   e.Event.TicketLink,
+		Status:       e.Event.Status,
+		StatusReason: e.Event.StatusReason,
+		Venue:        newPublicVenueResponse(e.Venue),
+		Programme:    newPublicProgrammeResponse(e.Programme),
+	}
+}
+
+// listEvents accepts the same query parameters as the admin event listing,
+// except for status: only published events are ever listed.
+func (h *PublicHandler) listEvents(w http.ResponseWriter, r *http.Request) {
+	q, ok := parseEventQuery(w, r)
+	if !ok {
+		return
+	}
+
+	page, err := h.eventService.ListPublished(r.Context(), q)
+	if err != nil {
+		if errors.Is(err, content.ErrInvalidResource) {
+			respondJSON(r.Context(), w,
+				http.StatusBadRequest,
+				pair("error", err.Error()),
+			)
+			return
+		}
+
+		respondJSON(r.Context(), w,
+			http.StatusInternalServerError,
+			pair("error", "internal server error"),
+		)
+		return
+	}
+
+	resp := newPageResponse(page, newPublicEventResponse)
+	respondJSON(r.Context(), w,
+		http.StatusOK,
+		resp,
+	)
+}
+
+func (h *PublicHandler) getEvent(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+
+	event, err := h.eventService.GetPublished(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, content.ErrResourceNotFound) {
+			respondJSON(r.Context(), w,
+				http.StatusNotFound,
+				pair("error", "event not found"),
+			)
+			return
+		}
+
+		respondJSON(r.Context(), w,
+			http.StatusInternalServerError,
+			pair("error", "internal server error"),
+		)
+		return
+	}
+
+	resp := newPublicEventResponse(event)
+	respondJSON(r.Context(), w,
+		http.StatusOK,
+		resp,
+	)
+}
+
+func (h *PublicHandler) getBiography(w http.ResponseWriter, r *http.Request) {
+	variant := content.BiographyVariant(r.PathValue("variant"))
+
+	biography, err := h.biographyService.GetPublic(r.Context(), variant, preferredLanguages(r)...)
+	if err != nil {
+		if errors.Is(err, content.ErrInvalidBiographyVariant) ||
+			errors.Is(err, content.ErrResourceNotFound) {
+			respondJSON(r.Context(), w,
+				http.StatusNotFound,
+				pair("error", "biography not found"),
+			)
+			return
+		}
+
+		respondJSON(r.Context(), w,
+			http.StatusInternalServerError,
+			pair("error", "internal server error"),
+		)
+		return
+	}
+
+	setLanguageHeaders(w, biography)
+
+	resp := newBiographyResponse(biography)
+	respondJSON(r.Context(), w,
+		http.StatusOK,
+		resp,
+	)
+}
+
+// publicContactResponse mirrors contactData without the private phone number.
+type publicContactResponse struct {
+	Email            string           `json:"email"`
+	ManagementAgency string           `json:"management_agency"`
+	ManagementEmail  string           `json:"management_email"`
+	ManagementPhone  string           `json:"management_phone"`
+	PressContact     string           `json:"press_contact"`
+	PressEmail       string           `json:"press_email"`
+	PressPhone       string           `json:"press_phone"`
+	SocialLinks      []socialLinkData `json:"social_links"`
+}
+
+func newPublicContactResponse(c *content.Contact) publicContactResponse {
+	full := newContactResponse(c)
+
+	return publicContactResponse{
+		Email:            full.Email,
+		ManagementAgency: full.ManagementAgency,
+		ManagementEmail:  full.ManagementEmail,
+		ManagementPhone:  full.ManagementPhone,
+		PressContact:     full.PressContact,
+		PressEmail:       full.PressEmail,
+		PressPhone:       full.PressPhone,
+		SocialLinks:      full.SocialLinks,
+	}
+}
+
+func (h *PublicHandler) getContact(w http.ResponseWriter, r *http.Request) {
+	contact, err := h.contactService.GetPublic(r.Context())
+	if err != nil {
+		if errors.Is(err, content.ErrResourceNotFound) {
+			respondJSON(r.Context(), w,
+				http.StatusNotFound,
+				pair("error", "contact not found"),
+			)
+			return
+		}
+
+		respondJSON(r.Context(), w,
+			http.StatusInternalServerError,
+			pair("error", "internal server error"),
+		)
+		return
+	}
+
+	resp := newPublicContactResponse(contact)
+	respondJSON(r.Context(), w,
+		http.StatusOK,
+		resp,
+	)
+}
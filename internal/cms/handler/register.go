@@ -3,7 +3,9 @@ package handler
 import (
 	"net/http"
 
+	"github.com/adamkadda/arman/internal/cms"
 	"github.com/adamkadda/arman/internal/cms/service"
+	"github.com/adamkadda/arman/pkg/blob"
 	"github.com/adamkadda/arman/pkg/logging"
 	"github.com/adamkadda/arman/pkg/middleware"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -11,6 +13,8 @@ import (
 
 func RegisterRoutes(
 	pool *pgxpool.Pool,
+	cfg *cms.Config,
+	blobs blob.Store,
 ) http.Handler {
 	layers := []middleware.Middleware{
 		logging.Middleware(),
@@ -22,35 +26,149 @@ func RegisterRoutes(
 
 	router := http.NewServeMux()
 
+	// Public routes share the admin server unless they are served on a port of
+	// their own, see RegisterPublicRoutes.
+	if cfg.PublicPort == "" {
+		registerPublic(router, pool, cfg)
+	}
+
+	authService := service.NewAuthService(pool, cfg.SessionTTL)
+	authHandler := NewAuthHandler(authService, !cfg.Development())
+	authHandler.Register(router)
+
+	// Every route registered on protected sits behind the authentication
+	// middleware. Unauthenticated routes are registered on router directly.
+	protected := http.NewServeMux()
+
 	venueService := service.NewVenueService(pool)
 	venueHandler := NewVenueHandler(venueService)
-	venueHandler.Register(router)
+	venueHandler.Register(protected)
 
 	composerService := service.NewComposerService(pool)
 	composerHandler := NewComposerHandler(composerService)
-	composerHandler.Register(router)
+	composerHandler.Register(protected)
 
 	pieceService := service.NewPieceService(pool)
 	pieceHandler := NewPieceHandler(pieceService)
-	pieceHandler.Register(router)
+	pieceHandler.Register(protected)
 
 	programmeService := service.NewProgrammeService(pool)
 	programmeHandler := NewProgrammeHandler(programmeService)
-	programmeHandler.Register(router)
+	programmeHandler.Register(protected)
 
 	eventService := service.NewEventService(pool)
-	eventHandler := NewEventHandler(eventService)
-	eventHandler.Register(router)
+	eventHandler := NewEventHandler(eventService, cfg.ArtistName)
+	eventHandler.Register(protected)
 
-	biographyService := service.NewBiographyService(pool)
+	biographyService := service.NewBiographyService(pool, cfg.Languages)
 	biographyHandler := NewBiographyHandler(biographyService)
-	biographyHandler.Register(router)
+	biographyHandler.Register(protected)
+
+	searchService := service.NewSearchService(pool)
+	searchHandler := NewSearchHandler(searchService)
+	searchHandler.Register(protected)
+
+	userService := service.NewUserService(pool)
+	userHandler := NewUserHandler(userService)
+	userHandler.Register(protected)
+
+	contactService := service.NewContactService(pool)
+	contactHandler := NewContactHandler(contactService)
+	contactHandler.Register(protected)
+
+	mediaService := service.NewMediaService(pool, blobs, cfg.MediaMaxSize)
+	mediaHandler := NewMediaHandler(mediaService, cfg.MediaMaxSize)
+	mediaHandler.Register(protected)
+
+	jobService := service.NewJobService(pool)
+	jobHandler := NewJobHandler(jobService)
+	jobHandler.Register(protected)
+
+	archiveService := service.NewArchiveService(pool)
+	archiveHandler := NewArchiveHandler(archiveService)
+	archiveHandler.Register(protected)
+
+	auditService := service.NewAuditService(pool)
+	auditHandler := NewAuditHandler(auditService)
+	auditHandler.Register(protected)
+
+	router.Handle("/", authHandler.Middleware()(protected))
+
+	// The dashboard checks sessions itself, so that signed out users are
+	// redirected to its login page rather than given a JSON error.
+	dashboardHandler := NewDashboardHandler(
+		composerService,
+		pieceService,
+		venueService,
+		programmeService,
+		eventService,
+		biographyService,
+	)
+	dashboardHandler.Register(router, authHandler)
 
-	// TODO: Create and register contact details routes.
+	return stack(router)
+}
 
-	// TODO: Create and register media (blob) routes.
+// RegisterPublicRoutes returns a handler serving only the read-only public
+// routes. It is used when the public API runs as a server of its own.
+func RegisterPublicRoutes(
+	pool *pgxpool.Pool,
+	cfg *cms.Config,
+) http.Handler {
+	stack := middleware.NewStack(
+		logging.Middleware(),
+	)
+
+	router := http.NewServeMux()
 
-	// TODO: Create and register authentication routes.
+	registerPublic(router, pool, cfg)
 
 	return stack(router)
 }
+
+// RegisterSiteRoutes returns a handler serving the public artist website,
+// along with the public routes its pages link to, such as the calendar and
+// syndication feeds. It fails if the configured theme cannot be loaded.
+func RegisterSiteRoutes(
+	pool *pgxpool.Pool,
+	cfg *cms.Config,
+) (http.Handler, error) {
+	stack := middleware.NewStack(
+		logging.Middleware(),
+	)
+
+	router := http.NewServeMux()
+
+	siteHandler, err := NewSiteHandler(
+		service.NewEventService(pool),
+		service.NewBiographyService(pool, cfg.Languages),
+		service.NewContactService(pool),
+		cfg,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	siteHandler.Register(router)
+	registerPublic(router, pool, cfg)
+
+	return stack(router), nil
+}
+
+func registerPublic(
+	mux *http.ServeMux,
+	pool *pgxpool.Pool,
+	cfg *cms.Config,
+) {
+	eventService := service.NewEventService(pool)
+	biographyService := service.NewBiographyService(pool, cfg.Languages)
+	contactService := service.NewContactService(pool)
+
+	publicHandler := NewPublicHandler(
+		eventService,
+		biographyService,
+		contactService,
+		&cfg.SiteURL,
+	)
+	publicHandler.Register(mux)
+}
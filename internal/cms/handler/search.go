@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/adamkadda/arman/internal/cms/model"
+	"github.com/adamkadda/arman/internal/cms/service"
+	"github.com/adamkadda/arman/internal/content"
+)
+
+// SearchHandler exposes the full-text search endpoint of the admin API.
+// It is a thin HTTP-to-service adapter and contains no business logic.
+type SearchHandler struct {
+	searchService *service.SearchService
+}
+
+func NewSearchHandler(
+	searchService *service.SearchService,
+) *SearchHandler {
+	return &SearchHandler{
+		searchService: searchService,
+	}
+}
+
+// Register registers all search-related HTTP routes on the provided ServeMux.
+// Routes are registered at the root and assume JSON response bodies.
+func (h *SearchHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /search", h.search)
+}
+
+// searchResponse groups results by resource type, reusing the response types
+// of the corresponding listings.
+type searchResponse struct {
+	Composers  []composerWithDetailsResponse  `json:"composers"`
+	Pieces     []pieceWithDetailsResponse     `json:"pieces"`
+	Programmes []programmeWithDetailsResponse `json:"programmes"`
+	Venues     []venueWithDetailsResponse     `json:"venues"`
+	Events     []eventResponse                `json:"events"`
+}
+
+func newSearchResponse(r *model.SearchResults) searchResponse {
+	return searchResponse{
+		Composers:  convertAll(r.Composers, newComposerWithDetailsResponse),
+		Pieces:     convertAll(r.Pieces, newPieceWithDetailsResponse),
+		Programmes: convertAll(r.Programmes, newProgrammeWithDetailsResponse),
+		Venues:     convertAll(r.Venues, newVenueWithDetailsResponse),
+		Events:     convertAll(r.Events, newEventResponse),
+	}
+}
+
+func (h *SearchHandler) search(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	q := model.SearchQuery{
+		Text: query.Get("q"),
+	}
+
+	if val := query.Get("limit"); val != "" {
+		n, err := strconv.Atoi(val)
+		if err != nil {
+			rejectParam(w, r, "limit", val)
+			return
+		}
+		q.Limit = n
+	}
+
+	results, err := h.searchService.Search(r.Context(), q)
+	if err != nil {
+		switch {
+		case errors.Is(err, content.ErrInvalidResource):
+			respondJSON(r.Context(), w,
+				http.StatusBadRequest,
+				pair("error", err.Error()),
+			)
+			return
+		case errors.Is(err, content.ErrPermissionDenied):
+			respondJSON(r.Context(), w,
+				http.StatusForbidden,
+				pair("error", "permission denied"),
+			)
+			return
+		default:
+			respondJSON(r.Context(), w,
+				http.StatusInternalServerError,
+				pair("error", "internal server error"),
+			)
+			return
+		}
+	}
+
+	resp := newSearchResponse(results)
+	respondJSON(r.Context(), w,
+		http.StatusOK,
+		resp,
+	)
+}
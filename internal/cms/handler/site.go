@@ -0,0 +1,401 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/adamkadda/arman/internal/cms"
+	"github.com/adamkadda/arman/internal/cms/jsonld"
+	"github.com/adamkadda/arman/internal/cms/model"
+	"github.com/adamkadda/arman/internal/cms/service"
+	"github.com/adamkadda/arman/internal/content"
+	"github.com/adamkadda/arman/pkg/logging"
+)
+
+// sitePages are the pages a theme must provide, besides layout.html, which
+// every page is rendered into.
+var sitePages = []string{"home", "biography", "concerts", "concert", "error"}
+
+// upcomingOnHome is how many upcoming concerts the home page lists.
+const upcomingOnHome = 3
+
+// SiteHandler renders the public artist website as HTML. Pages are rendered
+// with the templates of a theme, a directory holding layout.html, a template
+// for each of sitePages, and optionally static files under static/.
+//
+// Like PublicHandler, it only ever shows published content.
+type SiteHandler struct {
+	eventService     *service.EventService
+	biographyService *service.BiographyService
+	contactService   *service.ContactService
+	pages            map[string]*template.Template
+	static           fs.FS
+	artist           string
+	language         content.Language
+	site             *url.URL
+	maxAge           time.Duration
+}
+
+// NewSiteHandler loads the theme configured in cfg and returns a SiteHandler
+// rendering pages with it.
+func NewSiteHandler(
+	eventService *service.EventService,
+	biographyService *service.BiographyService,
+	contactService *service.ContactService,
+	cfg *cms.Config,
+) (*SiteHandler, error) {
+	dir := cfg.SiteTheme
+	theme := os.DirFS(dir)
+
+	funcs := template.FuncMap{
+		"date":       formatDate,
+		"clock":      formatClock,
+		"paragraphs": paragraphs,
+	}
+
+	pages := make(map[string]*template.Template, len(sitePages))
+	for _, name := range sitePages {
+		tmpl, err := template.New(name).Funcs(funcs).ParseFS(theme, "layout.html", name+".html")
+		if err != nil {
+			return nil, fmt.Errorf("load theme %s: %w", dir, err)
+		}
+
+		pages[name] = tmpl
+	}
+
+	static, err := StaticFiles(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SiteHandler{
+		eventService:     eventService,
+		biographyService: biographyService,
+		contactService:   contactService,
+		pages:            pages,
+		static:           static,
+		artist:           cfg.ArtistName,
+		language:         cfg.Languages[0],
+		site:             &cfg.SiteURL,
+		maxAge:           cfg.SiteMaxAge,
+	}, nil
+}
+
+// StaticFiles returns the static files of the theme configured in cfg, which
+// SiteHandler serves under /static/.
+func StaticFiles(cfg *cms.Config) (fs.FS, error) {
+	static, err := fs.Sub(os.DirFS(cfg.SiteTheme), "static")
+	if err != nil {
+		return nil, fmt.Errorf("load theme %s: %w", cfg.SiteTheme, err)
+	}
+
+	return static, nil
+}
+
+// Register registers the pages of the website on the provided ServeMux.
+func (h *SiteHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /", h.notFound)
+	mux.HandleFunc("GET /{$}", h.home)
+	mux.HandleFunc("GET /biography", h.biography)
+	mux.HandleFunc("GET /concerts", h.upcoming)
+	mux.HandleFunc("GET /concerts/page/{cursor}", h.upcoming)
+	mux.HandleFunc("GET /concerts/past", h.past)
+	mux.HandleFunc("GET /concerts/past/page/{cursor}", h.past)
+	mux.HandleFunc("GET /concerts/{id}", h.concert)
+	mux.Handle("GET /static/", h.cached(
+		http.StripPrefix("/static/", http.FileServerFS(h.static)),
+	))
+}
+
+// sitePage is what every template is executed with. Data holds what is
+// specific to the page.
+type sitePage struct {
+	Title     string
+	Artist    string
+	Language  string
+	Canonical string
+	Data      any
+}
+
+type homePage struct {
+	Biography *content.Biography
+	Upcoming  []model.EventWithProgramme
+	Contact   *content.Contact
+}
+
+// concertsPage is a page of a listing of concerts. Next is the address of the
+// next page, if there is one.
+type concertsPage struct {
+	Events []model.EventWithProgramme
+	Next   string
+}
+
+type concertPage struct {
+	Event  *model.EventWithProgramme
+	JSONLD *jsonld.MusicEvent
+}
+
+func (h *SiteHandler) home(w http.ResponseWriter, r *http.Request) {
+	data := homePage{}
+
+	biography, err := h.biographyService.GetPublic(r.Context(), content.BiographyShort, preferredLanguages(r)...)
+	if err != nil && !errors.Is(err, content.ErrResourceNotFound) {
+		h.renderError(w, r, err)
+		return
+	}
+
+	lang := h.language
+	if biography != nil {
+		data.Biography = biography
+		lang = biography.Language
+		setLanguageHeaders(w, biography)
+	}
+
+	timeframe := content.TimeframeUpcoming
+
+	page, err := h.eventService.ListPublished(r.Context(), model.EventQuery{
+		Timeframe: &timeframe,
+		Limit:     upcomingOnHome,
+	})
+	if err != nil {
+		h.renderError(w, r, err)
+		return
+	}
+
+	data.Upcoming = page.Items
+
+	contact, err := h.contactService.GetPublic(r.Context())
+	if err != nil && !errors.Is(err, content.ErrResourceNotFound) {
+		h.renderError(w, r, err)
+		return
+	}
+
+	data.Contact = contact
+
+	h.render(w, r, http.StatusOK, "home", sitePage{
+		Artist:    h.artist,
+		Language:  string(lang),
+		Canonical: h.canonical(r),
+		Data:      data,
+	})
+}
+
+func (h *SiteHandler) biography(w http.ResponseWriter, r *http.Request) {
+	biography, err := h.biographyService.GetPublic(r.Context(), content.BiographyFull, preferredLanguages(r)...)
+	if err != nil {
+		h.renderError(w, r, err)
+		return
+	}
+
+	setLanguageHeaders(w, biography)
+
+	h.render(w, r, http.StatusOK, "biography", sitePage{
+		Title:     "Biography",
+		Artist:    h.artist,
+		Language:  string(biography.Language),
+		Canonical: h.canonical(r),
+		Data:      biography,
+	})
+}
+
+func (h *SiteHandler) upcoming(w http.ResponseWriter, r *http.Request) {
+	h.concerts(w, r, content.TimeframeUpcoming, "/concerts", "Concerts")
+}
+
+func (h *SiteHandler) past(w http.ResponseWriter, r *http.Request) {
+	h.concerts(w, r, content.TimeframePast, "/concerts/past", "Past concerts")
+}
+
+// concerts lists the published events of a timeframe, a page at a time. Pages
+// after the first are addressed by their cursor, under base/page/, rather than
+// by a query parameter, so that every page can also be exported as a static
+// file. The next page is advertised in a Link header as well.
+func (h *SiteHandler) concerts(
+	w http.ResponseWriter,
+	r *http.Request,
+	timeframe content.Timeframe,
+	base string,
+	title string,
+) {
+	q := model.EventQuery{
+		Timeframe: &timeframe,
+	}
+
+	if val := r.PathValue("cursor"); val != "" {
+		cursor, err := model.DecodeEventCursor(val)
+		if err != nil {
+			h.renderError(w, r, fmt.Errorf("%w: %s", content.ErrInvalidResource, err))
+			return
+		}
+
+		q.Cursor = cursor
+	}
+
+	page, err := h.eventService.ListPublished(r.Context(), q)
+	if err != nil {
+		h.renderError(w, r, err)
+		return
+	}
+
+	var next string
+	if page.NextCursor != nil {
+		next = base + "/page/" + *page.NextCursor
+		w.Header().Set("Link", "<"+next+`>; rel="next"`)
+	}
+
+	h.render(w, r, http.StatusOK, "concerts", sitePage{
+		Title:     title,
+		Artist:    h.artist,
+		Language:  string(h.language),
+		Canonical: h.canonical(r),
+		Data: concertsPage{
+			Events: page.Items,
+			Next:   next,
+		},
+	})
+}
+
+func (h *SiteHandler) concert(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id < 1 {
+		h.renderError(w, r, content.ErrResourceNotFound)
+		return
+	}
+
+	event, err := h.eventService.GetPublished(r.Context(), id)
+	if err != nil {
+		h.renderError(w, r, err)
+		return
+	}
+
+	ld := jsonld.NewMusicEvent(event, h.artist)
+	ld.URL = h.canonical(r)
+
+	h.render(w, r, http.StatusOK, "concert", sitePage{
+		Title:     event.Event.Title,
+		Artist:    h.artist,
+		Language:  string(h.language),
+		Canonical: h.canonical(r),
+		Data: concertPage{
+			Event:  event,
+			JSONLD: ld,
+		},
+	})
+}
+
+func (h *SiteHandler) notFound(w http.ResponseWriter, r *http.Request) {
+	h.renderError(w, r, content.ErrResourceNotFound)
+}
+
+// render executes the named page and writes it. Pages are rendered before
+// anything is written, so that a failing template never leaves a partial page.
+func (h *SiteHandler) render(
+	w http.ResponseWriter,
+	r *http.Request,
+	status int,
+	name string,
+	page sitePage,
+) {
+	var buf bytes.Buffer
+	if err := h.pages[name].ExecuteTemplate(&buf, "layout", page); err != nil {
+		logging.FromContext(r.Context()).Error(
+			"render page failed",
+			slog.String("page", name),
+			slog.Any("error", err),
+		)
+
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if status == http.StatusOK {
+		h.setCacheControl(w)
+	} else {
+		w.Header().Set("Cache-Control", "no-store")
+	}
+
+	w.WriteHeader(status)
+	w.Write(buf.Bytes())
+}
+
+// renderError renders the error page matching err.
+func (h *SiteHandler) renderError(w http.ResponseWriter, r *http.Request, err error) {
+	status, title, message := http.StatusInternalServerError,
+		"Something went wrong",
+		"The page could not be shown. Please try again later."
+
+	switch {
+	case errors.Is(err, content.ErrResourceNotFound):
+		status, title, message = http.StatusNotFound,
+			"Page not found",
+			"The page you are looking for does not exist."
+
+	case errors.Is(err, content.ErrInvalidResource):
+		status, title, message = http.StatusBadRequest,
+			"Bad request",
+			"The page you are looking for does not exist."
+	}
+
+	h.render(w, r, status, "error", sitePage{
+		Title:    title,
+		Artist:   h.artist,
+		Language: string(h.language),
+		Data:     message,
+	})
+}
+
+// cached sets the Cache-Control header of the responses of next.
+func (h *SiteHandler) cached(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.setCacheControl(w)
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (h *SiteHandler) setCacheControl(w http.ResponseWriter) {
+	w.Header().Set("Cache-Control",
+		fmt.Sprintf("public, max-age=%d", int(h.maxAge.Seconds())),
+	)
+}
+
+// canonical returns the address of the requested page on the public site.
+func (h *SiteHandler) canonical(r *http.Request) string {
+	return h.site.ResolveReference(&url.URL{Path: r.URL.Path}).String()
+}
+
+// formatDate formats the date of an event for display.
+func formatDate(t *time.Time) string {
+	if t == nil {
+		return "Date to be announced"
+	}
+
+	return t.Format("Monday 2 January 2006")
+}
+
+// formatClock formats the time of day of an event for display.
+func formatClock(t *time.Time) string {
+	return t.Format("15:04")
+}
+
+// paragraphs splits text into its paragraphs, separated by blank lines.
+func paragraphs(text string) []string {
+	var paragraphs []string
+	for p := range strings.SplitSeq(strings.ReplaceAll(text, "\r\n", "\n"), "\n\n") {
+		if p = strings.TrimSpace(p); p != "" {
+			paragraphs = append(paragraphs, p)
+		}
+	}
+
+	return paragraphs
+}
@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/adamkadda/arman/internal/cms/service"
+	"github.com/adamkadda/arman/internal/content"
+)
+
+// UserHandler exposes HTTP endpoints for managing admin users.
+// It is a thin HTTP-to-service adapter and contains no business logic.
+type UserHandler struct {
+	userService *service.UserService
+}
+
+func NewUserHandler(
+	userService *service.UserService,
+) *UserHandler {
+	return &UserHandler{
+		userService: userService,
+	}
+}
+
+// Register registers all user-related HTTP routes on the provided ServeMux.
+// Routes are registered at the root and assume JSON request and response bodies.
+func (h *UserHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /users", h.list)
+	mux.HandleFunc("POST /users", h.create)
+	mux.HandleFunc("PUT /users/{id}/role", h.updateRole)
+}
+
+type userRequest struct {
+	Username string       `json:"username"`
+	Password string       `json:"password"`
+	Role     content.Role `json:"role"`
+}
+
+type roleRequest struct {
+	Role content.Role `json:"role"`
+}
+
+type userResponse struct {
+	ID       int          `json:"user_id"`
+	Username string       `json:"username"`
+	Role     content.Role `json:"role"`
+}
+
+func newUserResponse(u *content.User) userResponse {
+	return userResponse{
+		ID:       u.ID,
+		Username: u.Username,
+		Role:     u.Role,
+	}
+}
+
+func (h *UserHandler) list(w http.ResponseWriter, r *http.Request) {
+	users, err := h.userService.List(r.Context())
+	if err != nil {
+		if errors.Is(err, content.ErrPermissionDenied) {
+			respondJSON(r.Context(), w,
+				http.StatusForbidden,
+				pair("error", "permission denied"),
+			)
+			return
+		}
+
+		respondJSON(r.Context(), w,
+			http.StatusInternalServerError,
+			pair("error", "internal server error"),
+		)
+		return
+	}
+
+	resp := make([]userResponse, len(users))
+	for i := range users {
+		resp[i] = newUserResponse(&users[i])
+	}
+
+	respondJSON(r.Context(), w,
+		http.StatusOK,
+		resp,
+	)
+}
+
+func (h *UserHandler) create(w http.ResponseWriter, r *http.Request) {
+	req, ok := parseBody[userRequest](w, r)
+	if !ok {
+		return
+	}
+
+	user, err := h.userService.Create(r.Context(), req.Username, req.Password, req.Role)
+	if err != nil {
+		switch {
+		case errors.Is(err, content.ErrInvalidResource):
+			respondJSON(r.Context(), w,
+				http.StatusBadRequest,
+				pair("error", err.Error()),
+			)
+			return
+		case errors.Is(err, content.ErrPermissionDenied):
+			respondJSON(r.Context(), w,
+				http.StatusForbidden,
+				pair("error", "permission denied"),
+			)
+			return
+		default:
+			respondJSON(r.Context(), w,
+				http.StatusInternalServerError,
+				pair("error", "internal server error"),
+			)
+			return
+		}
+	}
+
+	resp := newUserResponse(user)
+	respondJSON(r.Context(), w,
+		http.StatusCreated,
+		resp,
+	)
+}
+
+func (h *UserHandler) updateRole(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+
+	req, ok := parseBody[roleRequest](w, r)
+	if !ok {
+		return
+	}
+
+	user, err := h.userService.UpdateRole(r.Context(), id, req.Role)
+	if err != nil {
+		switch {
+		case errors.Is(err, content.ErrInvalidResource):
+			respondJSON(r.Context(), w,
+				http.StatusBadRequest,
+				pair("error", err.Error()),
+			)
+			return
+		case errors.Is(err, content.ErrResourceNotFound):
+			respondJSON(r.Context(), w,
+				http.StatusNotFound,
+				pair("error", "user not found"),
+			)
+			return
+		case errors.Is(err, content.ErrPermissionDenied),
+			errors.Is(err, content.ErrOwnRoleImmutable):
+			respondJSON(r.Context(), w,
+				http.StatusForbidden,
+				pair("error", err.Error()),
+			)
+			return
+		default:
+			respondJSON(r.Context(), w,
+				http.StatusInternalServerError,
+				pair("error", "internal server error"),
+			)
+			return
+		}
+	}
+
+	resp := newUserResponse(user)
+	respondJSON(r.Context(), w,
+		http.StatusOK,
+		resp,
+	)
+}
@@ -132,6 +132,14 @@ func (h *VenueHandler) get(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if errors.Is(err, content.ErrPermissionDenied) {
+			respondJSON(r.Context(), w,
+				http.StatusForbidden,
+				pair("error", "permission denied"),
+			)
+			return
+		}
+
 		respondJSON(r.Context(), w,
 			http.StatusInternalServerError,
 			pair("error", "internal server error"),
@@ -147,20 +155,36 @@ func (h *VenueHandler) get(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *VenueHandler) list(w http.ResponseWriter, r *http.Request) {
-	venues, err := h.venueService.List(r.Context())
-	if err != nil {
-		respondJSON(r.Context(), w,
-			http.StatusInternalServerError,
-			pair("error", "internal server error"),
-		)
+	q, ok := parseListQuery(w, r)
+	if !ok {
 		return
 	}
 
-	resp := make([]venueWithDetailsResponse, len(venues))
-	for i := range venues {
-		resp[i] = newVenueWithDetailsResponse(&venues[i])
+	page, err := h.venueService.List(r.Context(), q)
+	if err != nil {
+		switch {
+		case errors.Is(err, content.ErrInvalidResource):
+			respondJSON(r.Context(), w,
+				http.StatusBadRequest,
+				pair("error", err.Error()),
+			)
+			return
+		case errors.Is(err, content.ErrPermissionDenied):
+			respondJSON(r.Context(), w,
+				http.StatusForbidden,
+				pair("error", "permission denied"),
+			)
+			return
+		default:
+			respondJSON(r.Context(), w,
+				http.StatusInternalServerError,
+				pair("error", "internal server error"),
+			)
+			return
+		}
 	}
 
+	resp := newPageResponse(page, newVenueWithDetailsResponse)
 	respondJSON(r.Context(), w,
 		http.StatusOK,
 		resp,
@@ -191,6 +215,14 @@ func (h *VenueHandler) create(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if errors.Is(err, content.ErrPermissionDenied) {
+			respondJSON(r.Context(), w,
+				http.StatusForbidden,
+				pair("error", "permission denied"),
+			)
+			return
+		}
+
 		respondJSON(r.Context(), w,
 			http.StatusInternalServerError,
 			pair("error", "internal server error"),
@@ -236,6 +268,14 @@ func (h *VenueHandler) update(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if errors.Is(err, content.ErrPermissionDenied) {
+			respondJSON(r.Context(), w,
+				http.StatusForbidden,
+				pair("error", "permission denied"),
+			)
+			return
+		}
+
 		respondJSON(r.Context(), w,
 			http.StatusInternalServerError,
 			pair("error", "internal server error"),
@@ -270,6 +310,12 @@ func (h *VenueHandler) delete(w http.ResponseWriter, r *http.Request) {
 				pair("error", "venue in use"),
 			)
 			return
+		case errors.Is(err, content.ErrPermissionDenied):
+			respondJSON(r.Context(), w,
+				http.StatusForbidden,
+				pair("error", "permission denied"),
+			)
+			return
 		default:
 			respondJSON(r.Context(), w,
 				http.StatusInternalServerError,
@@ -0,0 +1,139 @@
+// The jsonld package describes events as schema.org structured data, encoded
+// as JSON-LD, so that search engines can pick up concerts from the pages that
+// embed it.
+package jsonld
+
+import (
+	"github.com/adamkadda/arman/internal/cms/model"
+	"github.com/adamkadda/arman/internal/content"
+)
+
+// ContentType is the media type of a JSON-LD document.
+const ContentType = "application/ld+json"
+
+const schemaContext = "https://schema.org"
+
+// dateLayout writes dates without a time zone offset: event dates are the wall
+// clock time at the venue.
+const dateLayout = "2006-01-02T15:04:05"
+
+// Event statuses, see https://schema.org/EventStatusType.
+const (
+	EventScheduled = "https://schema.org/EventScheduled"
+	EventCancelled = "https://schema.org/EventCancelled"
+	EventPostponed = "https://schema.org/EventPostponed"
+)
+
+type Person struct {
+	Type string `json:"@type"`
+	Name string `json:"name"`
+}
+
+type PostalAddress struct {
+	Type          string `json:"@type"`
+	StreetAddress string `json:"streetAddress"`
+}
+
+type Place struct {
+	Type    string        `json:"@type"`
+	Name    string        `json:"name"`
+	Address PostalAddress `json:"address"`
+}
+
+type MusicComposition struct {
+	Type     string `json:"@type"`
+	Name     string `json:"name"`
+	Composer Person `json:"composer"`
+}
+
+type Offer struct {
+	Type string `json:"@type"`
+	URL  string `json:"url"`
+}
+
+// MusicEvent is a schema.org MusicEvent. Fields a renderer knows better, such
+// as the address of the page describing the event, may be set after creation.
+type MusicEvent struct {
+	Context             string             `json:"@context"`
+	Type                string             `json:"@type"`
+	Name                string             `json:"name"`
+	URL                 string             `json:"url,omitempty"`
+	StartDate           string             `json:"startDate,omitempty"`
+	EventStatus         string             `json:"eventStatus"`
+	EventAttendanceMode string             `json:"eventAttendanceMode"`
+	Performer           *Person            `json:"performer,omitempty"`
+	Location            *Place             `json:"location,omitempty"`
+	WorkPerformed       []MusicComposition `json:"workPerformed,omitempty"`
+	Offers              *Offer             `json:"offers,omitempty"`
+}
+
+// NewMusicEvent describes an EventWithProgramme as a MusicEvent performed by
+// the named performer. The performer is left out when the name is empty.
+func NewMusicEvent(e *model.EventWithProgramme, performer string) *MusicEvent {
+	event := &MusicEvent{
+		Context:             schemaContext,
+		Type:                "MusicEvent",
+		Name:                e.Event.Title,
+		EventStatus:         EventStatus(e.Event.Status),
+		EventAttendanceMode: "https://schema.org/OfflineEventAttendanceMode",
+	}
+
+	if e.Event.Date != nil {
+		event.StartDate = e.Event.Date.Format(dateLayout)
+	}
+
+	if performer != "" {
+		event.Performer = &Person{
+			Type: "Person",
+			Name: performer,
+		}
+	}
+
+	if e.Venue != nil {
+		event.Location = &Place{
+			Type: "Place",
+			Name: e.Venue.Name,
+			Address: PostalAddress{
+				Type:          "PostalAddress",
+				StreetAddress: e.Venue.FullAddress,
+			},
+		}
+	}
+
+	if e.Programme != nil {
+		event.WorkPerformed = make([]MusicComposition, len(e.Programme.Pieces))
+		for i, pp := range e.Programme.Pieces {
+			event.WorkPerformed[i] = MusicComposition{
+				Type: "MusicComposition",
+				Name: pp.Piece.Title,
+				Composer: Person{
+					Type: "Person",
+					Name: pp.Composer.FullName,
+				},
+			}
+		}
+	}
+
+	if e.Event.TicketLink != nil {
+		event.Offers = &Offer{
+			Type: "Offer",
+			URL:  *e.Event.TicketLink,
+		}
+	}
+
+	return event
+}
+
+// EventStatus maps the status of an event to a schema.org EventStatusType.
+// Statuses schema.org has no notion of, such as drafts, map to
+// EventScheduled.
+func EventStatus(s content.Status) string {
+	switch s {
+	case content.StatusCancelled:
+		return EventCancelled
+	case content.StatusPostponed:
+		return EventPostponed
+	default:
+		return EventScheduled
+	}
+}
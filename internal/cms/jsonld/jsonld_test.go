@@ -0,0 +1,111 @@
+package jsonld
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/adamkadda/arman/internal/cms/model"
+	"github.com/adamkadda/arman/internal/content"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventStatus(t *testing.T) {
+	tests := []struct {
+		status   content.Status
+		expected string
+	}{
+		{status: content.StatusDraft, expected: EventScheduled},
+		{status: content.StatusPublished, expected: EventScheduled},
+		{status: content.StatusArchived, expected: EventScheduled},
+		{status: content.StatusCancelled, expected: EventCancelled},
+		{status: content.StatusPostponed, expected: EventPostponed},
+		{status: "foo", expected: EventScheduled},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.expected, EventStatus(tt.status))
+		})
+	}
+}
+
+func TestNewMusicEvent(t *testing.T) {
+	date := time.Date(2026, 4, 1, 19, 30, 0, 0, time.FixedZone("CEST", 7200))
+	link := "https://example.com/tickets"
+
+	e := &model.EventWithProgramme{
+		Event: &content.Event{
+			ID:         1,
+			Title:      "Foo",
+			Date:       &date,
+			TicketLink: &link,
+			Status:     content.StatusCancelled,
+		},
+		Venue: &content.Venue{
+			Name:        "Foo Hall",
+			FullAddress: "11 Foo St. Foo City",
+		},
+		Programme: &model.ProgrammeWithPieces{
+			Programme: &content.Programme{Title: "Bar"},
+			Pieces: []content.ProgrammePiece{
+				{
+					Piece:    content.Piece{Title: "Ballade No. 1"},
+					Composer: content.Composer{FullName: "Frédéric Chopin"},
+					Sequence: 1,
+				},
+			},
+		},
+	}
+
+	body, err := json.Marshal(NewMusicEvent(e, "Baz"))
+	require.NoError(t, err)
+
+	require.JSONEq(t, `{
+		"@context": "https://schema.org",
+		"@type": "MusicEvent",
+		"name": "Foo",
+		"startDate": "2026-04-01T19:30:00",
+		"eventStatus": "https://schema.org/EventCancelled",
+		"eventAttendanceMode": "https://schema.org/OfflineEventAttendanceMode",
+		"performer": {"@type": "Person", "name": "Baz"},
+		"location": {
+			"@type": "Place",
+			"name": "Foo Hall",
+			"address": {"@type": "PostalAddress", "streetAddress": "11 Foo St. Foo City"}
+		},
+		"workPerformed": [
+			{
+				"@type": "MusicComposition",
+				"name": "Ballade No. 1",
+				"composer": {"@type": "Person", "name": "Frédéric Chopin"}
+			}
+		],
+		"offers": {"@type": "Offer", "url": "https://example.com/tickets"}
+	}`, string(body))
+}
+
+// Events without a date, venue, programme or tickets, and renderers without a
+// performer, leave the matching properties out.
+func TestNewMusicEvent_Minimal(t *testing.T) {
+	e := &model.EventWithProgramme{
+		Event: &content.Event{
+			ID:     1,
+			Title:  "Foo",
+			Status: content.StatusPublished,
+		},
+	}
+
+	body, err := json.Marshal(NewMusicEvent(e, ""))
+	require.NoError(t, err)
+
+	require.JSONEq(t, `{
+		"@context": "https://schema.org",
+		"@type": "MusicEvent",
+		"name": "Foo",
+		"eventStatus": "https://schema.org/EventScheduled",
+		"eventAttendanceMode": "https://schema.org/OfflineEventAttendanceMode"
+	}`, string(body))
+}
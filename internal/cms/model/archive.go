@@ -0,0 +1,326 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/adamkadda/arman/internal/content"
+)
+
+// ArchiveVersion is the version of the archive format written by the CMS.
+// Restoring reads archives of this version only; bump it whenever the format
+// changes in a way older releases cannot read.
+const ArchiveVersion = 1
+
+var (
+	ErrInvalidArchive            = errors.New("invalid archive")
+	ErrUnsupportedArchiveVersion = errors.New("unsupported archive version")
+	ErrArchiveTargetNotEmpty     = errors.New("database is not empty")
+)
+
+// Archive is a portable copy of every piece of content in the CMS, used to
+// recover from disasters and to refresh staging.
+//
+// Identifiers are those of the database the archive was taken from. They only
+// tie records of the archive together: restoring gives every record a new
+// identifier. Users are not archived, their accounts and sessions belong to a
+// database; who changed what is kept by username instead, and only linked back
+// to users existing in the database restored into.
+//
+// Media files are not archived, only their metadata. Files are found through
+// their storage key, so the media directory must be copied alongside.
+type Archive struct {
+	Version        int                     `json:"version"`
+	CreatedAt      time.Time               `json:"created_at"`
+	Composers      []ArchiveComposer       `json:"composers"`
+	Pieces         []ArchivePiece          `json:"pieces"`
+	Venues         []ArchiveVenue          `json:"venues"`
+	Programmes     []ArchiveProgramme      `json:"programmes"`
+	Media          []ArchiveMedia          `json:"media"`
+	Events         []ArchiveEvent          `json:"events"`
+	Biographies    []ArchiveBiography      `json:"biographies"`
+	BiographyMedia []ArchiveBiographyMedia `json:"biography_media"`
+	Contact        *ArchiveContact         `json:"contact"`
+}
+
+type ArchiveComposer struct {
+	ID        int                 `json:"id"`
+	FullName  string              `json:"full_name"`
+	ShortName string              `json:"short_name"`
+	Media     []ArchiveAttachment `json:"media"`
+}
+
+type ArchivePiece struct {
+	ID         int    `json:"id"`
+	Title      string `json:"title"`
+	ComposerID int    `json:"composer_id"`
+}
+
+type ArchiveVenue struct {
+	ID           int                 `json:"id"`
+	Name         string              `json:"name"`
+	FullAddress  string              `json:"full_address"`
+	ShortAddress string              `json:"short_address"`
+	Media        []ArchiveAttachment `json:"media"`
+}
+
+type ArchiveProgramme struct {
+	ID     int                     `json:"id"`
+	Title  string                  `json:"title"`
+	Pieces []ArchiveProgrammePiece `json:"pieces"`
+}
+
+type ArchiveProgrammePiece struct {
+	PieceID  int `json:"piece_id"`
+	Sequence int `json:"sequence"`
+}
+
+type ArchiveMedia struct {
+	ID         int       `json:"id"`
+	Filename   string    `json:"filename"`
+	MIMEType   string    `json:"mime_type"`
+	Size       int64     `json:"size"`
+	Checksum   string    `json:"checksum"`
+	StorageKey string    `json:"storage_key"`
+	AltText    string    `json:"alt_text"`
+	Credits    string    `json:"credits"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ArchiveAttachment attaches media to the record holding it, at a position.
+type ArchiveAttachment struct {
+	MediaID  int `json:"media_id"`
+	Position int `json:"position"`
+}
+
+type ArchiveEvent struct {
+	ID                 int                   `json:"id"`
+	Title              string                `json:"title"`
+	Date               *time.Time            `json:"date"`
+	TicketLink         *string               `json:"ticket_link"`
+	VenueID            *int                  `json:"venue_id"`
+	ProgrammeID        *int                  `json:"programme_id"`
+	Status             content.Status        `json:"status"`
+	Notes              *string               `json:"notes"`
+	PublishAt          *time.Time            `json:"publish_at"`
+	StatusReason       *string               `json:"status_reason"`
+	ReplacementEventID *int                  `json:"replacement_event_id"`
+	Sequence           int                   `json:"sequence"`
+	CreatedAt          time.Time             `json:"created_at"`
+	UpdatedAt          time.Time             `json:"updated_at"`
+	History            []ArchiveStatusChange `json:"history"`
+	Media              []ArchiveAttachment   `json:"media"`
+}
+
+// ArchiveStatusChange is an entry of the status history of an event.
+type ArchiveStatusChange struct {
+	From      content.Status `json:"from"`
+	To        content.Status `json:"to"`
+	Reason    *string        `json:"reason"`
+	ChangedBy *string        `json:"changed_by"`
+	ChangedAt time.Time      `json:"changed_at"`
+}
+
+type ArchiveBiography struct {
+	Variant   content.BiographyVariant `json:"variant"`
+	Language  content.Language         `json:"language"`
+	Content   string                   `json:"content"`
+	UpdatedAt time.Time                `json:"updated_at"`
+	UpdatedBy *string                  `json:"updated_by"`
+	Revisions []ArchiveRevision        `json:"revisions"`
+}
+
+// ArchiveRevision is a previous text of a biography.
+type ArchiveRevision struct {
+	Content   string    `json:"content"`
+	Author    *string   `json:"author"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ArchiveBiographyMedia attaches media to a variant of the biography, shared
+// by all of its languages.
+type ArchiveBiographyMedia struct {
+	Variant  content.BiographyVariant `json:"variant"`
+	MediaID  int                      `json:"media_id"`
+	Position int                      `json:"position"`
+}
+
+type ArchiveContact struct {
+	Email            string          `json:"email"`
+	Phone            string          `json:"phone"`
+	ManagementAgency string          `json:"management_agency"`
+	ManagementEmail  string          `json:"management_email"`
+	ManagementPhone  string          `json:"management_phone"`
+	PressContact     string          `json:"press_contact"`
+	PressEmail       string          `json:"press_email"`
+	PressPhone       string          `json:"press_phone"`
+	SocialLinks      json.RawMessage `json:"social_links"`
+	UpdatedAt        time.Time       `json:"updated_at"`
+}
+
+// RestoreReport counts the records restored from an Archive. Users named in
+// the archive but missing from the database are counted in UnknownUsers; the
+// records they changed are restored without an author.
+type RestoreReport struct {
+	Composers    int
+	Pieces       int
+	Venues       int
+	Programmes   int
+	Media        int
+	Events       int
+	Biographies  int
+	Contact      bool
+	UnknownUsers int
+}
+
+// DecodeArchive reads an Archive and checks that it is consistent. It returns
+// ErrUnsupportedArchiveVersion for archives of another version and
+// ErrInvalidArchive for archives that cannot be read or are inconsistent.
+func DecodeArchive(r io.Reader) (*Archive, error) {
+	var archive Archive
+
+	if err := json.NewDecoder(r).Decode(&archive); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidArchive, err)
+	}
+
+	if archive.Version != ArchiveVersion {
+		return nil, fmt.Errorf(
+			"%w: %d, want %d",
+			ErrUnsupportedArchiveVersion,
+			archive.Version,
+			ArchiveVersion,
+		)
+	}
+
+	if err := archive.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &archive, nil
+}
+
+// Validate checks that every reference between records of the archive is
+// resolved within it, and that no identifier is used twice. The content of
+// the records is checked when they are restored.
+func (a *Archive) Validate() error {
+	ids := func(kind string, n int, id func(i int) int) (map[int]bool, error) {
+		seen := make(map[int]bool, n)
+		for i := range n {
+			if seen[id(i)] {
+				return nil, fmt.Errorf("%w: duplicate %s %d", ErrInvalidArchive, kind, id(i))
+			}
+			seen[id(i)] = true
+		}
+
+		return seen, nil
+	}
+
+	composers, err := ids("composer", len(a.Composers), func(i int) int { return a.Composers[i].ID })
+	if err != nil {
+		return err
+	}
+
+	pieces, err := ids("piece", len(a.Pieces), func(i int) int { return a.Pieces[i].ID })
+	if err != nil {
+		return err
+	}
+
+	venues, err := ids("venue", len(a.Venues), func(i int) int { return a.Venues[i].ID })
+	if err != nil {
+		return err
+	}
+
+	programmes, err := ids("programme", len(a.Programmes), func(i int) int { return a.Programmes[i].ID })
+	if err != nil {
+		return err
+	}
+
+	media, err := ids("media", len(a.Media), func(i int) int { return a.Media[i].ID })
+	if err != nil {
+		return err
+	}
+
+	events, err := ids("event", len(a.Events), func(i int) int { return a.Events[i].ID })
+	if err != nil {
+		return err
+	}
+
+	missing := func(kind string, id int, known map[int]bool) error {
+		if known[id] {
+			return nil
+		}
+
+		return fmt.Errorf("%w: unknown %s %d", ErrInvalidArchive, kind, id)
+	}
+
+	attachments := func(refs []ArchiveAttachment) error {
+		for _, ref := range refs {
+			if err := missing("media", ref.MediaID, media); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	for _, composer := range a.Composers {
+		if err := attachments(composer.Media); err != nil {
+			return err
+		}
+	}
+
+	for _, piece := range a.Pieces {
+		if err := missing("composer", piece.ComposerID, composers); err != nil {
+			return err
+		}
+	}
+
+	for _, venue := range a.Venues {
+		if err := attachments(venue.Media); err != nil {
+			return err
+		}
+	}
+
+	for _, programme := range a.Programmes {
+		for _, pp := range programme.Pieces {
+			if err := missing("piece", pp.PieceID, pieces); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, event := range a.Events {
+		if event.VenueID != nil {
+			if err := missing("venue", *event.VenueID, venues); err != nil {
+				return err
+			}
+		}
+
+		if event.ProgrammeID != nil {
+			if err := missing("programme", *event.ProgrammeID, programmes); err != nil {
+				return err
+			}
+		}
+
+		if event.ReplacementEventID != nil {
+			if err := missing("event", *event.ReplacementEventID, events); err != nil {
+				return err
+			}
+		}
+
+		if err := attachments(event.Media); err != nil {
+			return err
+		}
+	}
+
+	for _, attachment := range a.BiographyMedia {
+		if err := missing("media", attachment.MediaID, media); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
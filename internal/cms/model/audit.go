@@ -0,0 +1,88 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// AuditEntity is the type of resource an AuditEntry records a change of.
+type AuditEntity string
+
+const (
+	AuditComposer  AuditEntity = "composer"
+	AuditVenue     AuditEntity = "venue"
+	AuditPiece     AuditEntity = "piece"
+	AuditProgramme AuditEntity = "programme"
+	AuditEvent     AuditEntity = "event"
+	AuditBiography AuditEntity = "biography"
+	AuditContact   AuditEntity = "contact"
+	AuditMedia     AuditEntity = "media"
+	AuditJob       AuditEntity = "job"
+	AuditUser      AuditEntity = "user"
+	AuditArchive   AuditEntity = "archive"
+)
+
+var ErrInvalidAuditEntity = errors.New("invalid audit entity type")
+
+func (e AuditEntity) Validate() error {
+	switch e {
+	case AuditComposer, AuditVenue, AuditPiece, AuditProgramme, AuditEvent,
+		AuditBiography, AuditContact, AuditMedia, AuditJob, AuditUser,
+		AuditArchive:
+		return nil
+	default:
+		return ErrInvalidAuditEntity
+	}
+}
+
+// AuditEntry records a single change made through the services.
+//
+// Operation is the operation that made the change, as authorized and logged
+// by the services, such as "event.publish". An operation may change several
+// resources, each of them recorded in an entry of its own. EntityID is the id
+// of the changed resource, or its key for resources without one, such as
+// "full/en" for biographies.
+//
+// Before and After are JSON snapshots of the resource. Before is nil for
+// creations, After is nil for deletions. ActorID and Actor are nil for changes
+// made by internal callers, and RequestID for changes made outside of an HTTP
+// request.
+type AuditEntry struct {
+	ID         int64
+	ActorID    *int
+	Actor      *string
+	Operation  string
+	EntityType AuditEntity
+	EntityID   string
+	Before     json.RawMessage
+	After      json.RawMessage
+	RequestID  *string
+	CreatedAt  time.Time
+}
+
+// AuditFilter narrows a listing of the audit log. Zero fields match every
+// entry.
+type AuditFilter struct {
+	ActorID    *int
+	Operation  string
+	EntityType AuditEntity
+	EntityID   string
+	RequestID  string
+	From       *time.Time
+	To         *time.Time
+}
+
+func (f *AuditFilter) Validate() error {
+	if f.EntityType != "" {
+		if err := f.EntityType.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if f.From != nil && f.To != nil && f.To.Before(*f.From) {
+		return ErrInvalidRange
+	}
+
+	return nil
+}
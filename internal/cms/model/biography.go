@@ -0,0 +1,24 @@
+package model
+
+import (
+	"github.com/adamkadda/arman/internal/content"
+	"github.com/adamkadda/arman/pkg/diff"
+)
+
+// BiographyDiff is the line-based difference between two texts of a Biography.
+// From and To are revision ids; a nil To stands for the current text.
+type BiographyDiff struct {
+	Variant  content.BiographyVariant
+	Language content.Language
+	From     int
+	To       *int
+	Lines    []diff.Line
+}
+
+// BiographyTranslations lists the languages a Biography variant has been
+// written in, and the supported languages it is still missing.
+type BiographyTranslations struct {
+	Variant   content.BiographyVariant
+	Languages []content.Language
+	Missing   []content.Language
+}
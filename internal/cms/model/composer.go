@@ -18,3 +18,6 @@ type ComposerWithDetails struct {
 	Composer   content.Composer
 	PieceCount int
 }
+
+// ComposerSortFields are the fields a listing of Composers may be sorted by.
+var ComposerSortFields = []string{"id", "full_name", "short_name", "piece_count"}
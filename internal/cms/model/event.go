@@ -6,13 +6,29 @@ import (
 	"github.com/adamkadda/arman/internal/content"
 )
 
+// EventWithTimestamps is a wrapper around the Event type. Sequence counts the
+// updates and status changes the Event went through.
 type EventWithTimestamps struct {
 	Event     content.Event
+	Sequence  int
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
 
+// EventWithProgramme is a wrapper around the Event type. It embeds the Venue and
+// the Programme (with its pieces) the Event references. Either may be nil for
+// drafts that do not reference them yet.
 type EventWithProgramme struct {
 	Event     *content.Event
+	Venue     *content.Venue
 	Programme *ProgrammeWithPieces
 }
+
+// PublishedEvent is an EventWithProgramme as listed in public feeds, along with
+// the timestamps and sequence number subscribers use to tell whether it changed.
+type PublishedEvent struct {
+	EventWithProgramme
+	Sequence  int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
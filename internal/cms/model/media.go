@@ -0,0 +1,48 @@
+package model
+
+import (
+	"errors"
+
+	"github.com/adamkadda/arman/internal/content"
+)
+
+// MediaWithDetails is a wrapper around the Media type. It includes additional
+// information on how many published Events the Media is attached to.
+type MediaWithDetails struct {
+	Media      content.Media
+	EventCount int
+}
+
+// MediaSortFields are the fields a listing of Media may be sorted by.
+var MediaSortFields = []string{"id", "filename", "size", "created_at"}
+
+// MediaOwnerType is the type of resource Media can be attached to.
+type MediaOwnerType string
+
+const (
+	MediaOwnerEvent     MediaOwnerType = "event"
+	MediaOwnerVenue     MediaOwnerType = "venue"
+	MediaOwnerComposer  MediaOwnerType = "composer"
+	MediaOwnerBiography MediaOwnerType = "biography"
+)
+
+var ErrInvalidMediaOwner = errors.New("invalid media owner")
+
+// MediaOwner identifies a resource Media can be attached to. Biographies are
+// identified by their Variant, every other resource by its ID.
+type MediaOwner struct {
+	Type    MediaOwnerType
+	ID      int
+	Variant content.BiographyVariant
+}
+
+func (o MediaOwner) Validate() error {
+	switch o.Type {
+	case MediaOwnerEvent, MediaOwnerVenue, MediaOwnerComposer:
+		return nil
+	case MediaOwnerBiography:
+		return o.Variant.Validate()
+	default:
+		return ErrInvalidMediaOwner
+	}
+}
@@ -0,0 +1,236 @@
+package model
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/adamkadda/arman/internal/content"
+)
+
+const (
+	// DefaultLimit is the page size used when a caller does not ask for one.
+	DefaultLimit = 50
+	// MaxLimit is the largest page size a caller may ask for.
+	MaxLimit = 200
+)
+
+var (
+	ErrInvalidLimit     = errors.New("invalid limit")
+	ErrInvalidCursor    = errors.New("invalid cursor")
+	ErrInvalidRange     = errors.New("invalid date range")
+	ErrInvalidSort      = errors.New("invalid sort field")
+	ErrInvalidDirection = errors.New("invalid sort direction")
+)
+
+// Page is a single page of a listing. NextCursor is nil on the last page.
+//
+// Total is the number of items matching the filters across all pages, counted
+// along with the items of the page. It is nil for listings that do not count
+// their items, such as events.
+type Page[T any] struct {
+	Items      []T
+	NextCursor *string
+	Total      *int
+}
+
+// Direction is the order in which a sort field is applied.
+type Direction string
+
+const (
+	Ascending  Direction = "asc"
+	Descending Direction = "desc"
+)
+
+func (d Direction) Validate() error {
+	switch d {
+	case Ascending, Descending:
+		return nil
+	default:
+		return ErrInvalidDirection
+	}
+}
+
+// ListQuery describes which page of a resource listing to return, and how to
+// sort and filter it. It is shared by every listing except events, which are
+// paginated by date instead (see EventQuery).
+//
+// Pages are found by offset, see OffsetCursor, since listings may be sorted by
+// computed fields such as event counts.
+//
+// The zero value lists the first page of DefaultLimit items sorted by id.
+type ListQuery struct {
+	// Filter is matched case-insensitively against the text fields of each
+	// resource, such as names and titles. An empty filter matches everything.
+	Filter string
+
+	// Sort is the field to sort by. The fields available depend on the
+	// resource, an empty field sorts by id.
+	Sort      string
+	Direction Direction
+
+	Offset int
+	Limit  int
+}
+
+// Validate checks a ListQuery against the sort fields of the listed resource.
+func (q *ListQuery) Validate(sortFields ...string) error {
+	if q.Sort != "" && !slices.Contains(sortFields, q.Sort) {
+		return ErrInvalidSort
+	}
+
+	if q.Direction != "" {
+		if err := q.Direction.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if q.Offset < 0 {
+		return ErrInvalidCursor
+	}
+
+	if q.Limit < 0 || q.Limit > MaxLimit {
+		return ErrInvalidLimit
+	}
+
+	return nil
+}
+
+// PageSize returns the limit of the query, falling back to DefaultLimit.
+func (q *ListQuery) PageSize() int {
+	if q.Limit == 0 {
+		return DefaultLimit
+	}
+
+	return q.Limit
+}
+
+// NewPage builds the page of a ListQuery from its items and the total number
+// of items matching the query.
+func NewPage[T any](q ListQuery, items []T, total int) *Page[T] {
+	page := &Page[T]{
+		Items: items,
+		Total: &total,
+	}
+
+	if next := q.Offset + len(items); len(items) > 0 && next < total {
+		cursor := OffsetCursor{Offset: next}.Encode()
+		page.NextCursor = &cursor
+	}
+
+	return page
+}
+
+// OffsetCursor is the offset of the first item of a page of a ListQuery,
+// encoded so that clients treat it as opaque. Unlike an EventCursor, it does
+// not point at an item: items added or removed while a client pages through a
+// listing shift the following pages, so an item may be skipped or listed twice.
+type OffsetCursor struct {
+	Offset int `json:"o"`
+}
+
+// Encode returns the opaque string representation of the cursor handed out to
+// clients.
+func (c OffsetCursor) Encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeOffsetCursor parses a cursor previously returned by Encode.
+func DecodeOffsetCursor(s string) (*OffsetCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var c OffsetCursor
+	if err := json.Unmarshal(b, &c); err != nil || c.Offset < 0 {
+		return nil, ErrInvalidCursor
+	}
+
+	return &c, nil
+}
+
+// EventQuery describes which Events to list and which page of them to return.
+// Every filter is optional; a nil filter is not applied.
+//
+// Events are ordered by date, then by id. Upcoming events are listed soonest
+// first, every other listing is ordered most recent first. Events without a
+// date are always listed last.
+type EventQuery struct {
+	Status    *content.Status
+	Timeframe *content.Timeframe
+
+	// Public restricts the listing to the events shown to the public:
+	// published events, and those called off after being published.
+	Public bool
+
+	// From and To bound the event date to the half-open range [From, To).
+	From *time.Time
+	To   *time.Time
+
+	Cursor *EventCursor
+	Limit  int
+}
+
+// Validate checks the filters of an EventQuery against each other. An unset
+// limit is not an error, see DefaultLimit.
+func (q *EventQuery) Validate() error {
+	if q.Status != nil {
+		if err := q.Status.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if q.Timeframe != nil {
+		if err := q.Timeframe.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if q.From != nil && q.To != nil && !q.From.Before(*q.To) {
+		return ErrInvalidRange
+	}
+
+	if q.Limit < 0 || q.Limit > MaxLimit {
+		return ErrInvalidLimit
+	}
+
+	return nil
+}
+
+// Ascending reports whether the query lists events soonest first.
+func (q *EventQuery) Ascending() bool {
+	return q.Timeframe != nil && *q.Timeframe == content.TimeframeUpcoming
+}
+
+// EventCursor points at the last Event of a page. The next page starts right
+// after it.
+type EventCursor struct {
+	Date *time.Time `json:"d,omitempty"`
+	ID   int        `json:"i"`
+}
+
+// Encode returns the opaque string representation of the cursor handed out to
+// clients.
+func (c EventCursor) Encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeEventCursor parses a cursor previously returned by Encode.
+func DecodeEventCursor(s string) (*EventCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var c EventCursor
+	if err := json.Unmarshal(b, &c); err != nil || c.ID < 1 {
+		return nil, ErrInvalidCursor
+	}
+
+	return &c, nil
+}
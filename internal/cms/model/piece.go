@@ -1,6 +1,15 @@
 package model
 
-import "github.com/adamkadda/arman/internal/content"
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/adamkadda/arman/internal/content"
+)
 
 type PieceCommand struct {
 	Piece    PieceIntent
@@ -18,3 +27,198 @@ type PieceWithDetails struct {
 	Piece          content.Piece
 	ProgrammeCount int
 }
+
+// PieceSortFields are the fields a listing of Pieces may be sorted by.
+var PieceSortFields = []string{"id", "title", "programme_count"}
+
+// MaxImportRows is the largest number of rows a single import may hold. An
+// import runs in one transaction, which this keeps reasonably short.
+const MaxImportRows = 5000
+
+// ImportFormat is the encoding of the rows of an import.
+type ImportFormat string
+
+const (
+	ImportCSV  ImportFormat = "csv"
+	ImportJSON ImportFormat = "json"
+)
+
+var (
+	ErrInvalidImport       = errors.New("invalid import")
+	ErrInvalidImportFormat = errors.New("invalid import format")
+)
+
+// PieceImportRow is a row of a bulk import of Pieces. The Composer is matched
+// by full name, ignoring case, and created with ComposerShortName when no
+// Composer matches. Line locates the row in the imported file.
+type PieceImportRow struct {
+	Line              int
+	Composer          string
+	ComposerShortName string
+	Title             string
+}
+
+// ImportStatus tells what became of an imported row.
+type ImportStatus string
+
+const (
+	ImportCreated ImportStatus = "created"
+	ImportSkipped ImportStatus = "skipped"
+	ImportFailed  ImportStatus = "failed"
+)
+
+// PieceImportResult reports on a single row of an import. Reason holds the
+// reason code of a skipped or failed row. The ids of a dry run, or of an
+// import that was not committed, refer to rows that were rolled back.
+type PieceImportResult struct {
+	Line            int
+	Status          ImportStatus
+	Reason          string
+	PieceID         int
+	ComposerID      int
+	ComposerCreated bool
+}
+
+// PieceImportReport reports on an import. An import is only committed when
+// every row is either created or skipped, and it is not a dry run.
+type PieceImportReport struct {
+	DryRun           bool
+	Committed        bool
+	Created          int
+	Skipped          int
+	Failed           int
+	ComposersCreated int
+	Rows             []PieceImportResult
+}
+
+// Add records the result of a row.
+func (r *PieceImportReport) Add(result PieceImportResult) {
+	switch result.Status {
+	case ImportCreated:
+		r.Created++
+	case ImportSkipped:
+		r.Skipped++
+	case ImportFailed:
+		r.Failed++
+	}
+
+	if result.ComposerCreated {
+		r.ComposersCreated++
+	}
+
+	r.Rows = append(r.Rows, result)
+}
+
+// DecodePieceImport reads the rows of an import of Pieces.
+//
+// CSV files start with a header naming their columns: composer, title, and
+// optionally composer_short_name. Other columns are ignored. JSON files hold
+// an array of objects with the same keys.
+func DecodePieceImport(r io.Reader, format ImportFormat) ([]PieceImportRow, error) {
+	var (
+		rows []PieceImportRow
+		err  error
+	)
+
+	switch format {
+	case ImportCSV:
+		rows, err = decodePieceImportCSV(r)
+	case ImportJSON:
+		rows, err = decodePieceImportJSON(r)
+	default:
+		return nil, ErrInvalidImportFormat
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: no rows", ErrInvalidImport)
+	}
+
+	if len(rows) > MaxImportRows {
+		return nil, fmt.Errorf("%w: more than %d rows", ErrInvalidImport, MaxImportRows)
+	}
+
+	return rows, nil
+}
+
+func decodePieceImportCSV(r io.Reader) ([]PieceImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidImport, err)
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	for _, required := range []string{"composer", "title"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("%w: missing %s column", ErrInvalidImport, required)
+		}
+	}
+
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+
+		return strings.TrimSpace(record[i])
+	}
+
+	var rows []PieceImportRow
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidImport, err)
+		}
+
+		line, _ := reader.FieldPos(0)
+
+		rows = append(rows, PieceImportRow{
+			Line:              line,
+			Composer:          field(record, "composer"),
+			ComposerShortName: field(record, "composer_short_name"),
+			Title:             field(record, "title"),
+		})
+	}
+}
+
+func decodePieceImportJSON(r io.Reader) ([]PieceImportRow, error) {
+	var records []struct {
+		Composer          string `json:"composer"`
+		ComposerShortName string `json:"composer_short_name"`
+		Title             string `json:"title"`
+	}
+
+	if err := json.NewDecoder(r).Decode(&records); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidImport, err)
+	}
+
+	rows := make([]PieceImportRow, len(records))
+	for i, record := range records {
+		rows[i] = PieceImportRow{
+			Line:              i + 1,
+			Composer:          strings.TrimSpace(record.Composer),
+			ComposerShortName: strings.TrimSpace(record.ComposerShortName),
+			Title:             strings.TrimSpace(record.Title),
+		}
+	}
+
+	return rows, nil
+}
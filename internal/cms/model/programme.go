@@ -17,3 +17,6 @@ type ProgrammeWithPieces struct {
 	Programme *content.Programme
 	Pieces    []content.ProgrammePiece
 }
+
+// ProgrammeSortFields are the fields a listing of Programmes may be sorted by.
+var ProgrammeSortFields = []string{"id", "title", "event_count"}
@@ -0,0 +1,57 @@
+package model
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/adamkadda/arman/internal/content"
+)
+
+const (
+	// DefaultSearchLimit is the number of results returned per resource type
+	// when a caller does not ask for a limit.
+	DefaultSearchLimit = 10
+	// MaxSearchLimit is the largest number of results a caller may ask for per
+	// resource type.
+	MaxSearchLimit = 50
+)
+
+var ErrSearchTextEmpty = errors.New("search text is empty")
+
+// SearchQuery describes a full-text search across every searchable resource.
+// Limit applies to each resource type separately.
+type SearchQuery struct {
+	Text  string
+	Limit int
+}
+
+func (q *SearchQuery) Validate() error {
+	if strings.TrimSpace(q.Text) == "" {
+		return ErrSearchTextEmpty
+	}
+
+	if q.Limit < 0 || q.Limit > MaxSearchLimit {
+		return ErrInvalidLimit
+	}
+
+	return nil
+}
+
+// PageSize returns the limit of the query, falling back to DefaultSearchLimit.
+func (q *SearchQuery) PageSize() int {
+	if q.Limit == 0 {
+		return DefaultSearchLimit
+	}
+
+	return q.Limit
+}
+
+// SearchResults groups the results of a search by resource type. Each group
+// is ordered by relevance, most relevant first.
+type SearchResults struct {
+	Composers  []ComposerWithDetails
+	Pieces     []PieceWithDetails
+	Programmes []ProgrammeWithDetails
+	Venues     []VenueWithDetails
+	Events     []content.Event
+}
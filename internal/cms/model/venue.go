@@ -17,3 +17,6 @@ type VenueWithDetails struct {
 	Venue      content.Venue
 	EventCount int
 }
+
+// VenueSortFields are the fields a listing of Venues may be sorted by.
+var VenueSortFields = []string{"id", "name", "event_count"}
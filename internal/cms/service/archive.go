@@ -0,0 +1,642 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/adamkadda/arman/internal/cms/model"
+	"github.com/adamkadda/arman/internal/cms/store"
+	"github.com/adamkadda/arman/internal/content"
+	"github.com/adamkadda/arman/pkg/logging"
+)
+
+// ArchiveService exports the content of the CMS into a model.Archive and
+// restores archives into an empty database.
+type ArchiveService struct {
+	db              DB
+	newArchiveStore func(db store.Executor) ArchiveStore
+	newAuditStore   func(db store.Executor) AuditStore
+}
+
+func NewArchiveService(db DB) *ArchiveService {
+	return &ArchiveService{
+		db: db,
+		newArchiveStore: func(db store.Executor) ArchiveStore {
+			return store.NewPostgresArchiveStore(db)
+		},
+		newAuditStore: newAuditStore,
+	}
+}
+
+type ArchiveStore interface {
+	Snapshot(ctx context.Context) error
+	Empty(ctx context.Context) (bool, error)
+	ListUsers(ctx context.Context) (map[string]int, error)
+
+	ListComposers(ctx context.Context) ([]model.ArchiveComposer, error)
+	ListPieces(ctx context.Context) ([]model.ArchivePiece, error)
+	ListVenues(ctx context.Context) ([]model.ArchiveVenue, error)
+	ListProgrammes(ctx context.Context) ([]model.ArchiveProgramme, error)
+	ListMedia(ctx context.Context) ([]model.ArchiveMedia, error)
+	ListEvents(ctx context.Context) ([]model.ArchiveEvent, error)
+	ListBiographies(ctx context.Context) ([]model.ArchiveBiography, error)
+	ListBiographyMedia(ctx context.Context) ([]model.ArchiveBiographyMedia, error)
+	GetContact(ctx context.Context) (*model.ArchiveContact, error)
+
+	CreateComposer(ctx context.Context, c model.ArchiveComposer) (int, error)
+	CreatePiece(ctx context.Context, p model.ArchivePiece, composerID int) (int, error)
+	CreateVenue(ctx context.Context, v model.ArchiveVenue) (int, error)
+	CreateProgramme(ctx context.Context, p model.ArchiveProgramme) (int, error)
+	AddProgrammePiece(ctx context.Context, programmeID, pieceID, sequence int) error
+	CreateMedia(ctx context.Context, m model.ArchiveMedia) (int, error)
+	Attach(ctx context.Context, owner model.MediaOwner, mediaID int, position int) error
+	CreateEvent(ctx context.Context, e model.ArchiveEvent, venueID, programmeID, replacementID *int) (int, error)
+	SetReplacement(ctx context.Context, eventID, replacementID int) error
+	AddStatusChange(ctx context.Context, eventID int, c model.ArchiveStatusChange, changedBy *int) error
+	CreateBiography(ctx context.Context, b model.ArchiveBiography, updatedBy *int) error
+	AddRevision(ctx context.Context, b model.ArchiveBiography, r model.ArchiveRevision, authorID *int) error
+	CreateContact(ctx context.Context, c model.ArchiveContact) error
+}
+
+// Export writes an Archive of every piece of content to w. The archive is
+// written one section at a time, as it is read, so that large archives can be
+// streamed; every section is read from the same snapshot of the database.
+//
+// If reading fails halfway, the archive written so far is left unfinished.
+func (s *ArchiveService) Export(
+	ctx context.Context,
+	w io.Writer,
+) error {
+	logger := logging.FromContext(ctx).With(
+		slog.String("operation", "archive.export"),
+	)
+
+	logger.Info(
+		"export archive",
+	)
+
+	if err := authorize(ctx, logger, "archive.export"); err != nil {
+		return err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		logger.Error(
+			"begin transaction failed",
+			slog.String("step", "tx.begin"),
+			slog.Any("error", err),
+		)
+
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	archiveStore := s.newArchiveStore(tx)
+
+	if err := archiveStore.Snapshot(ctx); err != nil {
+		logger.Error(
+			"take snapshot failed",
+			slog.String("step", "archive.snapshot"),
+			slog.Any("error", err),
+		)
+
+		return err
+	}
+
+	sections := []struct {
+		name string
+		step string
+		read func() (any, error)
+	}{
+		{"composers", "composer.list", func() (any, error) { return archiveStore.ListComposers(ctx) }},
+		{"pieces", "piece.list", func() (any, error) { return archiveStore.ListPieces(ctx) }},
+		{"venues", "venue.list", func() (any, error) { return archiveStore.ListVenues(ctx) }},
+		{"programmes", "programme.list", func() (any, error) { return archiveStore.ListProgrammes(ctx) }},
+		{"media", "media.list", func() (any, error) { return archiveStore.ListMedia(ctx) }},
+		{"events", "event.list", func() (any, error) { return archiveStore.ListEvents(ctx) }},
+		{"biographies", "biography.list", func() (any, error) { return archiveStore.ListBiographies(ctx) }},
+		{"biography_media", "biography_media.list", func() (any, error) { return archiveStore.ListBiographyMedia(ctx) }},
+		{"contact", "contact.get", func() (any, error) {
+			contact, err := archiveStore.GetContact(ctx)
+			if errors.Is(err, content.ErrResourceNotFound) {
+				return nil, nil
+			}
+
+			return contact, err
+		}},
+	}
+
+	aw := newArchiveWriter(w, time.Now().UTC())
+
+	for _, section := range sections {
+		v, err := section.read()
+		if err != nil {
+			logger.Error(
+				"read archive section failed",
+				slog.String("step", section.step),
+				slog.Any("error", err),
+			)
+
+			return err
+		}
+
+		aw.field(section.name, v)
+	}
+
+	if err := aw.close(); err != nil {
+		logger.Error(
+			"write archive failed",
+			slog.String("step", "archive.write"),
+			slog.Any("error", err),
+		)
+
+		return err
+	}
+
+	return nil
+}
+
+// archiveWriter writes the JSON object of an archive field by field. The
+// first error is kept and returned by close.
+type archiveWriter struct {
+	w   io.Writer
+	err error
+}
+
+func newArchiveWriter(w io.Writer, createdAt time.Time) *archiveWriter {
+	aw := &archiveWriter{w: w}
+
+	_, aw.err = fmt.Fprintf(w, `{"version":%d`, model.ArchiveVersion)
+	aw.field("created_at", createdAt)
+
+	return aw
+}
+
+func (aw *archiveWriter) field(name string, v any) {
+	if aw.err != nil {
+		return
+	}
+
+	body, err := json.Marshal(v)
+	if err != nil {
+		aw.err = err
+		return
+	}
+
+	_, aw.err = fmt.Fprintf(aw.w, ",%q:%s", name, body)
+}
+
+func (aw *archiveWriter) close() error {
+	if aw.err != nil {
+		return aw.err
+	}
+
+	_, err := io.WriteString(aw.w, "}\n")
+
+	return err
+}
+
+// Restore restores an Archive into a database holding no content yet. Every
+// record is given a new identifier, and references between records are
+// mapped to them. Authors are linked to the users of the database by
+// username. The archive is restored in a single transaction: either all of it
+// is, or nothing.
+//
+// An inconsistent archive, or one holding invalid content, is reported as
+// content.ErrInvalidResource. A database already holding content is reported
+// as model.ErrArchiveTargetNotEmpty.
+func (s *ArchiveService) Restore(
+	ctx context.Context,
+	archive *model.Archive,
+) (*model.RestoreReport, error) {
+	logger := logging.FromContext(ctx).With(
+		slog.String("operation", "archive.restore"),
+		slog.Time("created_at", archive.CreatedAt),
+	)
+
+	logger.Info(
+		"restore archive",
+	)
+
+	if err := authorize(ctx, logger, "archive.restore"); err != nil {
+		return nil, err
+	}
+
+	if err := validateArchive(archive); err != nil {
+		logger.Warn(
+			"validate archive rejected",
+			slog.String("reason", reason(err)),
+		)
+
+		return nil, fmt.Errorf("%w: %s", content.ErrInvalidResource, err)
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		logger.Error(
+			"begin transaction failed",
+			slog.String("step", "tx.begin"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	archiveStore := s.newArchiveStore(tx)
+
+	empty, err := archiveStore.Empty(ctx)
+	if err != nil {
+		logger.Error(
+			"check database empty failed",
+			slog.String("step", "archive.empty"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
+	if !empty {
+		logger.Warn(
+			"restore archive rejected",
+			slog.String("reason", reason(model.ErrArchiveTargetNotEmpty)),
+		)
+
+		return nil, model.ErrArchiveTargetNotEmpty
+	}
+
+	users, err := archiveStore.ListUsers(ctx)
+	if err != nil {
+		logger.Error(
+			"list users failed",
+			slog.String("step", "user.list"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
+	r := &archiveRestorer{
+		store:      archiveStore,
+		users:      users,
+		unknown:    make(map[string]bool),
+		composers:  make(map[int]int),
+		pieces:     make(map[int]int),
+		venues:     make(map[int]int),
+		programmes: make(map[int]int),
+		media:      make(map[int]int),
+		events:     make(map[int]int),
+		report:     &model.RestoreReport{},
+	}
+
+	if step, err := r.run(ctx, archive); err != nil {
+		logger.Error(
+			"restore archive failed",
+			slog.String("step", step),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
+	r.report.UnknownUsers = len(r.unknown)
+
+	// The restored records are summed up in a single entry rather than one
+	// entry each, identified by the creation time of the archive.
+	err = recordChange(ctx, logger, s.newAuditStore(tx),
+		"archive.restore", model.AuditArchive, archive.CreatedAt.Format(time.RFC3339),
+		nil, r.report,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		logger.Error(
+			"commit transaction failed",
+			slog.String("step", "tx.commit"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
+	return r.report, nil
+}
+
+// validateArchive checks the references of an Archive, and the content of
+// each of its records.
+func validateArchive(a *model.Archive) error {
+	if err := a.Validate(); err != nil {
+		return err
+	}
+
+	for _, c := range a.Composers {
+		composer := content.Composer{FullName: c.FullName, ShortName: c.ShortName}
+		if err := composer.Validate(); err != nil {
+			return err
+		}
+	}
+
+	for _, p := range a.Pieces {
+		piece := content.Piece{Title: p.Title}
+		if err := piece.Validate(); err != nil {
+			return err
+		}
+	}
+
+	for _, v := range a.Venues {
+		venue := content.Venue{Name: v.Name, FullAddress: v.FullAddress, ShortAddress: v.ShortAddress}
+		if err := venue.Validate(); err != nil {
+			return err
+		}
+	}
+
+	for _, p := range a.Programmes {
+		programme := content.Programme{Title: p.Title}
+		if err := programme.Validate(); err != nil {
+			return err
+		}
+	}
+
+	for _, e := range a.Events {
+		if e.Title == "" {
+			return content.ErrEventTitleEmpty
+		}
+
+		if err := e.Status.Validate(); err != nil {
+			return err
+		}
+	}
+
+	for _, b := range a.Biographies {
+		if err := b.Variant.Validate(); err != nil {
+			return err
+		}
+
+		if err := b.Language.Validate(); err != nil {
+			return err
+		}
+	}
+
+	for _, m := range a.BiographyMedia {
+		if err := m.Variant.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// archiveRestorer restores the records of a single archive, keeping the new
+// identifier of every record by its identifier in the archive.
+type archiveRestorer struct {
+	store   ArchiveStore
+	users   map[string]int
+	unknown map[string]bool
+
+	composers  map[int]int
+	pieces     map[int]int
+	venues     map[int]int
+	programmes map[int]int
+	media      map[int]int
+	events     map[int]int
+
+	report *model.RestoreReport
+}
+
+// run restores every record, referenced records first. It returns the step
+// that failed along with the error.
+func (r *archiveRestorer) run(ctx context.Context, a *model.Archive) (string, error) {
+	for _, m := range a.Media {
+		id, err := r.store.CreateMedia(ctx, m)
+		if err != nil {
+			return "media.create", err
+		}
+
+		r.media[m.ID] = id
+		r.report.Media++
+	}
+
+	for _, c := range a.Composers {
+		id, err := r.store.CreateComposer(ctx, c)
+		if err != nil {
+			return "composer.create", err
+		}
+
+		r.composers[c.ID] = id
+		r.report.Composers++
+
+		owner := model.MediaOwner{Type: model.MediaOwnerComposer, ID: id}
+		if err := r.attach(ctx, owner, c.Media); err != nil {
+			return "composer_media.create", err
+		}
+	}
+
+	for _, p := range a.Pieces {
+		id, err := r.store.CreatePiece(ctx, p, r.composers[p.ComposerID])
+		if err != nil {
+			return "piece.create", err
+		}
+
+		r.pieces[p.ID] = id
+		r.report.Pieces++
+	}
+
+	for _, v := range a.Venues {
+		id, err := r.store.CreateVenue(ctx, v)
+		if err != nil {
+			return "venue.create", err
+		}
+
+		r.venues[v.ID] = id
+		r.report.Venues++
+
+		owner := model.MediaOwner{Type: model.MediaOwnerVenue, ID: id}
+		if err := r.attach(ctx, owner, v.Media); err != nil {
+			return "venue_media.create", err
+		}
+	}
+
+	for _, p := range a.Programmes {
+		id, err := r.store.CreateProgramme(ctx, p)
+		if err != nil {
+			return "programme.create", err
+		}
+
+		r.programmes[p.ID] = id
+		r.report.Programmes++
+
+		for _, pp := range p.Pieces {
+			if err := r.store.AddProgrammePiece(ctx, id, r.pieces[pp.PieceID], pp.Sequence); err != nil {
+				return "programme_piece.create", err
+			}
+		}
+	}
+
+	if step, err := r.restoreEvents(ctx, a.Events); err != nil {
+		return step, err
+	}
+
+	for _, b := range a.Biographies {
+		if err := r.store.CreateBiography(ctx, b, r.user(b.UpdatedBy)); err != nil {
+			return "biography.create", err
+		}
+
+		r.report.Biographies++
+
+		for _, revision := range b.Revisions {
+			if err := r.store.AddRevision(ctx, b, revision, r.user(revision.Author)); err != nil {
+				return "biography_revision.create", err
+			}
+		}
+	}
+
+	for _, m := range a.BiographyMedia {
+		owner := model.MediaOwner{Type: model.MediaOwnerBiography, Variant: m.Variant}
+		if err := r.store.Attach(ctx, owner, r.media[m.MediaID], m.Position); err != nil {
+			return "biography_media.create", err
+		}
+	}
+
+	if a.Contact != nil {
+		if err := r.store.CreateContact(ctx, *a.Contact); err != nil {
+			return "contact.create", err
+		}
+
+		r.report.Contact = true
+	}
+
+	return "", nil
+}
+
+// restoreEvents restores events so that replacements exist before the events
+// they replace, which keeps the time every event was last updated. Events
+// replacing each other in a cycle are linked once all of them exist.
+func (r *archiveRestorer) restoreEvents(ctx context.Context, events []model.ArchiveEvent) (string, error) {
+	var deferred []model.ArchiveEvent
+
+	pending := events
+
+	for len(pending) > 0 {
+		var next []model.ArchiveEvent
+
+		for _, e := range pending {
+			if e.ReplacementEventID != nil {
+				if _, ok := r.events[*e.ReplacementEventID]; !ok {
+					next = append(next, e)
+					continue
+				}
+			}
+
+			if step, err := r.restoreEvent(ctx, e, true); err != nil {
+				return step, err
+			}
+		}
+
+		if len(next) == len(pending) {
+			// Every pending event waits for another: break the cycle.
+			if step, err := r.restoreEvent(ctx, next[0], false); err != nil {
+				return step, err
+			}
+
+			deferred = append(deferred, next[0])
+			next = next[1:]
+		}
+
+		pending = next
+	}
+
+	for _, e := range deferred {
+		if err := r.store.SetReplacement(ctx, r.events[e.ID], r.events[*e.ReplacementEventID]); err != nil {
+			return "event.update", err
+		}
+	}
+
+	return "", nil
+}
+
+func (r *archiveRestorer) restoreEvent(
+	ctx context.Context,
+	e model.ArchiveEvent,
+	withReplacement bool,
+) (string, error) {
+	var replacementID *int
+	if withReplacement {
+		replacementID = mapID(r.events, e.ReplacementEventID)
+	}
+
+	id, err := r.store.CreateEvent(
+		ctx,
+		e,
+		mapID(r.venues, e.VenueID),
+		mapID(r.programmes, e.ProgrammeID),
+		replacementID,
+	)
+	if err != nil {
+		return "event.create", err
+	}
+
+	r.events[e.ID] = id
+	r.report.Events++
+
+	for _, change := range e.History {
+		if err := r.store.AddStatusChange(ctx, id, change, r.user(change.ChangedBy)); err != nil {
+			return "event_status_history.create", err
+		}
+	}
+
+	owner := model.MediaOwner{Type: model.MediaOwnerEvent, ID: id}
+	if err := r.attach(ctx, owner, e.Media); err != nil {
+		return "event_media.create", err
+	}
+
+	return "", nil
+}
+
+func (r *archiveRestorer) attach(
+	ctx context.Context,
+	owner model.MediaOwner,
+	attachments []model.ArchiveAttachment,
+) error {
+	for _, a := range attachments {
+		if err := r.store.Attach(ctx, owner, r.media[a.MediaID], a.Position); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// user returns the id of the user with the passed username, or nil if there
+// is no such user.
+func (r *archiveRestorer) user(username *string) *int {
+	if username == nil {
+		return nil
+	}
+
+	id, ok := r.users[*username]
+	if !ok {
+		r.unknown[*username] = true
+		return nil
+	}
+
+	return &id
+}
+
+// mapID returns the new identifier of a record referenced by its identifier
+// in the archive.
+func mapID(ids map[int]int, id *int) *int {
+	if id == nil {
+		return nil
+	}
+
+	mapped := ids[*id]
+
+	return &mapped
+}
@@ -0,0 +1,362 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/adamkadda/arman/internal/cms/model"
+	"github.com/adamkadda/arman/internal/cms/store"
+	"github.com/adamkadda/arman/internal/content"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchiveService_Export(t *testing.T) {
+	archive := testArchive()
+
+	archiveStore := &mockArchiveStore{
+		archive: archive,
+	}
+
+	svc := ArchiveService{
+		db: mockDB{},
+		newArchiveStore: func(db store.Executor) ArchiveStore {
+			return archiveStore
+		},
+	}
+
+	var buf bytes.Buffer
+
+	err := svc.Export(testContext(), &buf)
+	require.NoError(t, err)
+
+	exported, err := model.DecodeArchive(&buf)
+	require.NoError(t, err)
+
+	require.Equal(t, model.ArchiveVersion, exported.Version)
+	require.Equal(t, archive.Composers, exported.Composers)
+	require.Equal(t, archive.Programmes, exported.Programmes)
+	require.Len(t, exported.Events, 2)
+	require.Nil(t, exported.Contact)
+}
+
+func TestArchiveService_Restore(t *testing.T) {
+	tests := []struct {
+		name        string
+		archive     func() *model.Archive
+		empty       bool
+		txErr       error
+		expectedErr error
+	}{
+		{
+			name: "unknown composer",
+			archive: func() *model.Archive {
+				a := testArchive()
+				a.Pieces[0].ComposerID = 9
+				return a
+			},
+			empty:       true,
+			expectedErr: content.ErrInvalidResource,
+		},
+		{
+			name: "invalid content",
+			archive: func() *model.Archive {
+				a := testArchive()
+				a.Venues[0].Name = ""
+				return a
+			},
+			empty:       true,
+			expectedErr: content.ErrInvalidResource,
+		},
+		{
+			name:        "database not empty",
+			archive:     testArchive,
+			empty:       false,
+			expectedErr: model.ErrArchiveTargetNotEmpty,
+		},
+		{
+			name:        "commit failed",
+			archive:     testArchive,
+			empty:       true,
+			txErr:       ErrTxCommit,
+			expectedErr: ErrTxCommit,
+		},
+		{
+			name:        "success",
+			archive:     testArchive,
+			empty:       true,
+			expectedErr: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			archiveStore := &mockArchiveStore{
+				empty: tt.empty,
+				users: map[string]int{"foo": 7},
+			}
+
+			svc := ArchiveService{
+				db: mockDB{
+					tx: mockTx{
+						err: tt.txErr,
+					},
+				},
+				newArchiveStore: func(db store.Executor) ArchiveStore {
+					return archiveStore
+				},
+				newAuditStore: newMockAuditStore,
+			}
+
+			report, err := svc.Restore(testContext(), tt.archive())
+
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, 1, report.Composers)
+			require.Equal(t, 2, report.Events)
+			require.Equal(t, 1, report.UnknownUsers)
+
+			// Records are given new ids, and references follow them.
+			composerID := archiveStore.created["composer:1"]
+			pieceID := archiveStore.created["piece:10"]
+			require.Equal(t, composerID, archiveStore.pieceComposers[pieceID])
+			require.Equal(t, []int{pieceID}, archiveStore.programmePieces[archiveStore.created["programme:30"]])
+
+			// The cancelled event is created after the event replacing it.
+			cancelledID := archiveStore.created["event:40"]
+			replacementID := archiveStore.created["event:41"]
+			require.Greater(t, cancelledID, replacementID)
+			require.Equal(t, replacementID, *archiveStore.replacements[cancelledID])
+
+			// History is linked to the users that exist.
+			require.Equal(t, 7, *archiveStore.changedBy[0])
+			require.Nil(t, archiveStore.changedBy[1])
+		})
+	}
+}
+
+func TestArchiveRestorer_ReplacementCycle(t *testing.T) {
+	archiveStore := &mockArchiveStore{}
+
+	r := &archiveRestorer{
+		store:  archiveStore,
+		events: make(map[int]int),
+		report: &model.RestoreReport{},
+	}
+
+	first, second := 1, 2
+
+	step, err := r.restoreEvents(testContext(), []model.ArchiveEvent{
+		{ID: first, Title: "Foo", Status: content.StatusCancelled, ReplacementEventID: &second},
+		{ID: second, Title: "Bar", Status: content.StatusPostponed, ReplacementEventID: &first},
+	})
+
+	require.NoError(t, err)
+	require.Empty(t, step)
+	require.Equal(t, 2, r.report.Events)
+	require.Equal(t, r.events[second], *archiveStore.replacements[r.events[first]])
+	require.Equal(t, r.events[first], *archiveStore.replacements[r.events[second]])
+}
+
+func testArchive() *model.Archive {
+	venueID, programmeID, replacementID := 20, 30, 41
+	reason := "Foo"
+	foo, bar := "foo", "bar"
+
+	return &model.Archive{
+		Version:   model.ArchiveVersion,
+		CreatedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		Composers: []model.ArchiveComposer{
+			{ID: 1, FullName: "Foo Bar", ShortName: "Bar"},
+		},
+		Pieces: []model.ArchivePiece{
+			{ID: 10, Title: "Foo", ComposerID: 1},
+		},
+		Venues: []model.ArchiveVenue{
+			{ID: 20, Name: "Foo", FullAddress: "Foo 1, Bar", ShortAddress: "Bar"},
+		},
+		Programmes: []model.ArchiveProgramme{
+			{ID: 30, Title: "Foo", Pieces: []model.ArchiveProgrammePiece{{PieceID: 10, Sequence: 1}}},
+		},
+		Events: []model.ArchiveEvent{
+			{
+				ID:                 40,
+				Title:              "Foo",
+				VenueID:            &venueID,
+				ProgrammeID:        &programmeID,
+				Status:             content.StatusCancelled,
+				StatusReason:       &reason,
+				ReplacementEventID: &replacementID,
+				History: []model.ArchiveStatusChange{
+					{From: content.StatusDraft, To: content.StatusPublished, ChangedBy: &foo},
+					{From: content.StatusPublished, To: content.StatusCancelled, ChangedBy: &bar},
+				},
+			},
+			{ID: 41, Title: "Bar", Status: content.StatusDraft},
+		},
+	}
+}
+
+// mockArchiveStore lists the records of archive, and records what is created,
+// handing out increasing ids.
+type mockArchiveStore struct {
+	archive *model.Archive
+	empty   bool
+	users   map[string]int
+
+	nextID          int
+	created         map[string]int
+	pieceComposers  map[int]int
+	programmePieces map[int][]int
+	replacements    map[int]*int
+	changedBy       []*int
+}
+
+func (s *mockArchiveStore) id(kind string, archiveID int) int {
+	if s.created == nil {
+		s.created = make(map[string]int)
+	}
+
+	s.nextID++
+	s.created[fmt.Sprintf("%s:%d", kind, archiveID)] = s.nextID
+
+	return s.nextID
+}
+
+func (s *mockArchiveStore) Snapshot(ctx context.Context) error { return nil }
+
+func (s *mockArchiveStore) Empty(ctx context.Context) (bool, error) { return s.empty, nil }
+
+func (s *mockArchiveStore) ListUsers(ctx context.Context) (map[string]int, error) {
+	return s.users, nil
+}
+
+func (s *mockArchiveStore) ListComposers(ctx context.Context) ([]model.ArchiveComposer, error) {
+	return s.archive.Composers, nil
+}
+
+func (s *mockArchiveStore) ListPieces(ctx context.Context) ([]model.ArchivePiece, error) {
+	return s.archive.Pieces, nil
+}
+
+func (s *mockArchiveStore) ListVenues(ctx context.Context) ([]model.ArchiveVenue, error) {
+	return s.archive.Venues, nil
+}
+
+func (s *mockArchiveStore) ListProgrammes(ctx context.Context) ([]model.ArchiveProgramme, error) {
+	return s.archive.Programmes, nil
+}
+
+func (s *mockArchiveStore) ListMedia(ctx context.Context) ([]model.ArchiveMedia, error) {
+	return s.archive.Media, nil
+}
+
+func (s *mockArchiveStore) ListEvents(ctx context.Context) ([]model.ArchiveEvent, error) {
+	return s.archive.Events, nil
+}
+
+func (s *mockArchiveStore) ListBiographies(ctx context.Context) ([]model.ArchiveBiography, error) {
+	return s.archive.Biographies, nil
+}
+
+func (s *mockArchiveStore) ListBiographyMedia(ctx context.Context) ([]model.ArchiveBiographyMedia, error) {
+	return s.archive.BiographyMedia, nil
+}
+
+func (s *mockArchiveStore) GetContact(ctx context.Context) (*model.ArchiveContact, error) {
+	return nil, content.ErrResourceNotFound
+}
+
+func (s *mockArchiveStore) CreateComposer(ctx context.Context, c model.ArchiveComposer) (int, error) {
+	return s.id("composer", c.ID), nil
+}
+
+func (s *mockArchiveStore) CreatePiece(ctx context.Context, p model.ArchivePiece, composerID int) (int, error) {
+	if s.pieceComposers == nil {
+		s.pieceComposers = make(map[int]int)
+	}
+
+	id := s.id("piece", p.ID)
+	s.pieceComposers[id] = composerID
+
+	return id, nil
+}
+
+func (s *mockArchiveStore) CreateVenue(ctx context.Context, v model.ArchiveVenue) (int, error) {
+	return s.id("venue", v.ID), nil
+}
+
+func (s *mockArchiveStore) CreateProgramme(ctx context.Context, p model.ArchiveProgramme) (int, error) {
+	return s.id("programme", p.ID), nil
+}
+
+func (s *mockArchiveStore) AddProgrammePiece(ctx context.Context, programmeID, pieceID, sequence int) error {
+	if s.programmePieces == nil {
+		s.programmePieces = make(map[int][]int)
+	}
+
+	s.programmePieces[programmeID] = append(s.programmePieces[programmeID], pieceID)
+
+	return nil
+}
+
+func (s *mockArchiveStore) CreateMedia(ctx context.Context, m model.ArchiveMedia) (int, error) {
+	return s.id("media", m.ID), nil
+}
+
+func (s *mockArchiveStore) Attach(ctx context.Context, owner model.MediaOwner, mediaID int, position int) error {
+	return nil
+}
+
+func (s *mockArchiveStore) CreateEvent(
+	ctx context.Context,
+	e model.ArchiveEvent,
+	venueID, programmeID, replacementID *int,
+) (int, error) {
+	if s.replacements == nil {
+		s.replacements = make(map[int]*int)
+	}
+
+	id := s.id("event", e.ID)
+	s.replacements[id] = replacementID
+
+	return id, nil
+}
+
+func (s *mockArchiveStore) SetReplacement(ctx context.Context, eventID, replacementID int) error {
+	s.replacements[eventID] = &replacementID
+	return nil
+}
+
+func (s *mockArchiveStore) AddStatusChange(
+	ctx context.Context,
+	eventID int,
+	c model.ArchiveStatusChange,
+	changedBy *int,
+) error {
+	s.changedBy = append(s.changedBy, changedBy)
+	return nil
+}
+
+func (s *mockArchiveStore) CreateBiography(ctx context.Context, b model.ArchiveBiography, updatedBy *int) error {
+	return nil
+}
+
+func (s *mockArchiveStore) AddRevision(
+	ctx context.Context,
+	b model.ArchiveBiography,
+	r model.ArchiveRevision,
+	authorID *int,
+) error {
+	return nil
+}
+
+func (s *mockArchiveStore) CreateContact(ctx context.Context, c model.ArchiveContact) error {
+	return nil
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/adamkadda/arman/internal/cms/model"
+	"github.com/adamkadda/arman/internal/cms/store"
+	"github.com/adamkadda/arman/internal/content"
+	"github.com/adamkadda/arman/pkg/logging"
+)
+
+// AuditService lets owners read the audit log. Entries are written by the
+// services making the changes, see recordChange.
+type AuditService struct {
+	db            DB
+	newAuditStore func(db store.Executor) AuditStore
+}
+
+func NewAuditService(db DB) *AuditService {
+	return &AuditService{
+		db:            db,
+		newAuditStore: newAuditStore,
+	}
+}
+
+type AuditStore interface {
+	Create(ctx context.Context, e model.AuditEntry) error
+	List(ctx context.Context, f model.AuditFilter, q model.ListQuery) (*model.Page[model.AuditEntry], error)
+}
+
+// newAuditStore is the default AuditStore constructor of every service
+// recording changes.
+func newAuditStore(db store.Executor) AuditStore {
+	return store.NewPostgresAuditStore(db)
+}
+
+// List returns a page of the audit log entries matching the filter, most
+// recent first. Only the page of the query is used.
+//
+// An invalid filter or query is reported as content.ErrInvalidResource.
+func (s *AuditService) List(
+	ctx context.Context,
+	f model.AuditFilter,
+	q model.ListQuery,
+) (*model.Page[model.AuditEntry], error) {
+	logger := logging.FromContext(ctx).With(
+		slog.String("operation", "audit.list"),
+		slog.Group("filter",
+			slog.Any("actor_id", f.ActorID),
+			slog.String("operation", f.Operation),
+			slog.String("entity_type", string(f.EntityType)),
+			slog.String("entity_id", f.EntityID),
+			slog.String("request_id", f.RequestID),
+		),
+		listQueryAttr(q),
+	)
+
+	logger.Info(
+		"list audit log",
+	)
+
+	if err := authorize(ctx, logger, "audit.list"); err != nil {
+		return nil, err
+	}
+
+	if err := f.Validate(); err != nil {
+		logger.Warn(
+			"list audit log rejected",
+			slog.String("reason", reason(err)),
+		)
+
+		return nil, fmt.Errorf("%w: %s", content.ErrInvalidResource, err)
+	}
+
+	if err := q.Validate(); err != nil {
+		logger.Warn(
+			"list audit log rejected",
+			slog.String("reason", reason(err)),
+		)
+
+		return nil, fmt.Errorf("%w: %s", content.ErrInvalidResource, err)
+	}
+
+	auditStore := s.newAuditStore(s.db)
+
+	page, err := auditStore.List(ctx, f, q)
+	if err != nil {
+		logger.Error(
+			"list audit log failed",
+			slog.String("step", "audit.list"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// recordChange records a change made by an operation in the audit log, along
+// with the user and request making it. auditStore must run in the transaction
+// making the change, so that the change is never made without its record.
+//
+// before and after are snapshots of the changed resource, marshalled to JSON.
+// Pass nil before for creations, and nil after for deletions.
+func recordChange(
+	ctx context.Context,
+	logger *slog.Logger,
+	auditStore AuditStore,
+	operation string,
+	entity model.AuditEntity,
+	id any,
+	before any,
+	after any,
+) error {
+	entry := model.AuditEntry{
+		Operation:  operation,
+		EntityType: entity,
+		EntityID:   fmt.Sprint(id),
+	}
+
+	if user, ok := UserFromContext(ctx); ok {
+		entry.ActorID = &user.ID
+		entry.Actor = &user.Username
+	}
+
+	if requestID, ok := logging.RequestIDFromContext(ctx); ok {
+		entry.RequestID = &requestID
+	}
+
+	var err error
+
+	if entry.Before, err = snapshot(before); err == nil {
+		entry.After, err = snapshot(after)
+	}
+
+	if err == nil {
+		err = auditStore.Create(ctx, entry)
+	}
+
+	if err != nil {
+		logger.Error(
+			"record change failed",
+			slog.String("step", "audit.create"),
+			slog.String("entity_type", string(entity)),
+			slog.Any("error", err),
+		)
+
+		return err
+	}
+
+	return nil
+}
+
+// snapshot marshals a resource for the audit log. Nil resources, including
+// nil pointers, have no snapshot.
+func snapshot(v any) (json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	body, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	if string(body) == "null" {
+		return nil, nil
+	}
+
+	return body, nil
+}
+
+// auditUser is the snapshot of a User. The password hash is left out.
+type auditUser struct {
+	ID       int
+	Username string
+	Role     content.Role
+}
+
+func newAuditUser(u *content.User) *auditUser {
+	if u == nil {
+		return nil
+	}
+
+	return &auditUser{
+		ID:       u.ID,
+		Username: u.Username,
+		Role:     u.Role,
+	}
+}
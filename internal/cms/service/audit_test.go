@@ -0,0 +1,163 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/adamkadda/arman/internal/cms/model"
+	"github.com/adamkadda/arman/internal/cms/store"
+	"github.com/adamkadda/arman/internal/content"
+	"github.com/adamkadda/arman/pkg/logging"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditService_List(t *testing.T) {
+	from := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		filter      model.AuditFilter
+		query       model.ListQuery
+		storeErr    error
+		expectedErr error
+	}{
+		{
+			name:        "invalid entity type",
+			filter:      model.AuditFilter{EntityType: "foo"},
+			query:       model.ListQuery{},
+			storeErr:    nil,
+			expectedErr: content.ErrInvalidResource,
+		},
+		{
+			name:        "invalid range",
+			filter:      model.AuditFilter{From: &from, To: &to},
+			query:       model.ListQuery{},
+			storeErr:    nil,
+			expectedErr: content.ErrInvalidResource,
+		},
+		{
+			name:        "invalid query",
+			filter:      model.AuditFilter{},
+			query:       model.ListQuery{Limit: model.MaxLimit + 1},
+			storeErr:    nil,
+			expectedErr: content.ErrInvalidResource,
+		},
+		{
+			name:        "store error",
+			filter:      model.AuditFilter{EntityType: model.AuditEvent},
+			query:       model.ListQuery{},
+			storeErr:    ErrFoo,
+			expectedErr: ErrFoo,
+		},
+		{
+			name:        "success",
+			filter:      model.AuditFilter{EntityType: model.AuditEvent},
+			query:       model.ListQuery{},
+			storeErr:    nil,
+			expectedErr: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			svc := AuditService{
+				newAuditStore: func(db store.Executor) AuditStore {
+					return &mockAuditStore{
+						err: tt.storeErr,
+					}
+				},
+			}
+
+			page, err := svc.List(testContext(), tt.filter, tt.query)
+
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+				require.NotNil(t, page)
+			}
+		})
+	}
+}
+
+func TestAuditService_ListNotOwner(t *testing.T) {
+	svc := AuditService{
+		newAuditStore: newMockAuditStore,
+	}
+
+	ctx := WithUser(testContext(), &content.User{ID: 1, Role: content.RoleEditor})
+
+	_, err := svc.List(ctx, model.AuditFilter{}, model.ListQuery{})
+	require.ErrorIs(t, err, content.ErrPermissionDenied)
+}
+
+func TestRecordChange(t *testing.T) {
+	auditStore := &mockAuditStore{}
+
+	ctx := WithUser(testContext(), &content.User{ID: 7, Username: "foo", Role: content.RoleOwner})
+	ctx = logging.WithRequestID(ctx, "bar")
+
+	before := &content.Composer{ID: 1, FullName: "Foo Bar", ShortName: "Bar"}
+
+	err := recordChange(ctx, logging.FromContext(ctx), auditStore,
+		"composer.delete", model.AuditComposer, 1,
+		before, (*content.Composer)(nil),
+	)
+	require.NoError(t, err)
+	require.Len(t, auditStore.entries, 1)
+
+	entry := auditStore.entries[0]
+	require.Equal(t, "composer.delete", entry.Operation)
+	require.Equal(t, model.AuditComposer, entry.EntityType)
+	require.Equal(t, "1", entry.EntityID)
+	require.Equal(t, 7, *entry.ActorID)
+	require.Equal(t, "foo", *entry.Actor)
+	require.Equal(t, "bar", *entry.RequestID)
+	require.JSONEq(t, `{"ID":1,"FullName":"Foo Bar","ShortName":"Bar"}`, string(entry.Before))
+	require.Nil(t, entry.After)
+}
+
+func TestRecordChange_Trusted(t *testing.T) {
+	auditStore := &mockAuditStore{}
+
+	err := recordChange(testContext(), logging.FromContext(testContext()), auditStore,
+		"event.publish", model.AuditEvent, 1,
+		nil, nil,
+	)
+	require.NoError(t, err)
+
+	entry := auditStore.entries[0]
+	require.Nil(t, entry.ActorID)
+	require.Nil(t, entry.Actor)
+	require.Nil(t, entry.RequestID)
+}
+
+// A change is rolled back when it cannot be recorded.
+func TestComposerService_CreateAuditFailed(t *testing.T) {
+	composer := &content.Composer{ID: 1, FullName: "Foo Bar", ShortName: "Bar"}
+
+	svc := ComposerService{
+		db: mockDB{},
+		newComposerStore: func(db store.Executor) ComposerStore {
+			return mockComposerStore{
+				composer: composer,
+			}
+		},
+		newAuditStore: func(db store.Executor) AuditStore {
+			return &mockAuditStore{
+				err: ErrFoo,
+			}
+		},
+	}
+
+	_, err := svc.Create(testContext(), model.ComposerCommand{
+		Composer: model.ComposerIntent{
+			Operation: model.OperationCreate,
+			Data:      *composer,
+		},
+	})
+	require.ErrorIs(t, err, ErrFoo)
+}
@@ -0,0 +1,332 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/adamkadda/arman/internal/cms/store"
+	"github.com/adamkadda/arman/internal/content"
+	"github.com/adamkadda/arman/pkg/logging"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// AuthService contains application logic for signing admin users in and out,
+// and for resolving a session token back to its user.
+//
+// Stores are created via a constructor function to keep the service decoupled
+// from concrete store implementations and easy to unit test.
+type AuthService struct {
+	db              DB
+	sessionTTL      time.Duration
+	now             func() time.Time
+	newUserStore    func(db store.Executor) UserStore
+	newSessionStore func(db store.Executor) SessionStore
+}
+
+// NewAuthService creates an AuthService using the default store constructors.
+// Sessions created by the service expire after the given ttl.
+func NewAuthService(db DB, sessionTTL time.Duration) *AuthService {
+	return &AuthService{
+		db:         db,
+		sessionTTL: sessionTTL,
+		now:        time.Now,
+		newUserStore: func(db store.Executor) UserStore {
+			return store.NewPostgresUserStore(db)
+		},
+		newSessionStore: func(db store.Executor) SessionStore {
+			return store.NewPostgresSessionStore(db)
+		},
+	}
+}
+
+type SessionStore interface {
+	Get(ctx context.Context, tokenHash string) (*content.Session, error)
+	Create(ctx context.Context, s content.Session) (*content.Session, error)
+	Delete(ctx context.Context, tokenHash string) error
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
+
+// dummyHash is compared against when a username does not exist, so that a
+// failed login takes roughly as long regardless of whether the user exists.
+var dummyHash, _ = bcrypt.GenerateFromPassword(
+	[]byte("arman-dummy-password"),
+	bcrypt.DefaultCost,
+)
+
+// Login verifies the given credentials and starts a new session.
+//
+// Upon success, Login returns the session token to hand to the client along
+// with the persisted Session. The token itself is never stored. Unknown users
+// and wrong passwords are indistinguishable to the caller; both return
+// content.ErrInvalidCredentials.
+func (s *AuthService) Login(
+	ctx context.Context,
+	username string,
+	password string,
+) (string, *content.Session, error) {
+	logger := logging.FromContext(ctx).With(
+		slog.String("operation", "auth.login"),
+		slog.String("username", username),
+	)
+
+	logger.Info(
+		"login",
+	)
+
+	userStore := s.newUserStore(s.db)
+
+	user, err := userStore.GetByUsername(ctx, username)
+	if err != nil && !errors.Is(err, content.ErrResourceNotFound) {
+		logger.Error(
+			"get user failed",
+			slog.String("step", "user.get_by_username"),
+			slog.Any("error", err),
+		)
+
+		return "", nil, err
+	}
+
+	hash := dummyHash
+	if user != nil {
+		hash = []byte(user.PasswordHash)
+	}
+
+	err = bcrypt.CompareHashAndPassword(hash, []byte(password))
+	if user == nil || err != nil {
+		logger.Warn(
+			"login rejected",
+			slog.String("reason", reason(content.ErrInvalidCredentials)),
+		)
+
+		return "", nil, content.ErrInvalidCredentials
+	}
+
+	token, err := newSessionToken()
+	if err != nil {
+		logger.Error(
+			"generate session token failed",
+			slog.String("step", "session.token"),
+			slog.Any("error", err),
+		)
+
+		return "", nil, err
+	}
+
+	sessionStore := s.newSessionStore(s.db)
+
+	session, err := sessionStore.Create(ctx, content.Session{
+		TokenHash: hashSessionToken(token),
+		UserID:    user.ID,
+		ExpiresAt: s.now().Add(s.sessionTTL),
+	})
+	if err != nil {
+		logger.Error(
+			"create session failed",
+			slog.String("step", "session.create"),
+			slog.Any("error", err),
+		)
+
+		return "", nil, err
+	}
+
+	return token, session, nil
+}
+
+// Logout ends the session identified by the given token. Logging out of a
+// session that no longer exists is not an error.
+func (s *AuthService) Logout(
+	ctx context.Context,
+	token string,
+) error {
+	logger := logging.FromContext(ctx).With(
+		slog.String("operation", "auth.logout"),
+	)
+
+	logger.Info(
+		"logout",
+	)
+
+	sessionStore := s.newSessionStore(s.db)
+
+	err := sessionStore.Delete(ctx, hashSessionToken(token))
+	if err != nil && !errors.Is(err, content.ErrResourceNotFound) {
+		logger.Error(
+			"delete session failed",
+			slog.String("step", "session.delete"),
+			slog.Any("error", err),
+		)
+
+		return err
+	}
+
+	return nil
+}
+
+// Authenticate resolves a session token to the User it belongs to.
+//
+// Unknown and expired sessions both return content.ErrUnauthenticated. Expired
+// sessions are removed as a side effect.
+func (s *AuthService) Authenticate(
+	ctx context.Context,
+	token string,
+) (*content.User, error) {
+	logger := logging.FromContext(ctx).With(
+		slog.String("operation", "auth.authenticate"),
+	)
+
+	logger.Debug(
+		"authenticate",
+	)
+
+	if token == "" {
+		return nil, content.ErrUnauthenticated
+	}
+
+	sessionStore := s.newSessionStore(s.db)
+
+	tokenHash := hashSessionToken(token)
+
+	session, err := sessionStore.Get(ctx, tokenHash)
+	if err != nil {
+		if errors.Is(err, content.ErrResourceNotFound) {
+			logger.Warn(
+				"authenticate rejected",
+				slog.String("reason", reason(content.ErrUnauthenticated)),
+			)
+
+			return nil, content.ErrUnauthenticated
+		}
+
+		logger.Error(
+			"get session failed",
+			slog.String("step", "session.get"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
+	if session.Expired(s.now()) {
+		logger.Warn(
+			"authenticate rejected",
+			slog.String("reason", reason(content.ErrSessionExpired)),
+		)
+
+		if err := sessionStore.Delete(ctx, tokenHash); err != nil &&
+			!errors.Is(err, content.ErrResourceNotFound) {
+			logger.Error(
+				"delete session failed",
+				slog.String("step", "session.delete"),
+				slog.Any("error", err),
+			)
+		}
+
+		return nil, content.ErrUnauthenticated
+	}
+
+	userStore := s.newUserStore(s.db)
+
+	user, err := userStore.Get(ctx, session.UserID)
+	if err != nil {
+		logger.Error(
+			"get user failed",
+			slog.String("step", "user.get"),
+			slog.Int("user_id", session.UserID),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// PurgeExpiredSessions removes every session that has already expired.
+func (s *AuthService) PurgeExpiredSessions(
+	ctx context.Context,
+) error {
+	logger := logging.FromContext(ctx).With(
+		slog.String("operation", "auth.purge_sessions"),
+	)
+
+	logger.Info(
+		"purge expired sessions",
+	)
+
+	sessionStore := s.newSessionStore(s.db)
+
+	n, err := sessionStore.DeleteExpired(ctx, s.now())
+	if err != nil {
+		logger.Error(
+			"delete expired sessions failed",
+			slog.String("step", "session.delete_expired"),
+			slog.Any("error", err),
+		)
+
+		return err
+	}
+
+	logger.Debug(
+		"expired sessions purged",
+		slog.Int64("count", n),
+	)
+
+	return nil
+}
+
+// newSessionToken returns 32 bytes of randomness encoded for use in a cookie.
+func newSessionToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashSessionToken returns the hex encoded SHA-256 hash of a session token.
+// Tokens carry enough entropy that a fast hash is sufficient.
+func hashSessionToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// userKey points to the value in the context where the authenticated user is
+// stored.
+type userKey struct{}
+
+// WithUser creates a new context with the authenticated user attached.
+func WithUser(ctx context.Context, user *content.User) context.Context {
+	return context.WithValue(ctx, userKey{}, user)
+}
+
+// UserFromContext returns the authenticated user stored in the context, if any.
+func UserFromContext(ctx context.Context) (*content.User, bool) {
+	user, ok := ctx.Value(userKey{}).(*content.User)
+	return user, ok && user != nil
+}
+
+// systemActorKey points to the value in the context marking a trusted internal
+// caller.
+type systemActorKey struct{}
+
+// WithSystemActor creates a new context marking the caller as trusted, such as
+// the scheduler or the command line. Services authorize every operation for
+// trusted callers, see authorize. It must never be used for contexts derived
+// from an HTTP request.
+func WithSystemActor(ctx context.Context) context.Context {
+	return context.WithValue(ctx, systemActorKey{}, true)
+}
+
+// isSystemActor reports whether the context belongs to a trusted internal
+// caller, see WithSystemActor.
+func isSystemActor(ctx context.Context) bool {
+	system, _ := ctx.Value(systemActorKey{}).(bool)
+	return system
+}
@@ -0,0 +1,275 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/adamkadda/arman/internal/cms/store"
+	"github.com/adamkadda/arman/internal/content"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestAuthService_Login(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("correct horse battery"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	user := &content.User{
+		ID:           1,
+		Username:     "foo",
+		PasswordHash: string(hash),
+	}
+
+	tests := []struct {
+		name        string
+		user        *content.User
+		userErr     error
+		password    string
+		sessionErr  error
+		expectedErr error
+	}{
+		{
+			name:        "unknown user",
+			user:        nil,
+			userErr:     content.ErrResourceNotFound,
+			password:    "correct horse battery",
+			sessionErr:  nil,
+			expectedErr: content.ErrInvalidCredentials,
+		},
+		{
+			name:        "wrong password",
+			user:        user,
+			userErr:     nil,
+			password:    "incorrect horse battery",
+			sessionErr:  nil,
+			expectedErr: content.ErrInvalidCredentials,
+		},
+		{
+			name:        "user store error",
+			user:        nil,
+			userErr:     ErrGet,
+			password:    "correct horse battery",
+			sessionErr:  nil,
+			expectedErr: ErrGet,
+		},
+		{
+			name:        "session store error",
+			user:        user,
+			userErr:     nil,
+			password:    "correct horse battery",
+			sessionErr:  ErrFoo,
+			expectedErr: ErrFoo,
+		},
+		{
+			name:        "success",
+			user:        user,
+			userErr:     nil,
+			password:    "correct horse battery",
+			sessionErr:  nil,
+			expectedErr: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+			svc := AuthService{
+				sessionTTL: time.Hour,
+				now:        func() time.Time { return now },
+				newUserStore: func(db store.Executor) UserStore {
+					return mockUserStore{
+						user: tt.user,
+						err:  tt.userErr,
+					}
+				},
+				newSessionStore: func(db store.Executor) SessionStore {
+					return mockSessionStore{
+						err: tt.sessionErr,
+					}
+				},
+			}
+
+			token, session, err := svc.Login(testContext(), "foo", tt.password)
+
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+				require.NotEmpty(t, token)
+				require.Equal(t, hashSessionToken(token), session.TokenHash)
+				require.Equal(t, user.ID, session.UserID)
+				require.Equal(t, now.Add(time.Hour), session.ExpiresAt)
+			}
+		})
+	}
+}
+
+func TestAuthService_Authenticate(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	user := &content.User{
+		ID:       1,
+		Username: "foo",
+	}
+
+	tests := []struct {
+		name        string
+		token       string
+		session     *content.Session
+		sessionErr  error
+		expectedErr error
+	}{
+		{
+			name:        "empty token",
+			token:       "",
+			session:     nil,
+			sessionErr:  nil,
+			expectedErr: content.ErrUnauthenticated,
+		},
+		{
+			name:        "unknown session",
+			token:       "foo",
+			session:     nil,
+			sessionErr:  content.ErrResourceNotFound,
+			expectedErr: content.ErrUnauthenticated,
+		},
+		{
+			name:        "session store error",
+			token:       "foo",
+			session:     nil,
+			sessionErr:  ErrGet,
+			expectedErr: ErrGet,
+		},
+		{
+			name:  "expired session",
+			token: "foo",
+			session: &content.Session{
+				UserID:    1,
+				ExpiresAt: now.Add(-time.Minute),
+			},
+			sessionErr:  nil,
+			expectedErr: content.ErrUnauthenticated,
+		},
+		{
+			name:  "success",
+			token: "foo",
+			session: &content.Session{
+				UserID:    1,
+				ExpiresAt: now.Add(time.Minute),
+			},
+			sessionErr:  nil,
+			expectedErr: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			svc := AuthService{
+				now: func() time.Time { return now },
+				newUserStore: func(db store.Executor) UserStore {
+					return mockUserStore{
+						user: user,
+					}
+				},
+				newSessionStore: func(db store.Executor) SessionStore {
+					return mockSessionStore{
+						session: tt.session,
+						err:     tt.sessionErr,
+					}
+				},
+			}
+
+			got, err := svc.Authenticate(testContext(), tt.token)
+
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, user, got)
+			}
+		})
+	}
+}
+
+type mockUserStore struct {
+	user *content.User
+	err  error
+}
+
+func (s mockUserStore) Get(
+	ctx context.Context,
+	id int,
+) (*content.User, error) {
+	return s.user, s.err
+}
+
+func (s mockUserStore) GetByUsername(
+	ctx context.Context,
+	username string,
+) (*content.User, error) {
+	return s.user, s.err
+}
+
+func (s mockUserStore) List(
+	ctx context.Context,
+) ([]content.User, error) {
+	return nil, s.err
+}
+
+func (s mockUserStore) Create(
+	ctx context.Context,
+	u content.User,
+) (*content.User, error) {
+	return s.user, s.err
+}
+
+func (s mockUserStore) UpdateRole(
+	ctx context.Context,
+	id int,
+	role content.Role,
+) (*content.User, error) {
+	return s.user, s.err
+}
+
+type mockSessionStore struct {
+	session *content.Session
+	err     error
+}
+
+func (s mockSessionStore) Get(
+	ctx context.Context,
+	tokenHash string,
+) (*content.Session, error) {
+	return s.session, s.err
+}
+
+func (s mockSessionStore) Create(
+	ctx context.Context,
+	sess content.Session,
+) (*content.Session, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return &sess, nil
+}
+
+func (s mockSessionStore) Delete(
+	ctx context.Context,
+	tokenHash string,
+) error {
+	return nil
+}
+
+func (s mockSessionStore) DeleteExpired(
+	ctx context.Context,
+	before time.Time,
+) (int64, error) {
+	return 0, s.err
+}
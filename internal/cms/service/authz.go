@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/adamkadda/arman/internal/content"
+)
+
+// permissions maps every operation a user can trigger to the least privileged
+// Role allowed to perform it. Keys are the same operation strings services log
+// at method entry, so a denial can be traced back to its log line.
+//
+// Operations missing from this map are denied to everyone.
+var permissions = map[string]content.Role{
+	// Composer
+	"composer.get":    content.RoleViewer,
+	"composer.list":   content.RoleViewer,
+	"composer.create": content.RoleEditor,
+	"composer.update": content.RoleEditor,
+	"composer.delete": content.RoleEditor,
+
+	// Venue
+	"venue.get":    content.RoleViewer,
+	"venue.list":   content.RoleViewer,
+	"venue.create": content.RoleEditor,
+	"venue.update": content.RoleEditor,
+	"venue.delete": content.RoleEditor,
+
+	// Piece
+	"piece.get":    content.RoleViewer,
+	"piece.list":   content.RoleViewer,
+	"piece.create": content.RoleEditor,
+	"piece.update": content.RoleEditor,
+	"piece.delete": content.RoleEditor,
+	"piece.import": content.RoleEditor,
+
+	// Programme
+	"programme.get":           content.RoleViewer,
+	"programme.list":          content.RoleViewer,
+	"programme.create":        content.RoleEditor,
+	"programme.update":        content.RoleEditor,
+	"programme.update_pieces": content.RoleEditor,
+	"programme.delete":        content.RoleEditor,
+
+	// Event
+	"event.get":                  content.RoleViewer,
+	"event.list":                 content.RoleViewer,
+	"event.list_with_timestamps": content.RoleViewer,
+	"event.create":               content.RoleEditor,
+	"event.update":               content.RoleEditor,
+	"event.update_notes":         content.RoleEditor,
+	"event.draft":                content.RoleEditor,
+	"event.delete":               content.RoleEditor,
+	"event.publish":              content.RoleOwner,
+	"event.archive":              content.RoleOwner,
+	"event.cancel":               content.RoleOwner,
+	"event.postpone":             content.RoleOwner,
+	"event.withdraw":             content.RoleOwner,
+	"event.history":              content.RoleViewer,
+	"event.publish_scheduled":    content.RoleOwner,
+	"event.archive_expired":      content.RoleOwner,
+
+	// Biography
+	"biography.get":            content.RoleViewer,
+	"biography.update":         content.RoleEditor,
+	"biography.list_revisions": content.RoleViewer,
+	"biography.diff":           content.RoleViewer,
+	"biography.restore":        content.RoleEditor,
+	"biography.translations":   content.RoleViewer,
+
+	// Contact
+	"contact.get":    content.RoleViewer,
+	"contact.update": content.RoleEditor,
+
+	// Media
+	"media.get":    content.RoleViewer,
+	"media.list":   content.RoleViewer,
+	"media.upload": content.RoleEditor,
+	"media.update": content.RoleEditor,
+	"media.attach": content.RoleEditor,
+	"media.delete": content.RoleEditor,
+
+	// Search
+	"search": content.RoleViewer,
+
+	// Job
+	"job.get":   content.RoleOwner,
+	"job.list":  content.RoleOwner,
+	"job.retry": content.RoleOwner,
+
+	// Archive
+	"archive.export":  content.RoleOwner,
+	"archive.restore": content.RoleOwner,
+
+	// Audit
+	"audit.list": content.RoleOwner,
+
+	// User
+	"user.list":        content.RoleOwner,
+	"user.create":      content.RoleOwner,
+	"user.update_role": content.RoleOwner,
+}
+
+// authorize checks whether the user attached to ctx may perform operation.
+// Denials are logged as a business rule failure on the given logger.
+//
+// Trusted internal callers, such as the scheduler or the command line, mark
+// their context with WithSystemActor and are always authorized. A context with
+// neither a user nor the mark is denied, so that a route left unauthenticated
+// by mistake cannot reach a service.
+func authorize(
+	ctx context.Context,
+	logger *slog.Logger,
+	operation string,
+) error {
+	user, ok := UserFromContext(ctx)
+	if !ok {
+		if isSystemActor(ctx) {
+			return nil
+		}
+
+		logger.Warn(
+			"operation denied",
+			slog.String("reason", reason(content.ErrPermissionDenied)),
+		)
+
+		return content.ErrPermissionDenied
+	}
+
+	required, ok := permissions[operation]
+	if ok && user.Role.Includes(required) {
+		return nil
+	}
+
+	logger.Warn(
+		"operation denied",
+		slog.String("reason", reason(content.ErrPermissionDenied)),
+		slog.String("role", string(user.Role)),
+	)
+
+	return content.ErrPermissionDenied
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/adamkadda/arman/internal/cms/store"
+	"github.com/adamkadda/arman/internal/content"
+	"github.com/adamkadda/arman/pkg/logging"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthorize(t *testing.T) {
+	tests := []struct {
+		name        string
+		user        *content.User
+		system      bool
+		operation   string
+		expectedErr error
+	}{
+		{
+			name:        "no user",
+			user:        nil,
+			operation:   "event.get",
+			expectedErr: content.ErrPermissionDenied,
+		},
+		{
+			name:        "system actor",
+			user:        nil,
+			system:      true,
+			operation:   "event.publish",
+			expectedErr: nil,
+		},
+		{
+			name:        "user acting in a system context",
+			user:        &content.User{ID: 1, Role: content.RoleViewer},
+			system:      true,
+			operation:   "event.publish",
+			expectedErr: content.ErrPermissionDenied,
+		},
+		{
+			name:        "viewer reads",
+			user:        &content.User{ID: 1, Role: content.RoleViewer},
+			operation:   "event.get",
+			expectedErr: nil,
+		},
+		{
+			name:        "viewer writes",
+			user:        &content.User{ID: 1, Role: content.RoleViewer},
+			operation:   "event.create",
+			expectedErr: content.ErrPermissionDenied,
+		},
+		{
+			name:        "editor drafts",
+			user:        &content.User{ID: 1, Role: content.RoleEditor},
+			operation:   "event.draft",
+			expectedErr: nil,
+		},
+		{
+			name:        "editor publishes",
+			user:        &content.User{ID: 1, Role: content.RoleEditor},
+			operation:   "event.publish",
+			expectedErr: content.ErrPermissionDenied,
+		},
+		{
+			name:        "editor archives",
+			user:        &content.User{ID: 1, Role: content.RoleEditor},
+			operation:   "event.archive",
+			expectedErr: content.ErrPermissionDenied,
+		},
+		{
+			name:        "editor cancels",
+			user:        &content.User{ID: 1, Role: content.RoleEditor},
+			operation:   "event.cancel",
+			expectedErr: content.ErrPermissionDenied,
+		},
+		{
+			name:        "owner publishes",
+			user:        &content.User{ID: 1, Role: content.RoleOwner},
+			operation:   "event.publish",
+			expectedErr: nil,
+		},
+		{
+			name:        "unknown operation",
+			user:        &content.User{ID: 1, Role: content.RoleOwner},
+			operation:   "event.teleport",
+			expectedErr: content.ErrPermissionDenied,
+		},
+		{
+			name:        "invalid role",
+			user:        &content.User{ID: 1, Role: content.Role("admin")},
+			operation:   "event.get",
+			expectedErr: content.ErrPermissionDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := anonymousContext()
+			if tt.system {
+				ctx = WithSystemActor(ctx)
+			}
+			if tt.user != nil {
+				ctx = WithUser(ctx, tt.user)
+			}
+
+			err := authorize(ctx, logging.FromContext(ctx), tt.operation)
+
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestVenueService_Delete_PermissionDenied(t *testing.T) {
+	svc := VenueService{
+		newVenueStore: func(db store.Executor) VenueStore {
+			panic("store used despite denial")
+		},
+	}
+
+	ctx := WithUser(testContext(), &content.User{ID: 1, Role: content.RoleViewer})
+
+	err := svc.Delete(ctx, 1)
+
+	require.ErrorIs(t, err, content.ErrPermissionDenied)
+}
@@ -2,38 +2,114 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 
+	"github.com/adamkadda/arman/internal/cms/model"
 	"github.com/adamkadda/arman/internal/cms/store"
 	"github.com/adamkadda/arman/internal/content"
+	"github.com/adamkadda/arman/pkg/diff"
 	"github.com/adamkadda/arman/pkg/logging"
-	"github.com/jackc/pgx/v5/pgxpool"
+	"golang.org/x/text/language"
 )
 
 type BiographyService struct {
-	pool *pgxpool.Pool
+	db                DB
+	newBiographyStore func(db store.Executor) BiographyStore
+	newRevisionStore  func(db store.Executor) BiographyRevisionStore
+	newAuditStore     func(db store.Executor) AuditStore
+
+	// languages are the languages a Biography may be written in. The first
+	// language is the fallback.
+	languages []content.Language
 }
 
-func NewBiographyService(pool *pgxpool.Pool) *BiographyService {
+// NewBiographyService returns a BiographyService accepting text in the passed
+// languages. The first language is the fallback, and must be present.
+func NewBiographyService(
+	db DB,
+	languages []content.Language,
+) *BiographyService {
 	return &BiographyService{
-		pool: pool,
+		db: db,
+		newBiographyStore: func(db store.Executor) BiographyStore {
+			return store.NewBiographyStore(db)
+		},
+		newRevisionStore: func(db store.Executor) BiographyRevisionStore {
+			return store.NewBiographyRevisionStore(db)
+		},
+		newAuditStore: newAuditStore,
+		languages:     languages,
 	}
 }
 
+type BiographyStore interface {
+	Get(ctx context.Context, variant content.BiographyVariant, lang content.Language) (*content.Biography, error)
+	Languages(ctx context.Context) (map[content.BiographyVariant][]content.Language, error)
+	Update(ctx context.Context, b content.Biography, authorID *int) (*content.Biography, error)
+	Snapshot(ctx context.Context, variant content.BiographyVariant, lang content.Language) error
+}
+
+type BiographyRevisionStore interface {
+	Get(ctx context.Context, variant content.BiographyVariant, lang content.Language, id int) (*content.BiographyRevision, error)
+	List(ctx context.Context, variant content.BiographyVariant, lang content.Language) ([]content.BiographyRevision, error)
+}
+
+// Get returns the text of a Biography variant in the language that best
+// matches the passed preferences, most preferred first. Without a match, the
+// text in the fallback language is returned.
 func (s *BiographyService) Get(
 	ctx context.Context,
 	variant content.BiographyVariant,
+	preferred ...content.Language,
 ) (*content.Biography, error) {
 	logger := logging.FromContext(ctx).With(
 		slog.String("operation", "biography.get"),
 		slog.String("variant", string(variant)),
+		slog.Any("preferred", preferred),
 	)
 
 	logger.Info(
-		"get composer",
+		"get biography",
+	)
+
+	if err := authorize(ctx, logger, "biography.get"); err != nil {
+		return nil, err
+	}
+
+	return s.get(ctx, logger, variant, preferred)
+}
+
+// GetPublic returns the text of a Biography variant like Get.
+//
+// GetPublic is meant for unauthenticated callers and is not subject to
+// authorization.
+func (s *BiographyService) GetPublic(
+	ctx context.Context,
+	variant content.BiographyVariant,
+	preferred ...content.Language,
+) (*content.Biography, error) {
+	logger := logging.FromContext(ctx).With(
+		slog.String("operation", "biography.get_public"),
+		slog.String("variant", string(variant)),
+		slog.Any("preferred", preferred),
 	)
 
+	logger.Info(
+		"get public biography",
+	)
+
+	return s.get(ctx, logger, variant, preferred)
+}
+
+func (s *BiographyService) get(
+	ctx context.Context,
+	logger *slog.Logger,
+	variant content.BiographyVariant,
+	preferred []content.Language,
+) (*content.Biography, error) {
 	if err := variant.Validate(); err != nil {
 		logger.Warn(
 			"validate variant failed",
@@ -43,13 +119,36 @@ func (s *BiographyService) Get(
 		return nil, fmt.Errorf("%w: %s", content.ErrInvalidBiographyVariant, err)
 	}
 
-	biographyStore := store.NewBiographyStore(s.pool)
+	biographyStore := s.newBiographyStore(s.db)
 
-	biography, err := biographyStore.Get(ctx, variant)
+	available, err := biographyStore.Languages(ctx)
+	if err != nil {
+		logger.Error(
+			"list biography languages failed",
+			slog.String("step", "biography.languages"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
+	lang, ok := negotiateLanguage(preferred, available[variant], s.fallback())
+	if !ok {
+		logger.Warn(
+			"get biography rejected",
+			slog.String("reason", reason(content.ErrResourceNotFound)),
+			slog.String("accept_language", fmt.Sprint(preferred)),
+		)
+
+		return nil, content.ErrResourceNotFound
+	}
+
+	biography, err := biographyStore.Get(ctx, variant, lang)
 	if err != nil {
 		logger.Error(
 			"get biography failed",
 			slog.String("step", "biography.get"),
+			slog.String("language", string(lang)),
 			slog.Any("error", err),
 		)
 
@@ -59,19 +158,28 @@ func (s *BiographyService) Get(
 	return biography, nil
 }
 
+// Update replaces the text of a Biography, or adds a translation if the
+// Biography has not been written in its language yet. An empty language stands
+// for the fallback language. The previous text is kept as a revision, see
+// ListRevisions.
 func (s *BiographyService) Update(
 	ctx context.Context,
 	b content.Biography,
 ) (*content.Biography, error) {
 	logger := logging.FromContext(ctx).With(
-		slog.String("operation", "biography.get"),
+		slog.String("operation", "biography.update"),
 		slog.String("variant", string(b.Variant)),
+		slog.String("language", string(b.Language)),
 	)
 
 	logger.Info(
-		"get composer",
+		"update biography",
 	)
 
+	if err := authorize(ctx, logger, "biography.update"); err != nil {
+		return nil, err
+	}
+
 	if err := b.Variant.Validate(); err != nil {
 		logger.Warn(
 			"validate variant failed",
@@ -81,10 +189,395 @@ func (s *BiographyService) Update(
 		return nil, fmt.Errorf("%w: %s", content.ErrInvalidBiographyVariant, err)
 	}
 
-	biographyStore := store.NewBiographyStore(s.pool)
+	lang, err := s.language(b.Language)
+	if err != nil {
+		logger.Warn(
+			"validate language rejected",
+			slog.String("reason", reason(err)),
+		)
+
+		return nil, fmt.Errorf("%w: %s", content.ErrInvalidResource, err)
+	}
+
+	b.Language = lang
+
+	return s.replace(ctx, logger, "biography.update", b)
+}
+
+// ListRevisions returns every previous text of a Biography in the passed
+// language, most recent first. An empty language stands for the fallback
+// language.
+func (s *BiographyService) ListRevisions(
+	ctx context.Context,
+	variant content.BiographyVariant,
+	lang content.Language,
+) ([]content.BiographyRevision, error) {
+	logger := logging.FromContext(ctx).With(
+		slog.String("operation", "biography.list_revisions"),
+		slog.String("variant", string(variant)),
+		slog.String("language", string(lang)),
+	)
+
+	logger.Info(
+		"list biography revisions",
+	)
+
+	if err := authorize(ctx, logger, "biography.list_revisions"); err != nil {
+		return nil, err
+	}
+
+	if err := variant.Validate(); err != nil {
+		logger.Warn(
+			"validate variant failed",
+			slog.String("reason", reason(err)),
+		)
+
+		return nil, fmt.Errorf("%w: %s", content.ErrInvalidBiographyVariant, err)
+	}
+
+	lang, err := s.language(lang)
+	if err != nil {
+		logger.Warn(
+			"validate language rejected",
+			slog.String("reason", reason(err)),
+		)
+
+		return nil, fmt.Errorf("%w: %s", content.ErrInvalidResource, err)
+	}
+
+	revisionStore := s.newRevisionStore(s.db)
+
+	revisions, err := revisionStore.List(ctx, variant, lang)
+	if err != nil {
+		logger.Error(
+			"list biography revisions failed",
+			slog.String("step", "biography_revision.list"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+// Diff returns the line-based difference between two revisions of a Biography
+// in the passed language. Pass a nil to to compare a revision against the
+// current text.
+func (s *BiographyService) Diff(
+	ctx context.Context,
+	variant content.BiographyVariant,
+	lang content.Language,
+	from int,
+	to *int,
+) (*model.BiographyDiff, error) {
+	logger := logging.FromContext(ctx).With(
+		slog.String("operation", "biography.diff"),
+		slog.String("variant", string(variant)),
+		slog.String("language", string(lang)),
+		slog.Int("from", from),
+		slog.Any("to", to),
+	)
+
+	logger.Info(
+		"diff biography revisions",
+	)
+
+	if err := authorize(ctx, logger, "biography.diff"); err != nil {
+		return nil, err
+	}
+
+	if err := variant.Validate(); err != nil {
+		logger.Warn(
+			"validate variant failed",
+			slog.String("reason", reason(err)),
+		)
+
+		return nil, fmt.Errorf("%w: %s", content.ErrInvalidBiographyVariant, err)
+	}
+
+	lang, err := s.language(lang)
+	if err != nil {
+		logger.Warn(
+			"validate language rejected",
+			slog.String("reason", reason(err)),
+		)
+
+		return nil, fmt.Errorf("%w: %s", content.ErrInvalidResource, err)
+	}
+
+	revisionStore := s.newRevisionStore(s.db)
+
+	old, err := revisionStore.Get(ctx, variant, lang, from)
+	if err != nil {
+		logger.Error(
+			"get biography revision failed",
+			slog.String("step", "biography_revision.get"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
+	var text string
+	if to != nil {
+		revision, err := revisionStore.Get(ctx, variant, lang, *to)
+		if err != nil {
+			logger.Error(
+				"get biography revision failed",
+				slog.String("step", "biography_revision.get"),
+				slog.Any("error", err),
+			)
+
+			return nil, err
+		}
+
+		text = revision.Content
+	} else {
+		biographyStore := s.newBiographyStore(s.db)
+
+		biography, err := biographyStore.Get(ctx, variant, lang)
+		if err != nil {
+			logger.Error(
+				"get biography failed",
+				slog.String("step", "biography.get"),
+				slog.Any("error", err),
+			)
+
+			return nil, err
+		}
+
+		text = biography.Content
+	}
+
+	return &model.BiographyDiff{
+		Variant:  variant,
+		Language: lang,
+		From:     from,
+		To:       to,
+		Lines:    diff.Lines(old.Content, text),
+	}, nil
+}
+
+// Restore replaces the text of a Biography with the text of one of its
+// revisions. Like Update, the replaced text is kept as a new revision, so a
+// restore can itself be undone.
+func (s *BiographyService) Restore(
+	ctx context.Context,
+	variant content.BiographyVariant,
+	lang content.Language,
+	revisionID int,
+) (*content.Biography, error) {
+	logger := logging.FromContext(ctx).With(
+		slog.String("operation", "biography.restore"),
+		slog.String("variant", string(variant)),
+		slog.String("language", string(lang)),
+		slog.Int("revision_id", revisionID),
+	)
+
+	logger.Info(
+		"restore biography revision",
+	)
+
+	if err := authorize(ctx, logger, "biography.restore"); err != nil {
+		return nil, err
+	}
+
+	if err := variant.Validate(); err != nil {
+		logger.Warn(
+			"validate variant failed",
+			slog.String("reason", reason(err)),
+		)
+
+		return nil, fmt.Errorf("%w: %s", content.ErrInvalidBiographyVariant, err)
+	}
+
+	lang, err := s.language(lang)
+	if err != nil {
+		logger.Warn(
+			"validate language rejected",
+			slog.String("reason", reason(err)),
+		)
+
+		return nil, fmt.Errorf("%w: %s", content.ErrInvalidResource, err)
+	}
+
+	revisionStore := s.newRevisionStore(s.db)
+
+	revision, err := revisionStore.Get(ctx, variant, lang, revisionID)
+	if err != nil {
+		logger.Error(
+			"get biography revision failed",
+			slog.String("step", "biography_revision.get"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
+	return s.replace(ctx, logger, "biography.restore", content.Biography{
+		Content:  revision.Content,
+		Variant:  variant,
+		Language: lang,
+	})
+}
+
+// Translations reports, for every Biography variant, the languages it has been
+// written in and the supported languages it is missing.
+func (s *BiographyService) Translations(
+	ctx context.Context,
+) ([]model.BiographyTranslations, error) {
+	logger := logging.FromContext(ctx).With(
+		slog.String("operation", "biography.translations"),
+	)
+
+	logger.Info(
+		"list biography translations",
+	)
+
+	if err := authorize(ctx, logger, "biography.translations"); err != nil {
+		return nil, err
+	}
+
+	biographyStore := s.newBiographyStore(s.db)
+
+	available, err := biographyStore.Languages(ctx)
+	if err != nil {
+		logger.Error(
+			"list biography languages failed",
+			slog.String("step", "biography.languages"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
+	translations := make([]model.BiographyTranslations, len(content.BiographyVariants))
+	for i, variant := range content.BiographyVariants {
+		languages := available[variant]
+
+		missing := []content.Language{}
+		for _, lang := range s.languages {
+			if !slices.Contains(languages, lang) {
+				missing = append(missing, lang)
+			}
+		}
 
-	biography, err := biographyStore.Update(ctx, b)
+		translations[i] = model.BiographyTranslations{
+			Variant:   variant,
+			Languages: languages,
+			Missing:   missing,
+		}
+	}
+
+	return translations, nil
+}
+
+// fallback returns the language served when no preferred language is
+// available.
+func (s *BiographyService) fallback() content.Language {
+	return s.languages[0]
+}
+
+// language validates a language a Biography is to be written in. An empty
+// language stands for the fallback language.
+func (s *BiographyService) language(lang content.Language) (content.Language, error) {
+	if lang == "" {
+		return s.fallback(), nil
+	}
+
+	if err := lang.Validate(); err != nil {
+		return "", err
+	}
+
+	if !slices.Contains(s.languages, lang) {
+		return "", content.ErrLanguageUnsupported
+	}
+
+	return lang, nil
+}
+
+// negotiateLanguage picks the available language best matching the preferred
+// languages, most preferred first. Regional variants match their base
+// language and vice versa, so "de-AT" is served "de". Without a match, the
+// fallback is picked if it is available.
+func negotiateLanguage(
+	preferred []content.Language,
+	available []content.Language,
+	fallback content.Language,
+) (content.Language, bool) {
+	// The first supported tag is what the matcher falls back to.
+	supported := []content.Language{fallback}
+	for _, lang := range available {
+		if lang != fallback {
+			supported = append(supported, lang)
+		}
+	}
+
+	tags := make([]language.Tag, len(supported))
+	for i, lang := range supported {
+		tags[i] = language.Make(string(lang))
+	}
+
+	prefs := make([]language.Tag, 0, len(preferred))
+	for _, lang := range preferred {
+		tag, err := language.Parse(string(lang))
+		if err != nil {
+			continue
+		}
+		prefs = append(prefs, tag)
+	}
+
+	_, i, _ := language.NewMatcher(tags).Match(prefs...)
+	lang := supported[i]
+
+	return lang, slices.Contains(available, lang)
+}
+
+// replace keeps the current text of a Biography as a revision and replaces it,
+// in a single transaction. The user attached to ctx is recorded as the author
+// of the new text, and the change is recorded in the audit log as made by
+// operation.
+func (s *BiographyService) replace(
+	ctx context.Context,
+	logger *slog.Logger,
+	operation string,
+	b content.Biography,
+) (*content.Biography, error) {
+	var authorID *int
+	if user, ok := UserFromContext(ctx); ok {
+		authorID = &user.ID
+	}
+
+	tx, err := s.db.Begin(ctx)
 	if err != nil {
+		logger.Error(
+			"begin transaction failed",
+			slog.String("step", "tx.begin"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	biographyStore := s.newBiographyStore(tx)
+
+	if err := biographyStore.Snapshot(ctx, b.Variant, b.Language); err != nil {
+		logger.Error(
+			"snapshot biography failed",
+			slog.String("step", "biography.snapshot"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
+	// The Biography is read under the lock taken by Snapshot, so that the text
+	// recorded in the audit log is the one replaced. A Biography is created the
+	// first time it is written in a language.
+	before, err := biographyStore.Get(ctx, b.Variant, b.Language)
+	if err != nil && !errors.Is(err, content.ErrResourceNotFound) {
 		logger.Error(
 			"get biography failed",
 			slog.String("step", "biography.get"),
@@ -94,5 +587,34 @@ func (s *BiographyService) Update(
 		return nil, err
 	}
 
+	biography, err := biographyStore.Update(ctx, b, authorID)
+	if err != nil {
+		logger.Error(
+			"update biography failed",
+			slog.String("step", "biography.update"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
+	err = recordChange(ctx, logger, s.newAuditStore(tx),
+		operation, model.AuditBiography, fmt.Sprintf("%s/%s", b.Variant, b.Language),
+		before, biography,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		logger.Error(
+			"commit transaction failed",
+			slog.String("step", "tx.commit"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
 	return biography, nil
 }
@@ -0,0 +1,424 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/adamkadda/arman/internal/cms/store"
+	"github.com/adamkadda/arman/internal/content"
+	"github.com/adamkadda/arman/pkg/diff"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiateLanguage(t *testing.T) {
+	tests := []struct {
+		name      string
+		preferred []content.Language
+		available []content.Language
+		expected  content.Language
+		found     bool
+	}{
+		{
+			name:      "no preference",
+			preferred: nil,
+			available: []content.Language{"de", "en"},
+			expected:  "en",
+			found:     true,
+		},
+		{
+			name:      "exact match",
+			preferred: []content.Language{"de"},
+			available: []content.Language{"de", "en", "ja"},
+			expected:  "de",
+			found:     true,
+		},
+		{
+			name:      "preference order",
+			preferred: []content.Language{"fr", "ja", "de"},
+			available: []content.Language{"de", "en", "ja"},
+			expected:  "ja",
+			found:     true,
+		},
+		{
+			name:      "regional variant",
+			preferred: []content.Language{"de-AT"},
+			available: []content.Language{"de", "en"},
+			expected:  "de",
+			found:     true,
+		},
+		{
+			name:      "no match",
+			preferred: []content.Language{"fr"},
+			available: []content.Language{"de", "en"},
+			expected:  "en",
+			found:     true,
+		},
+		{
+			name:      "malformed preference",
+			preferred: []content.Language{"not a language"},
+			available: []content.Language{"de", "en"},
+			expected:  "en",
+			found:     true,
+		},
+		{
+			name:      "fallback missing",
+			preferred: []content.Language{"fr"},
+			available: []content.Language{"de"},
+			expected:  "en",
+			found:     false,
+		},
+		{
+			name:      "nothing available",
+			preferred: []content.Language{"de"},
+			available: nil,
+			expected:  "en",
+			found:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			lang, found := negotiateLanguage(tt.preferred, tt.available, "en")
+
+			require.Equal(t, tt.found, found)
+			if tt.found {
+				require.Equal(t, tt.expected, lang)
+			}
+		})
+	}
+}
+
+func TestBiographyService_Update(t *testing.T) {
+	tests := []struct {
+		name        string
+		biography   content.Biography
+		snapshotErr error
+		updateErr   error
+		commitErr   error
+		expectedErr error
+	}{
+		{
+			name:        "invalid variant",
+			biography:   content.Biography{Variant: "foo", Content: "bar"},
+			expectedErr: content.ErrInvalidBiographyVariant,
+		},
+		{
+			name:        "unsupported language",
+			biography:   content.Biography{Variant: content.BiographyFull, Language: "fr", Content: "bar"},
+			expectedErr: content.ErrInvalidResource,
+		},
+		{
+			name:        "snapshot error",
+			biography:   content.Biography{Variant: content.BiographyFull, Content: "bar"},
+			snapshotErr: ErrFoo,
+			expectedErr: ErrFoo,
+		},
+		{
+			name:        "update error",
+			biography:   content.Biography{Variant: content.BiographyFull, Content: "bar"},
+			updateErr:   ErrFoo,
+			expectedErr: ErrFoo,
+		},
+		{
+			name:        "commit error",
+			biography:   content.Biography{Variant: content.BiographyFull, Content: "bar"},
+			commitErr:   ErrTxCommit,
+			expectedErr: ErrTxCommit,
+		},
+		{
+			name:        "success",
+			biography:   content.Biography{Variant: content.BiographyFull, Content: "bar"},
+			expectedErr: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			biographyStore := &mockBiographyStore{
+				biography: &content.Biography{
+					Variant:  content.BiographyFull,
+					Language: "en",
+					Content:  "foo",
+				},
+				snapshotErr: tt.snapshotErr,
+				updateErr:   tt.updateErr,
+			}
+
+			svc := newTestBiographyService(
+				mockDB{tx: mockTx{err: tt.commitErr}},
+				biographyStore,
+				&mockRevisionStore{},
+			)
+
+			biography, err := svc.Update(testContext(), tt.biography)
+
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, content.Language("en"), biography.Language)
+			require.Equal(t, "bar", biography.Content)
+
+			// The replaced text is kept as a revision.
+			require.Equal(t, []string{"foo"}, biographyStore.snapshots)
+
+			// The text recorded in the audit log is read under the lock
+			// taken by Snapshot.
+			require.Equal(t, []string{"snapshot", "get", "update"}, biographyStore.calls)
+		})
+	}
+}
+
+// A Biography written for the first time has no previous text to keep.
+func TestBiographyService_UpdateFirst(t *testing.T) {
+	biographyStore := &mockBiographyStore{}
+
+	svc := newTestBiographyService(mockDB{}, biographyStore, &mockRevisionStore{})
+
+	biography, err := svc.Update(testContext(), content.Biography{
+		Variant:  content.BiographyShort,
+		Language: "de",
+		Content:  "foo",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "foo", biography.Content)
+	require.Empty(t, biographyStore.snapshots)
+}
+
+// Public callers read the biography without a user, which Get denies.
+func TestBiographyService_GetPublic(t *testing.T) {
+	biographyStore := &mockBiographyStore{
+		biography: &content.Biography{
+			Variant:  content.BiographyShort,
+			Language: "en",
+			Content:  "foo",
+		},
+	}
+
+	svc := newTestBiographyService(mockDB{}, biographyStore, &mockRevisionStore{})
+
+	_, err := svc.Get(anonymousContext(), content.BiographyShort, "en")
+	require.ErrorIs(t, err, content.ErrPermissionDenied)
+
+	biography, err := svc.GetPublic(anonymousContext(), content.BiographyShort, "de", "en")
+	require.NoError(t, err)
+	require.Equal(t, "foo", biography.Content)
+}
+
+func TestBiographyService_Restore(t *testing.T) {
+	tests := []struct {
+		name        string
+		revisionID  int
+		expectedErr error
+	}{
+		{
+			name:        "revision not found",
+			revisionID:  2,
+			expectedErr: content.ErrResourceNotFound,
+		},
+		{
+			name:        "success",
+			revisionID:  1,
+			expectedErr: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			biographyStore := &mockBiographyStore{
+				biography: &content.Biography{
+					Variant:  content.BiographyFull,
+					Language: "en",
+					Content:  "bar",
+				},
+			}
+
+			revisionStore := &mockRevisionStore{
+				revisions: []content.BiographyRevision{
+					{ID: 1, Variant: content.BiographyFull, Language: "en", Content: "foo"},
+				},
+			}
+
+			svc := newTestBiographyService(mockDB{}, biographyStore, revisionStore)
+
+			biography, err := svc.Restore(testContext(), content.BiographyFull, "", tt.revisionID)
+
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+				require.Equal(t, "bar", biographyStore.biography.Content)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, "foo", biography.Content)
+
+			// The restore can itself be undone.
+			require.Equal(t, []string{"bar"}, biographyStore.snapshots)
+		})
+	}
+}
+
+func TestBiographyService_Diff(t *testing.T) {
+	biographyStore := &mockBiographyStore{
+		biography: &content.Biography{
+			Variant:  content.BiographyFull,
+			Language: "en",
+			Content:  "foo\nqux",
+		},
+	}
+
+	revisionStore := &mockRevisionStore{
+		revisions: []content.BiographyRevision{
+			{ID: 1, Variant: content.BiographyFull, Language: "en", Content: "foo\nbar"},
+			{ID: 2, Variant: content.BiographyFull, Language: "en", Content: "foo\nbaz"},
+		},
+	}
+
+	svc := newTestBiographyService(mockDB{}, biographyStore, revisionStore)
+
+	to := 2
+
+	d, err := svc.Diff(testContext(), content.BiographyFull, "en", 1, &to)
+	require.NoError(t, err)
+	require.Equal(t, []diff.Line{
+		{Op: diff.Equal, Text: "foo"},
+		{Op: diff.Delete, Text: "bar"},
+		{Op: diff.Insert, Text: "baz"},
+	}, d.Lines)
+
+	// Without a revision to compare against, the current text is used.
+	d, err = svc.Diff(testContext(), content.BiographyFull, "en", 1, nil)
+	require.NoError(t, err)
+	require.Equal(t, []diff.Line{
+		{Op: diff.Equal, Text: "foo"},
+		{Op: diff.Delete, Text: "bar"},
+		{Op: diff.Insert, Text: "qux"},
+	}, d.Lines)
+
+	_, err = svc.Diff(testContext(), content.BiographyFull, "en", 3, nil)
+	require.ErrorIs(t, err, content.ErrResourceNotFound)
+}
+
+func newTestBiographyService(
+	db DB,
+	biographyStore *mockBiographyStore,
+	revisionStore *mockRevisionStore,
+) *BiographyService {
+	return &BiographyService{
+		db: db,
+		newBiographyStore: func(db store.Executor) BiographyStore {
+			return biographyStore
+		},
+		newRevisionStore: func(db store.Executor) BiographyRevisionStore {
+			return revisionStore
+		},
+		newAuditStore: newMockAuditStore,
+		languages:     []content.Language{"en", "de"},
+	}
+}
+
+// mockBiographyStore holds the current text of a single Biography, and keeps
+// the texts passed to Snapshot along with the methods called.
+type mockBiographyStore struct {
+	biography   *content.Biography
+	snapshots   []string
+	calls       []string
+	snapshotErr error
+	updateErr   error
+}
+
+func (s *mockBiographyStore) Get(
+	ctx context.Context,
+	variant content.BiographyVariant,
+	lang content.Language,
+) (*content.Biography, error) {
+	s.calls = append(s.calls, "get")
+
+	if s.biography == nil {
+		return nil, content.ErrResourceNotFound
+	}
+
+	biography := *s.biography
+
+	return &biography, nil
+}
+
+func (s *mockBiographyStore) Languages(
+	ctx context.Context,
+) (map[content.BiographyVariant][]content.Language, error) {
+	languages := make(map[content.BiographyVariant][]content.Language)
+	if s.biography != nil {
+		languages[s.biography.Variant] = []content.Language{s.biography.Language}
+	}
+
+	return languages, nil
+}
+
+func (s *mockBiographyStore) Update(
+	ctx context.Context,
+	b content.Biography,
+	authorID *int,
+) (*content.Biography, error) {
+	s.calls = append(s.calls, "update")
+
+	if s.updateErr != nil {
+		return nil, s.updateErr
+	}
+
+	s.biography = &b
+
+	return &b, nil
+}
+
+func (s *mockBiographyStore) Snapshot(
+	ctx context.Context,
+	variant content.BiographyVariant,
+	lang content.Language,
+) error {
+	s.calls = append(s.calls, "snapshot")
+
+	if s.snapshotErr != nil {
+		return s.snapshotErr
+	}
+
+	if s.biography != nil {
+		s.snapshots = append(s.snapshots, s.biography.Content)
+	}
+
+	return nil
+}
+
+type mockRevisionStore struct {
+	revisions []content.BiographyRevision
+}
+
+func (s *mockRevisionStore) Get(
+	ctx context.Context,
+	variant content.BiographyVariant,
+	lang content.Language,
+	id int,
+) (*content.BiographyRevision, error) {
+	for _, revision := range s.revisions {
+		if revision.ID == id {
+			return &revision, nil
+		}
+	}
+
+	return nil, content.ErrResourceNotFound
+}
+
+func (s *mockRevisionStore) List(
+	ctx context.Context,
+	variant content.BiographyVariant,
+	lang content.Language,
+) ([]content.BiographyRevision, error) {
+	return s.revisions, nil
+}
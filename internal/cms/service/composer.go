@@ -18,6 +18,7 @@ import (
 type ComposerService struct {
 	db               DB
 	newComposerStore func(db store.Executor) ComposerStore
+	newAuditStore    func(db store.Executor) AuditStore
 }
 
 func NewComposerService(db DB) *ComposerService {
@@ -26,13 +27,15 @@ func NewComposerService(db DB) *ComposerService {
 		newComposerStore: func(db store.Executor) ComposerStore {
 			return store.NewPostgresComposerStore(db)
 		},
+		newAuditStore: newAuditStore,
 	}
 }
 
 type ComposerStore interface {
 	Get(ctx context.Context, id int) (*content.Composer, error)
+	GetByFullName(ctx context.Context, fullName string) (*content.Composer, error)
 	GetWithDetails(ctx context.Context, id int) (*model.ComposerWithDetails, error)
-	ListWithDetails(ctx context.Context) ([]model.ComposerWithDetails, error)
+	ListWithDetails(ctx context.Context, q model.ListQuery) (*model.Page[model.ComposerWithDetails], error)
 	Create(ctx context.Context, c content.Composer) (*content.Composer, error)
 	Update(ctx context.Context, c content.Composer) (*content.Composer, error)
 	Delete(ctx context.Context, id int) error
@@ -52,6 +55,10 @@ func (s *ComposerService) Get(
 		"get composer",
 	)
 
+	if err := authorize(ctx, logger, "composer.get"); err != nil {
+		return nil, err
+	}
+
 	composerStore := s.newComposerStore(s.db)
 
 	composer, err := composerStore.Get(ctx, id)
@@ -68,21 +75,39 @@ func (s *ComposerService) Get(
 	return composer, nil
 }
 
-// List returns an array of ComposerWithDetails, sorted by id.
+// List returns a page of ComposerWithDetails matching the passed query. See
+// model.ComposerSortFields for the fields composers may be sorted by.
+//
+// An invalid query is reported as content.ErrInvalidResource.
 func (s *ComposerService) List(
 	ctx context.Context,
-) ([]model.ComposerWithDetails, error) {
+	q model.ListQuery,
+) (*model.Page[model.ComposerWithDetails], error) {
 	logger := logging.FromContext(ctx).With(
 		slog.String("operation", "composer.list"),
+		listQueryAttr(q),
 	)
 
 	logger.Info(
 		"list composers",
 	)
 
+	if err := authorize(ctx, logger, "composer.list"); err != nil {
+		return nil, err
+	}
+
+	if err := q.Validate(model.ComposerSortFields...); err != nil {
+		logger.Warn(
+			"list composers rejected",
+			slog.String("reason", reason(err)),
+		)
+
+		return nil, fmt.Errorf("%w: %s", content.ErrInvalidResource, err)
+	}
+
 	composerStore := s.newComposerStore(s.db)
 
-	composerList, err := composerStore.ListWithDetails(ctx)
+	page, err := composerStore.ListWithDetails(ctx, q)
 	if err != nil {
 		logger.Error(
 			"list composers failed",
@@ -93,7 +118,7 @@ func (s *ComposerService) List(
 		return nil, err
 	}
 
-	return composerList, nil
+	return page, nil
 }
 
 // Create attempts to create a Composer.
@@ -113,6 +138,10 @@ func (s *ComposerService) Create(
 		"create composer",
 	)
 
+	if err := authorize(ctx, logger, "composer.create"); err != nil {
+		return nil, err
+	}
+
 	if cmd.Composer.Operation != model.OperationCreate {
 		logger.Warn(
 			"operation mismatch",
@@ -131,7 +160,19 @@ func (s *ComposerService) Create(
 		return nil, fmt.Errorf("%w: %s", content.ErrInvalidResource, err)
 	}
 
-	composerStore := s.newComposerStore(s.db)
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		logger.Error(
+			"begin transaction failed",
+			slog.String("step", "tx.begin"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	composerStore := s.newComposerStore(tx)
 
 	composer, err := composerStore.Create(ctx, cmd.Composer.Data)
 	if err != nil {
@@ -144,6 +185,24 @@ func (s *ComposerService) Create(
 		return nil, err
 	}
 
+	err = recordChange(ctx, logger, s.newAuditStore(tx),
+		"composer.create", model.AuditComposer, composer.ID,
+		nil, composer,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		logger.Error(
+			"commit transaction failed",
+			slog.String("step", "tx.commit"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
 	return composer, nil
 }
 
@@ -166,6 +225,10 @@ func (s *ComposerService) Update(
 		"update composer",
 	)
 
+	if err := authorize(ctx, logger, "composer.update"); err != nil {
+		return nil, err
+	}
+
 	if cmd.Composer.Operation != model.OperationUpdate {
 		logger.Warn(
 			"operation mismatch",
@@ -184,7 +247,30 @@ func (s *ComposerService) Update(
 		return nil, fmt.Errorf("%w: %s", content.ErrInvalidResource, err)
 	}
 
-	composerStore := s.newComposerStore(s.db)
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		logger.Error(
+			"begin transaction failed",
+			slog.String("step", "tx.begin"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	composerStore := s.newComposerStore(tx)
+
+	before, err := composerStore.Get(ctx, cmd.Composer.Data.ID)
+	if err != nil {
+		logger.Error(
+			"get composer failed",
+			slog.String("step", "composer.get"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
 
 	composer, err := composerStore.Update(ctx, cmd.Composer.Data)
 	if err != nil {
@@ -197,6 +283,24 @@ func (s *ComposerService) Update(
 		return nil, err
 	}
 
+	err = recordChange(ctx, logger, s.newAuditStore(tx),
+		"composer.update", model.AuditComposer, composer.ID,
+		before, composer,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		logger.Error(
+			"commit transaction failed",
+			slog.String("step", "tx.commit"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
 	return composer, err
 }
 
@@ -216,7 +320,23 @@ func (s *ComposerService) Delete(
 		"delete composer",
 	)
 
-	composerStore := s.newComposerStore(s.db)
+	if err := authorize(ctx, logger, "composer.delete"); err != nil {
+		return err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		logger.Error(
+			"begin transaction failed",
+			slog.String("step", "tx.begin"),
+			slog.Any("error", err),
+		)
+
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	composerStore := s.newComposerStore(tx)
 
 	composerWithDetails, err := composerStore.GetWithDetails(ctx, id)
 	if err != nil {
@@ -250,18 +370,44 @@ func (s *ComposerService) Delete(
 		return err
 	}
 
+	err = recordChange(ctx, logger, s.newAuditStore(tx),
+		"composer.delete", model.AuditComposer, id,
+		composerWithDetails.Composer, nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		logger.Error(
+			"commit transaction failed",
+			slog.String("step", "tx.commit"),
+			slog.Any("error", err),
+		)
+
+		return err
+	}
+
 	return nil
 }
 
+// composerResolver resolves a ComposerIntent. The Composers it creates or
+// updates are recorded in the audit log as changed by operation.
 type composerResolver struct {
 	composerStore ComposerStore
+	auditStore    AuditStore
+	operation     string
 }
 
 func newComposerResolver(
 	composerStore ComposerStore,
+	auditStore AuditStore,
+	operation string,
 ) *composerResolver {
 	return &composerResolver{
 		composerStore: composerStore,
+		auditStore:    auditStore,
+		operation:     operation,
 	}
 }
 
@@ -302,6 +448,14 @@ func (r *composerResolver) run(
 			)
 			return nil, err
 		}
+
+		err = recordChange(ctx, logger, r.auditStore,
+			r.operation, model.AuditComposer, piece.ID,
+			nil, piece,
+		)
+		if err != nil {
+			return nil, err
+		}
 		return piece, nil
 
 	case model.OperationUpdate:
@@ -313,6 +467,17 @@ func (r *composerResolver) run(
 			return nil, fmt.Errorf("%w: %s", content.ErrInvalidResource, err)
 		}
 
+		before, err := r.composerStore.Get(ctx, intent.Data.ID)
+		if err != nil {
+			logger.Error(
+				"get composer failed",
+				slog.Int("composer_id", intent.Data.ID),
+				slog.String("step", "composer.get"),
+				slog.Any("error", err),
+			)
+			return nil, err
+		}
+
 		piece, err := r.composerStore.Update(ctx, intent.Data)
 		if err != nil {
 			logger.Error(
@@ -323,6 +488,14 @@ func (r *composerResolver) run(
 			)
 			return nil, err
 		}
+
+		err = recordChange(ctx, logger, r.auditStore,
+			r.operation, model.AuditComposer, piece.ID,
+			before, piece,
+		)
+		if err != nil {
+			return nil, err
+		}
 		return piece, nil
 
 	default:
@@ -60,10 +60,18 @@ func TestComposerService_Get(t *testing.T) {
 func TestComposerService_List(t *testing.T) {
 	tests := []struct {
 		name              string
+		query             model.ListQuery
 		expectedComposers []model.ComposerWithDetails
 		storeErr          error
 		expectedErr       error
 	}{
+		{
+			name:              "invalid query",
+			query:             model.ListQuery{Sort: "foo"},
+			expectedComposers: nil,
+			storeErr:          nil,
+			expectedErr:       content.ErrInvalidResource,
+		},
 		{
 			name:              "store error",
 			expectedComposers: nil,
@@ -116,13 +124,13 @@ func TestComposerService_List(t *testing.T) {
 				},
 			}
 
-			composers, err := svc.List(testContext())
+			page, err := svc.List(testContext(), tt.query)
 
 			if tt.expectedErr != nil {
 				require.ErrorIs(t, err, tt.expectedErr)
 			} else {
 				require.NoError(t, err)
-				require.Equal(t, tt.expectedComposers, composers)
+				require.Equal(t, tt.expectedComposers, page.Items)
 			}
 		})
 	}
@@ -211,12 +219,14 @@ func TestComposerService_Create(t *testing.T) {
 			t.Parallel()
 
 			svc := ComposerService{
+				db: mockDB{},
 				newComposerStore: func(db store.Executor) ComposerStore {
 					return mockComposerStore{
 						composer: tt.expectedComposer,
 						err:      tt.storeErr,
 					}
 				},
+				newAuditStore: newMockAuditStore,
 			}
 
 			composer, err := svc.Create(testContext(), tt.cmd)
@@ -312,12 +322,14 @@ func TestComposerService_Update(t *testing.T) {
 			t.Parallel()
 
 			svc := ComposerService{
+				db: mockDB{},
 				newComposerStore: func(db store.Executor) ComposerStore {
 					return mockComposerStore{
 						composer: tt.expectedComposer,
 						err:      tt.expectedErr,
 					}
 				},
+				newAuditStore: newMockAuditStore,
 			}
 
 			composer, err := svc.Update(testContext(), tt.cmd)
@@ -396,6 +408,7 @@ func TestComposerService_Delete(t *testing.T) {
 			t.Parallel()
 
 			svc := ComposerService{
+				db: mockDB{},
 				newComposerStore: func(db store.Executor) ComposerStore {
 					return mockComposerStore{
 						detailedComposer: tt.composer,
@@ -403,6 +416,7 @@ func TestComposerService_Delete(t *testing.T) {
 						deleteErr:        tt.deleteErr,
 					}
 				},
+				newAuditStore: newMockAuditStore,
 			}
 
 			err := svc.Delete(testContext(), 2)
@@ -473,7 +487,11 @@ func TestComposerResolver_Run(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			resolver := newComposerResolver(mockComposerStore{})
+			resolver := newComposerResolver(
+				mockComposerStore{composer: &tt.intent.Data},
+				&mockAuditStore{},
+				"composer.test",
+			)
 
 			_, err := resolver.run(testContext(), tt.intent)
 
@@ -489,6 +507,8 @@ func TestComposerResolver_Run(t *testing.T) {
 type mockComposerStore struct {
 	composers         []content.Composer
 	composer          *content.Composer
+	namedComposer     *content.Composer
+	nameErr           error
 	detailedComposers []model.ComposerWithDetails
 	detailedComposer  *model.ComposerWithDetails
 	err               error
@@ -503,6 +523,13 @@ func (s mockComposerStore) Get(
 	return s.composer, s.err
 }
 
+func (s mockComposerStore) GetByFullName(
+	ctx context.Context,
+	fullName string,
+) (*content.Composer, error) {
+	return s.namedComposer, s.nameErr
+}
+
 func (s mockComposerStore) GetWithDetails(
 	ctx context.Context,
 	id int,
@@ -512,8 +539,13 @@ func (s mockComposerStore) GetWithDetails(
 
 func (s mockComposerStore) ListWithDetails(
 	ctx context.Context,
-) ([]model.ComposerWithDetails, error) {
-	return s.detailedComposers, s.err
+	q model.ListQuery,
+) (*model.Page[model.ComposerWithDetails], error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return model.NewPage(q, s.detailedComposers, len(s.detailedComposers)), nil
 }
 
 func (s mockComposerStore) Create(
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/adamkadda/arman/internal/cms/model"
+	"github.com/adamkadda/arman/internal/cms/store"
+	"github.com/adamkadda/arman/internal/content"
+	"github.com/adamkadda/arman/pkg/logging"
+)
+
+// ContactService contains application logic for the artist's contact details.
+//
+// Stores are created via a constructor function to keep the service decoupled
+// from concrete store implementations and easy to unit test.
+type ContactService struct {
+	db              DB
+	newContactStore func(db store.Executor) ContactStore
+	newAuditStore   func(db store.Executor) AuditStore
+}
+
+// NewContactService creates a ContactService using the default store
+// constructor.
+func NewContactService(db DB) *ContactService {
+	return &ContactService{
+		db: db,
+		newContactStore: func(db store.Executor) ContactStore {
+			return store.NewPostgresContactStore(db)
+		},
+		newAuditStore: newAuditStore,
+	}
+}
+
+type ContactStore interface {
+	Get(ctx context.Context) (*content.Contact, error)
+	Update(ctx context.Context, c content.Contact) (*content.Contact, error)
+}
+
+// Get returns the contact details, private fields included.
+func (s *ContactService) Get(
+	ctx context.Context,
+) (*content.Contact, error) {
+	logger := logging.FromContext(ctx).With(
+		slog.String("operation", "contact.get"),
+	)
+
+	logger.Info(
+		"get contact",
+	)
+
+	if err := authorize(ctx, logger, "contact.get"); err != nil {
+		return nil, err
+	}
+
+	return s.get(ctx, logger)
+}
+
+// GetPublic returns the contact details with every private field cleared.
+//
+// GetPublic is meant for unauthenticated callers and is not subject to
+// authorization.
+func (s *ContactService) GetPublic(
+	ctx context.Context,
+) (*content.Contact, error) {
+	logger := logging.FromContext(ctx).With(
+		slog.String("operation", "contact.get_public"),
+	)
+
+	logger.Info(
+		"get public contact",
+	)
+
+	contact, err := s.get(ctx, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	contact.Phone = ""
+
+	return contact, nil
+}
+
+func (s *ContactService) get(
+	ctx context.Context,
+	logger *slog.Logger,
+) (*content.Contact, error) {
+	contactStore := s.newContactStore(s.db)
+
+	contact, err := contactStore.Get(ctx)
+	if err != nil {
+		logger.Error(
+			"get contact failed",
+			slog.String("step", "contact.get"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
+	return contact, nil
+}
+
+// Update validates the passed Contact and replaces the contact details with
+// it. The passed Contact should describe the desired state.
+func (s *ContactService) Update(
+	ctx context.Context,
+	c content.Contact,
+) (*content.Contact, error) {
+	logger := logging.FromContext(ctx).With(
+		slog.String("operation", "contact.update"),
+	)
+
+	logger.Info(
+		"update contact",
+	)
+
+	if err := authorize(ctx, logger, "contact.update"); err != nil {
+		return nil, err
+	}
+
+	if err := c.Validate(); err != nil {
+		logger.Warn(
+			"validate contact rejected",
+			slog.String("reason", reason(err)),
+		)
+
+		return nil, fmt.Errorf("%w: %s", content.ErrInvalidResource, err)
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		logger.Error(
+			"begin transaction failed",
+			slog.String("step", "tx.begin"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	contactStore := s.newContactStore(tx)
+
+	// The contact details are created by their first update.
+	before, err := contactStore.Get(ctx)
+	if err != nil && !errors.Is(err, content.ErrResourceNotFound) {
+		logger.Error(
+			"get contact failed",
+			slog.String("step", "contact.get"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
+	contact, err := contactStore.Update(ctx, c)
+	if err != nil {
+		logger.Error(
+			"update contact failed",
+			slog.String("step", "contact.update"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
+	err = recordChange(ctx, logger, s.newAuditStore(tx),
+		"contact.update", model.AuditContact, "contact",
+		before, contact,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		logger.Error(
+			"commit transaction failed",
+			slog.String("step", "tx.commit"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
+	return contact, nil
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/adamkadda/arman/internal/cms/store"
+	"github.com/adamkadda/arman/internal/content"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContactService_GetPublic(t *testing.T) {
+	svc := ContactService{
+		newContactStore: func(db store.Executor) ContactStore {
+			return mockContactStore{
+				contact: &content.Contact{
+					Email: "foo@example.com",
+					Phone: "+1 555 0100",
+				},
+			}
+		},
+	}
+
+	contact, err := svc.GetPublic(testContext())
+
+	require.NoError(t, err)
+	require.Equal(t, "foo@example.com", contact.Email)
+	require.Empty(t, contact.Phone)
+}
+
+func TestContactService_Update(t *testing.T) {
+	tests := []struct {
+		name        string
+		contact     content.Contact
+		storeErr    error
+		expectedErr error
+	}{
+		{
+			name: "invalid email",
+			contact: content.Contact{
+				Email: "foo",
+			},
+			storeErr:    nil,
+			expectedErr: content.ErrInvalidResource,
+		},
+		{
+			name: "social link without platform",
+			contact: content.Contact{
+				SocialLinks: []content.SocialLink{
+					{Platform: "", URL: "https://example.com/foo"},
+				},
+			},
+			storeErr:    nil,
+			expectedErr: content.ErrInvalidResource,
+		},
+		{
+			name: "social link with invalid url",
+			contact: content.Contact{
+				SocialLinks: []content.SocialLink{
+					{Platform: "Foo", URL: "javascript:alert(1)"},
+				},
+			},
+			storeErr:    nil,
+			expectedErr: content.ErrInvalidResource,
+		},
+		{
+			name: "store error",
+			contact: content.Contact{
+				Email: "foo@example.com",
+			},
+			storeErr:    ErrFoo,
+			expectedErr: ErrFoo,
+		},
+		{
+			name: "success",
+			contact: content.Contact{
+				Email:            "foo@example.com",
+				ManagementAgency: "Bar Artists",
+				ManagementEmail:  "bar@example.com",
+				SocialLinks: []content.SocialLink{
+					{Platform: "Foo", URL: "https://example.com/foo"},
+				},
+			},
+			storeErr:    nil,
+			expectedErr: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			svc := ContactService{
+				db: mockDB{},
+				newContactStore: func(db store.Executor) ContactStore {
+					return mockContactStore{
+						err: tt.storeErr,
+					}
+				},
+				newAuditStore: newMockAuditStore,
+			}
+
+			contact, err := svc.Update(testContext(), tt.contact)
+
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.contact, *contact)
+			}
+		})
+	}
+}
+
+type mockContactStore struct {
+	contact *content.Contact
+	err     error
+}
+
+func (s mockContactStore) Get(
+	ctx context.Context,
+) (*content.Contact, error) {
+	return s.contact, s.err
+}
+
+func (s mockContactStore) Update(
+	ctx context.Context,
+	c content.Contact,
+) (*content.Contact, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return &c, nil
+}
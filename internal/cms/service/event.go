@@ -2,8 +2,11 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
+	"time"
 
 	"github.com/adamkadda/arman/internal/cms/model"
 	"github.com/adamkadda/arman/internal/cms/store"
@@ -12,15 +15,39 @@ import (
 )
 
 type EventService struct {
-	db DB
+	db            DB
+	newEventStore func(db store.Executor) EventStore
+	newAuditStore func(db store.Executor) AuditStore
 }
 
 func NewEventService(db DB) *EventService {
 	return &EventService{
 		db: db,
+		newEventStore: func(db store.Executor) EventStore {
+			return store.NewEventStore(db)
+		},
+		newAuditStore: newAuditStore,
 	}
 }
 
+type EventStore interface {
+	Get(ctx context.Context, id int) (*content.Event, error)
+	List(ctx context.Context, q model.EventQuery) (*model.Page[content.Event], error)
+	ListWithTimestamps(ctx context.Context, q model.EventQuery) (*model.Page[model.EventWithTimestamps], error)
+	Create(ctx context.Context, e content.Event) (*content.Event, error)
+	Update(ctx context.Context, e content.Event) (*content.Event, error)
+	UpdateNotes(ctx context.Context, id int, notes *string) error
+	SetStatus(ctx context.Context, id int, from, to content.Status, reason *string, replacementID *int) error
+	ListScheduled(ctx context.Context, before time.Time) ([]int, error)
+	ListExpired(ctx context.Context, before time.Time) ([]int, error)
+	ListCalendar(ctx context.Context, from, to time.Time) ([]model.EventWithTimestamps, error)
+	ListRecent(ctx context.Context, limit int) ([]model.EventWithTimestamps, error)
+	Unschedule(ctx context.Context, id int) error
+	RecordTransition(ctx context.Context, t content.StatusTransition) (*content.StatusTransition, error)
+	ListTransitions(ctx context.Context, eventID int) ([]content.StatusTransition, error)
+	Delete(ctx context.Context, id int) error
+}
+
 // Get returns an EventWithProgramme by Event id.
 func (s *EventService) Get(
 	ctx context.Context,
@@ -35,7 +62,11 @@ func (s *EventService) Get(
 		"get event",
 	)
 
-	eventStore := store.NewEventStore(s.db)
+	if err := authorize(ctx, logger, "event.get"); err != nil {
+		return nil, err
+	}
+
+	eventStore := s.newEventStore(s.db)
 
 	e, err := eventStore.Get(ctx, id)
 	if err != nil {
@@ -48,116 +79,342 @@ func (s *EventService) Get(
 		return nil, err
 	}
 
-	programmeStore := store.NewProgrammeStore(s.db)
+	return expandEvent(logging.WithLogger(ctx, logger), s.db, e)
+}
+
+// List returns a page of Events matching the passed query. Events are sorted
+// by date; see model.EventQuery for the available filters and ordering.
+//
+// List validates the query before reaching the store. An invalid query is
+// reported as content.ErrInvalidResource.
+func (s *EventService) List(
+	ctx context.Context,
+	q model.EventQuery,
+) (*model.Page[content.Event], error) {
+	logger := logging.FromContext(ctx).With(
+		slog.String("operation", "event.list"),
+		eventQueryAttr(q),
+	)
+
+	logger.Info(
+		"list events",
+	)
+
+	if err := authorize(ctx, logger, "event.list"); err != nil {
+		return nil, err
+	}
+
+	if err := q.Validate(); err != nil {
+		logger.Warn(
+			"list events rejected",
+			slog.String("reason", reason(err)),
+		)
+
+		return nil, fmt.Errorf("%w: %s", content.ErrInvalidResource, err)
+	}
+
+	eventStore := s.newEventStore(s.db)
 
-	p, err := programmeStore.Get(ctx, *e.ProgrammeID)
+	page, err := eventStore.List(ctx, q)
 	if err != nil {
 		logger.Error(
-			"get programme failed",
-			slog.Int("programme_id", *e.ProgrammeID),
+			"list events failed",
 			slog.Any("error", err),
 		)
 
 		return nil, err
 	}
 
-	programmePieceStore := store.NewProgrammePieceStore(s.db)
+	return page, nil
+}
+
+// ListWithTimestamp returns a page of EventWithTimestamps matching the passed
+// query. Events are sorted by date; see model.EventQuery for the available
+// filters and ordering.
+//
+// ListWithTimestamp validates the query before reaching the store. An invalid
+// query is reported as content.ErrInvalidResource.
+func (s *EventService) ListWithTimestamp(
+	ctx context.Context,
+	q model.EventQuery,
+) (*model.Page[model.EventWithTimestamps], error) {
+	logger := logging.FromContext(ctx).With(
+		slog.String("operation", "event.list_with_timestamps"),
+		eventQueryAttr(q),
+	)
+
+	logger.Info(
+		"list events with timestamps",
+	)
+
+	if err := authorize(ctx, logger, "event.list_with_timestamps"); err != nil {
+		return nil, err
+	}
+
+	if err := q.Validate(); err != nil {
+		logger.Warn(
+			"list events with timestamps rejected",
+			slog.String("reason", reason(err)),
+		)
+
+		return nil, fmt.Errorf("%w: %s", content.ErrInvalidResource, err)
+	}
+
+	eventStore := s.newEventStore(s.db)
 
-	pp, err := programmePieceStore.ListByProgrammeID(ctx, p.ID)
+	page, err := eventStore.ListWithTimestamps(ctx, q)
 	if err != nil {
 		logger.Error(
-			"list programme pieces failed",
-			slog.String("step", "programme_piece.list"),
+			"list events with timestamps failed",
 			slog.Any("error", err),
 		)
 
 		return nil, err
 	}
 
-	event := &model.EventWithProgramme{
-		Event: e,
-		Programme: &model.ProgrammeWithPieces{
-			Programme: p,
-			Pieces:    pp,
-		},
+	return page, nil
+}
+
+// GetPublished returns a published EventWithProgramme by Event id, or one that
+// was called off after being published, so that its page can explain why.
+// Drafts and archived events are reported as content.ErrResourceNotFound, so
+// that their existence is not leaked to public callers.
+//
+// GetPublished is meant for unauthenticated callers and is not subject to
+// authorization.
+func (s *EventService) GetPublished(
+	ctx context.Context,
+	id int,
+) (*model.EventWithProgramme, error) {
+	logger := logging.FromContext(ctx).With(
+		slog.String("operation", "event.get_published"),
+		slog.Int("event_id", id),
+	)
+
+	logger.Info(
+		"get published event",
+	)
+
+	eventStore := s.newEventStore(s.db)
+
+	e, err := eventStore.Get(ctx, id)
+	if errors.Is(err, content.ErrResourceNotFound) {
+		logger.Warn(
+			"get published event rejected",
+			slog.String("reason", reason(err)),
+		)
+
+		return nil, err
+	}
+	if err != nil {
+		logger.Error(
+			"get event failed",
+			slog.String("step", "event.get"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
 	}
 
-	return event, nil
+	if e.Status != content.StatusPublished && !e.Status.CallsOff() {
+		logger.Warn(
+			"get published event rejected",
+			slog.String("reason", reason(content.ErrResourceNotFound)),
+			slog.String("status", string(e.Status)),
+		)
+
+		return nil, content.ErrResourceNotFound
+	}
+
+	return expandEvent(logging.WithLogger(ctx, logger), s.db, e)
 }
 
-// List returns an array of Events sorted by date, starting from the most recent.
+// ListPublished returns a page of public EventWithProgramme matching the
+// passed query: published events, and those called off after being published,
+// as in the calendar. The status filter of the query is always overridden.
 //
-// List accepts two optional filters for status and timeframe.
-// If you don't want to pass a filter, pass nil instead. See the content package's
-// event.go file to better understand what these filters mean.
-func (s *EventService) List(
+// ListPublished is meant for unauthenticated callers and is not subject to
+// authorization.
+func (s *EventService) ListPublished(
 	ctx context.Context,
-	status *content.Status,
-	timeframe *content.Timeframe,
-) ([]content.Event, error) {
+	q model.EventQuery,
+) (*model.Page[model.EventWithProgramme], error) {
+	q.Status = nil
+	q.Public = true
+
 	logger := logging.FromContext(ctx).With(
-		slog.String("operation", "event.list"),
-		slog.Group("filters",
-			slog.Any("status", status),
-			slog.Any("timeframe", timeframe),
-		),
+		slog.String("operation", "event.list_published"),
+		eventQueryAttr(q),
 	)
 
 	logger.Info(
-		"list events",
+		"list published events",
 	)
 
-	eventStore := store.NewEventStore(s.db)
+	if err := q.Validate(); err != nil {
+		logger.Warn(
+			"list published events rejected",
+			slog.String("reason", reason(err)),
+		)
+
+		return nil, fmt.Errorf("%w: %s", content.ErrInvalidResource, err)
+	}
+
+	eventStore := s.newEventStore(s.db)
 
-	eventList, err := eventStore.List(ctx, status, timeframe)
+	page, err := eventStore.List(ctx, q)
 	if err != nil {
 		logger.Error(
 			"list events failed",
+			slog.String("step", "event.list"),
 			slog.Any("error", err),
 		)
 
 		return nil, err
 	}
 
-	return eventList, nil
+	// Never rely on the store alone to keep drafts and archived events away
+	// from the public.
+	published := make([]content.Event, 0, len(page.Items))
+	for _, e := range page.Items {
+		if e.Status == content.StatusPublished || e.Status.CallsOff() {
+			published = append(published, e)
+		}
+	}
+
+	events, err := expandEvents(logging.WithLogger(ctx, logger), s.db, published)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Page[model.EventWithProgramme]{
+		Items:      events,
+		NextCursor: page.NextCursor,
+	}, nil
 }
 
-// ListWithTimestamp returns an array of EventWithTimestamp, sorted by their
-// Event ids.
+// CalendarPast and CalendarAhead bound the events listed in the public
+// calendar around the present, so that the calendar does not grow with every
+// event ever held.
+const (
+	CalendarPast  = 90 * 24 * time.Hour
+	CalendarAhead = 2 * 365 * 24 * time.Hour
+)
+
+// Calendar returns the PublishedEvents listed in the public calendar, earliest
+// first: the events dated from CalendarPast before now up to CalendarAhead
+// after it. Besides published events, the calendar lists the events that were
+// called off after being published, so that subscribers learn about it.
 //
-// ListWithTimestamp accepts two optional filters for status and timeframe.
-// If you don't want to pass a filter, pass nil instead. See the content package's
-// event.go file to better understand what these filters mean.
-func (s *EventService) ListWithTimestamp(
+// Calendar is meant for unauthenticated callers and is not subject to
+// authorization.
+func (s *EventService) Calendar(
 	ctx context.Context,
-	status *content.Status,
-	timeframe *content.Timeframe,
-) ([]model.EventWithTimestamps, error) {
+	now time.Time,
+) ([]model.PublishedEvent, error) {
+	from := now.Add(-CalendarPast)
+	to := now.Add(CalendarAhead)
+
 	logger := logging.FromContext(ctx).With(
-		slog.String("operation", "event.list_with_timestamps"),
-		slog.Group("filters",
-			slog.Any("status", status),
-			slog.Any("timeframe", timeframe),
-		),
+		slog.String("operation", "event.calendar"),
+		slog.Time("from", from),
+		slog.Time("to", to),
 	)
 
 	logger.Info(
-		"list events with timestamps",
+		"list calendar events",
 	)
 
-	eventStore := store.NewEventStore(s.db)
+	eventStore := s.newEventStore(s.db)
 
-	eventList, err := eventStore.ListWithTimestamps(ctx, status, timeframe)
+	items, err := eventStore.ListCalendar(ctx, from, to)
 	if err != nil {
 		logger.Error(
-			"list events with timestamps failed",
+			"list calendar events failed",
+			slog.String("step", "event.list_calendar"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
+	return expandPublished(logging.WithLogger(ctx, logger), s.db, items)
+}
+
+// ListRecent returns the latest published events as PublishedEvent, the most
+// recently updated first. At most limit events are returned.
+//
+// ListRecent is meant for unauthenticated callers and is not subject to
+// authorization.
+func (s *EventService) ListRecent(
+	ctx context.Context,
+	limit int,
+) ([]model.PublishedEvent, error) {
+	logger := logging.FromContext(ctx).With(
+		slog.String("operation", "event.list_recent"),
+		slog.Int("limit", limit),
+	)
+
+	logger.Info(
+		"list recent events",
+	)
+
+	eventStore := s.newEventStore(s.db)
+
+	items, err := eventStore.ListRecent(ctx, limit)
+	if err != nil {
+		logger.Error(
+			"list recent events failed",
+			slog.String("step", "event.list_recent"),
 			slog.Any("error", err),
 		)
 
 		return nil, err
 	}
 
-	return eventList, nil
+	return expandPublished(logging.WithLogger(ctx, logger), s.db, items)
+}
+
+// expandPublished expands every passed event into a PublishedEvent.
+func expandPublished(
+	ctx context.Context,
+	db store.Executor,
+	items []model.EventWithTimestamps,
+) ([]model.PublishedEvent, error) {
+	plain := make([]content.Event, len(items))
+	for i := range items {
+		plain[i] = items[i].Event
+	}
+
+	expanded, err := expandEvents(ctx, db, plain)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]model.PublishedEvent, len(items))
+	for i := range items {
+		events[i] = model.PublishedEvent{
+			EventWithProgramme: expanded[i],
+			Sequence:           items[i].Sequence,
+			CreatedAt:          items[i].CreatedAt,
+			UpdatedAt:          items[i].UpdatedAt,
+		}
+	}
+
+	return events, nil
+}
+
+// eventQueryAttr groups the filters of an EventQuery for logging.
+func eventQueryAttr(q model.EventQuery) slog.Attr {
+	return slog.Group("filters",
+		slog.Any("status", q.Status),
+		slog.Bool("public", q.Public),
+		slog.Any("timeframe", q.Timeframe),
+		slog.Any("from", q.From),
+		slog.Any("to", q.To),
+		slog.Bool("cursor", q.Cursor != nil),
+		slog.Int("limit", q.Limit),
+	)
 }
 
 // Create attempts to create an Event.
@@ -177,7 +434,9 @@ func (s *EventService) Create(
 		"create event",
 	)
 
-	eventStore := store.NewEventStore(s.db)
+	if err := authorize(ctx, logger, "event.create"); err != nil {
+		return nil, err
+	}
 
 	if err := e.Validate(); err != nil {
 		logger.Warn(
@@ -188,6 +447,20 @@ func (s *EventService) Create(
 		return nil, fmt.Errorf("%w: %s", content.ErrInvalidResource, err)
 	}
 
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		logger.Error(
+			"begin transaction failed",
+			slog.String("step", "tx.begin"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	eventStore := s.newEventStore(tx)
+
 	event, err := eventStore.Create(ctx, e)
 	if err != nil {
 		logger.Error(
@@ -199,6 +472,24 @@ func (s *EventService) Create(
 		return nil, err
 	}
 
+	err = recordChange(ctx, logger, s.newAuditStore(tx),
+		"event.create", model.AuditEvent, event.ID,
+		nil, event,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		logger.Error(
+			"commit transaction failed",
+			slog.String("step", "tx.commit"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
 	return event, nil
 }
 
@@ -219,6 +510,10 @@ func (s *EventService) Update(
 		"update event",
 	)
 
+	if err := authorize(ctx, logger, "event.update"); err != nil {
+		return nil, err
+	}
+
 	tx, err := s.db.Begin(ctx)
 	if err != nil {
 		logger.Error(
@@ -231,7 +526,7 @@ func (s *EventService) Update(
 	}
 	defer tx.Rollback(ctx)
 
-	eventStore := store.NewEventStore(tx)
+	eventStore := s.newEventStore(tx)
 
 	event, err := eventStore.Get(ctx, e.ID)
 	if err != nil {
@@ -253,7 +548,11 @@ func (s *EventService) Update(
 		return nil, err
 	}
 
-	if err = event.Validate(); err != nil {
+	// The status and notes are not edited here, see Transition and UpdateNotes.
+	e.Status = event.Status
+	e.Notes = event.Notes
+
+	if err = e.Validate(); err != nil {
 		logger.Warn(
 			"validate event rejected",
 			slog.String("reason", reason(err)),
@@ -262,7 +561,9 @@ func (s *EventService) Update(
 		return nil, fmt.Errorf("%w: %s", content.ErrInvalidResource, err)
 	}
 
-	event, err = eventStore.Update(ctx, *event)
+	before := event
+
+	event, err = eventStore.Update(ctx, e)
 	if err != nil {
 		logger.Error(
 			"update event failed",
@@ -273,31 +574,16 @@ func (s *EventService) Update(
 		return nil, err
 	}
 
-	programmeStore := store.NewProgrammeStore(tx)
-
-	programme, err := programmeStore.Get(ctx, *event.ProgrammeID)
+	err = recordChange(ctx, logger, s.newAuditStore(tx),
+		"event.update", model.AuditEvent, event.ID,
+		before, event,
+	)
 	if err != nil {
-		logger.Error(
-			"get programme failed",
-			slog.String("step", "programme.get"),
-			slog.Int("programme_id", *event.ProgrammeID),
-			slog.Any("error", err),
-		)
-
 		return nil, err
 	}
 
-	programmePiecesStore := store.NewProgrammePieceStore(tx)
-
-	programmePieces, err := programmePiecesStore.ListByProgrammeID(ctx, programme.ID)
+	eventWithProgramme, err := expandEvent(logging.WithLogger(ctx, logger), tx, event)
 	if err != nil {
-		logger.Error(
-			"list programme pieces failed",
-			slog.String("step", "programme_piece.list"),
-			slog.Int("programme_id", programme.ID),
-			slog.Any("error", err),
-		)
-
 		return nil, err
 	}
 
@@ -311,20 +597,13 @@ func (s *EventService) Update(
 		return nil, err
 	}
 
-	eventWithProgramme := &model.EventWithProgramme{
-		Event: event,
-		Programme: &model.ProgrammeWithPieces{
-			Programme: programme,
-			Pieces:    programmePieces,
-		},
-	}
-
 	return eventWithProgramme, nil
 }
 
 // UpdateNotes attempts to update an Event's notes by id. As noted in the
 // content package, Event notes are not subject to mutability constraints unlike
-// other Event fields.
+// other Event fields. Notes are private, so editing them is not a revision of
+// the Event seen by calendar subscribers.
 func (s *EventService) UpdateNotes(
 	ctx context.Context,
 	id int,
@@ -339,6 +618,10 @@ func (s *EventService) UpdateNotes(
 		"update event notes",
 	)
 
+	if err := authorize(ctx, logger, "event.update_notes"); err != nil {
+		return nil, err
+	}
+
 	tx, err := s.db.Begin(ctx)
 	if err != nil {
 		logger.Error(
@@ -351,7 +634,7 @@ func (s *EventService) UpdateNotes(
 	}
 	defer tx.Rollback(ctx)
 
-	eventStore := store.NewEventStore(tx)
+	eventStore := s.newEventStore(tx)
 
 	event, err := eventStore.Get(ctx, id)
 	if err != nil {
@@ -364,19 +647,27 @@ func (s *EventService) UpdateNotes(
 		return nil, err
 	}
 
+	before := *event
 	event.Notes = &notes
 
-	event, err = eventStore.Update(ctx, *event)
-	if err != nil {
+	if err = eventStore.UpdateNotes(ctx, id, event.Notes); err != nil {
 		logger.Error(
-			"update event failed",
-			slog.String("step", "event.update"),
+			"update event notes failed",
+			slog.String("step", "event.update_notes"),
 			slog.Any("error", err),
 		)
 
 		return nil, err
 	}
 
+	err = recordChange(ctx, logger, s.newAuditStore(tx),
+		"event.update_notes", model.AuditEvent, event.ID,
+		before, event,
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	if err = tx.Commit(ctx); err != nil {
 		logger.Error(
 			"commit transaction failed",
@@ -390,95 +681,466 @@ func (s *EventService) UpdateNotes(
 	return event, nil
 }
 
-// Draft attempts to draft an event by id.
+// Draft attempts to draft an event by id, see Transition.
 func (s *EventService) Draft(
 	ctx context.Context,
 	id int,
 ) error {
-	logger := logging.FromContext(ctx).With(
-		slog.String("operation", "event.draft"),
-		slog.Int("event_id", id),
-	)
+	return s.Transition(ctx, id, content.StatusDraft, content.StatusChange{})
+}
 
-	logger.Info(
-		"draft event",
-	)
+// Publish attempts to publish an event by id, see Transition.
+func (s *EventService) Publish(
+	ctx context.Context,
+	id int,
+) error {
+	return s.Transition(ctx, id, content.StatusPublished, content.StatusChange{})
+}
 
-	eventStore := store.NewEventStore(s.db)
+// Archive attempts to archive an event by id, see Transition.
+func (s *EventService) Archive(
+	ctx context.Context,
+	id int,
+) error {
+	return s.Transition(ctx, id, content.StatusArchived, content.StatusChange{})
+}
 
-	if err := eventStore.Draft(ctx, id); err != nil {
-		logger.Error(
-			"draft event failed",
-			slog.String("step", "event.draft"),
-			slog.Any("error", err),
-		)
+// Cancel attempts to cancel an event by id, see Transition. Cancelled events
+// remain listed, along with their reason.
+func (s *EventService) Cancel(
+	ctx context.Context,
+	id int,
+	change content.StatusChange,
+) error {
+	return s.Transition(ctx, id, content.StatusCancelled, change)
+}
 
-		return err
-	}
+// Postpone attempts to postpone an event by id, see Transition. Postponed
+// events remain listed, along with their reason.
+func (s *EventService) Postpone(
+	ctx context.Context,
+	id int,
+	change content.StatusChange,
+) error {
+	return s.Transition(ctx, id, content.StatusPostponed, change)
+}
 
-	return nil
+// statusOperations maps each status to the operation authorizing a move to it.
+var statusOperations = map[content.Status]string{
+	content.StatusDraft:     "event.draft",
+	content.StatusPublished: "event.publish",
+	content.StatusArchived:  "event.archive",
+	content.StatusCancelled: "event.cancel",
+	content.StatusPostponed: "event.postpone",
 }
 
-// Publish attempts to publish an event by id. It checks for validity,
-// then it checks whether it is publishable.
-func (s *EventService) Publish(
+// Transition attempts to move an event by id to another status. Only the moves
+// listed in the transition table of the content package are allowed, and
+// events are only published once they are publishable. Moving an event the
+// public can see to any other status is left to the owner.
+//
+// The passed change explains the move. Events being called off must be given
+// a reason, and may link the event replacing them. Every move is recorded in
+// the status history of the event, along with the user who made it.
+func (s *EventService) Transition(
 	ctx context.Context,
 	id int,
+	to content.Status,
+	change content.StatusChange,
 ) error {
 	logger := logging.FromContext(ctx).With(
-		slog.String("operation", "event.publish"),
+		slog.String("operation", "event.transition"),
 		slog.Int("event_id", id),
+		slog.String("to", string(to)),
 	)
 
 	logger.Info(
-		"publish event",
+		"transition event",
 	)
 
-	eventStore := store.NewEventStore(s.db)
-
-	event, err := eventStore.Get(ctx, id)
-	if err != nil {
-		logger.Error(
-			"get event failed",
-			slog.String("step", "event.get"),
-			slog.Any("error", err),
+	if err := to.Validate(); err != nil {
+		logger.Warn(
+			"transition event rejected",
+			slog.String("reason", reason(err)),
 		)
 
+		return fmt.Errorf("%w: %s", content.ErrInvalidResource, err)
+	}
+
+	if err := authorize(ctx, logger, statusOperations[to]); err != nil {
 		return err
 	}
 
-	if err = event.Validate(); err != nil {
+	if err := change.Validate(to); err != nil {
 		logger.Warn(
-			"validate event rejected",
+			"validate status change rejected",
 			slog.String("reason", reason(err)),
 		)
 
 		return fmt.Errorf("%w: %s", content.ErrInvalidResource, err)
 	}
 
-	programmeStore := store.NewProgrammeStore(s.db)
+	if change.ReplacementID != nil && *change.ReplacementID == id {
+		logger.Warn(
+			"validate status change rejected",
+			slog.String("reason", reason(content.ErrEventReplacementInvalid)),
+		)
 
-	programme, err := programmeStore.GetWithDetails(ctx, *event.ProgrammeID)
-	if err != nil {
+		return fmt.Errorf("%w: event cannot replace itself", content.ErrInvalidResource)
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
 		logger.Error(
-			"get programme with details failed",
-			slog.String("step", "event.get_with_details"),
+			"begin transaction failed",
+			slog.String("step", "tx.begin"),
 			slog.Any("error", err),
 		)
 
 		return err
 	}
+	defer tx.Rollback(ctx)
 
-	if programme.PieceCount < 1 {
+	eventStore := s.newEventStore(tx)
+
+	event, err := eventStore.Get(ctx, id)
+	if err != nil {
+		logger.Error(
+			"get event failed",
+			slog.String("step", "event.get"),
+			slog.Any("error", err),
+		)
+
+		return err
+	}
+
+	logger = logger.With(
+		slog.String("from", string(event.Status)),
+	)
+
+	if err = event.Status.CanTransition(to); err != nil {
 		logger.Warn(
-			"publish event rejected",
-			slog.String("reason", reason(content.ErrProgrammeHasNoPieces)),
+			"transition event rejected",
+			slog.String("reason", reason(err)),
 		)
 
-		return content.ErrProgrammeHasNoPieces
+		return err
+	}
+
+	// Taking an event the public can see out of view is up to the owner,
+	// whichever status it moves to.
+	if event.Status == content.StatusPublished || event.Status.CallsOff() {
+		if err = authorize(ctx, logger, "event.withdraw"); err != nil {
+			return err
+		}
+	}
+
+	if to == content.StatusPublished {
+		if err = checkPublishable(logging.WithLogger(ctx, logger), tx, event); err != nil {
+			return err
+		}
+	}
+
+	if change.ReplacementID != nil {
+		_, err := eventStore.Get(ctx, *change.ReplacementID)
+		if errors.Is(err, content.ErrResourceNotFound) {
+			logger.Warn(
+				"validate status change rejected",
+				slog.String("reason", reason(content.ErrEventReplacementInvalid)),
+				slog.Int("replacement_id", *change.ReplacementID),
+			)
+
+			return fmt.Errorf("%w: replacement event not found", content.ErrInvalidResource)
+		}
+		if err != nil {
+			logger.Error(
+				"get replacement event failed",
+				slog.String("step", "event.get"),
+				slog.Any("error", err),
+			)
+
+			return err
+		}
+	}
+
+	var changeReason *string
+	if change.Reason != "" {
+		changeReason = &change.Reason
+	}
+
+	var statusReason *string
+	if to.CallsOff() {
+		statusReason = changeReason
+	}
+
+	err = eventStore.SetStatus(ctx, id, event.Status, to, statusReason, change.ReplacementID)
+	if errors.Is(err, content.ErrResourceNotFound) {
+		logger.Warn(
+			"transition event rejected",
+			slog.String("reason", reason(content.ErrInvalidStatusTransition)),
+		)
+
+		return fmt.Errorf("%w: status changed concurrently", content.ErrInvalidStatusTransition)
+	}
+	if err != nil {
+		logger.Error(
+			"set event status failed",
+			slog.String("step", "event.set_status"),
+			slog.Any("error", err),
+		)
+
+		return err
+	}
+
+	var changedBy *int
+	if user, ok := UserFromContext(ctx); ok {
+		changedBy = &user.ID
+	}
+
+	_, err = eventStore.RecordTransition(ctx, content.StatusTransition{
+		EventID:   id,
+		From:      event.Status,
+		To:        to,
+		Reason:    changeReason,
+		ChangedBy: changedBy,
+	})
+	if err != nil {
+		logger.Error(
+			"record event transition failed",
+			slog.String("step", "event.record_transition"),
+			slog.Any("error", err),
+		)
+
+		return err
+	}
+
+	after, err := eventStore.Get(ctx, id)
+	if err != nil {
+		logger.Error(
+			"get event failed",
+			slog.String("step", "event.get"),
+			slog.Any("error", err),
+		)
+
+		return err
+	}
+
+	err = recordChange(ctx, logger, s.newAuditStore(tx),
+		statusOperations[to], model.AuditEvent, id,
+		event, after,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		logger.Error(
+			"commit transaction failed",
+			slog.String("step", "tx.commit"),
+			slog.Any("error", err),
+		)
+
+		return err
+	}
+
+	return nil
+}
+
+// PublishScheduled publishes every draft whose publication time is at or
+// before now. Each draft goes through Publish, and so through its checks. A
+// draft that cannot be published is unscheduled, so that it is not retried
+// until it is scheduled again.
+//
+// PublishScheduled is meant for the scheduler. A failure to publish one draft
+// does not prevent publishing the others.
+func (s *EventService) PublishScheduled(
+	ctx context.Context,
+	now time.Time,
+) error {
+	logger := logging.FromContext(ctx).With(
+		slog.String("operation", "event.publish_scheduled"),
+	)
+
+	if err := authorize(ctx, logger, "event.publish_scheduled"); err != nil {
+		return err
+	}
+
+	eventStore := s.newEventStore(s.db)
+
+	ids, err := eventStore.ListScheduled(ctx, now)
+	if err != nil {
+		logger.Error(
+			"list scheduled events failed",
+			slog.String("step", "event.list_scheduled"),
+			slog.Any("error", err),
+		)
+
+		return err
+	}
+
+	for _, id := range ids {
+		err := s.Publish(ctx, id)
+		switch {
+		case err == nil:
+			logger.Info(
+				"published scheduled event",
+				slog.Int("event_id", id),
+			)
+		case rejected(err):
+			logger.Warn(
+				"scheduled publication rejected",
+				slog.Int("event_id", id),
+				slog.String("reason", reason(err)),
+			)
+
+			s.unschedule(ctx, logger.With(slog.Int("event_id", id)), id)
+		}
+	}
+
+	return nil
+}
+
+// unschedule clears the publication time of a draft, so that PublishScheduled
+// stops retrying it. Failures are only logged.
+func (s *EventService) unschedule(
+	ctx context.Context,
+	logger *slog.Logger,
+	id int,
+) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		logger.Error(
+			"begin transaction failed",
+			slog.String("step", "tx.begin"),
+			slog.Any("error", err),
+		)
+
+		return
+	}
+	defer tx.Rollback(ctx)
+
+	eventStore := s.newEventStore(tx)
+
+	before, err := eventStore.Get(ctx, id)
+	if err != nil {
+		logger.Error(
+			"get event failed",
+			slog.String("step", "event.get"),
+			slog.Any("error", err),
+		)
+
+		return
+	}
+
+	if err = eventStore.Unschedule(ctx, id); err != nil {
+		logger.Error(
+			"unschedule event failed",
+			slog.String("step", "event.unschedule"),
+			slog.Any("error", err),
+		)
+
+		return
 	}
 
-	if err = event.Publishable(); err != nil {
+	after, err := eventStore.Get(ctx, id)
+	if err != nil {
+		logger.Error(
+			"get event failed",
+			slog.String("step", "event.get"),
+			slog.Any("error", err),
+		)
+
+		return
+	}
+
+	err = recordChange(ctx, logger, s.newAuditStore(tx),
+		"event.publish_scheduled", model.AuditEvent, id,
+		before, after,
+	)
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		logger.Error(
+			"commit transaction failed",
+			slog.String("step", "tx.commit"),
+			slog.Any("error", err),
+		)
+	}
+}
+
+// ArchiveExpired archives every published, cancelled or postponed event whose
+// date is before the passed time. Each event goes through Archive.
+//
+// ArchiveExpired is meant for the scheduler. A failure to archive one event
+// does not prevent archiving the others.
+func (s *EventService) ArchiveExpired(
+	ctx context.Context,
+	before time.Time,
+) error {
+	logger := logging.FromContext(ctx).With(
+		slog.String("operation", "event.archive_expired"),
+		slog.Time("before", before),
+	)
+
+	if err := authorize(ctx, logger, "event.archive_expired"); err != nil {
+		return err
+	}
+
+	eventStore := s.newEventStore(s.db)
+
+	ids, err := eventStore.ListExpired(ctx, before)
+	if err != nil {
+		logger.Error(
+			"list expired events failed",
+			slog.String("step", "event.list_expired"),
+			slog.Any("error", err),
+		)
+
+		return err
+	}
+
+	for _, id := range ids {
+		if err := s.Archive(ctx, id); err == nil {
+			logger.Info(
+				"archived expired event",
+				slog.Int("event_id", id),
+			)
+		}
+	}
+
+	return nil
+}
+
+// rejected reports whether a transition failed because of a business rule,
+// rather than an error worth retrying.
+func rejected(err error) bool {
+	return errors.Is(err, content.ErrInvalidResource) ||
+		errors.Is(err, content.ErrInvalidStatusTransition) ||
+		errors.Is(err, content.ErrEventNotPublishable) ||
+		errors.Is(err, content.ErrProgrammeHasNoPieces)
+}
+
+// checkPublishable checks that an event is valid and complete, and that its
+// programme has at least one piece.
+func checkPublishable(
+	ctx context.Context,
+	db store.Executor,
+	event *content.Event,
+) error {
+	logger := logging.FromContext(ctx)
+
+	if err := event.Validate(); err != nil {
+		logger.Warn(
+			"validate event rejected",
+			slog.String("reason", reason(err)),
+		)
+
+		return fmt.Errorf("%w: %s", content.ErrInvalidResource, err)
+	}
+
+	if err := event.Publishable(); err != nil {
 		logger.Warn(
 			"publish event rejected",
 			slog.String("reason", reason(err)),
@@ -487,47 +1149,73 @@ func (s *EventService) Publish(
 		return fmt.Errorf("%w: %s", content.ErrEventNotPublishable, err)
 	}
 
-	err = eventStore.Publish(ctx, id)
+	programmeStore := store.NewProgrammeStore(db)
+
+	programme, err := programmeStore.GetWithDetails(ctx, *event.ProgrammeID)
 	if err != nil {
 		logger.Error(
-			"publish event failed",
-			slog.String("step", "event.publish"),
+			"get programme with details failed",
+			slog.String("step", "event.get_with_details"),
 			slog.Any("error", err),
 		)
 
 		return err
 	}
 
+	if programme.PieceCount < 1 {
+		logger.Warn(
+			"publish event rejected",
+			slog.String("reason", reason(content.ErrProgrammeHasNoPieces)),
+		)
+
+		return content.ErrProgrammeHasNoPieces
+	}
+
 	return nil
 }
 
-// Archive attempts to archive an event by id.
-func (s *EventService) Archive(
+// History returns the status history of an event by id, most recent first.
+func (s *EventService) History(
 	ctx context.Context,
 	id int,
-) error {
+) ([]content.StatusTransition, error) {
 	logger := logging.FromContext(ctx).With(
-		slog.String("operation", "event.archive"),
+		slog.String("operation", "event.history"),
 		slog.Int("event_id", id),
 	)
 
 	logger.Info(
-		"archive event",
+		"list event status history",
 	)
 
-	eventStore := store.NewEventStore(s.db)
+	if err := authorize(ctx, logger, "event.history"); err != nil {
+		return nil, err
+	}
+
+	eventStore := s.newEventStore(s.db)
 
-	if err := eventStore.Archive(ctx, id); err != nil {
+	if _, err := eventStore.Get(ctx, id); err != nil {
 		logger.Error(
-			"archive event failed",
-			slog.String("step", "event.archive"),
+			"get event failed",
+			slog.String("step", "event.get"),
 			slog.Any("error", err),
 		)
 
-		return err
+		return nil, err
 	}
 
-	return nil
+	transitions, err := eventStore.ListTransitions(ctx, id)
+	if err != nil {
+		logger.Error(
+			"list event transitions failed",
+			slog.String("step", "event.list_transitions"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
+	return transitions, nil
 }
 
 // Delete attempts to delete an event by id.
@@ -546,7 +1234,23 @@ func (s *EventService) Delete(
 		"delete event",
 	)
 
-	eventStore := store.NewEventStore(s.db)
+	if err := authorize(ctx, logger, "event.delete"); err != nil {
+		return err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		logger.Error(
+			"begin transaction failed",
+			slog.String("step", "tx.begin"),
+			slog.Any("error", err),
+		)
+
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	eventStore := s.newEventStore(tx)
 
 	event, err := eventStore.Get(ctx, id)
 	if err != nil {
@@ -579,5 +1283,162 @@ func (s *EventService) Delete(
 		return err
 	}
 
+	err = recordChange(ctx, logger, s.newAuditStore(tx),
+		"event.delete", model.AuditEvent, id,
+		event, nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		logger.Error(
+			"commit transaction failed",
+			slog.String("step", "tx.commit"),
+			slog.Any("error", err),
+		)
+
+		return err
+	}
+
 	return nil
 }
+
+// expandEvent loads the Venue and Programme (with its pieces) referenced by an
+// Event. Drafts may not reference either yet, in which case the corresponding
+// field is left nil.
+func expandEvent(
+	ctx context.Context,
+	db store.Executor,
+	e *content.Event,
+) (*model.EventWithProgramme, error) {
+	events, err := expandEvents(ctx, db, []content.Event{*e})
+	if err != nil {
+		return nil, err
+	}
+
+	return &events[0], nil
+}
+
+// expandEvents expands every passed Event like expandEvent does. The Venues,
+// Programmes and pieces of all the Events are loaded at once, rather than
+// Event by Event.
+func expandEvents(
+	ctx context.Context,
+	db store.Executor,
+	events []content.Event,
+) ([]model.EventWithProgramme, error) {
+	logger := logging.FromContext(ctx)
+
+	var venueIDs, programmeIDs []int
+	for _, e := range events {
+		if e.VenueID != nil && !slices.Contains(venueIDs, *e.VenueID) {
+			venueIDs = append(venueIDs, *e.VenueID)
+		}
+		if e.ProgrammeID != nil && !slices.Contains(programmeIDs, *e.ProgrammeID) {
+			programmeIDs = append(programmeIDs, *e.ProgrammeID)
+		}
+	}
+
+	venues := make(map[int]*content.Venue, len(venueIDs))
+	if len(venueIDs) > 0 {
+		venueStore := store.NewPostgresVenueStore(db)
+
+		items, err := venueStore.ListByIDs(ctx, venueIDs)
+		if err != nil {
+			logger.Error(
+				"list venues failed",
+				slog.String("step", "venue.list_by_ids"),
+				slog.Any("error", err),
+			)
+
+			return nil, err
+		}
+
+		for i := range items {
+			venues[items[i].ID] = &items[i]
+		}
+	}
+
+	programmes := make(map[int]*model.ProgrammeWithPieces, len(programmeIDs))
+	if len(programmeIDs) > 0 {
+		programmeStore := store.NewProgrammeStore(db)
+
+		items, err := programmeStore.ListByIDs(ctx, programmeIDs)
+		if err != nil {
+			logger.Error(
+				"list programmes failed",
+				slog.String("step", "programme.list_by_ids"),
+				slog.Any("error", err),
+			)
+
+			return nil, err
+		}
+
+		programmePieceStore := store.NewProgrammePieceStore(db)
+
+		programmePieces, err := programmePieceStore.ListByProgrammeIDs(ctx, programmeIDs)
+		if err != nil {
+			logger.Error(
+				"list programme pieces failed",
+				slog.String("step", "programme_piece.list_by_programme_ids"),
+				slog.Any("error", err),
+			)
+
+			return nil, err
+		}
+
+		for i := range items {
+			pieces := programmePieces[items[i].ID]
+			if pieces == nil {
+				pieces = []content.ProgrammePiece{}
+			}
+
+			programmes[items[i].ID] = &model.ProgrammeWithPieces{
+				Programme: &items[i],
+				Pieces:    pieces,
+			}
+		}
+	}
+
+	expanded := make([]model.EventWithProgramme, len(events))
+	for i := range events {
+		e := &events[i]
+
+		expanded[i].Event = e
+
+		if e.VenueID != nil {
+			venue, ok := venues[*e.VenueID]
+			if !ok {
+				logger.Error(
+					"get venue failed",
+					slog.String("step", "venue.get"),
+					slog.Int("venue_id", *e.VenueID),
+					slog.Any("error", content.ErrResourceNotFound),
+				)
+
+				return nil, content.ErrResourceNotFound
+			}
+
+			expanded[i].Venue = venue
+		}
+
+		if e.ProgrammeID != nil {
+			programme, ok := programmes[*e.ProgrammeID]
+			if !ok {
+				logger.Error(
+					"get programme failed",
+					slog.String("step", "programme.get"),
+					slog.Int("programme_id", *e.ProgrammeID),
+					slog.Any("error", content.ErrResourceNotFound),
+				)
+
+				return nil, content.ErrResourceNotFound
+			}
+
+			expanded[i].Programme = programme
+		}
+	}
+
+	return expanded, nil
+}
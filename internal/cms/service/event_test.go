@@ -0,0 +1,577 @@
+package service
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/adamkadda/arman/internal/cms/model"
+	"github.com/adamkadda/arman/internal/cms/store"
+	"github.com/adamkadda/arman/internal/content"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventService_Transition(t *testing.T) {
+	self := 1
+	missing := 3
+
+	tests := []struct {
+		name         string
+		status       content.Status
+		to           content.Status
+		change       content.StatusChange
+		setStatusErr error
+		recordErr    error
+		commitErr    error
+		expectedErr  error
+	}{
+		{
+			name:        "invalid status",
+			status:      content.StatusPublished,
+			to:          "foo",
+			expectedErr: content.ErrInvalidResource,
+		},
+		{
+			name:        "rejected edge",
+			status:      content.StatusDraft,
+			to:          content.StatusArchived,
+			expectedErr: content.ErrInvalidStatusTransition,
+		},
+		{
+			name:        "reason missing",
+			status:      content.StatusPublished,
+			to:          content.StatusCancelled,
+			change:      content.StatusChange{},
+			expectedErr: content.ErrInvalidResource,
+		},
+		{
+			name:        "replaced by itself",
+			status:      content.StatusPublished,
+			to:          content.StatusPostponed,
+			change:      content.StatusChange{Reason: "foo", ReplacementID: &self},
+			expectedErr: content.ErrInvalidResource,
+		},
+		{
+			name:        "replacement not found",
+			status:      content.StatusPublished,
+			to:          content.StatusPostponed,
+			change:      content.StatusChange{Reason: "foo", ReplacementID: &missing},
+			expectedErr: content.ErrInvalidResource,
+		},
+		{
+			name:         "status changed concurrently",
+			status:       content.StatusPublished,
+			to:           content.StatusArchived,
+			setStatusErr: content.ErrResourceNotFound,
+			expectedErr:  content.ErrInvalidStatusTransition,
+		},
+		{
+			name:        "record transition error",
+			status:      content.StatusPublished,
+			to:          content.StatusArchived,
+			recordErr:   ErrFoo,
+			expectedErr: ErrFoo,
+		},
+		{
+			name:        "commit error",
+			status:      content.StatusPublished,
+			to:          content.StatusArchived,
+			commitErr:   ErrTxCommit,
+			expectedErr: ErrTxCommit,
+		},
+		{
+			name:        "success",
+			status:      content.StatusPublished,
+			to:          content.StatusArchived,
+			expectedErr: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			events := &mockEvents{
+				events: map[int]content.Event{
+					1: {ID: 1, Title: "Foo", Status: tt.status},
+					2: {ID: 2, Title: "Bar", Status: content.StatusDraft},
+				},
+				setStatusErr: tt.setStatusErr,
+				recordErr:    tt.recordErr,
+			}
+
+			svc := EventService{
+				db:            mockDB{tx: mockTx{err: tt.commitErr}},
+				newEventStore: events.newEventStore,
+				newAuditStore: newMockAuditStore,
+			}
+
+			err := svc.Transition(testContext(), 1, tt.to, tt.change)
+
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+
+				// The mock store does not roll back, so only moves rejected
+				// before being written leave it untouched.
+				if tt.recordErr == nil && tt.commitErr == nil {
+					require.Equal(t, tt.status, events.events[1].Status)
+					require.Empty(t, events.transitions)
+				}
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.to, events.events[1].Status)
+			}
+		})
+	}
+}
+
+// The status of an event and its history are written in the same
+// transaction, along with the user making the move and the reason for it.
+func TestEventService_TransitionHistory(t *testing.T) {
+	replacementID := 2
+
+	events := &mockEvents{
+		events: map[int]content.Event{
+			1: {ID: 1, Title: "Foo", Status: content.StatusPublished},
+			2: {ID: 2, Title: "Bar", Status: content.StatusDraft},
+		},
+	}
+
+	tx := mockTx{}
+
+	svc := EventService{
+		db:            mockDB{tx: tx},
+		newEventStore: events.newEventStore,
+		newAuditStore: newMockAuditStore,
+	}
+
+	ctx := WithUser(testContext(), &content.User{ID: 7, Role: content.RoleOwner})
+
+	err := svc.Postpone(ctx, 1, content.StatusChange{
+		Reason:        "foo",
+		ReplacementID: &replacementID,
+	})
+	require.NoError(t, err)
+
+	event := events.events[1]
+	require.Equal(t, content.StatusPostponed, event.Status)
+	require.Equal(t, "foo", *event.StatusReason)
+	require.Equal(t, replacementID, *event.ReplacementID)
+
+	require.Len(t, events.transitions, 1)
+
+	transition := events.transitions[0]
+	require.Equal(t, content.StatusPublished, transition.From)
+	require.Equal(t, content.StatusPostponed, transition.To)
+	require.Equal(t, "foo", *transition.Reason)
+	require.Equal(t, 7, *transition.ChangedBy)
+
+	require.Equal(t, []store.Executor{tx, tx}, events.writes)
+}
+
+// Only the owner may take an event out of public view, even into a status an
+// editor may otherwise move events to.
+func TestEventService_TransitionWithdraw(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      content.Status
+		role        content.Role
+		expectedErr error
+	}{
+		{
+			name:        "editor withdraws postponed",
+			status:      content.StatusPostponed,
+			role:        content.RoleEditor,
+			expectedErr: content.ErrPermissionDenied,
+		},
+		{
+			name:        "editor drafts archived",
+			status:      content.StatusArchived,
+			role:        content.RoleEditor,
+			expectedErr: nil,
+		},
+		{
+			name:        "owner withdraws postponed",
+			status:      content.StatusPostponed,
+			role:        content.RoleOwner,
+			expectedErr: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			events := &mockEvents{
+				events: map[int]content.Event{
+					1: {ID: 1, Title: "Foo", Status: tt.status},
+				},
+			}
+
+			svc := EventService{
+				db:            mockDB{tx: mockTx{}},
+				newEventStore: events.newEventStore,
+				newAuditStore: newMockAuditStore,
+			}
+
+			ctx := WithUser(testContext(), &content.User{ID: 1, Role: tt.role})
+
+			err := svc.Transition(ctx, 1, content.StatusDraft, content.StatusChange{})
+
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+				require.Equal(t, tt.status, events.events[1].Status)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, content.StatusDraft, events.events[1].Status)
+			}
+		})
+	}
+}
+
+// Notes are written on their own, never through Update, which counts every
+// call as a revision of the event.
+func TestEventService_UpdateNotes(t *testing.T) {
+	events := &mockEvents{
+		events: map[int]content.Event{
+			1: {ID: 1, Title: "Foo", Status: content.StatusPublished},
+		},
+	}
+
+	tx := mockTx{}
+
+	svc := EventService{
+		db:            mockDB{tx: tx},
+		newEventStore: events.newEventStore,
+		newAuditStore: newMockAuditStore,
+	}
+
+	event, err := svc.UpdateNotes(testContext(), 1, "foo")
+	require.NoError(t, err)
+	require.Equal(t, "foo", *event.Notes)
+
+	require.Equal(t, "foo", *events.events[1].Notes)
+	require.Equal(t, []store.Executor{tx}, events.writes)
+}
+
+func TestEventService_GetPublished(t *testing.T) {
+	tests := []struct {
+		name        string
+		id          int
+		expectedErr error
+	}{
+		{
+			name:        "published",
+			id:          1,
+			expectedErr: nil,
+		},
+		{
+			name:        "draft",
+			id:          2,
+			expectedErr: content.ErrResourceNotFound,
+		},
+		{
+			name:        "archived",
+			id:          3,
+			expectedErr: content.ErrResourceNotFound,
+		},
+		{
+			name:        "not found",
+			id:          4,
+			expectedErr: content.ErrResourceNotFound,
+		},
+		{
+			name:        "cancelled",
+			id:          6,
+			expectedErr: nil,
+		},
+		{
+			name:        "postponed",
+			id:          7,
+			expectedErr: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			events := newPublicEvents()
+
+			svc := EventService{
+				db:            mockDB{},
+				newEventStore: events.newEventStore,
+			}
+
+			event, err := svc.GetPublished(testContext(), tt.id)
+
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.id, event.Event.ID)
+			}
+		})
+	}
+}
+
+func TestEventService_ListPublished(t *testing.T) {
+	events := newPublicEvents()
+
+	svc := EventService{
+		db:            mockDB{},
+		newEventStore: events.newEventStore,
+	}
+
+	draft := content.StatusDraft
+
+	page, err := svc.ListPublished(testContext(), model.EventQuery{Status: &draft})
+	require.NoError(t, err)
+
+	// The status filter of the query is overridden.
+	require.Len(t, events.queries, 1)
+	require.Nil(t, events.queries[0].Status)
+	require.True(t, events.queries[0].Public)
+
+	// Called off events stay listed, as in the calendar.
+	ids := make([]int, len(page.Items))
+	for i, event := range page.Items {
+		ids[i] = event.Event.ID
+	}
+	require.Equal(t, []int{1, 5, 6, 7}, ids)
+	require.Equal(t, content.StatusCancelled, page.Items[2].Event.Status)
+}
+
+// The calendar is bounded around the present.
+func TestEventService_Calendar(t *testing.T) {
+	events := &mockEvents{
+		events: map[int]content.Event{
+			1: {ID: 1, Title: "Foo", Status: content.StatusPublished},
+			2: {ID: 2, Title: "Bar", Status: content.StatusCancelled},
+		},
+	}
+
+	svc := EventService{
+		db:            mockDB{},
+		newEventStore: events.newEventStore,
+	}
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	calendar, err := svc.Calendar(testContext(), now)
+	require.NoError(t, err)
+	require.Len(t, calendar, 2)
+
+	require.Equal(t, [][2]time.Time{
+		{now.Add(-CalendarPast), now.Add(CalendarAhead)},
+	}, events.windows)
+}
+
+// newPublicEvents returns events in every status the public must not see, two
+// published events, and two events called off after being published. None of
+// them reference a venue or programme.
+func newPublicEvents() *mockEvents {
+	return &mockEvents{
+		events: map[int]content.Event{
+			1: {ID: 1, Title: "Foo", Status: content.StatusPublished},
+			2: {ID: 2, Title: "Bar", Status: content.StatusDraft},
+			3: {ID: 3, Title: "Baz", Status: content.StatusArchived},
+			5: {ID: 5, Title: "Qux", Status: content.StatusPublished},
+			6: {ID: 6, Title: "Quux", Status: content.StatusCancelled},
+			7: {ID: 7, Title: "Corge", Status: content.StatusPostponed},
+		},
+	}
+}
+
+// mockEvents is the state shared by the mockEventStores it creates, along with
+// the executors the writes went through.
+type mockEvents struct {
+	events       map[int]content.Event
+	transitions  []content.StatusTransition
+	queries      []model.EventQuery
+	windows      [][2]time.Time
+	writes       []store.Executor
+	setStatusErr error
+	recordErr    error
+}
+
+func (m *mockEvents) newEventStore(db store.Executor) EventStore {
+	return &mockEventStore{
+		mockEvents: m,
+		db:         db,
+	}
+}
+
+type mockEventStore struct {
+	*mockEvents
+	db store.Executor
+}
+
+func (s *mockEventStore) Get(
+	ctx context.Context,
+	id int,
+) (*content.Event, error) {
+	event, ok := s.events[id]
+	if !ok {
+		return nil, content.ErrResourceNotFound
+	}
+
+	return &event, nil
+}
+
+// List ignores the filters of the query, so that the service is tested for
+// filtering the events again.
+func (s *mockEventStore) List(
+	ctx context.Context,
+	q model.EventQuery,
+) (*model.Page[content.Event], error) {
+	s.queries = append(s.queries, q)
+
+	ids := slices.Sorted(maps.Keys(s.events))
+
+	events := make([]content.Event, len(ids))
+	for i, id := range ids {
+		events[i] = s.events[id]
+	}
+
+	return &model.Page[content.Event]{Items: events}, nil
+}
+
+func (s *mockEventStore) ListWithTimestamps(
+	ctx context.Context,
+	q model.EventQuery,
+) (*model.Page[model.EventWithTimestamps], error) {
+	panic("unexpected ListWithTimestamps call")
+}
+
+func (s *mockEventStore) Create(
+	ctx context.Context,
+	e content.Event,
+) (*content.Event, error) {
+	panic("unexpected Create call")
+}
+
+func (s *mockEventStore) Update(
+	ctx context.Context,
+	e content.Event,
+) (*content.Event, error) {
+	panic("unexpected Update call")
+}
+
+func (s *mockEventStore) UpdateNotes(
+	ctx context.Context,
+	id int,
+	notes *string,
+) error {
+	event, ok := s.events[id]
+	if !ok {
+		return content.ErrResourceNotFound
+	}
+
+	event.Notes = notes
+	s.events[id] = event
+
+	s.writes = append(s.writes, s.db)
+
+	return nil
+}
+
+func (s *mockEventStore) SetStatus(
+	ctx context.Context,
+	id int,
+	from content.Status,
+	to content.Status,
+	reason *string,
+	replacementID *int,
+) error {
+	if s.setStatusErr != nil {
+		return s.setStatusErr
+	}
+
+	event := s.events[id]
+	if event.Status != from {
+		return content.ErrResourceNotFound
+	}
+
+	event.Status = to
+	event.StatusReason = reason
+	event.ReplacementID = replacementID
+	s.events[id] = event
+
+	s.writes = append(s.writes, s.db)
+
+	return nil
+}
+
+func (s *mockEventStore) ListScheduled(
+	ctx context.Context,
+	before time.Time,
+) ([]int, error) {
+	panic("unexpected ListScheduled call")
+}
+
+func (s *mockEventStore) ListExpired(
+	ctx context.Context,
+	before time.Time,
+) ([]int, error) {
+	panic("unexpected ListExpired call")
+}
+
+func (s *mockEventStore) ListCalendar(
+	ctx context.Context,
+	from time.Time,
+	to time.Time,
+) ([]model.EventWithTimestamps, error) {
+	s.windows = append(s.windows, [2]time.Time{from, to})
+
+	var events []model.EventWithTimestamps
+	for _, id := range slices.Sorted(maps.Keys(s.events)) {
+		events = append(events, model.EventWithTimestamps{Event: s.events[id]})
+	}
+
+	return events, nil
+}
+
+func (s *mockEventStore) ListRecent(
+	ctx context.Context,
+	limit int,
+) ([]model.EventWithTimestamps, error) {
+	panic("unexpected ListRecent call")
+}
+
+func (s *mockEventStore) Unschedule(
+	ctx context.Context,
+	id int,
+) error {
+	panic("unexpected Unschedule call")
+}
+
+func (s *mockEventStore) RecordTransition(
+	ctx context.Context,
+	t content.StatusTransition,
+) (*content.StatusTransition, error) {
+	if s.recordErr != nil {
+		return nil, s.recordErr
+	}
+
+	s.transitions = append(s.transitions, t)
+	s.writes = append(s.writes, s.db)
+
+	return &t, nil
+}
+
+func (s *mockEventStore) ListTransitions(
+	ctx context.Context,
+	eventID int,
+) ([]content.StatusTransition, error) {
+	return s.transitions, nil
+}
+
+func (s *mockEventStore) Delete(
+	ctx context.Context,
+	id int,
+) error {
+	panic("unexpected Delete call")
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/adamkadda/arman/internal/cms/model"
+	"github.com/adamkadda/arman/internal/cms/store"
+	"github.com/adamkadda/arman/internal/content"
+	"github.com/adamkadda/arman/pkg/logging"
+	"github.com/adamkadda/arman/pkg/queue"
+)
+
+// JobService lets owners inspect the background job queue and retry
+// dead-lettered jobs. Jobs themselves are run by queue.Workers.
+type JobService struct {
+	db            DB
+	newJobStore   func(db store.Executor) JobStore
+	newAuditStore func(db store.Executor) AuditStore
+}
+
+func NewJobService(db DB) *JobService {
+	return &JobService{
+		db: db,
+		newJobStore: func(db store.Executor) JobStore {
+			return store.NewPostgresJobStore(db)
+		},
+		newAuditStore: newAuditStore,
+	}
+}
+
+type JobStore interface {
+	Get(ctx context.Context, id int64) (*queue.Job, error)
+	List(ctx context.Context, status *queue.Status, q model.ListQuery) (*model.Page[queue.Job], error)
+	Retry(ctx context.Context, id int64) error
+}
+
+// Get returns a Job by id.
+func (s *JobService) Get(
+	ctx context.Context,
+	id int64,
+) (*queue.Job, error) {
+	logger := logging.FromContext(ctx).With(
+		slog.String("operation", "job.get"),
+		slog.Int64("job_id", id),
+	)
+
+	logger.Info(
+		"get job",
+	)
+
+	if err := authorize(ctx, logger, "job.get"); err != nil {
+		return nil, err
+	}
+
+	jobStore := s.newJobStore(s.db)
+
+	job, err := jobStore.Get(ctx, id)
+	if err != nil {
+		logger.Error(
+			"get job failed",
+			slog.String("step", "job.get"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// List returns a page of Jobs in the passed status, or of every Job if status
+// is nil, most recent first. Only the page of the query is used.
+//
+// An invalid status or query is reported as content.ErrInvalidResource.
+func (s *JobService) List(
+	ctx context.Context,
+	status *queue.Status,
+	q model.ListQuery,
+) (*model.Page[queue.Job], error) {
+	logger := logging.FromContext(ctx).With(
+		slog.String("operation", "job.list"),
+		slog.Any("status", status),
+		listQueryAttr(q),
+	)
+
+	logger.Info(
+		"list jobs",
+	)
+
+	if err := authorize(ctx, logger, "job.list"); err != nil {
+		return nil, err
+	}
+
+	if status != nil {
+		if err := status.Validate(); err != nil {
+			logger.Warn(
+				"list jobs rejected",
+				slog.String("reason", reason(err)),
+			)
+
+			return nil, fmt.Errorf("%w: %s", content.ErrInvalidResource, err)
+		}
+	}
+
+	if err := q.Validate(); err != nil {
+		logger.Warn(
+			"list jobs rejected",
+			slog.String("reason", reason(err)),
+		)
+
+		return nil, fmt.Errorf("%w: %s", content.ErrInvalidResource, err)
+	}
+
+	jobStore := s.newJobStore(s.db)
+
+	page, err := jobStore.List(ctx, status, q)
+	if err != nil {
+		logger.Error(
+			"list jobs failed",
+			slog.String("step", "job.list"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// Retry moves a dead-lettered Job back into the queue with all of its attempts
+// restored. Jobs that are not dead are reported as queue.ErrNotDead.
+func (s *JobService) Retry(
+	ctx context.Context,
+	id int64,
+) error {
+	logger := logging.FromContext(ctx).With(
+		slog.String("operation", "job.retry"),
+		slog.Int64("job_id", id),
+	)
+
+	logger.Info(
+		"retry job",
+	)
+
+	if err := authorize(ctx, logger, "job.retry"); err != nil {
+		return err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		logger.Error(
+			"begin transaction failed",
+			slog.String("step", "tx.begin"),
+			slog.Any("error", err),
+		)
+
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	jobStore := s.newJobStore(tx)
+
+	before, err := jobStore.Get(ctx, id)
+	if err != nil {
+		logger.Error(
+			"get job failed",
+			slog.String("step", "job.get"),
+			slog.Any("error", err),
+		)
+
+		return err
+	}
+
+	err = jobStore.Retry(ctx, id)
+	if errors.Is(err, queue.ErrNotDead) {
+		logger.Warn(
+			"retry job rejected",
+			slog.String("reason", reason(err)),
+		)
+
+		return err
+	}
+	if err != nil {
+		logger.Error(
+			"retry job failed",
+			slog.String("step", "job.retry"),
+			slog.Any("error", err),
+		)
+
+		return err
+	}
+
+	after, err := jobStore.Get(ctx, id)
+	if err != nil {
+		logger.Error(
+			"get job failed",
+			slog.String("step", "job.get"),
+			slog.Any("error", err),
+		)
+
+		return err
+	}
+
+	err = recordChange(ctx, logger, s.newAuditStore(tx),
+		"job.retry", model.AuditJob, id,
+		before, after,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		logger.Error(
+			"commit transaction failed",
+			slog.String("step", "tx.commit"),
+			slog.Any("error", err),
+		)
+
+		return err
+	}
+
+	return nil
+}
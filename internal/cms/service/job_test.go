@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/adamkadda/arman/internal/cms/model"
+	"github.com/adamkadda/arman/internal/cms/store"
+	"github.com/adamkadda/arman/internal/content"
+	"github.com/adamkadda/arman/pkg/queue"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobService_List(t *testing.T) {
+	dead := queue.StatusDead
+	invalid := queue.Status("foo")
+
+	tests := []struct {
+		name        string
+		status      *queue.Status
+		query       model.ListQuery
+		storeErr    error
+		expectedErr error
+	}{
+		{
+			name:        "invalid status",
+			status:      &invalid,
+			query:       model.ListQuery{},
+			storeErr:    nil,
+			expectedErr: content.ErrInvalidResource,
+		},
+		{
+			name:        "invalid query",
+			status:      nil,
+			query:       model.ListQuery{Limit: model.MaxLimit + 1},
+			storeErr:    nil,
+			expectedErr: content.ErrInvalidResource,
+		},
+		{
+			name:        "store error",
+			status:      &dead,
+			query:       model.ListQuery{},
+			storeErr:    ErrFoo,
+			expectedErr: ErrFoo,
+		},
+		{
+			name:        "success",
+			status:      &dead,
+			query:       model.ListQuery{},
+			storeErr:    nil,
+			expectedErr: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			svc := JobService{
+				newJobStore: func(db store.Executor) JobStore {
+					return mockJobStore{
+						err: tt.storeErr,
+					}
+				},
+			}
+
+			page, err := svc.List(testContext(), tt.status, tt.query)
+
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+				require.NotNil(t, page)
+			}
+		})
+	}
+}
+
+func TestJobService_Retry(t *testing.T) {
+	tests := []struct {
+		name        string
+		owner       bool
+		storeErr    error
+		expectedErr error
+	}{
+		{
+			name:        "not owner",
+			owner:       false,
+			storeErr:    nil,
+			expectedErr: content.ErrPermissionDenied,
+		},
+		{
+			name:        "job not dead",
+			owner:       true,
+			storeErr:    queue.ErrNotDead,
+			expectedErr: queue.ErrNotDead,
+		},
+		{
+			name:        "job not found",
+			owner:       true,
+			storeErr:    content.ErrResourceNotFound,
+			expectedErr: content.ErrResourceNotFound,
+		},
+		{
+			name:        "success",
+			owner:       true,
+			storeErr:    nil,
+			expectedErr: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			svc := JobService{
+				db: mockDB{},
+				newJobStore: func(db store.Executor) JobStore {
+					return mockJobStore{
+						job:      &queue.Job{ID: 1, Status: queue.StatusDead},
+						retryErr: tt.storeErr,
+					}
+				},
+				newAuditStore: newMockAuditStore,
+			}
+
+			role := content.RoleEditor
+			if tt.owner {
+				role = content.RoleOwner
+			}
+
+			ctx := WithUser(testContext(), &content.User{ID: 1, Role: role})
+
+			err := svc.Retry(ctx, 1)
+
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+type mockJobStore struct {
+	job      *queue.Job
+	err      error
+	retryErr error
+}
+
+func (s mockJobStore) Get(
+	ctx context.Context,
+	id int64,
+) (*queue.Job, error) {
+	return s.job, s.err
+}
+
+func (s mockJobStore) List(
+	ctx context.Context,
+	status *queue.Status,
+	q model.ListQuery,
+) (*model.Page[queue.Job], error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return &model.Page[queue.Job]{}, nil
+}
+
+func (s mockJobStore) Retry(
+	ctx context.Context,
+	id int64,
+) error {
+	return s.retryErr
+}
@@ -0,0 +1,729 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+
+	"github.com/adamkadda/arman/internal/cms/model"
+	"github.com/adamkadda/arman/internal/cms/store"
+	"github.com/adamkadda/arman/internal/content"
+	"github.com/adamkadda/arman/pkg/blob"
+	"github.com/adamkadda/arman/pkg/logging"
+	"github.com/google/uuid"
+)
+
+// MediaService contains application logic for the media library. Metadata is
+// kept in the database, files are kept in a blob.Store.
+//
+// Stores are created via a constructor function to keep the service decoupled
+// from concrete store implementations and easy to unit test.
+type MediaService struct {
+	db            DB
+	blobs         blob.Store
+	maxSize       int64
+	newKey        func() string
+	newMediaStore func(db store.Executor) MediaStore
+	newAuditStore func(db store.Executor) AuditStore
+}
+
+// NewMediaService creates a MediaService using the default store constructor.
+// Uploads larger than maxSize bytes are rejected.
+func NewMediaService(db DB, blobs blob.Store, maxSize int64) *MediaService {
+	return &MediaService{
+		db:      db,
+		blobs:   blobs,
+		maxSize: maxSize,
+		newKey:  uuid.NewString,
+		newMediaStore: func(db store.Executor) MediaStore {
+			return store.NewPostgresMediaStore(db)
+		},
+		newAuditStore: newAuditStore,
+	}
+}
+
+type MediaStore interface {
+	Get(ctx context.Context, id int) (*content.Media, error)
+	GetWithDetails(ctx context.Context, id int) (*model.MediaWithDetails, error)
+	ListWithDetails(ctx context.Context, q model.ListQuery) (*model.Page[model.MediaWithDetails], error)
+	Create(ctx context.Context, m content.Media) (*content.Media, error)
+	Update(ctx context.Context, m content.Media) (*content.Media, error)
+	Delete(ctx context.Context, id int) error
+	ListByOwner(ctx context.Context, owner model.MediaOwner) ([]content.Media, error)
+	UpdateByOwner(ctx context.Context, owner model.MediaOwner, ids []int) ([]content.Media, error)
+}
+
+// Get returns a Media by id.
+func (s *MediaService) Get(
+	ctx context.Context,
+	id int,
+) (*model.MediaWithDetails, error) {
+	logger := logging.FromContext(ctx).With(
+		slog.String("operation", "media.get"),
+		slog.Int("media_id", id),
+	)
+
+	logger.Info(
+		"get media",
+	)
+
+	if err := authorize(ctx, logger, "media.get"); err != nil {
+		return nil, err
+	}
+
+	mediaStore := s.newMediaStore(s.db)
+
+	media, err := mediaStore.GetWithDetails(ctx, id)
+	if err != nil {
+		logger.Error(
+			"get media failed",
+			slog.String("step", "media.get_with_details"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
+	return media, nil
+}
+
+// Open returns a Media by id along with its file. The caller must close the
+// returned reader.
+func (s *MediaService) Open(
+	ctx context.Context,
+	id int,
+) (*content.Media, io.ReadCloser, error) {
+	logger := logging.FromContext(ctx).With(
+		slog.String("operation", "media.open"),
+		slog.Int("media_id", id),
+	)
+
+	logger.Info(
+		"open media",
+	)
+
+	if err := authorize(ctx, logger, "media.get"); err != nil {
+		return nil, nil, err
+	}
+
+	mediaStore := s.newMediaStore(s.db)
+
+	media, err := mediaStore.Get(ctx, id)
+	if err != nil {
+		logger.Error(
+			"get media failed",
+			slog.String("step", "media.get"),
+			slog.Any("error", err),
+		)
+
+		return nil, nil, err
+	}
+
+	file, err := s.blobs.Get(ctx, media.StorageKey)
+	if err != nil {
+		logger.Error(
+			"open media file failed",
+			slog.String("step", "blob.get"),
+			slog.String("storage_key", media.StorageKey),
+			slog.Any("error", err),
+		)
+
+		return nil, nil, err
+	}
+
+	return media, file, nil
+}
+
+// List returns a page of MediaWithDetails matching the passed query. See
+// model.MediaSortFields for the fields media may be sorted by.
+//
+// An invalid query is reported as content.ErrInvalidResource.
+func (s *MediaService) List(
+	ctx context.Context,
+	q model.ListQuery,
+) (*model.Page[model.MediaWithDetails], error) {
+	logger := logging.FromContext(ctx).With(
+		slog.String("operation", "media.list"),
+		listQueryAttr(q),
+	)
+
+	logger.Info(
+		"list media",
+	)
+
+	if err := authorize(ctx, logger, "media.list"); err != nil {
+		return nil, err
+	}
+
+	if err := q.Validate(model.MediaSortFields...); err != nil {
+		logger.Warn(
+			"list media rejected",
+			slog.String("reason", reason(err)),
+		)
+
+		return nil, fmt.Errorf("%w: %s", content.ErrInvalidResource, err)
+	}
+
+	mediaStore := s.newMediaStore(s.db)
+
+	page, err := mediaStore.ListWithDetails(ctx, q)
+	if err != nil {
+		logger.Error(
+			"list media failed",
+			slog.String("step", "media.list"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// Upload stores the file read from r and creates a Media describing it.
+//
+// The passed Media only needs a Filename, and optionally AltText and Credits.
+// The MIME type is sniffed from the file itself rather than trusted from the
+// client, and the size and checksum are computed while the file is streamed to
+// the blob.Store. Files larger than the configured maximum are rejected with
+// content.ErrMediaTooLarge.
+func (s *MediaService) Upload(
+	ctx context.Context,
+	m content.Media,
+	r io.Reader,
+) (*content.Media, error) {
+	logger := logging.FromContext(ctx).With(
+		slog.String("operation", "media.upload"),
+		slog.String("filename", m.Filename),
+	)
+
+	logger.Info(
+		"upload media",
+	)
+
+	if err := authorize(ctx, logger, "media.upload"); err != nil {
+		return nil, err
+	}
+
+	head := make([]byte, sniffLen)
+
+	n, err := io.ReadFull(r, head)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		logger.Error(
+			"read media failed",
+			slog.String("step", "media.read"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
+	head = head[:n]
+
+	// The size is only known once the whole file is stored, but the head tells
+	// whether the file is empty.
+	m.MIMEType, _, _ = mime.ParseMediaType(http.DetectContentType(head))
+	m.Size = int64(len(head))
+
+	if err := m.Validate(); err != nil {
+		logger.Warn(
+			"validate media rejected",
+			slog.String("reason", reason(err)),
+			slog.String("mime_type", m.MIMEType),
+		)
+
+		return nil, fmt.Errorf("%w: %s", content.ErrInvalidResource, err)
+	}
+
+	m.StorageKey = s.newKey() + m.Extension()
+
+	upload := &uploadReader{
+		r:    io.MultiReader(bytes.NewReader(head), r),
+		hash: sha256.New(),
+		max:  s.maxSize,
+	}
+
+	if err := s.blobs.Put(ctx, m.StorageKey, upload); err != nil {
+		if errors.Is(err, content.ErrMediaTooLarge) {
+			logger.Warn(
+				"upload media rejected",
+				slog.String("reason", reason(content.ErrMediaTooLarge)),
+				slog.Int64("max_size", s.maxSize),
+			)
+
+			return nil, content.ErrMediaTooLarge
+		}
+
+		logger.Error(
+			"store media file failed",
+			slog.String("step", "blob.put"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
+	m.Size = upload.size
+	m.Checksum = hex.EncodeToString(upload.hash.Sum(nil))
+
+	media, err := s.create(ctx, logger, m)
+	if err != nil {
+		s.removeFile(ctx, logger, m.StorageKey)
+
+		return nil, err
+	}
+
+	return media, nil
+}
+
+// sniffLen is the number of bytes http.DetectContentType considers.
+const sniffLen = 512
+
+// uploadReader counts and hashes the bytes of an upload as they are read. It
+// fails with content.ErrMediaTooLarge once more than max bytes were read, so
+// that the blob.Store discards the partially written file.
+type uploadReader struct {
+	r    io.Reader
+	hash hash.Hash
+	size int64
+	max  int64
+}
+
+func (u *uploadReader) Read(p []byte) (int, error) {
+	n, err := u.r.Read(p)
+
+	u.size += int64(n)
+	if u.size > u.max {
+		return 0, content.ErrMediaTooLarge
+	}
+
+	u.hash.Write(p[:n])
+
+	return n, err
+}
+
+// create creates a Media describing an uploaded file, and records it in the
+// audit log.
+func (s *MediaService) create(
+	ctx context.Context,
+	logger *slog.Logger,
+	m content.Media,
+) (*content.Media, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		logger.Error(
+			"begin transaction failed",
+			slog.String("step", "tx.begin"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	mediaStore := s.newMediaStore(tx)
+
+	media, err := mediaStore.Create(ctx, m)
+	if err != nil {
+		logger.Error(
+			"create media failed",
+			slog.String("step", "media.create"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
+	err = recordChange(ctx, logger, s.newAuditStore(tx),
+		"media.upload", model.AuditMedia, media.ID,
+		nil, media,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		logger.Error(
+			"commit transaction failed",
+			slog.String("step", "tx.commit"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
+	return media, nil
+}
+
+// Update updates the alt text and credits of a Media. Every other field of the
+// passed Media is ignored.
+func (s *MediaService) Update(
+	ctx context.Context,
+	m content.Media,
+) (*content.Media, error) {
+	logger := logging.FromContext(ctx).With(
+		slog.String("operation", "media.update"),
+		slog.Int("media_id", m.ID),
+	)
+
+	logger.Info(
+		"update media",
+	)
+
+	if err := authorize(ctx, logger, "media.update"); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		logger.Error(
+			"begin transaction failed",
+			slog.String("step", "tx.begin"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	mediaStore := s.newMediaStore(tx)
+
+	before, err := mediaStore.Get(ctx, m.ID)
+	if err != nil {
+		logger.Error(
+			"get media failed",
+			slog.String("step", "media.get"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
+	media, err := mediaStore.Update(ctx, m)
+	if err != nil {
+		logger.Error(
+			"update media failed",
+			slog.String("step", "media.update"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
+	err = recordChange(ctx, logger, s.newAuditStore(tx),
+		"media.update", model.AuditMedia, media.ID,
+		before, media,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		logger.Error(
+			"commit transaction failed",
+			slog.String("step", "tx.commit"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
+	return media, nil
+}
+
+// Delete attempts to delete a Media by id, along with its file.
+//
+// Media attached to at least one published Event is protected against
+// deletion.
+func (s *MediaService) Delete(
+	ctx context.Context,
+	id int,
+) error {
+	logger := logging.FromContext(ctx).With(
+		slog.String("operation", "media.delete"),
+		slog.Int("media_id", id),
+	)
+
+	logger.Info(
+		"delete media",
+	)
+
+	if err := authorize(ctx, logger, "media.delete"); err != nil {
+		return err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		logger.Error(
+			"begin transaction failed",
+			slog.String("step", "tx.begin"),
+			slog.Any("error", err),
+		)
+
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	mediaStore := s.newMediaStore(tx)
+
+	mediaWithDetails, err := mediaStore.GetWithDetails(ctx, id)
+	if err != nil {
+		logger.Error(
+			"get media with details failed",
+			slog.String("step", "media.get_with_details"),
+			slog.Any("error", err),
+		)
+
+		return err
+	}
+
+	if mediaWithDetails.EventCount > 0 {
+		logger.Warn(
+			"delete media blocked",
+			slog.String("reason", reason(content.ErrMediaProtected)),
+			slog.Int("event_count", mediaWithDetails.EventCount),
+		)
+
+		return content.ErrMediaProtected
+	}
+
+	err = mediaStore.Delete(ctx, id)
+	if err != nil {
+		logger.Error(
+			"delete media failed",
+			slog.String("step", "media.delete"),
+			slog.Any("error", err),
+		)
+
+		return err
+	}
+
+	err = recordChange(ctx, logger, s.newAuditStore(tx),
+		"media.delete", model.AuditMedia, id,
+		mediaWithDetails.Media, nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		logger.Error(
+			"commit transaction failed",
+			slog.String("step", "tx.commit"),
+			slog.Any("error", err),
+		)
+
+		return err
+	}
+
+	s.removeFile(ctx, logger, mediaWithDetails.Media.StorageKey)
+
+	return nil
+}
+
+// ListAttached returns the Media attached to the owner, in order.
+func (s *MediaService) ListAttached(
+	ctx context.Context,
+	owner model.MediaOwner,
+) ([]content.Media, error) {
+	logger := logging.FromContext(ctx).With(
+		slog.String("operation", "media.list_attached"),
+		mediaOwnerAttr(owner),
+	)
+
+	logger.Info(
+		"list attached media",
+	)
+
+	if err := authorize(ctx, logger, "media.list"); err != nil {
+		return nil, err
+	}
+
+	if err := owner.Validate(); err != nil {
+		logger.Warn(
+			"list attached media rejected",
+			slog.String("reason", reason(err)),
+		)
+
+		return nil, fmt.Errorf("%w: %s", content.ErrInvalidResource, err)
+	}
+
+	mediaStore := s.newMediaStore(s.db)
+
+	media, err := mediaStore.ListByOwner(ctx, owner)
+	if err != nil {
+		logger.Error(
+			"list attached media failed",
+			slog.String("step", "media.list_by_owner"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
+	return media, nil
+}
+
+// Attach replaces the Media attached to the owner with the Media identified by
+// ids, in order. Pass an empty slice to detach every Media.
+//
+// Like every other change to an Event, attaching Media to an Event requires it
+// to be mutable.
+func (s *MediaService) Attach(
+	ctx context.Context,
+	owner model.MediaOwner,
+	ids []int,
+) ([]content.Media, error) {
+	logger := logging.FromContext(ctx).With(
+		slog.String("operation", "media.attach"),
+		mediaOwnerAttr(owner),
+	)
+
+	logger.Info(
+		"attach media",
+	)
+
+	if err := authorize(ctx, logger, "media.attach"); err != nil {
+		return nil, err
+	}
+
+	if err := owner.Validate(); err != nil {
+		logger.Warn(
+			"attach media rejected",
+			slog.String("reason", reason(err)),
+		)
+
+		return nil, fmt.Errorf("%w: %s", content.ErrInvalidResource, err)
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		logger.Error(
+			"begin transaction failed",
+			slog.String("step", "tx.begin"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if owner.Type == model.MediaOwnerEvent {
+		eventStore := store.NewEventStore(tx)
+
+		event, err := eventStore.Get(ctx, owner.ID)
+		if err != nil {
+			logger.Error(
+				"get event failed",
+				slog.String("step", "event.get"),
+				slog.Any("error", err),
+			)
+
+			return nil, err
+		}
+
+		if err := event.Mutable(); err != nil {
+			logger.Warn(
+				"attach media blocked",
+				slog.String("reason", reason(err)),
+			)
+
+			return nil, err
+		}
+	}
+
+	mediaStore := s.newMediaStore(tx)
+
+	before, err := mediaStore.ListByOwner(ctx, owner)
+	if err != nil {
+		logger.Error(
+			"list attached media failed",
+			slog.String("step", "media.list_by_owner"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
+	media, err := mediaStore.UpdateByOwner(ctx, owner, ids)
+	if err != nil {
+		logger.Error(
+			"attach media failed",
+			slog.String("step", "media.update_by_owner"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
+	// The change is recorded against the owner, whose attached Media changed.
+	err = recordChange(ctx, logger, s.newAuditStore(tx),
+		"media.attach", model.AuditEntity(owner.Type), mediaOwnerID(owner),
+		before, media,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		logger.Error(
+			"commit transaction failed",
+			slog.String("step", "tx.commit"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
+	return media, nil
+}
+
+// removeFile deletes a file that is no longer referenced by any Media. Failing
+// to do so only leaves an orphaned file behind, so errors are logged rather
+// than returned.
+func (s *MediaService) removeFile(
+	ctx context.Context,
+	logger *slog.Logger,
+	key string,
+) {
+	if err := s.blobs.Delete(ctx, key); err != nil {
+		logger.Error(
+			"remove media file failed",
+			slog.String("step", "blob.delete"),
+			slog.String("storage_key", key),
+			slog.Any("error", err),
+		)
+	}
+}
+
+// mediaOwnerID returns the id of the owner, or its variant for biographies.
+func mediaOwnerID(o model.MediaOwner) any {
+	if o.Type == model.MediaOwnerBiography {
+		return o.Variant
+	}
+
+	return o.ID
+}
+
+// mediaOwnerAttr groups a model.MediaOwner for logging.
+func mediaOwnerAttr(o model.MediaOwner) slog.Attr {
+	if o.Type == model.MediaOwnerBiography {
+		return slog.Group("owner",
+			slog.String("type", string(o.Type)),
+			slog.String("variant", string(o.Variant)),
+		)
+	}
+
+	return slog.Group("owner",
+		slog.String("type", string(o.Type)),
+		slog.Int("id", o.ID),
+	)
+}
@@ -0,0 +1,328 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/adamkadda/arman/internal/cms/model"
+	"github.com/adamkadda/arman/internal/cms/store"
+	"github.com/adamkadda/arman/internal/content"
+	"github.com/adamkadda/arman/pkg/blob"
+	"github.com/stretchr/testify/require"
+)
+
+// png is the smallest prefix http.DetectContentType recognises as a PNG.
+var png = []byte("\x89PNG\x0D\x0A\x1A\x0A")
+
+func TestMediaService_Upload(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        []byte
+		storeErr    error
+		expectedErr error
+	}{
+		{
+			name:        "too large",
+			data:        append(bytes.Clone(png), bytes.Repeat([]byte{0}, 64)...),
+			storeErr:    nil,
+			expectedErr: content.ErrMediaTooLarge,
+		},
+		{
+			name:        "unsupported type",
+			data:        []byte("plain text"),
+			storeErr:    nil,
+			expectedErr: content.ErrInvalidResource,
+		},
+		{
+			name:        "store error",
+			data:        png,
+			storeErr:    ErrFoo,
+			expectedErr: ErrFoo,
+		},
+		{
+			name:        "success",
+			data:        png,
+			storeErr:    nil,
+			expectedErr: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			blobs := newMockBlobStore()
+
+			svc := MediaService{
+				db:      mockDB{},
+				blobs:   blobs,
+				maxSize: 32,
+				newKey:  func() string { return "foo" },
+				newMediaStore: func(db store.Executor) MediaStore {
+					return mockMediaStore{
+						err: tt.storeErr,
+					}
+				},
+				newAuditStore: newMockAuditStore,
+			}
+
+			media, err := svc.Upload(
+				testContext(),
+				content.Media{Filename: "foo.png"},
+				bytes.NewReader(tt.data),
+			)
+
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+				require.Empty(t, blobs.objects)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, "image/png", media.MIMEType)
+				require.Equal(t, int64(len(png)), media.Size)
+				require.Len(t, media.Checksum, 64)
+				require.Equal(t, "foo.png", media.StorageKey)
+				require.Equal(t, png, blobs.objects["foo.png"])
+			}
+		})
+	}
+}
+
+// Files are streamed past the head sniffed for their MIME type.
+func TestMediaService_UploadStreamed(t *testing.T) {
+	data := append(bytes.Clone(png), bytes.Repeat([]byte{0}, 1024)...)
+
+	blobs := newMockBlobStore()
+
+	svc := MediaService{
+		db:      mockDB{},
+		blobs:   blobs,
+		maxSize: 2048,
+		newKey:  func() string { return "foo" },
+		newMediaStore: func(db store.Executor) MediaStore {
+			return mockMediaStore{}
+		},
+		newAuditStore: newMockAuditStore,
+	}
+
+	media, err := svc.Upload(
+		testContext(),
+		content.Media{Filename: "foo.png"},
+		bytes.NewReader(data),
+	)
+	require.NoError(t, err)
+	require.Equal(t, int64(len(data)), media.Size)
+	require.Equal(t, data, blobs.objects["foo.png"])
+
+	svc.maxSize = 1024
+
+	_, err = svc.Upload(
+		testContext(),
+		content.Media{Filename: "foo.png"},
+		bytes.NewReader(data),
+	)
+	require.ErrorIs(t, err, content.ErrMediaTooLarge)
+}
+
+func TestMediaService_Delete(t *testing.T) {
+	tests := []struct {
+		name        string
+		eventCount  int
+		getErr      error
+		deleteErr   error
+		expectedErr error
+	}{
+		{
+			name:        "not found",
+			eventCount:  0,
+			getErr:      content.ErrResourceNotFound,
+			deleteErr:   nil,
+			expectedErr: content.ErrResourceNotFound,
+		},
+		{
+			name:        "attached to published event",
+			eventCount:  1,
+			getErr:      nil,
+			deleteErr:   nil,
+			expectedErr: content.ErrMediaProtected,
+		},
+		{
+			name:        "delete error",
+			eventCount:  0,
+			getErr:      nil,
+			deleteErr:   ErrDelete,
+			expectedErr: ErrDelete,
+		},
+		{
+			name:        "success",
+			eventCount:  0,
+			getErr:      nil,
+			deleteErr:   nil,
+			expectedErr: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			blobs := newMockBlobStore()
+			blobs.objects["foo.png"] = png
+
+			svc := MediaService{
+				db:    mockDB{},
+				blobs: blobs,
+				newMediaStore: func(db store.Executor) MediaStore {
+					return mockMediaStore{
+						detailedMedia: &model.MediaWithDetails{
+							Media: content.Media{
+								ID:         1,
+								StorageKey: "foo.png",
+							},
+							EventCount: tt.eventCount,
+						},
+						getErr:    tt.getErr,
+						deleteErr: tt.deleteErr,
+					}
+				},
+				newAuditStore: newMockAuditStore,
+			}
+
+			err := svc.Delete(testContext(), 1)
+
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+				require.Contains(t, blobs.objects, "foo.png")
+			} else {
+				require.NoError(t, err)
+				require.NotContains(t, blobs.objects, "foo.png")
+			}
+		})
+	}
+}
+
+type mockMediaStore struct {
+	detailedMedia *model.MediaWithDetails
+	getErr        error
+	deleteErr     error
+	err           error
+}
+
+func (s mockMediaStore) Get(
+	ctx context.Context,
+	id int,
+) (*content.Media, error) {
+	if s.getErr != nil {
+		return nil, s.getErr
+	}
+
+	return &s.detailedMedia.Media, nil
+}
+
+func (s mockMediaStore) GetWithDetails(
+	ctx context.Context,
+	id int,
+) (*model.MediaWithDetails, error) {
+	return s.detailedMedia, s.getErr
+}
+
+func (s mockMediaStore) ListWithDetails(
+	ctx context.Context,
+	q model.ListQuery,
+) (*model.Page[model.MediaWithDetails], error) {
+	return nil, s.err
+}
+
+func (s mockMediaStore) Create(
+	ctx context.Context,
+	m content.Media,
+) (*content.Media, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return &m, nil
+}
+
+func (s mockMediaStore) Update(
+	ctx context.Context,
+	m content.Media,
+) (*content.Media, error) {
+	return &m, s.err
+}
+
+func (s mockMediaStore) Delete(
+	ctx context.Context,
+	id int,
+) error {
+	return s.deleteErr
+}
+
+func (s mockMediaStore) ListByOwner(
+	ctx context.Context,
+	owner model.MediaOwner,
+) ([]content.Media, error) {
+	return nil, s.err
+}
+
+func (s mockMediaStore) UpdateByOwner(
+	ctx context.Context,
+	owner model.MediaOwner,
+	ids []int,
+) ([]content.Media, error) {
+	return nil, s.err
+}
+
+// mockBlobStore is an in-memory blob.Store.
+type mockBlobStore struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func newMockBlobStore() *mockBlobStore {
+	return &mockBlobStore{
+		objects: make(map[string][]byte),
+	}
+}
+
+func (s *mockBlobStore) Put(ctx context.Context, key string, r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.objects[key] = data
+
+	return nil
+}
+
+func (s *mockBlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, ok := s.objects[key]
+	if !ok {
+		return nil, blob.ErrNotFound
+	}
+
+	return io.NopCloser(strings.NewReader(string(data))), nil
+}
+
+func (s *mockBlobStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.objects[key]; !ok {
+		return blob.ErrNotFound
+	}
+
+	delete(s.objects, key)
+
+	return nil
+}
@@ -2,8 +2,10 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
 
 	"github.com/adamkadda/arman/internal/cms/model"
 	"github.com/adamkadda/arman/internal/cms/store"
@@ -15,6 +17,7 @@ type PieceService struct {
 	db               DB
 	newPieceStore    func(db store.Executor) PieceStore
 	newComposerStore func(db store.Executor) ComposerStore
+	newAuditStore    func(db store.Executor) AuditStore
 }
 
 func NewPieceService(db DB) *PieceService {
@@ -26,13 +29,15 @@ func NewPieceService(db DB) *PieceService {
 		newComposerStore: func(db store.Executor) ComposerStore {
 			return store.NewPostgresComposerStore(db)
 		},
+		newAuditStore: newAuditStore,
 	}
 }
 
 type PieceStore interface {
 	Get(ctx context.Context, id int) (*content.Piece, error)
+	GetByTitle(ctx context.Context, composerID int, title string) (*content.Piece, error)
 	GetWithDetails(ctx context.Context, id int) (*model.PieceWithDetails, error)
-	ListWithDetails(ctx context.Context) ([]model.PieceWithDetails, error)
+	ListWithDetails(ctx context.Context, q model.ListQuery) (*model.Page[model.PieceWithDetails], error)
 	Create(ctx context.Context, p content.Piece) (*content.Piece, error)
 	Update(ctx context.Context, p content.Piece) (*content.Piece, error)
 	Delete(ctx context.Context, id int) error
@@ -52,6 +57,10 @@ func (s *PieceService) Get(
 		"get piece",
 	)
 
+	if err := authorize(ctx, logger, "piece.get"); err != nil {
+		return nil, err
+	}
+
 	pieceStore := s.newPieceStore(s.db)
 
 	piece, err := pieceStore.Get(ctx, id)
@@ -68,32 +77,50 @@ func (s *PieceService) Get(
 	return piece, nil
 }
 
-// List returns an array of PieceWithDetails, sorted by id.
+// List returns a page of PieceWithDetails matching the passed query. See
+// model.PieceSortFields for the fields pieces may be sorted by.
+//
+// An invalid query is reported as content.ErrInvalidResource.
 func (s *PieceService) List(
 	ctx context.Context,
-) ([]model.PieceWithDetails, error) {
+	q model.ListQuery,
+) (*model.Page[model.PieceWithDetails], error) {
 	logger := logging.FromContext(ctx).With(
 		slog.String("operation", "piece.list"),
+		listQueryAttr(q),
 	)
 
 	logger.Info(
 		"list pieces",
 	)
 
+	if err := authorize(ctx, logger, "piece.list"); err != nil {
+		return nil, err
+	}
+
+	if err := q.Validate(model.PieceSortFields...); err != nil {
+		logger.Warn(
+			"list pieces rejected",
+			slog.String("reason", reason(err)),
+		)
+
+		return nil, fmt.Errorf("%w: %s", content.ErrInvalidResource, err)
+	}
+
 	pieceStore := s.newPieceStore(s.db)
 
-	pieceList, err := pieceStore.ListWithDetails(ctx)
+	page, err := pieceStore.ListWithDetails(ctx, q)
 	if err != nil {
 		logger.Error(
 			"list pieces failed",
-			slog.String("step", "piece.list_with_details"),
+			slog.String("step", "piece.list"),
 			slog.Any("error", err),
 		)
 
 		return nil, err
 	}
 
-	return pieceList, nil
+	return page, nil
 }
 
 // Create attempts to create a Piece.
@@ -113,6 +140,10 @@ func (s *PieceService) Create(
 		"create piece",
 	)
 
+	if err := authorize(ctx, logger, "piece.create"); err != nil {
+		return nil, err
+	}
+
 	tx, err := s.db.Begin(ctx)
 	if err != nil {
 		logger.Error(
@@ -143,8 +174,12 @@ func (s *PieceService) Create(
 		return nil, fmt.Errorf("%w: %s", content.ErrInvalidResource, err)
 	}
 
+	auditStore := s.newAuditStore(tx)
+
 	composerResolver := newComposerResolver(
 		s.newComposerStore(tx),
+		auditStore,
+		"piece.create",
 	)
 
 	composer, err := composerResolver.run(
@@ -170,6 +205,14 @@ func (s *PieceService) Create(
 		return nil, err
 	}
 
+	err = recordChange(ctx, logger, auditStore,
+		"piece.create", model.AuditPiece, piece.ID,
+		nil, piece,
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	if err = tx.Commit(ctx); err != nil {
 		logger.Error(
 			"commit transaction failed",
@@ -202,6 +245,10 @@ func (s *PieceService) Update(
 		"update piece",
 	)
 
+	if err := authorize(ctx, logger, "piece.update"); err != nil {
+		return nil, err
+	}
+
 	tx, err := s.db.Begin(ctx)
 	if err != nil {
 		logger.Error(
@@ -232,8 +279,12 @@ func (s *PieceService) Update(
 		return nil, fmt.Errorf("%w: %s", content.ErrInvalidResource, err)
 	}
 
+	auditStore := s.newAuditStore(tx)
+
 	composerResolver := newComposerResolver(
 		s.newComposerStore(tx),
+		auditStore,
+		"piece.update",
 	)
 
 	composer, err := composerResolver.run(
@@ -248,6 +299,17 @@ func (s *PieceService) Update(
 
 	pieceStore := s.newPieceStore(tx)
 
+	before, err := pieceStore.Get(ctx, cmd.Piece.Data.ID)
+	if err != nil {
+		logger.Error(
+			"get piece failed",
+			slog.String("step", "piece.get"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
 	piece, err := pieceStore.Update(ctx, cmd.Piece.Data)
 	if err != nil {
 		logger.Error(
@@ -259,6 +321,14 @@ func (s *PieceService) Update(
 		return nil, err
 	}
 
+	err = recordChange(ctx, logger, auditStore,
+		"piece.update", model.AuditPiece, piece.ID,
+		before, piece,
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	if err = tx.Commit(ctx); err != nil {
 		logger.Error(
 			"commit transaction failed",
@@ -289,7 +359,23 @@ func (s *PieceService) Delete(
 		"delete piece",
 	)
 
-	pieceStore := s.newPieceStore(s.db)
+	if err := authorize(ctx, logger, "piece.delete"); err != nil {
+		return err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		logger.Error(
+			"begin transaction failed",
+			slog.String("step", "tx.begin"),
+			slog.Any("error", err),
+		)
+
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	pieceStore := s.newPieceStore(tx)
 
 	pieceWithDetails, err := pieceStore.GetWithDetails(ctx, id)
 	if err != nil {
@@ -323,18 +409,44 @@ func (s *PieceService) Delete(
 		return err
 	}
 
+	err = recordChange(ctx, logger, s.newAuditStore(tx),
+		"piece.delete", model.AuditPiece, id,
+		pieceWithDetails.Piece, nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		logger.Error(
+			"commit transaction failed",
+			slog.String("step", "tx.commit"),
+			slog.Any("error", err),
+		)
+
+		return err
+	}
+
 	return nil
 }
 
+// pieceResolver resolves a PieceIntent. The Pieces it creates or updates are
+// recorded in the audit log as changed by operation.
 type pieceResolver struct {
 	pieceStore PieceStore
+	auditStore AuditStore
+	operation  string
 }
 
 func newPieceResolver(
 	pieceStore PieceStore,
+	auditStore AuditStore,
+	operation string,
 ) *pieceResolver {
 	return &pieceResolver{
 		pieceStore: pieceStore,
+		auditStore: auditStore,
+		operation:  operation,
 	}
 }
 
@@ -376,6 +488,14 @@ func (r *pieceResolver) run(
 			)
 			return nil, err
 		}
+
+		err = recordChange(ctx, logger, r.auditStore,
+			r.operation, model.AuditPiece, piece.ID,
+			nil, piece,
+		)
+		if err != nil {
+			return nil, err
+		}
 		return piece, nil
 
 	case model.OperationUpdate:
@@ -387,6 +507,17 @@ func (r *pieceResolver) run(
 			return nil, fmt.Errorf("%w: %s", content.ErrInvalidResource, err)
 		}
 
+		before, err := r.pieceStore.Get(ctx, intent.Data.ID)
+		if err != nil {
+			logger.Error(
+				"get piece failed",
+				slog.Int("piece_id", intent.Data.ID),
+				slog.String("step", "piece.get"),
+				slog.Any("error", err),
+			)
+			return nil, err
+		}
+
 		piece, err := r.pieceStore.Update(ctx, intent.Data)
 		if err != nil {
 			logger.Error(
@@ -397,6 +528,14 @@ func (r *pieceResolver) run(
 			)
 			return nil, err
 		}
+
+		err = recordChange(ctx, logger, r.auditStore,
+			r.operation, model.AuditPiece, piece.ID,
+			before, piece,
+		)
+		if err != nil {
+			return nil, err
+		}
 		return piece, nil
 
 	default:
@@ -407,3 +546,276 @@ func (r *pieceResolver) run(
 		return nil, model.ErrInvalidOperation
 	}
 }
+
+// Import creates Pieces in bulk, along with the Composers they need, in a
+// single transaction.
+//
+// Every row is resolved the way Create resolves a PieceCommand: the Composer
+// is selected by full name, or created when no Composer matches. Rows naming a
+// Piece the Composer already has are skipped, so that a file may be imported
+// again. Rows failing validation are reported with their reason code, and
+// cause the whole import to be rolled back. A dry run reports what an import
+// would do, and always rolls back.
+//
+// Failing to reach the database aborts the import with an error rather than a
+// report.
+func (s *PieceService) Import(
+	ctx context.Context,
+	rows []model.PieceImportRow,
+	dryRun bool,
+) (*model.PieceImportReport, error) {
+	logger := logging.FromContext(ctx).With(
+		slog.String("operation", "piece.import"),
+		slog.Int("rows", len(rows)),
+		slog.Bool("dry_run", dryRun),
+	)
+
+	logger.Info(
+		"import pieces",
+	)
+
+	if err := authorize(ctx, logger, "piece.import"); err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 || len(rows) > model.MaxImportRows {
+		logger.Warn(
+			"import pieces rejected",
+			slog.String("reason", reason(model.ErrInvalidImport)),
+		)
+
+		return nil, fmt.Errorf("%w: %s", content.ErrInvalidResource, model.ErrInvalidImport)
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		logger.Error(
+			"begin transaction failed",
+			slog.String("step", "tx.begin"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	importer := &pieceImporter{
+		composerStore: s.newComposerStore(tx),
+		pieceStore:    s.newPieceStore(tx),
+		auditStore:    s.newAuditStore(tx),
+		composers:     make(map[string]*content.Composer),
+	}
+
+	report := &model.PieceImportReport{
+		DryRun: dryRun,
+	}
+
+	for _, row := range rows {
+		result, err := importer.run(logging.WithLogger(ctx, logger), row)
+		if err != nil {
+			return nil, err
+		}
+
+		report.Add(*result)
+	}
+
+	if report.Failed > 0 {
+		logger.Warn(
+			"import pieces rejected",
+			slog.String("reason", reason(model.ErrInvalidImport)),
+			slog.Int("failed", report.Failed),
+		)
+
+		return report, nil
+	}
+
+	if dryRun {
+		return report, nil
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		logger.Error(
+			"commit transaction failed",
+			slog.String("step", "tx.commit"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
+	report.Committed = true
+
+	return report, nil
+}
+
+// pieceImporter imports the rows of a single import. Composers are cached by
+// full name, so that a Composer created by a row is selected by the next.
+type pieceImporter struct {
+	composerStore ComposerStore
+	pieceStore    PieceStore
+	auditStore    AuditStore
+	composers     map[string]*content.Composer
+}
+
+// run imports a row. Rows failing validation are reported as failed; an error
+// is only returned when a store fails.
+func (i *pieceImporter) run(
+	ctx context.Context,
+	row model.PieceImportRow,
+) (*model.PieceImportResult, error) {
+	logger := logging.FromContext(ctx).With(
+		slog.Int("line", row.Line),
+	)
+
+	result := &model.PieceImportResult{
+		Line: row.Line,
+	}
+
+	fail := func(err error) (*model.PieceImportResult, error) {
+		logger.Warn(
+			"import row rejected",
+			slog.String("reason", reason(err)),
+		)
+
+		result.Status = model.ImportFailed
+		result.Reason = reason(err)
+
+		return result, nil
+	}
+
+	cmd := model.PieceCommand{
+		Piece: model.PieceIntent{
+			Operation: model.OperationCreate,
+			Data: content.Piece{
+				Title: row.Title,
+			},
+		},
+	}
+
+	if err := cmd.Piece.Data.Validate(); err != nil {
+		return fail(err)
+	}
+
+	intent, err := i.composerIntent(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+
+	// The resolver reports invalid Composers without their reason, so they are
+	// validated here first.
+	if intent.Operation == model.OperationCreate {
+		if err := intent.Data.Validate(); err != nil {
+			return fail(err)
+		}
+	}
+
+	cmd.Composer = intent
+
+	composerResolver := newComposerResolver(
+		i.composerStore,
+		i.auditStore,
+		"piece.import",
+	)
+
+	composer, err := composerResolver.run(
+		logging.WithLogger(ctx, logger),
+		cmd.Composer,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	i.composers[strings.ToLower(composer.FullName)] = composer
+
+	result.ComposerID = composer.ID
+	result.ComposerCreated = cmd.Composer.Operation == model.OperationCreate
+
+	existing, err := i.pieceStore.GetByTitle(ctx, composer.ID, cmd.Piece.Data.Title)
+	switch {
+	case err == nil:
+		result.Status = model.ImportSkipped
+		result.Reason = reason(content.ErrPieceExists)
+		result.PieceID = existing.ID
+
+		return result, nil
+	case !errors.Is(err, content.ErrResourceNotFound):
+		logger.Error(
+			"get piece failed",
+			slog.String("step", "piece.get_by_title"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
+	cmd.Piece.Data.ComposerID = composer.ID
+
+	piece, err := i.pieceStore.Create(ctx, cmd.Piece.Data)
+	if err != nil {
+		logger.Error(
+			"create piece failed",
+			slog.String("step", "piece.create"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
+	err = recordChange(ctx, logger, i.auditStore,
+		"piece.import", model.AuditPiece, piece.ID,
+		nil, piece,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Status = model.ImportCreated
+	result.PieceID = piece.ID
+
+	return result, nil
+}
+
+// composerIntent selects the Composer named by a row, or creates it when no
+// Composer has that full name.
+func (i *pieceImporter) composerIntent(
+	ctx context.Context,
+	row model.PieceImportRow,
+) (model.ComposerIntent, error) {
+	if composer, ok := i.composers[strings.ToLower(row.Composer)]; ok {
+		return model.ComposerIntent{
+			Operation: model.OperationSelect,
+			Data:      *composer,
+		}, nil
+	}
+
+	create := model.ComposerIntent{
+		Operation: model.OperationCreate,
+		Data: content.Composer{
+			FullName:  row.Composer,
+			ShortName: row.ComposerShortName,
+		},
+	}
+
+	if row.Composer == "" {
+		return create, nil
+	}
+
+	composer, err := i.composerStore.GetByFullName(ctx, row.Composer)
+	switch {
+	case errors.Is(err, content.ErrResourceNotFound):
+		return create, nil
+	case err != nil:
+		logging.FromContext(ctx).Error(
+			"get composer failed",
+			slog.String("step", "composer.get_by_full_name"),
+			slog.Any("error", err),
+		)
+
+		return model.ComposerIntent{}, err
+	}
+
+	return model.ComposerIntent{
+		Operation: model.OperationSelect,
+		Data:      *composer,
+	}, nil
+}
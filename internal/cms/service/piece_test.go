@@ -60,9 +60,16 @@ func TestPieceService_Get(t *testing.T) {
 func TestPieceService_List(t *testing.T) {
 	tests := []struct {
 		name           string
+		query          model.ListQuery
 		expectedPieces []model.PieceWithDetails
 		expectedErr    error
 	}{
+		{
+			name:           "invalid query",
+			query:          model.ListQuery{Sort: "foo"},
+			expectedPieces: nil,
+			expectedErr:    content.ErrInvalidResource,
+		},
 		{
 			name:           "store error",
 			expectedPieces: nil,
@@ -113,13 +120,13 @@ func TestPieceService_List(t *testing.T) {
 				},
 			}
 
-			pieces, err := svc.List(testContext())
+			page, err := svc.List(testContext(), tt.query)
 
 			if tt.expectedErr != nil {
 				require.ErrorIs(t, err, tt.expectedErr)
 			} else {
 				require.NoError(t, err)
-				require.Equal(t, tt.expectedPieces, pieces)
+				require.Equal(t, tt.expectedPieces, page.Items)
 			}
 		})
 	}
@@ -258,7 +265,11 @@ func TestPieceService_Create(t *testing.T) {
 					},
 				},
 			},
-			expectedPiece:    nil,
+			expectedPiece: &content.Piece{
+				ID:         1,
+				Title:      "Foo Sonata",
+				ComposerID: 1,
+			},
 			beginErr:         nil,
 			commitErr:        ErrTxCommit,
 			pieceStoreErr:    nil,
@@ -318,6 +329,7 @@ func TestPieceService_Create(t *testing.T) {
 						err:      tt.composerStoreErr,
 					}
 				},
+				newAuditStore: newMockAuditStore,
 			}
 
 			piece, err := svc.Create(testContext(), tt.cmd)
@@ -469,7 +481,11 @@ func TestPieceService_Update(t *testing.T) {
 					},
 				},
 			},
-			expectedPiece:    nil,
+			expectedPiece: &content.Piece{
+				ID:         1,
+				Title:      "Foo Sonata",
+				ComposerID: 1,
+			},
 			beginErr:         nil,
 			commitErr:        ErrTxCommit,
 			pieceStoreErr:    nil,
@@ -530,6 +546,7 @@ func TestPieceService_Update(t *testing.T) {
 						err:      tt.composerStoreErr,
 					}
 				},
+				newAuditStore: newMockAuditStore,
 			}
 
 			piece, err := svc.Update(testContext(), tt.cmd)
@@ -596,6 +613,7 @@ func TestPieceService_Delete(t *testing.T) {
 			t.Parallel()
 
 			svc := PieceService{
+				db: mockDB{},
 				newPieceStore: func(db store.Executor) PieceStore {
 					return mockPieceStore{
 						detailedPiece: tt.piece,
@@ -603,6 +621,7 @@ func TestPieceService_Delete(t *testing.T) {
 						deleteErr:     tt.deleteErr,
 					}
 				},
+				newAuditStore: newMockAuditStore,
 			}
 
 			err := svc.Delete(testContext(), 1)
@@ -616,6 +635,153 @@ func TestPieceService_Delete(t *testing.T) {
 	}
 }
 
+func TestPieceService_Import(t *testing.T) {
+	bach := &content.Composer{
+		ID:        1,
+		FullName:  "Johann Sebastian Bach",
+		ShortName: "Bach",
+	}
+
+	tests := []struct {
+		name           string
+		rows           []model.PieceImportRow
+		dryRun         bool
+		namedComposer  *content.Composer
+		nameErr        error
+		titledPiece    *content.Piece
+		titleErr       error
+		commitErr      error
+		expectedReport *model.PieceImportReport
+		expectedErr    error
+	}{
+		{
+			name:        "no rows",
+			rows:        nil,
+			expectedErr: content.ErrInvalidResource,
+		},
+		{
+			name: "composer lookup failed",
+			rows: []model.PieceImportRow{
+				{Line: 2, Composer: "Johann Sebastian Bach", Title: "Partita No. 2"},
+			},
+			nameErr:     ErrGet,
+			expectedErr: ErrGet,
+		},
+		{
+			name: "invalid rows",
+			rows: []model.PieceImportRow{
+				{Line: 2, Composer: "Johann Sebastian Bach", Title: ""},
+				{Line: 3, Composer: "Frédéric Chopin", Title: "Ballade No. 1"},
+			},
+			nameErr:  content.ErrResourceNotFound,
+			titleErr: content.ErrResourceNotFound,
+			expectedReport: &model.PieceImportReport{
+				Failed: 2,
+				Rows: []model.PieceImportResult{
+					{Line: 2, Status: model.ImportFailed, Reason: "piece_title_empty"},
+					{Line: 3, Status: model.ImportFailed, Reason: "composer_short_name_empty"},
+				},
+			},
+		},
+		{
+			name: "existing piece skipped",
+			rows: []model.PieceImportRow{
+				{Line: 2, Composer: "johann sebastian bach", Title: "Partita No. 2"},
+			},
+			namedComposer: bach,
+			titledPiece:   &content.Piece{ID: 7, Title: "Partita No. 2", ComposerID: 1},
+			expectedReport: &model.PieceImportReport{
+				Committed: true,
+				Skipped:   1,
+				Rows: []model.PieceImportResult{
+					{Line: 2, Status: model.ImportSkipped, Reason: "piece_exists", PieceID: 7, ComposerID: 1},
+				},
+			},
+		},
+		{
+			name: "dry run",
+			rows: []model.PieceImportRow{
+				{Line: 2, Composer: "Johann Sebastian Bach", Title: "Partita No. 2"},
+			},
+			dryRun:        true,
+			namedComposer: bach,
+			titleErr:      content.ErrResourceNotFound,
+			commitErr:     ErrTxCommit,
+			expectedReport: &model.PieceImportReport{
+				DryRun:  true,
+				Created: 1,
+				Rows: []model.PieceImportResult{
+					{Line: 2, Status: model.ImportCreated, PieceID: 1, ComposerID: 1},
+				},
+			},
+		},
+		{
+			name: "commit tx failed",
+			rows: []model.PieceImportRow{
+				{Line: 2, Composer: "Johann Sebastian Bach", Title: "Partita No. 2"},
+			},
+			namedComposer: bach,
+			titleErr:      content.ErrResourceNotFound,
+			commitErr:     ErrTxCommit,
+			expectedErr:   ErrTxCommit,
+		},
+		{
+			name: "success",
+			rows: []model.PieceImportRow{
+				{Line: 2, Composer: "Johann Sebastian Bach", ComposerShortName: "Bach", Title: "Partita No. 2"},
+			},
+			nameErr:  content.ErrResourceNotFound,
+			titleErr: content.ErrResourceNotFound,
+			expectedReport: &model.PieceImportReport{
+				Committed:        true,
+				Created:          1,
+				ComposersCreated: 1,
+				Rows: []model.PieceImportResult{
+					{Line: 2, Status: model.ImportCreated, PieceID: 1, ComposerID: 1, ComposerCreated: true},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			svc := PieceService{
+				db: mockDB{
+					tx: mockTx{
+						err: tt.commitErr,
+					},
+				},
+				newPieceStore: func(db store.Executor) PieceStore {
+					return mockPieceStore{
+						piece:       &content.Piece{ID: 1, Title: "Partita No. 2", ComposerID: 1},
+						titledPiece: tt.titledPiece,
+						titleErr:    tt.titleErr,
+					}
+				},
+				newComposerStore: func(db store.Executor) ComposerStore {
+					return mockComposerStore{
+						composer:      bach,
+						namedComposer: tt.namedComposer,
+						nameErr:       tt.nameErr,
+					}
+				},
+				newAuditStore: newMockAuditStore,
+			}
+
+			report, err := svc.Import(testContext(), tt.rows, tt.dryRun)
+
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.expectedReport, report)
+			}
+		})
+	}
+}
+
 func TestPieceResolver_Run(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -675,7 +841,11 @@ func TestPieceResolver_Run(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			resolver := newPieceResolver(mockPieceStore{})
+			resolver := newPieceResolver(
+				mockPieceStore{piece: &tt.intent.Data},
+				&mockAuditStore{},
+				"piece.test",
+			)
 
 			_, err := resolver.run(testContext(), tt.intent)
 
@@ -690,6 +860,8 @@ func TestPieceResolver_Run(t *testing.T) {
 
 type mockPieceStore struct {
 	piece          *content.Piece
+	titledPiece    *content.Piece
+	titleErr       error
 	detailedPieces []model.PieceWithDetails
 	detailedPiece  *model.PieceWithDetails
 	err            error
@@ -704,6 +876,14 @@ func (s mockPieceStore) Get(
 	return s.piece, s.err
 }
 
+func (s mockPieceStore) GetByTitle(
+	ctx context.Context,
+	composerID int,
+	title string,
+) (*content.Piece, error) {
+	return s.titledPiece, s.titleErr
+}
+
 func (s mockPieceStore) GetWithDetails(
 	ctx context.Context,
 	id int,
@@ -713,8 +893,13 @@ func (s mockPieceStore) GetWithDetails(
 
 func (s mockPieceStore) ListWithDetails(
 	ctx context.Context,
-) ([]model.PieceWithDetails, error) {
-	return s.detailedPieces, s.err
+	q model.ListQuery,
+) (*model.Page[model.PieceWithDetails], error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return model.NewPage(q, s.detailedPieces, len(s.detailedPieces)), nil
 }
 
 func (s mockPieceStore) Create(
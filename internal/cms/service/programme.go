@@ -12,12 +12,14 @@ import (
 )
 
 type ProgrammeService struct {
-	db DB
+	db            DB
+	newAuditStore func(db store.Executor) AuditStore
 }
 
 func NewProgrammeService(db DB) *ProgrammeService {
 	return &ProgrammeService{
-		db: db,
+		db:            db,
+		newAuditStore: newAuditStore,
 	}
 }
 
@@ -35,6 +37,10 @@ func (s *ProgrammeService) Get(
 		"get programme",
 	)
 
+	if err := authorize(ctx, logger, "programme.get"); err != nil {
+		return nil, err
+	}
+
 	programmeStore := store.NewProgrammeStore(s.db)
 
 	p, err := programmeStore.Get(ctx, id)
@@ -69,21 +75,39 @@ func (s *ProgrammeService) Get(
 	return programme, nil
 }
 
-// List returns an array of ProgrammeWithDetails, sorted by id.
+// List returns a page of ProgrammeWithDetails matching the passed query. See
+// model.ProgrammeSortFields for the fields programmes may be sorted by.
+//
+// An invalid query is reported as content.ErrInvalidResource.
 func (s *ProgrammeService) List(
 	ctx context.Context,
-) ([]model.ProgrammeWithDetails, error) {
+	q model.ListQuery,
+) (*model.Page[model.ProgrammeWithDetails], error) {
 	logger := logging.FromContext(ctx).With(
 		slog.String("operation", "programme.list"),
+		listQueryAttr(q),
 	)
 
 	logger.Info(
 		"list programmes",
 	)
 
+	if err := authorize(ctx, logger, "programme.list"); err != nil {
+		return nil, err
+	}
+
+	if err := q.Validate(model.ProgrammeSortFields...); err != nil {
+		logger.Warn(
+			"list programmes rejected",
+			slog.String("reason", reason(err)),
+		)
+
+		return nil, fmt.Errorf("%w: %s", content.ErrInvalidResource, err)
+	}
+
 	programmeStore := store.NewProgrammeStore(s.db)
 
-	programmeList, err := programmeStore.ListWithDetails(ctx)
+	page, err := programmeStore.ListWithDetails(ctx, q)
 	if err != nil {
 		logger.Error(
 			"list programmes failed",
@@ -94,7 +118,7 @@ func (s *ProgrammeService) List(
 		return nil, err
 	}
 
-	return programmeList, nil
+	return page, nil
 }
 
 // Create attempts to create a Programme.
@@ -114,7 +138,9 @@ func (s *ProgrammeService) Create(
 		"create programme",
 	)
 
-	programmeStore := store.NewProgrammeStore(s.db)
+	if err := authorize(ctx, logger, "programme.create"); err != nil {
+		return nil, err
+	}
 
 	if err := p.Validate(); err != nil {
 		logger.Warn(
@@ -125,6 +151,20 @@ func (s *ProgrammeService) Create(
 		return nil, fmt.Errorf("%w: %s", content.ErrInvalidResource, err)
 	}
 
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		logger.Error(
+			"begin transaction failed",
+			slog.String("step", "tx.begin"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	programmeStore := store.NewProgrammeStore(tx)
+
 	programme, err := programmeStore.Create(ctx, p)
 	if err != nil {
 		logger.Error(
@@ -136,6 +176,24 @@ func (s *ProgrammeService) Create(
 		return nil, err
 	}
 
+	err = recordChange(ctx, logger, s.newAuditStore(tx),
+		"programme.create", model.AuditProgramme, programme.ID,
+		nil, programme,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		logger.Error(
+			"commit transaction failed",
+			slog.String("step", "tx.commit"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
 	return programme, nil
 }
 
@@ -163,6 +221,10 @@ func (s *ProgrammeService) Update(
 		"update programme",
 	)
 
+	if err := authorize(ctx, logger, "programme.update"); err != nil {
+		return nil, err
+	}
+
 	tx, err := s.db.Begin(ctx)
 	if err != nil {
 		logger.Error(
@@ -184,7 +246,7 @@ func (s *ProgrammeService) Update(
 		return nil, fmt.Errorf("%w: %s", content.ErrInvalidResource, err)
 	}
 
-	programmeStore := store.NewProgrammeStore(s.db)
+	programmeStore := store.NewProgrammeStore(tx)
 
 	programmeWithDetails, err := programmeStore.GetWithDetails(ctx, p.ID)
 	if err != nil {
@@ -218,6 +280,14 @@ func (s *ProgrammeService) Update(
 		return nil, err
 	}
 
+	err = recordChange(ctx, logger, s.newAuditStore(tx),
+		"programme.update", model.AuditProgramme, programme.ID,
+		programmeWithDetails.Programme, programme,
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	if err = tx.Commit(ctx); err != nil {
 		logger.Error(
 			"commit transaction failed",
@@ -256,6 +326,10 @@ func (s *ProgrammeService) UpdatePieces(
 		"update programme pieces",
 	)
 
+	if err := authorize(ctx, logger, "programme.update_pieces"); err != nil {
+		return nil, err
+	}
+
 	tx, err := s.db.Begin(ctx)
 	if err != nil {
 		logger.Error(
@@ -304,6 +378,17 @@ func (s *ProgrammeService) UpdatePieces(
 
 	programmePieceStore := store.NewProgrammePieceStore(tx)
 
+	previous, err := programmePieceStore.ListByProgrammeID(ctx, id)
+	if err != nil {
+		logger.Error(
+			"list programme pieces failed",
+			slog.String("step", "programme_piece.list"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
 	pp, err := programmePieceStore.Update(ctx, id, ids)
 	if err != nil {
 		logger.Error(
@@ -315,6 +400,24 @@ func (s *ProgrammeService) UpdatePieces(
 		return nil, err
 	}
 
+	before := &model.ProgrammeWithPieces{
+		Programme: p,
+		Pieces:    previous,
+	}
+
+	programme := &model.ProgrammeWithPieces{
+		Programme: p,
+		Pieces:    pp,
+	}
+
+	err = recordChange(ctx, logger, s.newAuditStore(tx),
+		"programme.update_pieces", model.AuditProgramme, id,
+		before, programme,
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	if err = tx.Commit(ctx); err != nil {
 		logger.Error(
 			"commit transaction failed",
@@ -325,11 +428,6 @@ func (s *ProgrammeService) UpdatePieces(
 		return nil, err
 	}
 
-	programme := &model.ProgrammeWithPieces{
-		Programme: p,
-		Pieces:    pp,
-	}
-
 	return programme, nil
 }
 
@@ -350,7 +448,23 @@ func (s *ProgrammeService) Delete(
 		"delete programme",
 	)
 
-	programmeStore := store.NewProgrammeStore(s.db)
+	if err := authorize(ctx, logger, "programme.delete"); err != nil {
+		return err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		logger.Error(
+			"begin transaction failed",
+			slog.String("step", "tx.begin"),
+			slog.Any("error", err),
+		)
+
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	programmeStore := store.NewProgrammeStore(tx)
 
 	programmeWithDetails, err := programmeStore.GetWithDetails(ctx, id)
 	if err != nil {
@@ -384,5 +498,23 @@ func (s *ProgrammeService) Delete(
 		return err
 	}
 
+	err = recordChange(ctx, logger, s.newAuditStore(tx),
+		"programme.delete", model.AuditProgramme, id,
+		programmeWithDetails.Programme, nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		logger.Error(
+			"commit transaction failed",
+			slog.String("step", "tx.commit"),
+			slog.Any("error", err),
+		)
+
+		return err
+	}
+
 	return nil
 }
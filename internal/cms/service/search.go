@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/adamkadda/arman/internal/cms/model"
+	"github.com/adamkadda/arman/internal/cms/store"
+	"github.com/adamkadda/arman/internal/content"
+	"github.com/adamkadda/arman/pkg/logging"
+)
+
+// SearchService contains application logic for searching across resources.
+type SearchService struct {
+	db             DB
+	newSearchStore func(db store.Executor) SearchStore
+}
+
+// NewSearchService creates a SearchService using the default store constructor.
+func NewSearchService(db DB) *SearchService {
+	return &SearchService{
+		db: db,
+		newSearchStore: func(db store.Executor) SearchStore {
+			return store.NewPostgresSearchStore(db)
+		},
+	}
+}
+
+type SearchStore interface {
+	SearchComposers(ctx context.Context, q model.SearchQuery) ([]model.ComposerWithDetails, error)
+	SearchPieces(ctx context.Context, q model.SearchQuery) ([]model.PieceWithDetails, error)
+	SearchProgrammes(ctx context.Context, q model.SearchQuery) ([]model.ProgrammeWithDetails, error)
+	SearchVenues(ctx context.Context, q model.SearchQuery) ([]model.VenueWithDetails, error)
+	SearchEvents(ctx context.Context, q model.SearchQuery) ([]content.Event, error)
+}
+
+// Search returns the composers, pieces, programmes, venues and events matching
+// the search text, grouped by resource type and ordered by relevance. Events
+// of every status are searched.
+//
+// An invalid query is reported as content.ErrInvalidResource.
+func (s *SearchService) Search(
+	ctx context.Context,
+	q model.SearchQuery,
+) (*model.SearchResults, error) {
+	logger := logging.FromContext(ctx).With(
+		slog.String("operation", "search"),
+		slog.String("text", q.Text),
+		slog.Int("limit", q.Limit),
+	)
+
+	logger.Info(
+		"search",
+	)
+
+	if err := authorize(ctx, logger, "search"); err != nil {
+		return nil, err
+	}
+
+	if err := q.Validate(); err != nil {
+		logger.Warn(
+			"search rejected",
+			slog.String("reason", reason(err)),
+		)
+
+		return nil, fmt.Errorf("%w: %s", content.ErrInvalidResource, err)
+	}
+
+	searchStore := s.newSearchStore(s.db)
+
+	var results model.SearchResults
+	var err error
+
+	results.Composers, err = searchStore.SearchComposers(ctx, q)
+	if err != nil {
+		logger.Error(
+			"search failed",
+			slog.String("step", "search.composers"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
+	results.Pieces, err = searchStore.SearchPieces(ctx, q)
+	if err != nil {
+		logger.Error(
+			"search failed",
+			slog.String("step", "search.pieces"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
+	results.Programmes, err = searchStore.SearchProgrammes(ctx, q)
+	if err != nil {
+		logger.Error(
+			"search failed",
+			slog.String("step", "search.programmes"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
+	results.Venues, err = searchStore.SearchVenues(ctx, q)
+	if err != nil {
+		logger.Error(
+			"search failed",
+			slog.String("step", "search.venues"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
+	results.Events, err = searchStore.SearchEvents(ctx, q)
+	if err != nil {
+		logger.Error(
+			"search failed",
+			slog.String("step", "search.events"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
+	return &results, nil
+}
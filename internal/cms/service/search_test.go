@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/adamkadda/arman/internal/cms/model"
+	"github.com/adamkadda/arman/internal/cms/store"
+	"github.com/adamkadda/arman/internal/content"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchService_Search(t *testing.T) {
+	results := &model.SearchResults{
+		Composers: []model.ComposerWithDetails{
+			{
+				Composer: content.Composer{
+					ID:        1,
+					FullName:  "Antonín Dvořák",
+					ShortName: "Dvořák",
+				},
+				PieceCount: 2,
+			},
+		},
+		Pieces: []model.PieceWithDetails{},
+		Programmes: []model.ProgrammeWithDetails{
+			{
+				Programme: content.Programme{
+					ID:    1,
+					Title: "Dvořák and Friends",
+				},
+				EventCount: 1,
+			},
+		},
+		Venues: []model.VenueWithDetails{},
+		Events: []content.Event{},
+	}
+
+	tests := []struct {
+		name        string
+		query       model.SearchQuery
+		storeErr    error
+		expectedErr error
+	}{
+		{
+			name:        "empty text",
+			query:       model.SearchQuery{Text: "  "},
+			storeErr:    nil,
+			expectedErr: content.ErrInvalidResource,
+		},
+		{
+			name:        "limit too large",
+			query:       model.SearchQuery{Text: "dvorak", Limit: model.MaxSearchLimit + 1},
+			storeErr:    nil,
+			expectedErr: content.ErrInvalidResource,
+		},
+		{
+			name:        "store error",
+			query:       model.SearchQuery{Text: "dvorak"},
+			storeErr:    ErrFoo,
+			expectedErr: ErrFoo,
+		},
+		{
+			name:        "success",
+			query:       model.SearchQuery{Text: "dvorak"},
+			storeErr:    nil,
+			expectedErr: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			svc := SearchService{
+				newSearchStore: func(db store.Executor) SearchStore {
+					return mockSearchStore{
+						results: results,
+						err:     tt.storeErr,
+					}
+				},
+			}
+
+			got, err := svc.Search(testContext(), tt.query)
+
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, results, got)
+			}
+		})
+	}
+}
+
+type mockSearchStore struct {
+	results *model.SearchResults
+	err     error
+}
+
+func (s mockSearchStore) SearchComposers(
+	ctx context.Context,
+	q model.SearchQuery,
+) ([]model.ComposerWithDetails, error) {
+	return s.results.Composers, s.err
+}
+
+func (s mockSearchStore) SearchPieces(
+	ctx context.Context,
+	q model.SearchQuery,
+) ([]model.PieceWithDetails, error) {
+	return s.results.Pieces, s.err
+}
+
+func (s mockSearchStore) SearchProgrammes(
+	ctx context.Context,
+	q model.SearchQuery,
+) ([]model.ProgrammeWithDetails, error) {
+	return s.results.Programmes, s.err
+}
+
+func (s mockSearchStore) SearchVenues(
+	ctx context.Context,
+	q model.SearchQuery,
+) ([]model.VenueWithDetails, error) {
+	return s.results.Venues, s.err
+}
+
+func (s mockSearchStore) SearchEvents(
+	ctx context.Context,
+	q model.SearchQuery,
+) ([]content.Event, error) {
+	return s.results.Events, s.err
+}
@@ -8,9 +8,11 @@ package service
 import (
 	"context"
 	"errors"
+	"log/slog"
 
 	"github.com/adamkadda/arman/internal/cms/model"
 	"github.com/adamkadda/arman/internal/content"
+	"github.com/adamkadda/arman/pkg/queue"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 )
@@ -110,8 +112,17 @@ type DB interface {
 
 var reasons = map[error]string{
 	// General
+	content.ErrResourceNotFound:  "resource_not_found",
 	content.ErrOperationMismatch: "operation_mismatch",
 	model.ErrInvalidOperation:    "invalid_operation",
+	model.ErrInvalidLimit:        "limit_invalid",
+	model.ErrInvalidRange:        "range_invalid",
+	model.ErrInvalidCursor:       "cursor_invalid",
+	model.ErrInvalidSort:         "sort_invalid",
+	model.ErrInvalidDirection:    "direction_invalid",
+	model.ErrSearchTextEmpty:     "search_text_empty",
+	model.ErrInvalidImport:       "import_invalid",
+	model.ErrInvalidImportFormat: "import_format_invalid",
 
 	// Composer
 	content.ErrComposerFullNameEmpty:  "composer_full_name_empty",
@@ -127,6 +138,7 @@ var reasons = map[error]string{
 	// Piece
 	content.ErrPieceTitleEmpty: "piece_title_empty",
 	content.ErrPieceProtected:  "piece_protected",
+	content.ErrPieceExists:     "piece_exists",
 
 	// Programme
 	content.ErrProgrammeTitleEmpty:  "programme_title_empty",
@@ -135,12 +147,54 @@ var reasons = map[error]string{
 	content.ErrProgrammeProtected:   "programme_protected",
 
 	// Event
-	content.ErrEventTitleEmpty: "event_title_empty",
-	content.ErrEventImmutable:  "event_immutable",
-	content.ErrEventProtected:  "event_protected",
+	content.ErrEventTitleEmpty:         "event_title_empty",
+	content.ErrEventImmutable:          "event_immutable",
+	content.ErrEventProtected:          "event_protected",
+	content.ErrInvalidEventStatus:      "event_status_invalid",
+	content.ErrInvalidTimeframe:        "timeframe_invalid",
+	content.ErrInvalidStatusTransition: "status_transition_invalid",
+	content.ErrEventStatusReasonEmpty:  "event_status_reason_empty",
+	content.ErrEventReplacementInvalid: "event_replacement_invalid",
 
 	// Biography
 	content.ErrInvalidBiographyVariant: "biography_variant_invalid",
+	content.ErrInvalidLanguage:         "language_invalid",
+	content.ErrLanguageUnsupported:     "language_unsupported",
+
+	// Contact
+	content.ErrContactEmailInvalid: "contact_email_invalid",
+	content.ErrSocialPlatformEmpty: "social_platform_empty",
+	content.ErrSocialURLInvalid:    "social_url_invalid",
+
+	// Media
+	content.ErrMediaFilenameEmpty:   "media_filename_empty",
+	content.ErrMediaTypeUnsupported: "media_type_unsupported",
+	content.ErrMediaEmpty:           "media_empty",
+	content.ErrMediaTooLarge:        "media_too_large",
+	content.ErrMediaProtected:       "media_protected",
+	model.ErrInvalidMediaOwner:      "media_owner_invalid",
+
+	// Archive
+	model.ErrInvalidArchive:            "archive_invalid",
+	model.ErrUnsupportedArchiveVersion: "archive_version_unsupported",
+	model.ErrArchiveTargetNotEmpty:     "archive_target_not_empty",
+
+	// Audit
+	model.ErrInvalidAuditEntity: "audit_entity_invalid",
+
+	// Job
+	queue.ErrInvalidStatus: "job_status_invalid",
+	queue.ErrNotDead:       "job_not_dead",
+
+	// User
+	content.ErrUsernameEmpty:      "username_empty",
+	content.ErrPasswordTooShort:   "password_too_short",
+	content.ErrInvalidCredentials: "invalid_credentials",
+	content.ErrSessionExpired:     "session_expired",
+	content.ErrUnauthenticated:    "unauthenticated",
+	content.ErrInvalidRole:        "role_invalid",
+	content.ErrPermissionDenied:   "permission_denied",
+	content.ErrOwnRoleImmutable:   "own_role_immutable",
 }
 
 // reason returns a standardized, machine-readable string for business-rule
@@ -164,3 +218,14 @@ func reason(err error) string {
 
 	return "unknown_reason"
 }
+
+// listQueryAttr groups a model.ListQuery for logging.
+func listQueryAttr(q model.ListQuery) slog.Attr {
+	return slog.Group("query",
+		slog.String("filter", q.Filter),
+		slog.String("sort", q.Sort),
+		slog.String("direction", string(q.Direction)),
+		slog.Int("offset", q.Offset),
+		slog.Int("limit", q.Limit),
+	)
+}
@@ -0,0 +1,277 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/adamkadda/arman/internal/cms/model"
+	"github.com/adamkadda/arman/internal/cms/store"
+	"github.com/adamkadda/arman/internal/content"
+	"github.com/adamkadda/arman/pkg/logging"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// UserService contains application logic for admin user accounts.
+//
+// Stores are created via a constructor function to keep the service decoupled
+// from concrete store implementations and easy to unit test.
+type UserService struct {
+	db            DB
+	newUserStore  func(db store.Executor) UserStore
+	newAuditStore func(db store.Executor) AuditStore
+}
+
+// NewUserService creates a UserService using the default store constructor.
+func NewUserService(db DB) *UserService {
+	return &UserService{
+		db: db,
+		newUserStore: func(db store.Executor) UserStore {
+			return store.NewPostgresUserStore(db)
+		},
+		newAuditStore: newAuditStore,
+	}
+}
+
+type UserStore interface {
+	Get(ctx context.Context, id int) (*content.User, error)
+	GetByUsername(ctx context.Context, username string) (*content.User, error)
+	List(ctx context.Context) ([]content.User, error)
+	Create(ctx context.Context, u content.User) (*content.User, error)
+	UpdateRole(ctx context.Context, id int, role content.Role) (*content.User, error)
+}
+
+// List returns an array of Users, sorted by id.
+func (s *UserService) List(
+	ctx context.Context,
+) ([]content.User, error) {
+	logger := logging.FromContext(ctx).With(
+		slog.String("operation", "user.list"),
+	)
+
+	logger.Info(
+		"list users",
+	)
+
+	if err := authorize(ctx, logger, "user.list"); err != nil {
+		return nil, err
+	}
+
+	userStore := s.newUserStore(s.db)
+
+	userList, err := userStore.List(ctx)
+	if err != nil {
+		logger.Error(
+			"list users failed",
+			slog.String("step", "user.list"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
+	return userList, nil
+}
+
+// Create attempts to create a User with the given plain text password and role.
+//
+// Create validates the username, role and password, then persists the User with
+// a bcrypt hash of the password. The plain text password is never stored.
+func (s *UserService) Create(
+	ctx context.Context,
+	username string,
+	password string,
+	role content.Role,
+) (*content.User, error) {
+	logger := logging.FromContext(ctx).With(
+		slog.String("operation", "user.create"),
+		slog.String("username", username),
+	)
+
+	logger.Info(
+		"create user",
+	)
+
+	if err := authorize(ctx, logger, "user.create"); err != nil {
+		return nil, err
+	}
+
+	u := content.User{
+		Username: username,
+		Role:     role,
+	}
+
+	if err := u.Validate(); err != nil {
+		logger.Warn(
+			"validate user rejected",
+			slog.String("reason", reason(err)),
+		)
+
+		return nil, fmt.Errorf("%w: %s", content.ErrInvalidResource, err)
+	}
+
+	if err := content.ValidatePassword(password); err != nil {
+		logger.Warn(
+			"validate password rejected",
+			slog.String("reason", reason(err)),
+		)
+
+		return nil, fmt.Errorf("%w: %s", content.ErrInvalidResource, err)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		logger.Error(
+			"hash password failed",
+			slog.String("step", "user.hash_password"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
+	u.PasswordHash = string(hash)
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		logger.Error(
+			"begin transaction failed",
+			slog.String("step", "tx.begin"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	userStore := s.newUserStore(tx)
+
+	user, err := userStore.Create(ctx, u)
+	if err != nil {
+		logger.Error(
+			"create user failed",
+			slog.String("step", "user.create"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
+	err = recordChange(ctx, logger, s.newAuditStore(tx),
+		"user.create", model.AuditUser, user.ID,
+		nil, newAuditUser(user),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		logger.Error(
+			"commit transaction failed",
+			slog.String("step", "tx.commit"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// UpdateRole attempts to change the Role of the User identified by id.
+//
+// Users cannot change their own role. This keeps the last owner from locking
+// everyone out of user management by accident.
+func (s *UserService) UpdateRole(
+	ctx context.Context,
+	id int,
+	role content.Role,
+) (*content.User, error) {
+	logger := logging.FromContext(ctx).With(
+		slog.String("operation", "user.update_role"),
+		slog.Int("target_user_id", id),
+		slog.String("role", string(role)),
+	)
+
+	logger.Info(
+		"update user role",
+	)
+
+	if err := authorize(ctx, logger, "user.update_role"); err != nil {
+		return nil, err
+	}
+
+	if err := role.Validate(); err != nil {
+		logger.Warn(
+			"validate role rejected",
+			slog.String("reason", reason(err)),
+		)
+
+		return nil, fmt.Errorf("%w: %s", content.ErrInvalidResource, err)
+	}
+
+	if current, ok := UserFromContext(ctx); ok && current.ID == id {
+		logger.Warn(
+			"update user role blocked",
+			slog.String("reason", reason(content.ErrOwnRoleImmutable)),
+		)
+
+		return nil, content.ErrOwnRoleImmutable
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		logger.Error(
+			"begin transaction failed",
+			slog.String("step", "tx.begin"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	userStore := s.newUserStore(tx)
+
+	before, err := userStore.Get(ctx, id)
+	if err != nil {
+		logger.Error(
+			"get user failed",
+			slog.String("step", "user.get"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
+	user, err := userStore.UpdateRole(ctx, id, role)
+	if err != nil {
+		logger.Error(
+			"update user role failed",
+			slog.String("step", "user.update_role"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
+	err = recordChange(ctx, logger, s.newAuditStore(tx),
+		"user.update_role", model.AuditUser, user.ID,
+		newAuditUser(before), newAuditUser(user),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		logger.Error(
+			"commit transaction failed",
+			slog.String("step", "tx.commit"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
+	return user, nil
+}
@@ -6,6 +6,8 @@ import (
 	"io"
 	"log/slog"
 
+	"github.com/adamkadda/arman/internal/cms/model"
+	"github.com/adamkadda/arman/internal/cms/store"
 	"github.com/adamkadda/arman/pkg/logging"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
@@ -80,7 +82,48 @@ func (tx mockTx) Conn() *pgx.Conn {
 	panic("unexpected Conn call")
 }
 
+// mockAuditStore records the entries created, and fails to create them when
+// err is set.
+type mockAuditStore struct {
+	entries []model.AuditEntry
+	err     error
+}
+
+func newMockAuditStore(db store.Executor) AuditStore {
+	return &mockAuditStore{}
+}
+
+func (s *mockAuditStore) Create(ctx context.Context, e model.AuditEntry) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	s.entries = append(s.entries, e)
+
+	return nil
+}
+
+func (s *mockAuditStore) List(
+	ctx context.Context,
+	f model.AuditFilter,
+	q model.ListQuery,
+) (*model.Page[model.AuditEntry], error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return model.NewPage(q, s.entries, len(s.entries)), nil
+}
+
+// testContext returns a context logging nowhere, marked as a trusted caller so
+// that services authorize every operation. Attach a user to test authorization.
 func testContext() context.Context {
+	return WithSystemActor(anonymousContext())
+}
+
+// anonymousContext returns a context logging nowhere, with neither a user nor
+// the mark of a trusted caller, like that of a public request.
+func anonymousContext() context.Context {
 	handler := slog.NewTextHandler(io.Discard, nil)
 	logger := slog.New(handler)
 	return logging.WithLogger(context.Background(), logger)
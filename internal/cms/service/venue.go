@@ -18,6 +18,7 @@ import (
 type VenueService struct {
 	db            DB
 	newVenueStore func(db store.Executor) VenueStore
+	newAuditStore func(db store.Executor) AuditStore
 }
 
 // NewVenueService creates a VenueService using the default store constructor.
@@ -27,13 +28,14 @@ func NewVenueService(db DB) *VenueService {
 		newVenueStore: func(db store.Executor) VenueStore {
 			return store.NewPostgresVenueStore(db)
 		},
+		newAuditStore: newAuditStore,
 	}
 }
 
 type VenueStore interface {
 	Get(ctx context.Context, id int) (*content.Venue, error)
 	GetWithDetails(ctx context.Context, id int) (*model.VenueWithDetails, error)
-	ListWithDetails(ctx context.Context) ([]model.VenueWithDetails, error)
+	ListWithDetails(ctx context.Context, q model.ListQuery) (*model.Page[model.VenueWithDetails], error)
 	Create(ctx context.Context, v content.Venue) (*content.Venue, error)
 	Update(ctx context.Context, v content.Venue) (*content.Venue, error)
 	Delete(ctx context.Context, id int) error
@@ -53,6 +55,10 @@ func (s *VenueService) Get(
 		"get venue",
 	)
 
+	if err := authorize(ctx, logger, "venue.get"); err != nil {
+		return nil, err
+	}
+
 	venueStore := s.newVenueStore(s.db)
 
 	venue, err := venueStore.Get(ctx, id)
@@ -69,21 +75,39 @@ func (s *VenueService) Get(
 	return venue, nil
 }
 
-// List returns an array of VenueWithDetails, sorted by id.
+// List returns a page of VenueWithDetails matching the passed query. See
+// model.VenueSortFields for the fields venues may be sorted by.
+//
+// An invalid query is reported as content.ErrInvalidResource.
 func (s *VenueService) List(
 	ctx context.Context,
-) ([]model.VenueWithDetails, error) {
+	q model.ListQuery,
+) (*model.Page[model.VenueWithDetails], error) {
 	logger := logging.FromContext(ctx).With(
 		slog.String("operation", "venue.list"),
+		listQueryAttr(q),
 	)
 
 	logger.Info(
 		"list venues",
 	)
 
+	if err := authorize(ctx, logger, "venue.list"); err != nil {
+		return nil, err
+	}
+
+	if err := q.Validate(model.VenueSortFields...); err != nil {
+		logger.Warn(
+			"list venues rejected",
+			slog.String("reason", reason(err)),
+		)
+
+		return nil, fmt.Errorf("%w: %s", content.ErrInvalidResource, err)
+	}
+
 	venueStore := s.newVenueStore(s.db)
 
-	venueList, err := venueStore.ListWithDetails(ctx)
+	page, err := venueStore.ListWithDetails(ctx, q)
 	if err != nil {
 		logger.Error(
 			"list venues failed",
@@ -94,7 +118,7 @@ func (s *VenueService) List(
 		return nil, err
 	}
 
-	return venueList, nil
+	return page, nil
 }
 
 // Create attempts to create a Venue.
@@ -114,6 +138,10 @@ func (s *VenueService) Create(
 		"create venue",
 	)
 
+	if err := authorize(ctx, logger, "venue.create"); err != nil {
+		return nil, err
+	}
+
 	if cmd.Venue.Operation != model.OperationCreate {
 		logger.Warn(
 			"operation mismatch",
@@ -123,8 +151,6 @@ func (s *VenueService) Create(
 		return nil, content.ErrOperationMismatch
 	}
 
-	venueStore := s.newVenueStore(s.db)
-
 	if err := cmd.Venue.Data.Validate(); err != nil {
 		logger.Warn(
 			"validate venue rejected",
@@ -134,6 +160,20 @@ func (s *VenueService) Create(
 		return nil, fmt.Errorf("%w: %s", content.ErrInvalidResource, err)
 	}
 
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		logger.Error(
+			"begin transaction failed",
+			slog.String("step", "tx.begin"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	venueStore := s.newVenueStore(tx)
+
 	venue, err := venueStore.Create(ctx, cmd.Venue.Data)
 	if err != nil {
 		logger.Error(
@@ -145,6 +185,24 @@ func (s *VenueService) Create(
 		return nil, err
 	}
 
+	err = recordChange(ctx, logger, s.newAuditStore(tx),
+		"venue.create", model.AuditVenue, venue.ID,
+		nil, venue,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		logger.Error(
+			"commit transaction failed",
+			slog.String("step", "tx.commit"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
 	return venue, err
 }
 
@@ -167,6 +225,10 @@ func (s *VenueService) Update(
 		"update venue",
 	)
 
+	if err := authorize(ctx, logger, "venue.update"); err != nil {
+		return nil, err
+	}
+
 	if cmd.Venue.Operation != model.OperationUpdate {
 		logger.Warn(
 			"operation mismatch",
@@ -176,8 +238,6 @@ func (s *VenueService) Update(
 		return nil, content.ErrOperationMismatch
 	}
 
-	venueStore := s.newVenueStore(s.db)
-
 	if err := cmd.Venue.Data.Validate(); err != nil {
 		logger.Warn(
 			"validate venue rejected",
@@ -187,6 +247,31 @@ func (s *VenueService) Update(
 		return nil, fmt.Errorf("%w: %s", content.ErrInvalidResource, err)
 	}
 
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		logger.Error(
+			"begin transaction failed",
+			slog.String("step", "tx.begin"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	venueStore := s.newVenueStore(tx)
+
+	before, err := venueStore.Get(ctx, cmd.Venue.Data.ID)
+	if err != nil {
+		logger.Error(
+			"get venue failed",
+			slog.String("step", "venue.get"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
 	venue, err := venueStore.Update(ctx, cmd.Venue.Data)
 	if err != nil {
 		logger.Error(
@@ -198,6 +283,24 @@ func (s *VenueService) Update(
 		return nil, err
 	}
 
+	err = recordChange(ctx, logger, s.newAuditStore(tx),
+		"venue.update", model.AuditVenue, venue.ID,
+		before, venue,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		logger.Error(
+			"commit transaction failed",
+			slog.String("step", "tx.commit"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
 	return venue, err
 }
 
@@ -218,7 +321,23 @@ func (s *VenueService) Delete(
 		"delete venue",
 	)
 
-	venueStore := s.newVenueStore(s.db)
+	if err := authorize(ctx, logger, "venue.delete"); err != nil {
+		return err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		logger.Error(
+			"begin transaction failed",
+			slog.String("step", "tx.begin"),
+			slog.Any("error", err),
+		)
+
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	venueStore := s.newVenueStore(tx)
 
 	venueWithDetails, err := venueStore.GetWithDetails(ctx, id)
 	if err != nil {
@@ -256,18 +375,44 @@ func (s *VenueService) Delete(
 		return err
 	}
 
+	err = recordChange(ctx, logger, s.newAuditStore(tx),
+		"venue.delete", model.AuditVenue, id,
+		venueWithDetails.Venue, nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		logger.Error(
+			"commit transaction failed",
+			slog.String("step", "tx.commit"),
+			slog.Any("error", err),
+		)
+
+		return err
+	}
+
 	return nil
 }
 
+// venueResolver resolves a VenueIntent. The Venues it creates or updates are
+// recorded in the audit log as changed by operation.
 type venueResolver struct {
 	venueStore VenueStore
+	auditStore AuditStore
+	operation  string
 }
 
 func newVenueResolver(
 	venueStore VenueStore,
+	auditStore AuditStore,
+	operation string,
 ) *venueResolver {
 	return &venueResolver{
 		venueStore: venueStore,
+		auditStore: auditStore,
+		operation:  operation,
 	}
 }
 
@@ -308,6 +453,14 @@ func (r *venueResolver) run(
 			)
 			return nil, err
 		}
+
+		err = recordChange(ctx, logger, r.auditStore,
+			r.operation, model.AuditVenue, piece.ID,
+			nil, piece,
+		)
+		if err != nil {
+			return nil, err
+		}
 		return piece, nil
 
 	case model.OperationUpdate:
@@ -319,6 +472,17 @@ func (r *venueResolver) run(
 			return nil, fmt.Errorf("%w: %s", content.ErrInvalidResource, err)
 		}
 
+		before, err := r.venueStore.Get(ctx, intent.Data.ID)
+		if err != nil {
+			logger.Error(
+				"get venue failed",
+				slog.Int("venue_id", intent.Data.ID),
+				slog.String("step", "venue.get"),
+				slog.Any("error", err),
+			)
+			return nil, err
+		}
+
 		piece, err := r.venueStore.Update(ctx, intent.Data)
 		if err != nil {
 			logger.Error(
@@ -329,6 +493,14 @@ func (r *venueResolver) run(
 			)
 			return nil, err
 		}
+
+		err = recordChange(ctx, logger, r.auditStore,
+			r.operation, model.AuditVenue, piece.ID,
+			before, piece,
+		)
+		if err != nil {
+			return nil, err
+		}
 		return piece, nil
 
 	default:
@@ -61,9 +61,16 @@ func TestVenueService_Get(t *testing.T) {
 func TestVenueService_List(t *testing.T) {
 	tests := []struct {
 		name           string
+		query          model.ListQuery
 		expectedVenues []model.VenueWithDetails
 		expectedErr    error
 	}{
+		{
+			name:           "invalid query",
+			query:          model.ListQuery{Sort: "foo"},
+			expectedVenues: nil,
+			expectedErr:    content.ErrInvalidResource,
+		},
 		{
 			name: "success",
 			expectedVenues: []model.VenueWithDetails{
@@ -117,13 +124,13 @@ func TestVenueService_List(t *testing.T) {
 				},
 			}
 
-			venues, err := svc.List(testContext())
+			page, err := svc.List(testContext(), tt.query)
 
 			if tt.expectedErr != nil {
 				require.ErrorIs(t, err, tt.expectedErr)
 			} else {
 				require.NoError(t, err)
-				require.Equal(t, tt.expectedVenues, venues)
+				require.Equal(t, tt.expectedVenues, page.Items)
 			}
 		})
 	}
@@ -209,12 +216,14 @@ func TestVenueService_Create(t *testing.T) {
 			t.Parallel()
 
 			svc := VenueService{
+				db: mockDB{},
 				newVenueStore: func(db store.Executor) VenueStore {
 					return mockVenueStore{
 						venue: tt.venue,
 						err:   tt.storeErr,
 					}
 				},
+				newAuditStore: newMockAuditStore,
 			}
 
 			venue, err := svc.Create(testContext(), tt.cmd)
@@ -314,12 +323,14 @@ func TestVenueService_Update(t *testing.T) {
 			t.Parallel()
 
 			svc := VenueService{
+				db: mockDB{},
 				newVenueStore: func(db store.Executor) VenueStore {
 					return mockVenueStore{
 						venue: tt.venue,
 						err:   tt.storeErr,
 					}
 				},
+				newAuditStore: newMockAuditStore,
 			}
 
 			venue, err := svc.Update(testContext(), tt.cmd)
@@ -401,6 +412,7 @@ func TestVenueService_Delete(t *testing.T) {
 			t.Parallel()
 
 			svc := VenueService{
+				db: mockDB{},
 				newVenueStore: func(db store.Executor) VenueStore {
 					return mockVenueStore{
 						detailedVenue: tt.venue,
@@ -408,6 +420,7 @@ func TestVenueService_Delete(t *testing.T) {
 						deleteErr:     tt.deleteErr,
 					}
 				},
+				newAuditStore: newMockAuditStore,
 			}
 
 			err := svc.Delete(testContext(), 1)
@@ -481,7 +494,11 @@ func TestVenueResolver_Run(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			resolver := newVenueResolver(mockVenueStore{})
+			resolver := newVenueResolver(
+				mockVenueStore{venue: &tt.intent.Data},
+				&mockAuditStore{},
+				"venue.test",
+			)
 
 			_, err := resolver.run(testContext(), tt.intent)
 
@@ -520,8 +537,13 @@ func (s mockVenueStore) GetWithDetails(
 
 func (s mockVenueStore) ListWithDetails(
 	ctx context.Context,
-) ([]model.VenueWithDetails, error) {
-	return s.detailedVenues, s.err
+	q model.ListQuery,
+) (*model.Page[model.VenueWithDetails], error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return model.NewPage(q, s.detailedVenues, len(s.detailedVenues)), nil
 }
 
 func (s mockVenueStore) Create(
@@ -0,0 +1,981 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/adamkadda/arman/internal/cms/model"
+	"github.com/adamkadda/arman/internal/content"
+)
+
+// PostgresArchiveStore reads and writes every content table at once, for
+// archives. Records are written with new identifiers; callers map the
+// identifiers of the archive to those returned.
+type PostgresArchiveStore struct {
+	db Executor
+}
+
+func NewPostgresArchiveStore(db Executor) *PostgresArchiveStore {
+	return &PostgresArchiveStore{
+		db: db,
+	}
+}
+
+// Snapshot makes the transaction the store runs in read only, and makes every
+// following query see the database as it was at the first one. It must be the
+// first call in the transaction.
+func (s *PostgresArchiveStore) Snapshot(ctx context.Context) error {
+	_, err := s.db.Exec(ctx, "SET TRANSACTION ISOLATION LEVEL REPEATABLE READ, READ ONLY")
+	if err != nil {
+		return fmt.Errorf("exec failed: %w", err)
+	}
+
+	return nil
+}
+
+// Empty reports whether the database holds no content. Users, sessions and
+// jobs are not content.
+func (s *PostgresArchiveStore) Empty(ctx context.Context) (bool, error) {
+	query := `
+	SELECT NOT (
+		EXISTS (SELECT 1 FROM composers) OR
+		EXISTS (SELECT 1 FROM pieces) OR
+		EXISTS (SELECT 1 FROM venues) OR
+		EXISTS (SELECT 1 FROM programmes) OR
+		EXISTS (SELECT 1 FROM events) OR
+		EXISTS (SELECT 1 FROM media) OR
+		EXISTS (SELECT 1 FROM biographies) OR
+		EXISTS (SELECT 1 FROM contact)
+	)
+	`
+
+	var empty bool
+	if err := s.db.QueryRow(ctx, query).Scan(&empty); err != nil {
+		return false, fmt.Errorf("query failed: %w", err)
+	}
+
+	return empty, nil
+}
+
+type archiveUserRow struct {
+	UserID   int    `db:"user_id"`
+	Username string `db:"username"`
+}
+
+// ListUsers maps the username of every user to its id.
+func (s *PostgresArchiveStore) ListUsers(ctx context.Context) (map[string]int, error) {
+	pgxRows, err := s.db.Query(ctx, "SELECT user_id, username FROM users")
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	rows, err := collectRows[archiveUserRow](pgxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make(map[string]int, len(rows))
+	for _, row := range rows {
+		users[row.Username] = row.UserID
+	}
+
+	return users, nil
+}
+
+type archiveAttachmentRow struct {
+	OwnerID  int `db:"owner_id"`
+	MediaID  int `db:"media_id"`
+	Position int `db:"position"`
+}
+
+// listAttachments returns the media attached to every owner of a type, by
+// owner id and in order.
+func (s *PostgresArchiveStore) listAttachments(
+	ctx context.Context,
+	ownerType model.MediaOwnerType,
+) (map[int][]model.ArchiveAttachment, error) {
+	t := mediaOwnerTables[ownerType]
+
+	query := fmt.Sprintf(`
+	SELECT
+		%s AS owner_id,
+		media_id,
+		position
+	FROM %s
+	ORDER BY owner_id, position
+	`, t.column, t.table)
+
+	pgxRows, err := s.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	rows, err := collectRows[archiveAttachmentRow](pgxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	attachments := make(map[int][]model.ArchiveAttachment)
+	for _, row := range rows {
+		attachments[row.OwnerID] = append(attachments[row.OwnerID], model.ArchiveAttachment{
+			MediaID:  row.MediaID,
+			Position: row.Position,
+		})
+	}
+
+	return attachments, nil
+}
+
+// ListComposers returns every composer, along with its media.
+func (s *PostgresArchiveStore) ListComposers(ctx context.Context) ([]model.ArchiveComposer, error) {
+	pgxRows, err := s.db.Query(ctx, `
+	SELECT
+		composer_id,
+		full_name,
+		short_name,
+		0 AS piece_count
+	FROM composers
+	ORDER BY composer_id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	rows, err := collectRows[composerRow](pgxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	attachments, err := s.listAttachments(ctx, model.MediaOwnerComposer)
+	if err != nil {
+		return nil, err
+	}
+
+	composers := make([]model.ArchiveComposer, len(rows))
+	for i, row := range rows {
+		composers[i] = model.ArchiveComposer{
+			ID:        row.ComposerID,
+			FullName:  row.FullName,
+			ShortName: row.ShortName,
+			Media:     attachments[row.ComposerID],
+		}
+	}
+
+	return composers, nil
+}
+
+type archivePieceRow struct {
+	PieceID    int    `db:"piece_id"`
+	PieceTitle string `db:"piece_title"`
+	ComposerID int    `db:"composer_id"`
+}
+
+// ListPieces returns every piece.
+func (s *PostgresArchiveStore) ListPieces(ctx context.Context) ([]model.ArchivePiece, error) {
+	pgxRows, err := s.db.Query(ctx, `
+	SELECT
+		piece_id,
+		piece_title,
+		composer_id
+	FROM pieces
+	ORDER BY piece_id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	rows, err := collectRows[archivePieceRow](pgxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	pieces := make([]model.ArchivePiece, len(rows))
+	for i, row := range rows {
+		pieces[i] = model.ArchivePiece{
+			ID:         row.PieceID,
+			Title:      row.PieceTitle,
+			ComposerID: row.ComposerID,
+		}
+	}
+
+	return pieces, nil
+}
+
+// ListVenues returns every venue, along with its media.
+func (s *PostgresArchiveStore) ListVenues(ctx context.Context) ([]model.ArchiveVenue, error) {
+	pgxRows, err := s.db.Query(ctx, `
+	SELECT
+		venue_id,
+		venue_name,
+		full_address,
+		short_address,
+		0 AS event_count
+	FROM venues
+	ORDER BY venue_id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	rows, err := collectRows[venueRow](pgxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	attachments, err := s.listAttachments(ctx, model.MediaOwnerVenue)
+	if err != nil {
+		return nil, err
+	}
+
+	venues := make([]model.ArchiveVenue, len(rows))
+	for i, row := range rows {
+		venues[i] = model.ArchiveVenue{
+			ID:           row.VenueID,
+			Name:         row.VenueName,
+			FullAddress:  row.FullAddress,
+			ShortAddress: row.ShortAddress,
+			Media:        attachments[row.VenueID],
+		}
+	}
+
+	return venues, nil
+}
+
+type archiveProgrammeRow struct {
+	ProgrammeID    int    `db:"programme_id"`
+	ProgrammeTitle string `db:"programme_title"`
+}
+
+type archiveProgrammePieceRow struct {
+	ProgrammeID int `db:"programme_id"`
+	PieceID     int `db:"piece_id"`
+	Sequence    int `db:"sequence"`
+}
+
+// ListProgrammes returns every programme, along with its pieces in sequence.
+func (s *PostgresArchiveStore) ListProgrammes(ctx context.Context) ([]model.ArchiveProgramme, error) {
+	pgxRows, err := s.db.Query(ctx, `
+	SELECT
+		programme_id,
+		programme_title
+	FROM programmes
+	ORDER BY programme_id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	rows, err := collectRows[archiveProgrammeRow](pgxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	pgxRows, err = s.db.Query(ctx, `
+	SELECT
+		programme_id,
+		piece_id,
+		sequence
+	FROM programme_pieces
+	ORDER BY programme_id, sequence
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	pieceRows, err := collectRows[archiveProgrammePieceRow](pgxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	pieces := make(map[int][]model.ArchiveProgrammePiece)
+	for _, row := range pieceRows {
+		pieces[row.ProgrammeID] = append(pieces[row.ProgrammeID], model.ArchiveProgrammePiece{
+			PieceID:  row.PieceID,
+			Sequence: row.Sequence,
+		})
+	}
+
+	programmes := make([]model.ArchiveProgramme, len(rows))
+	for i, row := range rows {
+		programmes[i] = model.ArchiveProgramme{
+			ID:     row.ProgrammeID,
+			Title:  row.ProgrammeTitle,
+			Pieces: pieces[row.ProgrammeID],
+		}
+	}
+
+	return programmes, nil
+}
+
+// ListMedia returns the metadata of every media file.
+func (s *PostgresArchiveStore) ListMedia(ctx context.Context) ([]model.ArchiveMedia, error) {
+	pgxRows, err := s.db.Query(ctx, `
+	SELECT
+		media_id,
+		filename,
+		mime_type,
+		size,
+		checksum,
+		storage_key,
+		alt_text,
+		credits,
+		created_at,
+		0 AS event_count
+	FROM media
+	ORDER BY media_id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	rows, err := collectRows[mediaRow](pgxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	media := make([]model.ArchiveMedia, len(rows))
+	for i, row := range rows {
+		media[i] = model.ArchiveMedia{
+			ID:         row.MediaID,
+			Filename:   row.Filename,
+			MIMEType:   row.MimeType,
+			Size:       row.Size,
+			Checksum:   row.Checksum,
+			StorageKey: row.StorageKey,
+			AltText:    row.AltText,
+			Credits:    row.Credits,
+			CreatedAt:  row.CreatedAt,
+		}
+	}
+
+	return media, nil
+}
+
+type archiveStatusChangeRow struct {
+	EventID    int            `db:"event_id"`
+	FromStatus content.Status `db:"from_status"`
+	ToStatus   content.Status `db:"to_status"`
+	Reason     *string        `db:"reason"`
+	ChangedBy  *string        `db:"changed_by"`
+	ChangedAt  time.Time      `db:"changed_at"`
+}
+
+// ListEvents returns every event, along with its status history, oldest
+// first, and its media.
+func (s *PostgresArchiveStore) ListEvents(ctx context.Context) ([]model.ArchiveEvent, error) {
+	pgxRows, err := s.db.Query(ctx, `
+	SELECT
+		event_id,
+		event_title,
+		event_date,
+		ticket_link,
+		venue_id,
+		programme_id,
+		status,
+		notes,
+		publish_at,
+		status_reason,
+		replacement_event_id,
+		sequence,
+		created_at,
+		updated_at
+	FROM events
+	ORDER BY event_id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	rows, err := collectRows[eventRow](pgxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	pgxRows, err = s.db.Query(ctx, `
+	SELECT
+		h.event_id,
+		h.from_status,
+		h.to_status,
+		h.reason,
+		u.username AS changed_by,
+		h.changed_at
+	FROM event_status_history h
+	LEFT JOIN users u ON u.user_id = h.changed_by
+	ORDER BY h.event_id, h.changed_at, h.history_id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	historyRows, err := collectRows[archiveStatusChangeRow](pgxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make(map[int][]model.ArchiveStatusChange)
+	for _, row := range historyRows {
+		history[row.EventID] = append(history[row.EventID], model.ArchiveStatusChange{
+			From:      row.FromStatus,
+			To:        row.ToStatus,
+			Reason:    row.Reason,
+			ChangedBy: row.ChangedBy,
+			ChangedAt: row.ChangedAt,
+		})
+	}
+
+	attachments, err := s.listAttachments(ctx, model.MediaOwnerEvent)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]model.ArchiveEvent, len(rows))
+	for i, row := range rows {
+		events[i] = model.ArchiveEvent{
+			ID:                 row.EventID,
+			Title:              row.EventTitle,
+			Date:               row.EventDate,
+			TicketLink:         row.TicketLink,
+			VenueID:            row.VenueID,
+			ProgrammeID:        row.ProgrammeID,
+			Status:             row.Status,
+			Notes:              row.Notes,
+			PublishAt:          row.PublishAt,
+			StatusReason:       row.StatusReason,
+			ReplacementEventID: row.ReplacementID,
+			Sequence:           row.Sequence,
+			CreatedAt:          row.CreatedAt,
+			UpdatedAt:          row.UpdatedAt,
+			History:            history[row.EventID],
+			Media:              attachments[row.EventID],
+		}
+	}
+
+	return events, nil
+}
+
+type archiveBiographyRow struct {
+	Variant   content.BiographyVariant `db:"variant"`
+	Language  content.Language         `db:"language"`
+	Content   string                   `db:"content"`
+	UpdatedAt time.Time                `db:"updated_at"`
+	UpdatedBy *string                  `db:"updated_by"`
+}
+
+type archiveRevisionRow struct {
+	Variant   content.BiographyVariant `db:"variant"`
+	Language  content.Language         `db:"language"`
+	Content   string                   `db:"content"`
+	Author    *string                  `db:"author"`
+	CreatedAt time.Time                `db:"created_at"`
+}
+
+// ListBiographies returns every biography text, along with its revisions,
+// oldest first.
+func (s *PostgresArchiveStore) ListBiographies(ctx context.Context) ([]model.ArchiveBiography, error) {
+	pgxRows, err := s.db.Query(ctx, `
+	SELECT
+		b.variant,
+		b.language,
+		b.content,
+		b.updated_at,
+		u.username AS updated_by
+	FROM biographies b
+	LEFT JOIN users u ON u.user_id = b.updated_by
+	ORDER BY b.variant, b.language
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	rows, err := collectRows[archiveBiographyRow](pgxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	pgxRows, err = s.db.Query(ctx, `
+	SELECT
+		r.variant,
+		r.language,
+		r.content,
+		u.username AS author,
+		r.created_at
+	FROM biography_revisions r
+	LEFT JOIN users u ON u.user_id = r.author_id
+	ORDER BY r.variant, r.language, r.created_at, r.revision_id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	revisionRows, err := collectRows[archiveRevisionRow](pgxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	type key struct {
+		variant  content.BiographyVariant
+		language content.Language
+	}
+
+	revisions := make(map[key][]model.ArchiveRevision)
+	for _, row := range revisionRows {
+		k := key{row.Variant, row.Language}
+		revisions[k] = append(revisions[k], model.ArchiveRevision{
+			Content:   row.Content,
+			Author:    row.Author,
+			CreatedAt: row.CreatedAt,
+		})
+	}
+
+	biographies := make([]model.ArchiveBiography, len(rows))
+	for i, row := range rows {
+		biographies[i] = model.ArchiveBiography{
+			Variant:   row.Variant,
+			Language:  row.Language,
+			Content:   row.Content,
+			UpdatedAt: row.UpdatedAt,
+			UpdatedBy: row.UpdatedBy,
+			Revisions: revisions[key{row.Variant, row.Language}],
+		}
+	}
+
+	return biographies, nil
+}
+
+type archiveBiographyMediaRow struct {
+	Variant  content.BiographyVariant `db:"variant"`
+	MediaID  int                      `db:"media_id"`
+	Position int                      `db:"position"`
+}
+
+// ListBiographyMedia returns the media attached to every biography variant.
+func (s *PostgresArchiveStore) ListBiographyMedia(ctx context.Context) ([]model.ArchiveBiographyMedia, error) {
+	pgxRows, err := s.db.Query(ctx, `
+	SELECT
+		variant,
+		media_id,
+		position
+	FROM biography_media
+	ORDER BY variant, position
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	rows, err := collectRows[archiveBiographyMediaRow](pgxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	attachments := make([]model.ArchiveBiographyMedia, len(rows))
+	for i, row := range rows {
+		attachments[i] = model.ArchiveBiographyMedia{
+			Variant:  row.Variant,
+			MediaID:  row.MediaID,
+			Position: row.Position,
+		}
+	}
+
+	return attachments, nil
+}
+
+type archiveContactRow struct {
+	Email            string          `db:"email"`
+	Phone            string          `db:"phone"`
+	ManagementAgency string          `db:"management_agency"`
+	ManagementEmail  string          `db:"management_email"`
+	ManagementPhone  string          `db:"management_phone"`
+	PressContact     string          `db:"press_contact"`
+	PressEmail       string          `db:"press_email"`
+	PressPhone       string          `db:"press_phone"`
+	SocialLinks      json.RawMessage `db:"social_links"`
+	UpdatedAt        time.Time       `db:"updated_at"`
+}
+
+// GetContact returns the contact details. It returns
+// content.ErrResourceNotFound if they were never set.
+func (s *PostgresArchiveStore) GetContact(ctx context.Context) (*model.ArchiveContact, error) {
+	pgxRows, err := s.db.Query(ctx, `
+	SELECT
+		email,
+		phone,
+		management_agency,
+		management_email,
+		management_phone,
+		press_contact,
+		press_email,
+		press_phone,
+		social_links,
+		updated_at
+	FROM contact
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	row, err := collectRow[archiveContactRow](pgxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.ArchiveContact{
+		Email:            row.Email,
+		Phone:            row.Phone,
+		ManagementAgency: row.ManagementAgency,
+		ManagementEmail:  row.ManagementEmail,
+		ManagementPhone:  row.ManagementPhone,
+		PressContact:     row.PressContact,
+		PressEmail:       row.PressEmail,
+		PressPhone:       row.PressPhone,
+		SocialLinks:      row.SocialLinks,
+		UpdatedAt:        row.UpdatedAt,
+	}, nil
+}
+
+// insert runs an INSERT returning the id of the new row.
+func (s *PostgresArchiveStore) insert(ctx context.Context, query string, args ...any) (int, error) {
+	var id int
+	if err := s.db.QueryRow(ctx, query, args...).Scan(&id); err != nil {
+		return 0, fmt.Errorf("query failed: %w", err)
+	}
+
+	return id, nil
+}
+
+// CreateComposer inserts a composer and returns its id.
+func (s *PostgresArchiveStore) CreateComposer(ctx context.Context, c model.ArchiveComposer) (int, error) {
+	return s.insert(ctx, `
+	INSERT INTO composers (
+		full_name,
+		short_name
+	)
+	VALUES ($1, $2)
+	RETURNING composer_id
+	`,
+		c.FullName,
+		c.ShortName,
+	)
+}
+
+// CreatePiece inserts a piece of the composer with the passed id and returns
+// its id.
+func (s *PostgresArchiveStore) CreatePiece(ctx context.Context, p model.ArchivePiece, composerID int) (int, error) {
+	return s.insert(ctx, `
+	INSERT INTO pieces (
+		piece_title,
+		composer_id
+	)
+	VALUES ($1, $2)
+	RETURNING piece_id
+	`,
+		p.Title,
+		composerID,
+	)
+}
+
+// CreateVenue inserts a venue and returns its id.
+func (s *PostgresArchiveStore) CreateVenue(ctx context.Context, v model.ArchiveVenue) (int, error) {
+	return s.insert(ctx, `
+	INSERT INTO venues (
+		venue_name,
+		full_address,
+		short_address
+	)
+	VALUES ($1, $2, $3)
+	RETURNING venue_id
+	`,
+		v.Name,
+		v.FullAddress,
+		v.ShortAddress,
+	)
+}
+
+// CreateProgramme inserts a programme, without its pieces, and returns its id.
+func (s *PostgresArchiveStore) CreateProgramme(ctx context.Context, p model.ArchiveProgramme) (int, error) {
+	return s.insert(ctx, `
+	INSERT INTO programmes (
+		programme_title
+	)
+	VALUES ($1)
+	RETURNING programme_id
+	`,
+		p.Title,
+	)
+}
+
+// AddProgrammePiece inserts a piece into a programme at a sequence.
+func (s *PostgresArchiveStore) AddProgrammePiece(ctx context.Context, programmeID, pieceID, sequence int) error {
+	cmdTag, err := s.db.Exec(ctx, `
+	INSERT INTO programme_pieces (
+		programme_id,
+		piece_id,
+		sequence
+	)
+	VALUES ($1, $2, $3)
+	`,
+		programmeID,
+		pieceID,
+		sequence,
+	)
+	if err != nil {
+		return fmt.Errorf("exec failed: %w", err)
+	}
+
+	return checkAffected(cmdTag)
+}
+
+// CreateMedia inserts the metadata of a media file and returns its id.
+func (s *PostgresArchiveStore) CreateMedia(ctx context.Context, m model.ArchiveMedia) (int, error) {
+	return s.insert(ctx, `
+	INSERT INTO media (
+		filename,
+		mime_type,
+		size,
+		checksum,
+		storage_key,
+		alt_text,
+		credits,
+		created_at
+	)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	RETURNING media_id
+	`,
+		m.Filename,
+		m.MIMEType,
+		m.Size,
+		m.Checksum,
+		m.StorageKey,
+		m.AltText,
+		m.Credits,
+		m.CreatedAt,
+	)
+}
+
+// Attach attaches media to an owner at a position.
+func (s *PostgresArchiveStore) Attach(
+	ctx context.Context,
+	owner model.MediaOwner,
+	mediaID int,
+	position int,
+) error {
+	t, ok := mediaOwnerTables[owner.Type]
+	if !ok {
+		return model.ErrInvalidMediaOwner
+	}
+
+	query := fmt.Sprintf(`
+	INSERT INTO %s (
+		%s,
+		media_id,
+		position
+	)
+	VALUES ($1, $2, $3)
+	`, t.table, t.column)
+
+	cmdTag, err := s.db.Exec(ctx, query,
+		mediaOwnerKey(owner),
+		mediaID,
+		position,
+	)
+	if err != nil {
+		return fmt.Errorf("exec failed: %w", err)
+	}
+
+	return checkAffected(cmdTag)
+}
+
+// CreateEvent inserts an event and returns its id. References to other
+// records are passed already mapped.
+func (s *PostgresArchiveStore) CreateEvent(
+	ctx context.Context,
+	e model.ArchiveEvent,
+	venueID *int,
+	programmeID *int,
+	replacementID *int,
+) (int, error) {
+	return s.insert(ctx, `
+	INSERT INTO events (
+		event_title,
+		event_date,
+		ticket_link,
+		venue_id,
+		programme_id,
+		status,
+		notes,
+		publish_at,
+		status_reason,
+		replacement_event_id,
+		sequence,
+		created_at,
+		updated_at
+	)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	RETURNING event_id
+	`,
+		e.Title,
+		e.Date,
+		e.TicketLink,
+		venueID,
+		programmeID,
+		e.Status,
+		e.Notes,
+		e.PublishAt,
+		e.StatusReason,
+		replacementID,
+		e.Sequence,
+		e.CreatedAt,
+		e.UpdatedAt,
+	)
+}
+
+// SetReplacement sets the event replacing a called off event, for events
+// created before their replacement. The events trigger moves the time the
+// event was last updated to now.
+func (s *PostgresArchiveStore) SetReplacement(ctx context.Context, eventID, replacementID int) error {
+	cmdTag, err := s.db.Exec(ctx, `
+	UPDATE events
+	SET replacement_event_id = $2
+	WHERE event_id = $1
+	`,
+		eventID,
+		replacementID,
+	)
+	if err != nil {
+		return fmt.Errorf("exec failed: %w", err)
+	}
+
+	return checkAffected(cmdTag)
+}
+
+// AddStatusChange inserts an entry of the status history of an event.
+func (s *PostgresArchiveStore) AddStatusChange(
+	ctx context.Context,
+	eventID int,
+	c model.ArchiveStatusChange,
+	changedBy *int,
+) error {
+	cmdTag, err := s.db.Exec(ctx, `
+	INSERT INTO event_status_history (
+		event_id,
+		from_status,
+		to_status,
+		reason,
+		changed_by,
+		changed_at
+	)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	`,
+		eventID,
+		c.From,
+		c.To,
+		c.Reason,
+		changedBy,
+		c.ChangedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("exec failed: %w", err)
+	}
+
+	return checkAffected(cmdTag)
+}
+
+// CreateBiography inserts the text of a biography variant in a language.
+func (s *PostgresArchiveStore) CreateBiography(
+	ctx context.Context,
+	b model.ArchiveBiography,
+	updatedBy *int,
+) error {
+	cmdTag, err := s.db.Exec(ctx, `
+	INSERT INTO biographies (
+		variant,
+		language,
+		content,
+		updated_at,
+		updated_by
+	)
+	VALUES ($1, $2, $3, $4, $5)
+	`,
+		b.Variant,
+		b.Language,
+		b.Content,
+		b.UpdatedAt,
+		updatedBy,
+	)
+	if err != nil {
+		return fmt.Errorf("exec failed: %w", err)
+	}
+
+	return checkAffected(cmdTag)
+}
+
+// AddRevision inserts a previous text of a biography.
+func (s *PostgresArchiveStore) AddRevision(
+	ctx context.Context,
+	b model.ArchiveBiography,
+	r model.ArchiveRevision,
+	authorID *int,
+) error {
+	cmdTag, err := s.db.Exec(ctx, `
+	INSERT INTO biography_revisions (
+		variant,
+		language,
+		content,
+		author_id,
+		created_at
+	)
+	VALUES ($1, $2, $3, $4, $5)
+	`,
+		b.Variant,
+		b.Language,
+		r.Content,
+		authorID,
+		r.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("exec failed: %w", err)
+	}
+
+	return checkAffected(cmdTag)
+}
+
+// CreateContact inserts the contact details.
+func (s *PostgresArchiveStore) CreateContact(ctx context.Context, c model.ArchiveContact) error {
+	socialLinks := c.SocialLinks
+	if len(socialLinks) == 0 {
+		socialLinks = json.RawMessage("[]")
+	}
+
+	cmdTag, err := s.db.Exec(ctx, `
+	INSERT INTO contact (
+		email,
+		phone,
+		management_agency,
+		management_email,
+		management_phone,
+		press_contact,
+		press_email,
+		press_phone,
+		social_links,
+		updated_at
+	)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`,
+		c.Email,
+		c.Phone,
+		c.ManagementAgency,
+		c.ManagementEmail,
+		c.ManagementPhone,
+		c.PressContact,
+		c.PressEmail,
+		c.PressPhone,
+		socialLinks,
+		c.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("exec failed: %w", err)
+	}
+
+	return checkAffected(cmdTag)
+}
@@ -0,0 +1,157 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/adamkadda/arman/internal/cms/model"
+)
+
+type PostgresAuditStore struct {
+	db Executor
+}
+
+func NewPostgresAuditStore(db Executor) *PostgresAuditStore {
+	return &PostgresAuditStore{
+		db: db,
+	}
+}
+
+type auditRow struct {
+	AuditID       int64             `db:"audit_id"`
+	ActorID       *int              `db:"actor_id"`
+	ActorUsername *string           `db:"actor_username"`
+	Operation     string            `db:"operation"`
+	EntityType    model.AuditEntity `db:"entity_type"`
+	EntityID      string            `db:"entity_id"`
+	Before        json.RawMessage   `db:"before"`
+	After         json.RawMessage   `db:"after"`
+	RequestID     *string           `db:"request_id"`
+	CreatedAt     time.Time         `db:"created_at"`
+}
+
+func (r *auditRow) toAuditEntry() model.AuditEntry {
+	return model.AuditEntry{
+		ID:         r.AuditID,
+		ActorID:    r.ActorID,
+		Actor:      r.ActorUsername,
+		Operation:  r.Operation,
+		EntityType: r.EntityType,
+		EntityID:   r.EntityID,
+		Before:     r.Before,
+		After:      r.After,
+		RequestID:  r.RequestID,
+		CreatedAt:  r.CreatedAt,
+	}
+}
+
+// Create appends an entry to the audit log. The id and time of the entry are
+// set by the database.
+func (s *PostgresAuditStore) Create(
+	ctx context.Context,
+	e model.AuditEntry,
+) error {
+	query := `
+	INSERT INTO audit_log (
+		actor_id,
+		actor_username,
+		operation,
+		entity_type,
+		entity_id,
+		before,
+		after,
+		request_id
+	)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	cmdTag, err := s.db.Exec(ctx, query,
+		e.ActorID,
+		e.Actor,
+		e.Operation,
+		e.EntityType,
+		e.EntityID,
+		e.Before,
+		e.After,
+		e.RequestID,
+	)
+	if err != nil {
+		return fmt.Errorf("exec failed: %w", err)
+	}
+
+	return checkAffected(cmdTag)
+}
+
+// List returns a page of the entries matching the filter, most recent first.
+// Only the page of the query is used.
+func (s *PostgresAuditStore) List(
+	ctx context.Context,
+	f model.AuditFilter,
+	q model.ListQuery,
+) (*model.Page[model.AuditEntry], error) {
+	limit := q.PageSize()
+
+	// Fetch one entry more than the page size to tell whether a next page
+	// exists, rather than counting a table that only ever grows.
+	query := fmt.Sprintf(`
+	SELECT
+		audit_id,
+		actor_id,
+		actor_username,
+		operation,
+		entity_type,
+		entity_id,
+		before,
+		after,
+		request_id,
+		created_at
+	FROM audit_log
+	WHERE ($1::int IS NULL OR actor_id = $1)
+	AND ($2::text = '' OR operation = $2)
+	AND ($3::text = '' OR entity_type = $3)
+	AND ($4::text = '' OR entity_id = $4)
+	AND ($5::text = '' OR request_id = $5)
+	AND ($6::timestamp IS NULL OR created_at >= $6)
+	AND ($7::timestamp IS NULL OR created_at < $7)
+	ORDER BY created_at DESC, audit_id DESC
+	LIMIT %d OFFSET %d
+	`, limit+1, q.Offset)
+
+	pgxRows, err := s.db.Query(ctx, query,
+		f.ActorID,
+		f.Operation,
+		string(f.EntityType),
+		f.EntityID,
+		f.RequestID,
+		f.From,
+		f.To,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	rows, err := collectRows[auditRow](pgxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]model.AuditEntry, len(rows))
+	for i, row := range rows {
+		entries[i] = row.toAuditEntry()
+	}
+
+	page := &model.Page[model.AuditEntry]{
+		Items: entries,
+	}
+
+	if len(entries) > limit {
+		page.Items = entries[:limit]
+
+		cursor := model.OffsetCursor{Offset: q.Offset + limit}.Encode()
+		page.NextCursor = &cursor
+	}
+
+	return page, nil
+}
@@ -3,6 +3,7 @@ package store
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/adamkadda/arman/internal/content"
 )
@@ -18,30 +19,35 @@ func NewBiographyStore(db Executor) *BiographyStore {
 }
 
 type biographyRow struct {
-	content string `db:"content"`
-	variant string `db:"variant"`
+	Content  string `db:"content"`
+	Variant  string `db:"variant"`
+	Language string `db:"language"`
 }
 
 func (r *biographyRow) toBiography() content.Biography {
 	return content.Biography{
-		Content: r.content,
-		Variant: content.BiographyVariant(r.variant),
+		Content:  r.Content,
+		Variant:  content.BiographyVariant(r.Variant),
+		Language: content.Language(r.Language),
 	}
 }
 
 func (s *BiographyStore) Get(
 	ctx context.Context,
 	variant content.BiographyVariant,
+	lang content.Language,
 ) (*content.Biography, error) {
 	query := `
 	SELECT
-		content
-		variant
+		content,
+		variant,
+		language
 	FROM biographies
 	WHERE variant = $1
+	AND language = $2
 	`
 
-	pgxRows, err := s.db.Query(ctx, query, variant)
+	pgxRows, err := s.db.Query(ctx, query, variant, lang)
 	if err != nil {
 		return nil, fmt.Errorf("query failed: %w", err)
 	}
@@ -56,22 +62,78 @@ func (s *BiographyStore) Get(
 	return &biography, nil
 }
 
+type biographyLanguageRow struct {
+	Variant  string `db:"variant"`
+	Language string `db:"language"`
+}
+
+// Languages returns the languages every Biography variant has been written in.
+// Variants without any text are absent from the result.
+func (s *BiographyStore) Languages(
+	ctx context.Context,
+) (map[content.BiographyVariant][]content.Language, error) {
+	query := `
+	SELECT
+		variant,
+		language
+	FROM biographies
+	ORDER BY variant, language
+	`
+
+	pgxRows, err := s.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	rows, err := collectRows[biographyLanguageRow](pgxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	languages := make(map[content.BiographyVariant][]content.Language)
+	for _, row := range rows {
+		variant := content.BiographyVariant(row.Variant)
+		languages[variant] = append(languages[variant], content.Language(row.Language))
+	}
+
+	return languages, nil
+}
+
+// Update replaces the text of a Biography and records authorID as its author.
+// A Biography not yet written in the passed language is created. Pass a nil
+// authorID for internal callers.
+//
+// Update does not keep the previous text; call Snapshot first, in the same
+// transaction.
 func (s *BiographyStore) Update(
 	ctx context.Context,
 	b content.Biography,
+	authorID *int,
 ) (*content.Biography, error) {
 	query := `
-	UPDATE biographies
+	INSERT INTO biographies (
+		variant,
+		language,
+		content,
+		updated_by
+	)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (variant, language) DO UPDATE
 	SET
-		content = $1
-	WHERE variant = $2
+		content = EXCLUDED.content,
+		updated_by = EXCLUDED.updated_by,
+		updated_at = CURRENT_TIMESTAMP
 	RETURNING
-		content
+		content,
+		variant,
+		language
 	`
 
 	pgxRows, err := s.db.Query(ctx, query,
-		b.Content,
 		b.Variant,
+		b.Language,
+		b.Content,
+		authorID,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("query failed: %w", err)
@@ -86,3 +148,146 @@ func (s *BiographyStore) Update(
 
 	return &biography, nil
 }
+
+// Snapshot records the current text of a Biography as a revision, keeping its
+// original author and time of writing. The Biography row is locked until the
+// end of the transaction, so that concurrent updates cannot lose a revision.
+//
+// A Biography not yet written in the passed language has nothing to record, so
+// Snapshot does nothing.
+func (s *BiographyStore) Snapshot(
+	ctx context.Context,
+	variant content.BiographyVariant,
+	lang content.Language,
+) error {
+	query := `
+	INSERT INTO biography_revisions (
+		variant,
+		language,
+		content,
+		author_id,
+		created_at
+	)
+	SELECT
+		variant,
+		language,
+		content,
+		updated_by,
+		updated_at
+	FROM biographies
+	WHERE variant = $1
+	AND language = $2
+	FOR UPDATE
+	`
+
+	if _, err := s.db.Exec(ctx, query, variant, lang); err != nil {
+		return fmt.Errorf("query failed: %w", err)
+	}
+
+	return nil
+}
+
+type biographyRevisionRow struct {
+	RevisionID int       `db:"revision_id"`
+	Variant    string    `db:"variant"`
+	Language   string    `db:"language"`
+	Content    string    `db:"content"`
+	AuthorID   *int      `db:"author_id"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+func (r *biographyRevisionRow) toBiographyRevision() content.BiographyRevision {
+	return content.BiographyRevision{
+		ID:        r.RevisionID,
+		Variant:   content.BiographyVariant(r.Variant),
+		Language:  content.Language(r.Language),
+		Content:   r.Content,
+		AuthorID:  r.AuthorID,
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+type BiographyRevisionStore struct {
+	db Executor
+}
+
+func NewBiographyRevisionStore(db Executor) *BiographyRevisionStore {
+	return &BiographyRevisionStore{
+		db: db,
+	}
+}
+
+// Get returns a revision of the given Biography. Revisions of other variants
+// or languages are reported as content.ErrResourceNotFound.
+func (s *BiographyRevisionStore) Get(
+	ctx context.Context,
+	variant content.BiographyVariant,
+	lang content.Language,
+	id int,
+) (*content.BiographyRevision, error) {
+	query := `
+	SELECT
+		revision_id,
+		variant,
+		language,
+		content,
+		author_id,
+		created_at
+	FROM biography_revisions
+	WHERE revision_id = $1
+	AND variant = $2
+	AND language = $3
+	`
+
+	pgxRows, err := s.db.Query(ctx, query, id, variant, lang)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	row, err := collectRow[biographyRevisionRow](pgxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	revision := row.toBiographyRevision()
+
+	return &revision, nil
+}
+
+// List returns every revision of the given Biography, most recent first.
+func (s *BiographyRevisionStore) List(
+	ctx context.Context,
+	variant content.BiographyVariant,
+	lang content.Language,
+) ([]content.BiographyRevision, error) {
+	query := `
+	SELECT
+		revision_id,
+		variant,
+		language,
+		content,
+		author_id,
+		created_at
+	FROM biography_revisions
+	WHERE variant = $1
+	AND language = $2
+	ORDER BY created_at DESC, revision_id DESC
+	`
+
+	pgxRows, err := s.db.Query(ctx, query, variant, lang)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	rows, err := collectRows[biographyRevisionRow](pgxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions := make([]content.BiographyRevision, len(rows))
+	for i, row := range rows {
+		revisions[i] = row.toBiographyRevision()
+	}
+
+	return revisions, nil
+}
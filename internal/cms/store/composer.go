@@ -19,24 +19,25 @@ func NewPostgresComposerStore(db Executor) *PostgresComposerStore {
 }
 
 type composerRow struct {
-	composerID int    `db:"composer_id"`
-	fullName   string `db:"full_name"`
-	shortName  string `db:"short_name"`
-	pieceCount int    `db:"piece_count"`
+	ComposerID int    `db:"composer_id"`
+	FullName   string `db:"full_name"`
+	ShortName  string `db:"short_name"`
+	PieceCount int    `db:"piece_count"`
+	TotalCount int    `db:"total_count"`
 }
 
 func (r *composerRow) toComposer() content.Composer {
 	return content.Composer{
-		ID:        r.composerID,
-		FullName:  r.fullName,
-		ShortName: r.shortName,
+		ID:        r.ComposerID,
+		FullName:  r.FullName,
+		ShortName: r.ShortName,
 	}
 }
 
 func (r *composerRow) toComposerWithDetails() model.ComposerWithDetails {
 	return model.ComposerWithDetails{
 		Composer:   r.toComposer(),
-		PieceCount: r.pieceCount,
+		PieceCount: r.PieceCount,
 	}
 }
 
@@ -68,6 +69,38 @@ func (s *PostgresComposerStore) Get(
 	return &composer, nil
 }
 
+// GetByFullName returns the Composer with the passed full name, ignoring case.
+// Full names are not unique; the oldest matching Composer is returned.
+func (s *PostgresComposerStore) GetByFullName(
+	ctx context.Context,
+	fullName string,
+) (*content.Composer, error) {
+	query := `
+	SELECT
+		composer_id,
+		full_name,
+		short_name
+	FROM composers
+	WHERE lower(full_name) = lower($1)
+	ORDER BY composer_id
+	LIMIT 1
+	`
+
+	pgxRows, err := s.db.Query(ctx, query, fullName)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	row, err := collectRow[composerRow](pgxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	composer := row.toComposer()
+
+	return &composer, nil
+}
+
 func (s *PostgresComposerStore) GetWithDetails(
 	ctx context.Context,
 	id int,
@@ -103,25 +136,54 @@ func (s *PostgresComposerStore) GetWithDetails(
 	return &composer, nil
 }
 
+// composerSortColumns maps model.ComposerSortFields to their columns.
+var composerSortColumns = map[string]string{
+	"id":          "c.composer_id",
+	"full_name":   "c.full_name",
+	"short_name":  "c.short_name",
+	"piece_count": "piece_count",
+}
+
+// ListWithDetails returns a page of composers with their piece counts. The
+// filter is matched against both the full and the short name.
 func (s *PostgresComposerStore) ListWithDetails(
 	ctx context.Context,
-) ([]model.ComposerWithDetails, error) {
+	q model.ListQuery,
+) (*model.Page[model.ComposerWithDetails], error) {
+	clause, err := pageClause(q, composerSortColumns, "c.composer_id")
+	if err != nil {
+		return nil, err
+	}
+
+	filter := `
+	WHERE $1 = ''
+	OR c.full_name ILIKE $1
+	OR c.short_name ILIKE $1
+	`
+
+	countQuery := `
+	SELECT COUNT(*)
+	FROM composers c
+	` + filter
+
 	query := `
 	SELECT
-		composer_id,
-		full_name,
-		short_name,
-	COALESCE(p.piece_count, 0) AS piece_count
+		c.composer_id,
+		c.full_name,
+		c.short_name,
+		COALESCE(p.piece_count, 0) AS piece_count,
+		COUNT(*) OVER () AS total_count
 	FROM composers c
 	LEFT JOIN (
 		SELECT composer_id, COUNT(*) AS piece_count
 		FROM pieces
 		GROUP BY composer_id
 	) p ON p.composer_id = c.composer_id
-	ORDER BY c.composer_id
-	`
+	` + filter + clause
+
+	pattern := likePattern(q.Filter)
 
-	pgxRows, err := s.db.Query(ctx, query)
+	pgxRows, err := s.db.Query(ctx, query, pattern)
 	if err != nil {
 		return nil, fmt.Errorf("query failed: %w", err)
 	}
@@ -131,12 +193,21 @@ func (s *PostgresComposerStore) ListWithDetails(
 		return nil, err
 	}
 
+	total := 0
+	if len(rows) > 0 {
+		total = rows[0].TotalCount
+	} else if q.Offset > 0 {
+		if err := s.db.QueryRow(ctx, countQuery, pattern).Scan(&total); err != nil {
+			return nil, fmt.Errorf("count failed: %w", err)
+		}
+	}
+
 	composers := make([]model.ComposerWithDetails, len(rows))
 	for i, row := range rows {
 		composers[i] = row.toComposerWithDetails()
 	}
 
-	return composers, nil
+	return model.NewPage(q, composers, total), nil
 }
 
 func (s *PostgresComposerStore) Create(
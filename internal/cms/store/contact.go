@@ -0,0 +1,168 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/adamkadda/arman/internal/content"
+)
+
+type PostgresContactStore struct {
+	db Executor
+}
+
+func NewPostgresContactStore(db Executor) *PostgresContactStore {
+	return &PostgresContactStore{
+		db: db,
+	}
+}
+
+// socialLinkRow is how a content.SocialLink is stored inside the social_links
+// JSONB column.
+type socialLinkRow struct {
+	Platform string `json:"platform"`
+	URL      string `json:"url"`
+}
+
+type contactRow struct {
+	Email            string          `db:"email"`
+	Phone            string          `db:"phone"`
+	ManagementAgency string          `db:"management_agency"`
+	ManagementEmail  string          `db:"management_email"`
+	ManagementPhone  string          `db:"management_phone"`
+	PressContact     string          `db:"press_contact"`
+	PressEmail       string          `db:"press_email"`
+	PressPhone       string          `db:"press_phone"`
+	SocialLinks      []socialLinkRow `db:"social_links"`
+}
+
+func (r *contactRow) toContact() content.Contact {
+	links := make([]content.SocialLink, len(r.SocialLinks))
+	for i, l := range r.SocialLinks {
+		links[i] = content.SocialLink{
+			Platform: l.Platform,
+			URL:      l.URL,
+		}
+	}
+
+	return content.Contact{
+		Email:            r.Email,
+		Phone:            r.Phone,
+		ManagementAgency: r.ManagementAgency,
+		ManagementEmail:  r.ManagementEmail,
+		ManagementPhone:  r.ManagementPhone,
+		PressContact:     r.PressContact,
+		PressEmail:       r.PressEmail,
+		PressPhone:       r.PressPhone,
+		SocialLinks:      links,
+	}
+}
+
+// Get returns the contact details. It returns content.ErrResourceNotFound
+// until they are set for the first time.
+func (s *PostgresContactStore) Get(
+	ctx context.Context,
+) (*content.Contact, error) {
+	query := `
+	SELECT
+		email,
+		phone,
+		management_agency,
+		management_email,
+		management_phone,
+		press_contact,
+		press_email,
+		press_phone,
+		social_links
+	FROM contact
+	`
+
+	pgxRows, err := s.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	row, err := collectRow[contactRow](pgxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	contact := row.toContact()
+
+	return &contact, nil
+}
+
+// Update replaces the contact details, creating them if they were never set.
+func (s *PostgresContactStore) Update(
+	ctx context.Context,
+	c content.Contact,
+) (*content.Contact, error) {
+	query := `
+	INSERT INTO contact (
+		email,
+		phone,
+		management_agency,
+		management_email,
+		management_phone,
+		press_contact,
+		press_email,
+		press_phone,
+		social_links
+	)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	ON CONFLICT (contact_id) DO UPDATE
+	SET
+		email = EXCLUDED.email,
+		phone = EXCLUDED.phone,
+		management_agency = EXCLUDED.management_agency,
+		management_email = EXCLUDED.management_email,
+		management_phone = EXCLUDED.management_phone,
+		press_contact = EXCLUDED.press_contact,
+		press_email = EXCLUDED.press_email,
+		press_phone = EXCLUDED.press_phone,
+		social_links = EXCLUDED.social_links,
+		updated_at = CURRENT_TIMESTAMP
+	RETURNING
+		email,
+		phone,
+		management_agency,
+		management_email,
+		management_phone,
+		press_contact,
+		press_email,
+		press_phone,
+		social_links
+	`
+
+	links := make([]socialLinkRow, len(c.SocialLinks))
+	for i, l := range c.SocialLinks {
+		links[i] = socialLinkRow{
+			Platform: l.Platform,
+			URL:      l.URL,
+		}
+	}
+
+	pgxRows, err := s.db.Query(ctx, query,
+		c.Email,
+		c.Phone,
+		c.ManagementAgency,
+		c.ManagementEmail,
+		c.ManagementPhone,
+		c.PressContact,
+		c.PressEmail,
+		c.PressPhone,
+		links,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	row, err := collectRow[contactRow](pgxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	contact := row.toContact()
+
+	return &contact, nil
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/adamkadda/arman/internal/cms/model"
 	"github.com/adamkadda/arman/internal/content"
+	"github.com/jackc/pgx/v5"
 )
 
 type EventStore struct {
@@ -20,36 +21,44 @@ func NewEventStore(db Executor) *EventStore {
 }
 
 type eventRow struct {
-	eventID     int            `db:"event_id"`
-	eventTitle  string         `db:"event_title"`
-	eventDate   *time.Time     `db:"event_date"`
-	ticketLink  *string        `db:"ticket_link"`
-	venueID     *int           `db:"venue_id"`
-	programmeID *int           `db:"programme_id"`
-	status      content.Status `db:"status"`
-	notes       *string        `db:"notes"`
-	createdAt   time.Time      `db:"created_at"`
-	updatedAt   time.Time      `db:"updated_at"`
+	EventID       int            `db:"event_id"`
+	EventTitle    string         `db:"event_title"`
+	EventDate     *time.Time     `db:"event_date"`
+	TicketLink    *string        `db:"ticket_link"`
+	VenueID       *int           `db:"venue_id"`
+	ProgrammeID   *int           `db:"programme_id"`
+	Status        content.Status `db:"status"`
+	Notes         *string        `db:"notes"`
+	PublishAt     *time.Time     `db:"publish_at"`
+	StatusReason  *string        `db:"status_reason"`
+	ReplacementID *int           `db:"replacement_event_id"`
+	Sequence      int            `db:"sequence"`
+	CreatedAt     time.Time      `db:"created_at"`
+	UpdatedAt     time.Time      `db:"updated_at"`
 }
 
 func (r *eventRow) toEvent() content.Event {
 	return content.Event{
-		ID:          r.eventID,
-		Title:       r.eventTitle,
-		Date:        r.eventDate,
-		TicketLink:  r.ticketLink,
-		VenueID:     r.venueID,
-		ProgrammeID: r.programmeID,
-		Status:      r.status,
-		Notes:       r.notes,
+		ID:            r.EventID,
+		Title:         r.EventTitle,
+		Date:          r.EventDate,
+		TicketLink:    r.TicketLink,
+		VenueID:       r.VenueID,
+		ProgrammeID:   r.ProgrammeID,
+		Status:        r.Status,
+		Notes:         r.Notes,
+		PublishAt:     r.PublishAt,
+		StatusReason:  r.StatusReason,
+		ReplacementID: r.ReplacementID,
 	}
 }
 
 func (r *eventRow) toEventWithTimestamps() model.EventWithTimestamps {
 	return model.EventWithTimestamps{
 		Event:     r.toEvent(),
-		CreatedAt: r.createdAt,
-		UpdatedAt: r.updatedAt,
+		Sequence:  r.Sequence,
+		CreatedAt: r.CreatedAt,
+		UpdatedAt: r.UpdatedAt,
 	}
 }
 
@@ -66,9 +75,12 @@ func (s *EventStore) Get(
 		venue_id,
 		programme_id,
 		status,
-		notes
+		notes,
+		publish_at,
+		status_reason,
+		replacement_event_id
 	FROM events
-	WHERE id = $1
+	WHERE event_id = $1
 	`
 
 	pgxRows, err := s.db.Query(ctx, query, id)
@@ -100,10 +112,14 @@ func (s *EventStore) GetWithTimestamps(
 		programme_id,
 		status,
 		notes,
+		publish_at,
+		status_reason,
+		replacement_event_id,
+		sequence,
 		created_at,
 		updated_at
 	FROM events
-	WHERE id = $1
+	WHERE event_id = $1
 	`
 
 	pgxRows, err := s.db.Query(ctx, query, id)
@@ -121,13 +137,45 @@ func (s *EventStore) GetWithTimestamps(
 	return &event, nil
 }
 
-func (s *EventStore) List(
-	ctx context.Context,
-	status *content.Status,
-	timeframe *content.Timeframe,
-) ([]content.Event, error) {
-	query := `
+// listEventsQuery selects a page of events. It is formatted with the sort
+// direction and the matching keyset comparison operator, so that both
+// directions remain static SQL. The parameters are:
+//
+//	$1 status, $2 timeframe, $3 from, $4 to,
+//	$5 cursor date, $6 cursor id, $7 limit
+//
+// Every filter is optional and skipped when NULL. Events without a date never
+// match a timeframe or date range, and are always listed last.
+const listEventsQuery = `
 	SELECT
+		%[1]s
+	FROM events
+	WHERE ($1::text IS NULL OR status = $1::text::event_status)
+	AND (NOT $8::bool OR status IN ('published', 'cancelled', 'postponed'))
+	AND (
+		$2::text IS NULL
+		OR ($2::text = 'upcoming' AND event_date >= CURRENT_TIMESTAMP)
+		OR ($2::text = 'past' AND event_date < CURRENT_TIMESTAMP)
+	)
+	AND ($3::timestamp IS NULL OR event_date >= $3::timestamp)
+	AND ($4::timestamp IS NULL OR event_date < $4::timestamp)
+	AND (
+		$6::int IS NULL
+		OR (
+			$5::timestamp IS NOT NULL
+			AND (
+				event_date %[3]s $5::timestamp
+				OR (event_date = $5::timestamp AND event_id %[3]s $6::int)
+				OR event_date IS NULL
+			)
+		)
+		OR ($5::timestamp IS NULL AND event_date IS NULL AND event_id %[3]s $6::int)
+	)
+	ORDER BY event_date %[2]s NULLS LAST, event_id %[2]s
+	LIMIT $7
+	`
+
+const eventColumns = `
 		event_id,
 		event_title,
 		event_date,
@@ -136,11 +184,51 @@ func (s *EventStore) List(
 		programme_id,
 		status,
 		notes,
-	FROM events
-	ORDER BY event_id DESC
-	`
+		publish_at,
+		status_reason,
+		replacement_event_id`
 
-	pgxRows, err := s.db.Query(ctx, query)
+const eventColumnsWithTimestamps = eventColumns + `,
+		sequence,
+		created_at,
+		updated_at`
+
+// listEvents runs listEventsQuery for the given query and returns one row
+// more than the page size, which tells the caller whether a next page exists.
+func (s *EventStore) listEvents(
+	ctx context.Context,
+	columns string,
+	q model.EventQuery,
+) ([]eventRow, error) {
+	direction, operator := "DESC", "<"
+	if q.Ascending() {
+		direction, operator = "ASC", ">"
+	}
+
+	query := fmt.Sprintf(listEventsQuery, columns, direction, operator)
+
+	limit := q.Limit
+	if limit == 0 {
+		limit = model.DefaultLimit
+	}
+
+	var cursorDate *time.Time
+	var cursorID *int
+	if q.Cursor != nil {
+		cursorDate = q.Cursor.Date
+		cursorID = &q.Cursor.ID
+	}
+
+	pgxRows, err := s.db.Query(ctx, query,
+		q.Status,
+		q.Timeframe,
+		q.From,
+		q.To,
+		cursorDate,
+		cursorID,
+		limit+1,
+		q.Public,
+	)
 	if err != nil {
 		return nil, fmt.Errorf("query failed: %w", err)
 	}
@@ -150,51 +238,78 @@ func (s *EventStore) List(
 		return nil, err
 	}
 
+	return rows, nil
+}
+
+// nextEventCursor trims the extra row fetched by listEvents and returns the
+// cursor of the next page, if there is one.
+func nextEventCursor(rows []eventRow, q model.EventQuery) ([]eventRow, *string) {
+	limit := q.Limit
+	if limit == 0 {
+		limit = model.DefaultLimit
+	}
+
+	if len(rows) <= limit {
+		return rows, nil
+	}
+
+	rows = rows[:limit]
+	last := rows[limit-1]
+
+	cursor := model.EventCursor{
+		Date: last.EventDate,
+		ID:   last.EventID,
+	}.Encode()
+
+	return rows, &cursor
+}
+
+// List returns a page of events matching the query. See model.EventQuery for
+// how events are filtered and ordered.
+func (s *EventStore) List(
+	ctx context.Context,
+	q model.EventQuery,
+) (*model.Page[content.Event], error) {
+	rows, err := s.listEvents(ctx, eventColumns, q)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, next := nextEventCursor(rows, q)
+
 	events := make([]content.Event, len(rows))
 	for i, row := range rows {
 		events[i] = row.toEvent()
 	}
 
-	return events, nil
+	return &model.Page[content.Event]{
+		Items:      events,
+		NextCursor: next,
+	}, nil
 }
 
+// ListWithTimestamps returns a page of events with their timestamps matching
+// the query. See model.EventQuery for how events are filtered and ordered.
 func (s *EventStore) ListWithTimestamps(
 	ctx context.Context,
-	status *content.Status,
-	timeframe *content.Timeframe,
-) ([]model.EventWithTimestamps, error) {
-	query := `
-	SELECT
-		event_id,
-		event_title,
-		event_date,
-		ticket_link,
-		venue_id,
-		programme_id,
-		status,
-		notes,
-		created_at,
-		updated_at
-	FROM events
-	ORDER BY event_id DESC
-	`
-
-	pgxRows, err := s.db.Query(ctx, query)
-	if err != nil {
-		return nil, fmt.Errorf("query failed: %w", err)
-	}
-
-	rows, err := collectRows[eventRow](pgxRows)
+	q model.EventQuery,
+) (*model.Page[model.EventWithTimestamps], error) {
+	rows, err := s.listEvents(ctx, eventColumnsWithTimestamps, q)
 	if err != nil {
 		return nil, err
 	}
 
+	rows, next := nextEventCursor(rows, q)
+
 	events := make([]model.EventWithTimestamps, len(rows))
 	for i, row := range rows {
 		events[i] = row.toEventWithTimestamps()
 	}
 
-	return events, nil
+	return &model.Page[model.EventWithTimestamps]{
+		Items:      events,
+		NextCursor: next,
+	}, nil
 }
 
 func (s *EventStore) Create(
@@ -208,9 +323,10 @@ func (s *EventStore) Create(
 		ticket_link,
 		venue_id,
 		programme_id,
-		notes
+		notes,
+		publish_at
 	)
-	VALUES ($1, $2, $3, $4, $5, $6)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
 	RETURNING
 		event_id,
 		event_title,
@@ -219,7 +335,10 @@ func (s *EventStore) Create(
 		venue_id,
 		programme_id,
 		status,
-		notes
+		notes,
+		publish_at,
+		status_reason,
+		replacement_event_id
 	`
 
 	pgxRows, err := s.db.Query(ctx, query,
@@ -229,6 +348,7 @@ func (s *EventStore) Create(
 		e.VenueID,
 		e.ProgrammeID,
 		e.Notes,
+		e.PublishAt,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("query failed: %w", err)
@@ -251,13 +371,15 @@ func (s *EventStore) Update(
 	query := `
 	UPDATE events
 	SET
-		event_title = $1
-		event_date = $2
-		ticket_link = $3
-		venue_id = $4
-		programme_id = $5
-		notes = $6
-	WHERE event_id = $7
+		event_title = $1,
+		event_date = $2,
+		ticket_link = $3,
+		venue_id = $4,
+		programme_id = $5,
+		notes = $6,
+		publish_at = $7,
+		sequence = sequence + 1
+	WHERE event_id = $8
 	RETURNING
 		event_id,
 		event_title,
@@ -266,7 +388,10 @@ func (s *EventStore) Update(
 		venue_id,
 		programme_id,
 		status,
-		notes
+		notes,
+		publish_at,
+		status_reason,
+		replacement_event_id
 	`
 
 	pgxRows, err := s.db.Query(ctx, query,
@@ -276,6 +401,7 @@ func (s *EventStore) Update(
 		e.VenueID,
 		e.ProgrammeID,
 		e.Notes,
+		e.PublishAt,
 		e.ID,
 	)
 	if err != nil {
@@ -292,18 +418,22 @@ func (s *EventStore) Update(
 	return &event, nil
 }
 
-func (s *EventStore) Draft(
+// UpdateNotes replaces the notes of an event. Notes are private to the
+// editors, so unlike Update, a change of notes is not a revision: neither the
+// sequence nor the update time of the event move.
+func (s *EventStore) UpdateNotes(
 	ctx context.Context,
 	id int,
+	notes *string,
 ) error {
 	query := `
 	UPDATE events
 	SET
-		status = 'draft'
+		notes = $2
 	WHERE event_id = $1
 	`
 
-	cmdTag, err := s.db.Exec(ctx, query, id)
+	cmdTag, err := s.db.Exec(ctx, query, id, notes)
 	if err != nil {
 		return fmt.Errorf("query failed: %w", err)
 	}
@@ -311,18 +441,46 @@ func (s *EventStore) Draft(
 	return checkAffected(cmdTag)
 }
 
-func (s *EventStore) Publish(
+// SetStatus moves an event from one status to another. The event is only
+// moved if it is still in the from status, otherwise content.ErrResourceNotFound
+// is returned, so that concurrent moves cannot skip a check of the transition.
+//
+// The reason and replacement are only kept while an event is called off.
+// Archiving keeps them, every other move clears them. The publication schedule
+// is cleared by every move. Like Update, every move counts as a revision.
+func (s *EventStore) SetStatus(
 	ctx context.Context,
 	id int,
+	from content.Status,
+	to content.Status,
+	reason *string,
+	replacementID *int,
 ) error {
 	query := `
 	UPDATE events
 	SET
-		status = 'published'
-	WHERE event_id = $1
+		status = $1,
+		status_reason = CASE
+			WHEN $1 = 'archived' THEN status_reason
+			ELSE $2
+		END,
+		replacement_event_id = CASE
+			WHEN $1 = 'archived' THEN replacement_event_id
+			ELSE $3
+		END,
+		publish_at = NULL,
+		sequence = sequence + 1
+	WHERE event_id = $4
+	AND status = $5
 	`
 
-	cmdTag, err := s.db.Exec(ctx, query, id)
+	cmdTag, err := s.db.Exec(ctx, query,
+		to,
+		reason,
+		replacementID,
+		id,
+		from,
+	)
 	if err != nil {
 		return fmt.Errorf("query failed: %w", err)
 	}
@@ -330,14 +488,136 @@ func (s *EventStore) Publish(
 	return checkAffected(cmdTag)
 }
 
-func (s *EventStore) Archive(
+// ListScheduled returns the ids of the drafts scheduled to be published at or
+// before the passed time, earliest first.
+func (s *EventStore) ListScheduled(
+	ctx context.Context,
+	before time.Time,
+) ([]int, error) {
+	query := `
+	SELECT event_id
+	FROM events
+	WHERE status = 'draft'
+	AND publish_at <= $1
+	ORDER BY publish_at, event_id
+	`
+
+	pgxRows, err := s.db.Query(ctx, query, before)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	ids, err := pgx.CollectRows(pgxRows, pgx.RowTo[int])
+	if err != nil {
+		return nil, fmt.Errorf("collect rows failed: %w", err)
+	}
+
+	return ids, nil
+}
+
+// ListExpired returns the ids of the events that took place, or were to take
+// place, before the passed time and are not yet archived or drafted.
+func (s *EventStore) ListExpired(
+	ctx context.Context,
+	before time.Time,
+) ([]int, error) {
+	query := `
+	SELECT event_id
+	FROM events
+	WHERE status IN ('published', 'cancelled', 'postponed')
+	AND event_date < $1
+	ORDER BY event_date, event_id
+	`
+
+	pgxRows, err := s.db.Query(ctx, query, before)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	ids, err := pgx.CollectRows(pgxRows, pgx.RowTo[int])
+	if err != nil {
+		return nil, fmt.Errorf("collect rows failed: %w", err)
+	}
+
+	return ids, nil
+}
+
+// ListCalendar returns the events dated from from up to to listed in the public
+// calendar, earliest first: published events, and the events called off after
+// being published.
+func (s *EventStore) ListCalendar(
+	ctx context.Context,
+	from time.Time,
+	to time.Time,
+) ([]model.EventWithTimestamps, error) {
+	query := `
+	SELECT` + eventColumnsWithTimestamps + `
+	FROM events
+	WHERE status IN ('published', 'cancelled', 'postponed')
+	AND event_date >= $1
+	AND event_date < $2
+	ORDER BY event_date, event_id
+	`
+
+	pgxRows, err := s.db.Query(ctx, query, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	rows, err := collectRows[eventRow](pgxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]model.EventWithTimestamps, len(rows))
+	for i, row := range rows {
+		events[i] = row.toEventWithTimestamps()
+	}
+
+	return events, nil
+}
+
+// ListRecent returns the latest published events, the most recently updated
+// first. At most limit events are returned.
+func (s *EventStore) ListRecent(
+	ctx context.Context,
+	limit int,
+) ([]model.EventWithTimestamps, error) {
+	query := `
+	SELECT` + eventColumnsWithTimestamps + `
+	FROM events
+	WHERE status = 'published'
+	ORDER BY updated_at DESC, event_id DESC
+	LIMIT $1
+	`
+
+	pgxRows, err := s.db.Query(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	rows, err := collectRows[eventRow](pgxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]model.EventWithTimestamps, len(rows))
+	for i, row := range rows {
+		events[i] = row.toEventWithTimestamps()
+	}
+
+	return events, nil
+}
+
+// Unschedule clears the publication schedule of an event.
+func (s *EventStore) Unschedule(
 	ctx context.Context,
 	id int,
 ) error {
 	query := `
 	UPDATE events
 	SET
-		status = 'archived'
+		publish_at = NULL
 	WHERE event_id = $1
 	`
 
@@ -349,6 +629,110 @@ func (s *EventStore) Archive(
 	return checkAffected(cmdTag)
 }
 
+type statusTransitionRow struct {
+	HistoryID  int            `db:"history_id"`
+	EventID    int            `db:"event_id"`
+	FromStatus content.Status `db:"from_status"`
+	ToStatus   content.Status `db:"to_status"`
+	Reason     *string        `db:"reason"`
+	ChangedBy  *int           `db:"changed_by"`
+	ChangedAt  time.Time      `db:"changed_at"`
+}
+
+func (r *statusTransitionRow) toStatusTransition() content.StatusTransition {
+	return content.StatusTransition{
+		ID:        r.HistoryID,
+		EventID:   r.EventID,
+		From:      r.FromStatus,
+		To:        r.ToStatus,
+		Reason:    r.Reason,
+		ChangedBy: r.ChangedBy,
+		ChangedAt: r.ChangedAt,
+	}
+}
+
+// RecordTransition adds a status change to the history of an event.
+func (s *EventStore) RecordTransition(
+	ctx context.Context,
+	t content.StatusTransition,
+) (*content.StatusTransition, error) {
+	query := `
+	INSERT INTO event_status_history (
+		event_id,
+		from_status,
+		to_status,
+		reason,
+		changed_by
+	)
+	VALUES ($1, $2, $3, $4, $5)
+	RETURNING
+		history_id,
+		event_id,
+		from_status,
+		to_status,
+		reason,
+		changed_by,
+		changed_at
+	`
+
+	pgxRows, err := s.db.Query(ctx, query,
+		t.EventID,
+		t.From,
+		t.To,
+		t.Reason,
+		t.ChangedBy,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	row, err := collectRow[statusTransitionRow](pgxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	transition := row.toStatusTransition()
+
+	return &transition, nil
+}
+
+// ListTransitions returns the status history of an event, most recent first.
+func (s *EventStore) ListTransitions(
+	ctx context.Context,
+	eventID int,
+) ([]content.StatusTransition, error) {
+	query := `
+	SELECT
+		history_id,
+		event_id,
+		from_status,
+		to_status,
+		reason,
+		changed_by,
+		changed_at
+	FROM event_status_history
+	WHERE event_id = $1
+	ORDER BY changed_at DESC, history_id DESC
+	`
+
+	pgxRows, err := s.db.Query(ctx, query, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	rows, err := collectRows[statusTransitionRow](pgxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	transitions := make([]content.StatusTransition, len(rows))
+	for i, row := range rows {
+		transitions[i] = row.toStatusTransition()
+	}
+
+	return transitions, nil
+}
+
 func (s *EventStore) Delete(
 	ctx context.Context,
 	id int,
@@ -0,0 +1,43 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/adamkadda/arman/internal/content"
+	"github.com/stretchr/testify/require"
+)
+
+// Editing the notes of an event is not a revision of it.
+func TestEventStore_UpdateNotes(t *testing.T) {
+	tx := testTx(t)
+	ctx := context.Background()
+
+	eventStore := NewEventStore(tx)
+
+	event, err := eventStore.Create(ctx, content.Event{Title: "Foo"})
+	require.NoError(t, err)
+
+	sequence := func() int {
+		var n int
+		err := tx.QueryRow(ctx,
+			"SELECT sequence FROM events WHERE event_id = $1",
+			event.ID,
+		).Scan(&n)
+		require.NoError(t, err)
+
+		return n
+	}
+
+	before := sequence()
+
+	notes := "foo"
+	require.NoError(t, eventStore.UpdateNotes(ctx, event.ID, &notes))
+	require.Equal(t, before, sequence())
+
+	got, err := eventStore.Get(ctx, event.ID)
+	require.NoError(t, err)
+	require.Equal(t, notes, *got.Notes)
+
+	require.ErrorIs(t, eventStore.UpdateNotes(ctx, 0, &notes), content.ErrResourceNotFound)
+}
@@ -0,0 +1,79 @@
+package store
+
+import (
+	"context"
+	"errors"
+
+	"github.com/adamkadda/arman/internal/cms/model"
+	"github.com/adamkadda/arman/internal/content"
+	"github.com/adamkadda/arman/pkg/queue"
+)
+
+// PostgresJobStore exposes the background job queue to the services. The queue
+// itself is implemented by the queue package; PostgresJobStore translates its
+// errors into content errors.
+type PostgresJobStore struct {
+	db Executor
+}
+
+func NewPostgresJobStore(db Executor) *PostgresJobStore {
+	return &PostgresJobStore{
+		db: db,
+	}
+}
+
+func jobError(err error) error {
+	if errors.Is(err, queue.ErrNotFound) {
+		return content.ErrResourceNotFound
+	}
+
+	return err
+}
+
+func (s *PostgresJobStore) Get(
+	ctx context.Context,
+	id int64,
+) (*queue.Job, error) {
+	job, err := queue.Get(ctx, s.db, id)
+	if err != nil {
+		return nil, jobError(err)
+	}
+
+	return job, nil
+}
+
+// List returns a page of jobs in the passed status, or of every job if status
+// is nil, most recent first. The filter and sort of q are not supported.
+func (s *PostgresJobStore) List(
+	ctx context.Context,
+	status *queue.Status,
+	q model.ListQuery,
+) (*model.Page[queue.Job], error) {
+	limit := q.PageSize()
+
+	// Fetch one job more than the page size to tell whether a next page exists.
+	jobs, err := queue.List(ctx, s.db, status, q.Offset, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &model.Page[queue.Job]{
+		Items: jobs,
+	}
+
+	if len(jobs) > limit {
+		page.Items = jobs[:limit]
+
+		cursor := model.OffsetCursor{Offset: q.Offset + limit}.Encode()
+		page.NextCursor = &cursor
+	}
+
+	return page, nil
+}
+
+func (s *PostgresJobStore) Retry(
+	ctx context.Context,
+	id int64,
+) error {
+	return jobError(queue.Retry(ctx, s.db, id))
+}
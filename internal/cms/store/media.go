@@ -0,0 +1,450 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/adamkadda/arman/internal/cms/model"
+	"github.com/adamkadda/arman/internal/content"
+)
+
+type PostgresMediaStore struct {
+	db Executor
+}
+
+func NewPostgresMediaStore(db Executor) *PostgresMediaStore {
+	return &PostgresMediaStore{
+		db: db,
+	}
+}
+
+type mediaRow struct {
+	MediaID    int       `db:"media_id"`
+	Filename   string    `db:"filename"`
+	MimeType   string    `db:"mime_type"`
+	Size       int64     `db:"size"`
+	Checksum   string    `db:"checksum"`
+	StorageKey string    `db:"storage_key"`
+	AltText    string    `db:"alt_text"`
+	Credits    string    `db:"credits"`
+	CreatedAt  time.Time `db:"created_at"`
+	EventCount int       `db:"event_count"`
+	TotalCount int       `db:"total_count"`
+}
+
+func (r *mediaRow) toMedia() content.Media {
+	return content.Media{
+		ID:         r.MediaID,
+		Filename:   r.Filename,
+		MIMEType:   r.MimeType,
+		Size:       r.Size,
+		Checksum:   r.Checksum,
+		StorageKey: r.StorageKey,
+		AltText:    r.AltText,
+		Credits:    r.Credits,
+		CreatedAt:  r.CreatedAt,
+	}
+}
+
+func (r *mediaRow) toMediaWithDetails() model.MediaWithDetails {
+	return model.MediaWithDetails{
+		Media:      r.toMedia(),
+		EventCount: r.EventCount,
+	}
+}
+
+// mediaOwnerTables maps each model.MediaOwnerType to its attachment table and
+// the column referencing the owner. Only names from this map reach the SQL.
+var mediaOwnerTables = map[model.MediaOwnerType]struct {
+	table  string
+	column string
+}{
+	model.MediaOwnerEvent:     {"event_media", "event_id"},
+	model.MediaOwnerVenue:     {"venue_media", "venue_id"},
+	model.MediaOwnerComposer:  {"composer_media", "composer_id"},
+	model.MediaOwnerBiography: {"biography_media", "variant"},
+}
+
+func mediaOwnerKey(o model.MediaOwner) any {
+	if o.Type == model.MediaOwnerBiography {
+		return string(o.Variant)
+	}
+
+	return o.ID
+}
+
+func (s *PostgresMediaStore) Get(
+	ctx context.Context,
+	id int,
+) (*content.Media, error) {
+	query := `
+	SELECT
+		media_id,
+		filename,
+		mime_type,
+		size,
+		checksum,
+		storage_key,
+		alt_text,
+		credits,
+		created_at
+	FROM media
+	WHERE media_id = $1
+	`
+
+	pgxRows, err := s.db.Query(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	row, err := collectRow[mediaRow](pgxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	media := row.toMedia()
+
+	return &media, nil
+}
+
+// GetWithDetails returns the Media along with the number of published events
+// it is attached to.
+func (s *PostgresMediaStore) GetWithDetails(
+	ctx context.Context,
+	id int,
+) (*model.MediaWithDetails, error) {
+	query := `
+	SELECT
+		m.media_id,
+		m.filename,
+		m.mime_type,
+		m.size,
+		m.checksum,
+		m.storage_key,
+		m.alt_text,
+		m.credits,
+		m.created_at,
+		(
+			SELECT COUNT(*)
+			FROM event_media em
+			JOIN events e ON e.event_id = em.event_id
+			WHERE em.media_id = m.media_id
+			AND e.status = 'published'
+		) AS event_count
+	FROM media m
+	WHERE m.media_id = $1
+	`
+
+	pgxRows, err := s.db.Query(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	row, err := collectRow[mediaRow](pgxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	media := row.toMediaWithDetails()
+
+	return &media, nil
+}
+
+// mediaSortColumns maps model.MediaSortFields to their columns.
+var mediaSortColumns = map[string]string{
+	"id":         "m.media_id",
+	"filename":   "m.filename",
+	"size":       "m.size",
+	"created_at": "m.created_at",
+}
+
+// ListWithDetails returns a page of media with their published event counts.
+// The filter is matched against the filename, alt text and credits.
+func (s *PostgresMediaStore) ListWithDetails(
+	ctx context.Context,
+	q model.ListQuery,
+) (*model.Page[model.MediaWithDetails], error) {
+	clause, err := pageClause(q, mediaSortColumns, "m.media_id")
+	if err != nil {
+		return nil, err
+	}
+
+	filter := `
+	WHERE $1 = ''
+	OR m.filename ILIKE $1
+	OR m.alt_text ILIKE $1
+	OR m.credits ILIKE $1
+	`
+
+	countQuery := `
+	SELECT COUNT(*)
+	FROM media m
+	` + filter
+
+	query := `
+	SELECT
+		m.media_id,
+		m.filename,
+		m.mime_type,
+		m.size,
+		m.checksum,
+		m.storage_key,
+		m.alt_text,
+		m.credits,
+		m.created_at,
+		COALESCE(e.event_count, 0) AS event_count,
+		COUNT(*) OVER () AS total_count
+	FROM media m
+	LEFT JOIN (
+		SELECT em.media_id, COUNT(*) AS event_count
+		FROM event_media em
+		JOIN events e ON e.event_id = em.event_id
+		WHERE e.status = 'published'
+		GROUP BY em.media_id
+	) e ON e.media_id = m.media_id
+	` + filter + clause
+
+	pattern := likePattern(q.Filter)
+
+	pgxRows, err := s.db.Query(ctx, query, pattern)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	rows, err := collectRows[mediaRow](pgxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	total := 0
+	if len(rows) > 0 {
+		total = rows[0].TotalCount
+	} else if q.Offset > 0 {
+		if err := s.db.QueryRow(ctx, countQuery, pattern).Scan(&total); err != nil {
+			return nil, fmt.Errorf("count failed: %w", err)
+		}
+	}
+
+	media := make([]model.MediaWithDetails, len(rows))
+	for i, row := range rows {
+		media[i] = row.toMediaWithDetails()
+	}
+
+	return model.NewPage(q, media, total), nil
+}
+
+func (s *PostgresMediaStore) Create(
+	ctx context.Context,
+	m content.Media,
+) (*content.Media, error) {
+	query := `
+	INSERT INTO media (
+		filename,
+		mime_type,
+		size,
+		checksum,
+		storage_key,
+		alt_text,
+		credits
+	)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	RETURNING
+		media_id,
+		filename,
+		mime_type,
+		size,
+		checksum,
+		storage_key,
+		alt_text,
+		credits,
+		created_at
+	`
+
+	pgxRows, err := s.db.Query(ctx, query,
+		m.Filename,
+		m.MIMEType,
+		m.Size,
+		m.Checksum,
+		m.StorageKey,
+		m.AltText,
+		m.Credits,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	row, err := collectRow[mediaRow](pgxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	media := row.toMedia()
+
+	return &media, nil
+}
+
+// Update updates the descriptive fields of the Media. The file and the fields
+// derived from it cannot change; upload new Media instead.
+func (s *PostgresMediaStore) Update(
+	ctx context.Context,
+	m content.Media,
+) (*content.Media, error) {
+	query := `
+	UPDATE media
+	SET
+		alt_text = $1,
+		credits = $2
+	WHERE media_id = $3
+	RETURNING
+		media_id,
+		filename,
+		mime_type,
+		size,
+		checksum,
+		storage_key,
+		alt_text,
+		credits,
+		created_at
+	`
+
+	pgxRows, err := s.db.Query(ctx, query,
+		m.AltText,
+		m.Credits,
+		m.ID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	row, err := collectRow[mediaRow](pgxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	media := row.toMedia()
+
+	return &media, nil
+}
+
+func (s *PostgresMediaStore) Delete(
+	ctx context.Context,
+	id int,
+) error {
+	query := `
+	DELETE
+	FROM media
+	WHERE media_id = $1
+	`
+
+	cmdTag, err := s.db.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("query failed: %w", err)
+	}
+
+	return checkAffected(cmdTag)
+}
+
+// ListByOwner returns the Media attached to the owner, in attachment order.
+func (s *PostgresMediaStore) ListByOwner(
+	ctx context.Context,
+	owner model.MediaOwner,
+) ([]content.Media, error) {
+	t, ok := mediaOwnerTables[owner.Type]
+	if !ok {
+		return nil, model.ErrInvalidMediaOwner
+	}
+
+	query := fmt.Sprintf(`
+	SELECT
+		m.media_id,
+		m.filename,
+		m.mime_type,
+		m.size,
+		m.checksum,
+		m.storage_key,
+		m.alt_text,
+		m.credits,
+		m.created_at
+	FROM %[1]s a
+	JOIN media m ON m.media_id = a.media_id
+	WHERE a.%[2]s = $1
+	ORDER BY a.position ASC
+	`, t.table, t.column)
+
+	pgxRows, err := s.db.Query(ctx, query, mediaOwnerKey(owner))
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	rows, err := collectRows[mediaRow](pgxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	media := make([]content.Media, len(rows))
+	for i, row := range rows {
+		media[i] = row.toMedia()
+	}
+
+	return media, nil
+}
+
+// UpdateByOwner replaces the Media attached to the owner with the given ids,
+// in order. It should run inside a transaction.
+func (s *PostgresMediaStore) UpdateByOwner(
+	ctx context.Context,
+	owner model.MediaOwner,
+	ids []int,
+) ([]content.Media, error) {
+	t, ok := mediaOwnerTables[owner.Type]
+	if !ok {
+		return nil, model.ErrInvalidMediaOwner
+	}
+
+	key := mediaOwnerKey(owner)
+
+	deleteQuery := fmt.Sprintf(`
+	DELETE
+	FROM %[1]s
+	WHERE %[2]s = $1
+	`, t.table, t.column)
+
+	_, err := s.db.Exec(ctx, deleteQuery, key)
+	if err != nil {
+		return nil, fmt.Errorf("delete query failed: %w", err)
+	}
+
+	if len(ids) == 0 {
+		return []content.Media{}, nil
+	}
+
+	positions := make([]int, len(ids))
+	for i := range ids {
+		positions[i] = i + 1
+	}
+
+	insertQuery := fmt.Sprintf(`
+	INSERT INTO %[1]s (
+		%[2]s,
+		media_id,
+		position
+	)
+	SELECT
+		$1,
+		media_id,
+		position
+	FROM UNNEST($2::int[], $3::int[]) AS t(media_id, position)
+	`, t.table, t.column)
+
+	_, err = s.db.Exec(ctx, insertQuery,
+		key,
+		ids,
+		positions,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("insert query failed: %w", err)
+	}
+
+	return s.ListByOwner(ctx, owner)
+}
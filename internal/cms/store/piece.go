@@ -19,24 +19,25 @@ func NewPostgresPieceStore(db Executor) *PostgresPieceStore {
 }
 
 type pieceRow struct {
-	pieceID         int    `db:"piece_id"`
-	pieceTitle      string `db:"piece_title"`
-	composerID      int    `db:"composer_id"`
-	programme_count int    `db:"programme_count"`
+	PieceID        int    `db:"piece_id"`
+	PieceTitle     string `db:"piece_title"`
+	ComposerID     int    `db:"composer_id"`
+	ProgrammeCount int    `db:"programme_count"`
+	TotalCount     int    `db:"total_count"`
 }
 
 func (r *pieceRow) toPiece() content.Piece {
 	return content.Piece{
-		ID:         r.pieceID,
-		Title:      r.pieceTitle,
-		ComposerID: r.composerID,
+		ID:         r.PieceID,
+		Title:      r.PieceTitle,
+		ComposerID: r.ComposerID,
 	}
 }
 
 func (r *pieceRow) toPieceWithDetails() model.PieceWithDetails {
 	return model.PieceWithDetails{
 		Piece:          r.toPiece(),
-		ProgrammeCount: r.programme_count,
+		ProgrammeCount: r.ProgrammeCount,
 	}
 }
 
@@ -68,6 +69,39 @@ func (s *PostgresPieceStore) Get(
 	return &piece, nil
 }
 
+// GetByTitle returns the Piece of a Composer with the passed title, ignoring
+// case.
+func (s *PostgresPieceStore) GetByTitle(
+	ctx context.Context,
+	composerID int,
+	title string,
+) (*content.Piece, error) {
+	query := `
+	SELECT
+		piece_id,
+		piece_title,
+		composer_id
+	FROM pieces
+	WHERE composer_id = $1 AND lower(piece_title) = lower($2)
+	ORDER BY piece_id
+	LIMIT 1
+	`
+
+	pgxRows, err := s.db.Query(ctx, query, composerID, title)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	row, err := collectRow[pieceRow](pgxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	piece := row.toPiece()
+
+	return &piece, nil
+}
+
 func (s *PostgresPieceStore) GetWithDetails(
 	ctx context.Context,
 	id int,
@@ -103,25 +137,57 @@ func (s *PostgresPieceStore) GetWithDetails(
 	return &piece, nil
 }
 
+// pieceSortColumns maps model.PieceSortFields to their columns.
+var pieceSortColumns = map[string]string{
+	"id":              "p.piece_id",
+	"title":           "p.piece_title",
+	"programme_count": "programme_count",
+}
+
+// ListWithDetails returns a page of pieces with their programme counts. The
+// filter is matched against the title of the piece and the names of its
+// composer.
 func (s *PostgresPieceStore) ListWithDetails(
 	ctx context.Context,
-) ([]model.PieceWithDetails, error) {
+	q model.ListQuery,
+) (*model.Page[model.PieceWithDetails], error) {
+	clause, err := pageClause(q, pieceSortColumns, "p.piece_id")
+	if err != nil {
+		return nil, err
+	}
+
+	filter := `
+	WHERE $1 = ''
+	OR p.piece_title ILIKE $1
+	OR c.full_name ILIKE $1
+	OR c.short_name ILIKE $1
+	`
+
+	countQuery := `
+	SELECT COUNT(*)
+	FROM pieces p
+	JOIN composers c ON c.composer_id = p.composer_id
+	` + filter
+
 	query := `
 	SELECT
-		piece_id,
-		piece_title,
-		composer_id
-	COALESCE() AS programme_count
+		p.piece_id,
+		p.piece_title,
+		p.composer_id,
+		COALESCE(pp.programme_count, 0) AS programme_count,
+		COUNT(*) OVER () AS total_count
 	FROM pieces p
+	JOIN composers c ON c.composer_id = p.composer_id
 	LEFT JOIN (
 		SELECT piece_id, COUNT(*) AS programme_count
 		FROM programme_pieces
-		GROUP BY composer_id
-	) pp on pp.piece_id = p.piece_id
-	ORDER BY p.piece_id
-	`
+		GROUP BY piece_id
+	) pp ON pp.piece_id = p.piece_id
+	` + filter + clause
+
+	pattern := likePattern(q.Filter)
 
-	pgxRows, err := s.db.Query(ctx, query)
+	pgxRows, err := s.db.Query(ctx, query, pattern)
 	if err != nil {
 		return nil, fmt.Errorf("query failed: %w", err)
 	}
@@ -131,12 +197,21 @@ func (s *PostgresPieceStore) ListWithDetails(
 		return nil, err
 	}
 
+	total := 0
+	if len(rows) > 0 {
+		total = rows[0].TotalCount
+	} else if q.Offset > 0 {
+		if err := s.db.QueryRow(ctx, countQuery, pattern).Scan(&total); err != nil {
+			return nil, fmt.Errorf("count failed: %w", err)
+		}
+	}
+
 	pieces := make([]model.PieceWithDetails, len(rows))
 	for i, row := range rows {
 		pieces[i] = row.toPieceWithDetails()
 	}
 
-	return pieces, nil
+	return model.NewPage(q, pieces, total), nil
 }
 
 func (s *PostgresPieceStore) Create(
@@ -19,22 +19,23 @@ func NewProgrammeStore(db Executor) *ProgrammeStore {
 }
 
 type programmeRow struct {
-	programmeID    int    `db:"programme_id"`
-	programmeTitle string `db:"programme_title"`
-	eventCount     int    `db:"event_count"`
+	ProgrammeID    int    `db:"programme_id"`
+	ProgrammeTitle string `db:"programme_title"`
+	EventCount     int    `db:"event_count"`
+	TotalCount     int    `db:"total_count"`
 }
 
 func (r *programmeRow) toProgramme() content.Programme {
 	return content.Programme{
-		ID:    r.programmeID,
-		Title: r.programmeTitle,
+		ID:    r.ProgrammeID,
+		Title: r.ProgrammeTitle,
 	}
 }
 
 func (r *programmeRow) toProgrammeWithDetails() model.ProgrammeWithDetails {
 	return model.ProgrammeWithDetails{
 		Programme:  r.toProgramme(),
-		EventCount: r.eventCount,
+		EventCount: r.EventCount,
 	}
 }
 
@@ -65,6 +66,38 @@ func (s *ProgrammeStore) Get(
 	return &programme, nil
 }
 
+// ListByIDs returns the Programmes with the passed ids, in no particular
+// order. Ids without a Programme are left out.
+func (s *ProgrammeStore) ListByIDs(
+	ctx context.Context,
+	ids []int,
+) ([]content.Programme, error) {
+	query := `
+	SELECT
+		programme_id,
+		programme_title
+	FROM programmes
+	WHERE programme_id = ANY($1)
+	`
+
+	pgxRows, err := s.db.Query(ctx, query, ids)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	rows, err := collectRows[programmeRow](pgxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	programmes := make([]content.Programme, len(rows))
+	for i, row := range rows {
+		programmes[i] = row.toProgramme()
+	}
+
+	return programmes, nil
+}
+
 func (s *ProgrammeStore) GetWithDetails(
 	ctx context.Context,
 	id int,
@@ -99,29 +132,56 @@ func (s *ProgrammeStore) GetWithDetails(
 	return &programme, nil
 }
 
-// ListWithDetails returns a list of programmes with additional details. This method
-// was the alternative to an N+1 query approach of making multiple queries for each
-// Programme. It's a tradeoff between performance and adherence to a (strict) clean
-// separation of concerns.
+// programmeSortColumns maps model.ProgrammeSortFields to their columns.
+var programmeSortColumns = map[string]string{
+	"id":          "p.programme_id",
+	"title":       "p.programme_title",
+	"event_count": "event_count",
+}
+
+// ListWithDetails returns a page of programmes with additional details. This
+// method was the alternative to an N+1 query approach of making multiple
+// queries for each Programme. It's a tradeoff between performance and adherence
+// to a (strict) clean separation of concerns.
+//
+// The filter is matched against the programme title.
 func (s *ProgrammeStore) ListWithDetails(
 	ctx context.Context,
-) ([]model.ProgrammeWithDetails, error) {
+	q model.ListQuery,
+) (*model.Page[model.ProgrammeWithDetails], error) {
+	clause, err := pageClause(q, programmeSortColumns, "p.programme_id")
+	if err != nil {
+		return nil, err
+	}
+
+	filter := `
+	WHERE $1 = ''
+	OR p.programme_title ILIKE $1
+	`
+
+	countQuery := `
+	SELECT COUNT(*)
+	FROM programmes p
+	` + filter
+
 	query := `
 	SELECT
-		programme_id,
-		programme_title
-	COALESCE(e.event_count, 0) AS event_count
+		p.programme_id,
+		p.programme_title,
+		COALESCE(e.event_count, 0) AS event_count,
+		COUNT(*) OVER () AS total_count
 	FROM programmes p
 	LEFT JOIN (
-	SELECT programme_id, COUNT(*) AS event_count
-	FROM events
-	WHERE status = "published"
-	GROUP BY programme_id
+		SELECT programme_id, COUNT(*) AS event_count
+		FROM events
+		WHERE status = 'published'
+		GROUP BY programme_id
 	) e ON e.programme_id = p.programme_id
-	ORDER BY p.programme_id
-	`
+	` + filter + clause
+
+	pattern := likePattern(q.Filter)
 
-	pgxRows, err := s.db.Query(ctx, query)
+	pgxRows, err := s.db.Query(ctx, query, pattern)
 	if err != nil {
 		return nil, fmt.Errorf("query failed: %w", err)
 	}
@@ -131,12 +191,21 @@ func (s *ProgrammeStore) ListWithDetails(
 		return nil, err
 	}
 
+	total := 0
+	if len(rows) > 0 {
+		total = rows[0].TotalCount
+	} else if q.Offset > 0 {
+		if err := s.db.QueryRow(ctx, countQuery, pattern).Scan(&total); err != nil {
+			return nil, fmt.Errorf("count failed: %w", err)
+		}
+	}
+
 	programmes := make([]model.ProgrammeWithDetails, len(rows))
 	for i, row := range rows {
 		programmes[i] = row.toProgrammeWithDetails()
 	}
 
-	return programmes, nil
+	return model.NewPage(q, programmes, total), nil
 }
 
 func (s *ProgrammeStore) Create(
@@ -231,26 +300,27 @@ func NewProgrammePieceStore(db Executor) *ProgrammePieceStore {
 }
 
 type programmePieceRow struct {
-	pieceID    int    `db:"piece_id"`
-	pieceTitle string `db:"piece_title"`
-	composerID int    `db:"composer_id"`
-	fullName   string `db:"full_name"`
-	shortName  string `db:"short_name"`
-	sequence   int    `db:"sequence"`
+	ProgrammeID int    `db:"programme_id"`
+	PieceID     int    `db:"piece_id"`
+	PieceTitle  string `db:"piece_title"`
+	ComposerID  int    `db:"composer_id"`
+	FullName    string `db:"full_name"`
+	ShortName   string `db:"short_name"`
+	Sequence    int    `db:"sequence"`
 }
 
 func (r *programmePieceRow) toProgrammePiece() content.ProgrammePiece {
 	return content.ProgrammePiece{
 		Piece: content.Piece{
-			ID:    r.pieceID,
-			Title: r.pieceTitle,
+			ID:    r.PieceID,
+			Title: r.PieceTitle,
 		},
 		Composer: content.Composer{
-			ID:        r.composerID,
-			FullName:  r.fullName,
-			ShortName: r.shortName,
+			ID:        r.ComposerID,
+			FullName:  r.FullName,
+			ShortName: r.ShortName,
 		},
-		Sequence: r.sequence,
+		Sequence: r.Sequence,
 	}
 }
 
@@ -291,6 +361,46 @@ func (s *ProgrammePieceStore) ListByProgrammeID(
 	return programmePieces, nil
 }
 
+// ListByProgrammeIDs returns the ProgrammePieces of every passed Programme by
+// Programme id, sorted by sequence.
+func (s *ProgrammePieceStore) ListByProgrammeIDs(
+	ctx context.Context,
+	ids []int,
+) (map[int][]content.ProgrammePiece, error) {
+	query := `
+	SELECT
+	pp.programme_id,
+	p.piece_id,
+	p.piece_title,
+	c.composer_id,
+	c.full_name,
+	c.short_name,
+	pp.sequence
+	FROM programme_pieces pp
+	JOIN pieces p ON p.piece_id = pp.piece_id
+	JOIN composers c ON c.composer_id = p.composer_id
+	WHERE pp.programme_id = ANY($1)
+	ORDER BY pp.programme_id, pp.sequence ASC
+	`
+
+	pgxRows, err := s.db.Query(ctx, query, ids)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	rows, err := collectRows[programmePieceRow](pgxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	programmePieces := make(map[int][]content.ProgrammePiece, len(ids))
+	for _, row := range rows {
+		programmePieces[row.ProgrammeID] = append(programmePieces[row.ProgrammeID], row.toProgrammePiece())
+	}
+
+	return programmePieces, nil
+}
+
 func (s *ProgrammePieceStore) Update(
 	ctx context.Context,
 	id int,
@@ -0,0 +1,244 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/adamkadda/arman/internal/cms/model"
+	"github.com/adamkadda/arman/internal/content"
+)
+
+// PostgresSearchStore runs full-text searches against the search_vector
+// columns of each searchable table. Both the indexed text and the search text
+// are stripped of accents, see f_unaccent in the schema.
+//
+// Results reuse the row types of the resource stores, so they come back in the
+// same shapes as the corresponding listings.
+type PostgresSearchStore struct {
+	db Executor
+}
+
+func NewPostgresSearchStore(db Executor) *PostgresSearchStore {
+	return &PostgresSearchStore{
+		db: db,
+	}
+}
+
+func (s *PostgresSearchStore) SearchComposers(
+	ctx context.Context,
+	q model.SearchQuery,
+) ([]model.ComposerWithDetails, error) {
+	query := `
+	WITH q AS (
+		SELECT websearch_to_tsquery('simple', f_unaccent($1)) AS query
+	)
+	SELECT
+		c.composer_id,
+		c.full_name,
+		c.short_name,
+		COALESCE(p.piece_count, 0) AS piece_count
+	FROM composers c
+	CROSS JOIN q
+	LEFT JOIN (
+		SELECT composer_id, COUNT(*) AS piece_count
+		FROM pieces
+		GROUP BY composer_id
+	) p ON p.composer_id = c.composer_id
+	WHERE c.search_vector @@ q.query
+	ORDER BY ts_rank(c.search_vector, q.query) DESC, c.composer_id
+	LIMIT $2
+	`
+
+	pgxRows, err := s.db.Query(ctx, query, q.Text, q.PageSize())
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	rows, err := collectRows[composerRow](pgxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	composers := make([]model.ComposerWithDetails, len(rows))
+	for i, row := range rows {
+		composers[i] = row.toComposerWithDetails()
+	}
+
+	return composers, nil
+}
+
+func (s *PostgresSearchStore) SearchPieces(
+	ctx context.Context,
+	q model.SearchQuery,
+) ([]model.PieceWithDetails, error) {
+	query := `
+	WITH q AS (
+		SELECT websearch_to_tsquery('simple', f_unaccent($1)) AS query
+	)
+	SELECT
+		p.piece_id,
+		p.piece_title,
+		p.composer_id,
+		COALESCE(pp.programme_count, 0) AS programme_count
+	FROM pieces p
+	CROSS JOIN q
+	LEFT JOIN (
+		SELECT piece_id, COUNT(*) AS programme_count
+		FROM programme_pieces
+		GROUP BY piece_id
+	) pp ON pp.piece_id = p.piece_id
+	WHERE p.search_vector @@ q.query
+	ORDER BY ts_rank(p.search_vector, q.query) DESC, p.piece_id
+	LIMIT $2
+	`
+
+	pgxRows, err := s.db.Query(ctx, query, q.Text, q.PageSize())
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	rows, err := collectRows[pieceRow](pgxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	pieces := make([]model.PieceWithDetails, len(rows))
+	for i, row := range rows {
+		pieces[i] = row.toPieceWithDetails()
+	}
+
+	return pieces, nil
+}
+
+func (s *PostgresSearchStore) SearchProgrammes(
+	ctx context.Context,
+	q model.SearchQuery,
+) ([]model.ProgrammeWithDetails, error) {
+	query := `
+	WITH q AS (
+		SELECT websearch_to_tsquery('simple', f_unaccent($1)) AS query
+	)
+	SELECT
+		p.programme_id,
+		p.programme_title,
+		COALESCE(e.event_count, 0) AS event_count
+	FROM programmes p
+	CROSS JOIN q
+	LEFT JOIN (
+		SELECT programme_id, COUNT(*) AS event_count
+		FROM events
+		WHERE status = 'published'
+		GROUP BY programme_id
+	) e ON e.programme_id = p.programme_id
+	WHERE p.search_vector @@ q.query
+	ORDER BY ts_rank(p.search_vector, q.query) DESC, p.programme_id
+	LIMIT $2
+	`
+
+	pgxRows, err := s.db.Query(ctx, query, q.Text, q.PageSize())
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	rows, err := collectRows[programmeRow](pgxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	programmes := make([]model.ProgrammeWithDetails, len(rows))
+	for i, row := range rows {
+		programmes[i] = row.toProgrammeWithDetails()
+	}
+
+	return programmes, nil
+}
+
+func (s *PostgresSearchStore) SearchVenues(
+	ctx context.Context,
+	q model.SearchQuery,
+) ([]model.VenueWithDetails, error) {
+	query := `
+	WITH q AS (
+		SELECT websearch_to_tsquery('simple', f_unaccent($1)) AS query
+	)
+	SELECT
+		v.venue_id,
+		v.venue_name,
+		v.full_address,
+		v.short_address,
+		COALESCE(e.event_count, 0) AS event_count
+	FROM venues v
+	CROSS JOIN q
+	LEFT JOIN (
+		SELECT venue_id, COUNT(*) AS event_count
+		FROM events
+		WHERE status = 'published'
+		GROUP BY venue_id
+	) e ON e.venue_id = v.venue_id
+	WHERE v.search_vector @@ q.query
+	ORDER BY ts_rank(v.search_vector, q.query) DESC, v.venue_id
+	LIMIT $2
+	`
+
+	pgxRows, err := s.db.Query(ctx, query, q.Text, q.PageSize())
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	rows, err := collectRows[venueRow](pgxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	venues := make([]model.VenueWithDetails, len(rows))
+	for i, row := range rows {
+		venues[i] = row.toVenueWithDetails()
+	}
+
+	return venues, nil
+}
+
+func (s *PostgresSearchStore) SearchEvents(
+	ctx context.Context,
+	q model.SearchQuery,
+) ([]content.Event, error) {
+	query := `
+	WITH q AS (
+		SELECT websearch_to_tsquery('simple', f_unaccent($1)) AS query
+	)
+	SELECT
+		e.event_id,
+		e.event_title,
+		e.event_date,
+		e.ticket_link,
+		e.venue_id,
+		e.programme_id,
+		e.status,
+		e.notes,
+		e.publish_at,
+		e.status_reason,
+		e.replacement_event_id
+	FROM events e
+	CROSS JOIN q
+	WHERE e.search_vector @@ q.query
+	ORDER BY ts_rank(e.search_vector, q.query) DESC, e.event_id
+	LIMIT $2
+	`
+
+	pgxRows, err := s.db.Query(ctx, query, q.Text, q.PageSize())
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	rows, err := collectRows[eventRow](pgxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]content.Event, len(rows))
+	for i, row := range rows {
+		events[i] = row.toEvent()
+	}
+
+	return events, nil
+}
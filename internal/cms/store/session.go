@@ -0,0 +1,135 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/adamkadda/arman/internal/content"
+)
+
+type PostgresSessionStore struct {
+	db Executor
+}
+
+func NewPostgresSessionStore(db Executor) *PostgresSessionStore {
+	return &PostgresSessionStore{
+		db: db,
+	}
+}
+
+type sessionRow struct {
+	TokenHash string    `db:"token_hash"`
+	UserID    int       `db:"user_id"`
+	ExpiresAt time.Time `db:"expires_at"`
+}
+
+func (r *sessionRow) toSession() content.Session {
+	return content.Session{
+		TokenHash: r.TokenHash,
+		UserID:    r.UserID,
+		ExpiresAt: r.ExpiresAt,
+	}
+}
+
+func (s *PostgresSessionStore) Get(
+	ctx context.Context,
+	tokenHash string,
+) (*content.Session, error) {
+	query := `
+	SELECT
+		token_hash,
+		user_id,
+		expires_at
+	FROM sessions
+	WHERE token_hash = $1
+	`
+
+	pgxRows, err := s.db.Query(ctx, query, tokenHash)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	row, err := collectRow[sessionRow](pgxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	session := row.toSession()
+
+	return &session, nil
+}
+
+func (s *PostgresSessionStore) Create(
+	ctx context.Context,
+	sess content.Session,
+) (*content.Session, error) {
+	query := `
+	INSERT INTO sessions (
+		token_hash,
+		user_id,
+		expires_at
+	)
+	VALUES ($1, $2, $3)
+	RETURNING
+		token_hash,
+		user_id,
+		expires_at
+	`
+
+	pgxRows, err := s.db.Query(ctx, query,
+		sess.TokenHash,
+		sess.UserID,
+		sess.ExpiresAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	row, err := collectRow[sessionRow](pgxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	session := row.toSession()
+
+	return &session, nil
+}
+
+func (s *PostgresSessionStore) Delete(
+	ctx context.Context,
+	tokenHash string,
+) error {
+	query := `
+	DELETE
+	FROM sessions
+	WHERE token_hash = $1
+	`
+
+	cmdTag, err := s.db.Exec(ctx, query, tokenHash)
+	if err != nil {
+		return fmt.Errorf("query failed: %w", err)
+	}
+
+	return checkAffected(cmdTag)
+}
+
+// DeleteExpired removes every session that expired before the given instant.
+// It returns the number of sessions removed.
+func (s *PostgresSessionStore) DeleteExpired(
+	ctx context.Context,
+	before time.Time,
+) (int64, error) {
+	query := `
+	DELETE
+	FROM sessions
+	WHERE expires_at < $1
+	`
+
+	cmdTag, err := s.db.Exec(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("query failed: %w", err)
+	}
+
+	return cmdTag.RowsAffected(), nil
+}
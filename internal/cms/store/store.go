@@ -7,7 +7,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
+	"github.com/adamkadda/arman/internal/cms/model"
 	"github.com/adamkadda/arman/internal/content"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
@@ -23,9 +25,13 @@ type Executor interface {
 
 // collectRow is a convenience function that wraps around pgx.CollectExactlyOneRow.
 // It returns the corresponding content error whenever applicable.
+//
+// Rows are scanned leniently: fields of T missing from the row, such as the
+// counts only some queries select, are left zero. Every column of the row must
+// still have a field.
 func collectRow[T any](pgxRows pgx.Rows) (T, error) {
 	var none T
-	row, err := pgx.CollectExactlyOneRow(pgxRows, pgx.RowToStructByName[T])
+	row, err := pgx.CollectExactlyOneRow(pgxRows, pgx.RowToStructByNameLax[T])
 
 	switch {
 	case errors.Is(err, pgx.ErrNoRows):
@@ -39,10 +45,11 @@ func collectRow[T any](pgxRows pgx.Rows) (T, error) {
 	}
 }
 
-// collectRow is a convenience function that wraps around pgx.CollectRows.
-// It returns the corresponding content error whenever applicable.
+// collectRows is a convenience function that wraps around pgx.CollectRows.
+// It returns the corresponding content error whenever applicable. Rows are
+// scanned like collectRow scans them.
 func collectRows[T any](pgxRows pgx.Rows) ([]T, error) {
-	rows, err := pgx.CollectRows(pgxRows, pgx.RowToStructByName[T])
+	rows, err := pgx.CollectRows(pgxRows, pgx.RowToStructByNameLax[T])
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, content.ErrResourceNotFound
@@ -73,3 +80,57 @@ func checkAffected(cmdTag pgconn.CommandTag) error {
 		)
 	}
 }
+
+// pageClause renders the ORDER BY, LIMIT and OFFSET clauses of a ListQuery.
+//
+// Listings select COUNT(*) OVER () AS total_count along with the page, so that
+// the total of model.Page and its items are read from the same snapshot. Only a
+// page past the last item, which has no row to read the total from, counts the
+// items with a query of its own.
+//
+// Sort fields are looked up in columns, which doubles as a whitelist: only
+// column names written by the store ever reach the SQL. Rows are always
+// tie-broken by idColumn so that pages are stable.
+func pageClause(
+	q model.ListQuery,
+	columns map[string]string,
+	idColumn string,
+) (string, error) {
+	column := idColumn
+	if q.Sort != "" {
+		c, ok := columns[q.Sort]
+		if !ok {
+			return "", model.ErrInvalidSort
+		}
+		column = c
+	}
+
+	direction := "ASC"
+	if q.Direction == model.Descending {
+		direction = "DESC"
+	}
+
+	return fmt.Sprintf(
+		"ORDER BY %s %s, %s %s LIMIT %d OFFSET %d",
+		column, direction,
+		idColumn, direction,
+		q.PageSize(), q.Offset,
+	), nil
+}
+
+// likePattern turns a ListQuery filter into an ILIKE pattern matching any
+// text containing it. An empty filter stays empty, which stores treat as no
+// filter at all.
+func likePattern(filter string) string {
+	if filter == "" {
+		return ""
+	}
+
+	escaped := strings.NewReplacer(
+		`\`, `\\`,
+		`%`, `\%`,
+		`_`, `\_`,
+	).Replace(filter)
+
+	return "%" + escaped + "%"
+}
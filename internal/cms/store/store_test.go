@@ -0,0 +1,207 @@
+package store
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/adamkadda/arman/internal/content"
+	"github.com/adamkadda/arman/pkg/migrate"
+	"github.com/adamkadda/arman/schema"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/require"
+)
+
+// rows lists the row types scanned by collectRow and collectRows. pgx skips
+// unexported fields, and then fails to find a field for the column.
+var rows = []any{
+	archiveAttachmentRow{},
+	archiveBiographyMediaRow{},
+	archiveBiographyRow{},
+	archiveContactRow{},
+	archivePieceRow{},
+	archiveProgrammePieceRow{},
+	archiveProgrammeRow{},
+	archiveRevisionRow{},
+	archiveStatusChangeRow{},
+	archiveUserRow{},
+	auditRow{},
+	biographyLanguageRow{},
+	biographyRevisionRow{},
+	biographyRow{},
+	composerRow{},
+	contactRow{},
+	eventRow{},
+	mediaRow{},
+	pieceRow{},
+	programmePieceRow{},
+	programmeRow{},
+	sessionRow{},
+	statusTransitionRow{},
+	userRow{},
+	venueRow{},
+}
+
+func TestRowsExported(t *testing.T) {
+	for _, row := range rows {
+		typ := reflect.TypeOf(row)
+
+		t.Run(typ.Name(), func(t *testing.T) {
+			for i := range typ.NumField() {
+				field := typ.Field(i)
+
+				require.Truef(t, field.IsExported(), "field %s is unexported", field.Name)
+				require.NotEmptyf(t, field.Tag.Get("db"), "field %s has no db tag", field.Name)
+			}
+		})
+	}
+}
+
+func TestCollectRow(t *testing.T) {
+	tests := []struct {
+		name        string
+		columns     []string
+		values      [][]any
+		expected    venueRow
+		expectedErr error
+	}{
+		{
+			name:    "every column",
+			columns: []string{"venue_id", "venue_name", "full_address", "short_address", "event_count"},
+			values:  [][]any{{1, "Foo Hall", "11 Foo St. Foo City", "11 Foo St.", 2}},
+			expected: venueRow{
+				VenueID:      1,
+				VenueName:    "Foo Hall",
+				FullAddress:  "11 Foo St. Foo City",
+				ShortAddress: "11 Foo St.",
+				EventCount:   2,
+			},
+		},
+		{
+			name:    "column left out",
+			columns: []string{"venue_id", "venue_name", "full_address", "short_address"},
+			values:  [][]any{{1, "Foo Hall", "11 Foo St. Foo City", "11 Foo St."}},
+			expected: venueRow{
+				VenueID:      1,
+				VenueName:    "Foo Hall",
+				FullAddress:  "11 Foo St. Foo City",
+				ShortAddress: "11 Foo St.",
+			},
+		},
+		{
+			name:        "no rows",
+			columns:     []string{"venue_id"},
+			values:      nil,
+			expectedErr: content.ErrResourceNotFound,
+		},
+		{
+			name:        "too many rows",
+			columns:     []string{"venue_id"},
+			values:      [][]any{{1}, {2}},
+			expectedErr: content.ErrInvariantViolation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			row, err := collectRow[venueRow](newMockRows(tt.columns, tt.values))
+
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.expected, row)
+			}
+		})
+	}
+}
+
+// A column without a field is still an error, so that typos in queries are
+// not silently ignored.
+func TestCollectRows_UnknownColumn(t *testing.T) {
+	_, err := collectRows[venueRow](newMockRows(
+		[]string{"venue_id", "foo"},
+		[][]any{{1, "bar"}},
+	))
+	require.Error(t, err)
+}
+
+// mockRows returns rows of values, assigning them to the scan targets as is.
+type mockRows struct {
+	pgx.Rows
+	fields []pgconn.FieldDescription
+	values [][]any
+	i      int
+}
+
+func newMockRows(columns []string, values [][]any) *mockRows {
+	fields := make([]pgconn.FieldDescription, len(columns))
+	for i, column := range columns {
+		fields[i] = pgconn.FieldDescription{Name: column}
+	}
+
+	return &mockRows{
+		fields: fields,
+		values: values,
+	}
+}
+
+func (r *mockRows) FieldDescriptions() []pgconn.FieldDescription {
+	return r.fields
+}
+
+func (r *mockRows) Next() bool {
+	r.i++
+	return r.i <= len(r.values)
+}
+
+func (r *mockRows) Scan(dest ...any) error {
+	for i, d := range dest {
+		reflect.ValueOf(d).Elem().Set(reflect.ValueOf(r.values[r.i-1][i]))
+	}
+
+	return nil
+}
+
+func (r *mockRows) Close() {}
+
+func (r *mockRows) Err() error {
+	return nil
+}
+
+// testTx returns a transaction on the database named by TEST_DATABASE_URL,
+// migrated to the latest version. The transaction is rolled back when the test
+// ends. Tests calling testTx are skipped when TEST_DATABASE_URL is unset.
+func testTx(t *testing.T) pgx.Tx {
+	t.Helper()
+
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	ctx := context.Background()
+
+	pool, err := pgxpool.New(ctx, url)
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+
+	migrations, err := schema.Migrations()
+	require.NoError(t, err)
+
+	_, err = migrate.New(pool, migrations).Up(ctx)
+	require.NoError(t, err)
+
+	tx, err := pool.Begin(ctx)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		tx.Rollback(ctx)
+	})
+
+	return tx
+}
@@ -0,0 +1,195 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/adamkadda/arman/internal/content"
+)
+
+type PostgresUserStore struct {
+	db Executor
+}
+
+func NewPostgresUserStore(db Executor) *PostgresUserStore {
+	return &PostgresUserStore{
+		db: db,
+	}
+}
+
+type userRow struct {
+	UserID       int    `db:"user_id"`
+	Username     string `db:"username"`
+	PasswordHash string `db:"password_hash"`
+	Role         string `db:"role"`
+}
+
+func (r *userRow) toUser() content.User {
+	return content.User{
+		ID:           r.UserID,
+		Username:     r.Username,
+		PasswordHash: r.PasswordHash,
+		Role:         content.Role(r.Role),
+	}
+}
+
+func (s *PostgresUserStore) Get(
+	ctx context.Context,
+	id int,
+) (*content.User, error) {
+	query := `
+	SELECT
+		user_id,
+		username,
+		password_hash,
+		role
+	FROM users
+	WHERE user_id = $1
+	`
+
+	pgxRows, err := s.db.Query(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	row, err := collectRow[userRow](pgxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	user := row.toUser()
+
+	return &user, nil
+}
+
+func (s *PostgresUserStore) GetByUsername(
+	ctx context.Context,
+	username string,
+) (*content.User, error) {
+	query := `
+	SELECT
+		user_id,
+		username,
+		password_hash,
+		role
+	FROM users
+	WHERE username = $1
+	`
+
+	pgxRows, err := s.db.Query(ctx, query, username)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	row, err := collectRow[userRow](pgxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	user := row.toUser()
+
+	return &user, nil
+}
+
+func (s *PostgresUserStore) List(
+	ctx context.Context,
+) ([]content.User, error) {
+	query := `
+	SELECT
+		user_id,
+		username,
+		password_hash,
+		role
+	FROM users
+	ORDER BY user_id
+	`
+
+	pgxRows, err := s.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	rows, err := collectRows[userRow](pgxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]content.User, len(rows))
+	for i, row := range rows {
+		users[i] = row.toUser()
+	}
+
+	return users, nil
+}
+
+func (s *PostgresUserStore) Create(
+	ctx context.Context,
+	u content.User,
+) (*content.User, error) {
+	query := `
+	INSERT INTO users (
+		username,
+		password_hash,
+		role
+	)
+	VALUES ($1, $2, $3)
+	RETURNING
+		user_id,
+		username,
+		password_hash,
+		role
+	`
+
+	pgxRows, err := s.db.Query(ctx, query,
+		u.Username,
+		u.PasswordHash,
+		u.Role,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	row, err := collectRow[userRow](pgxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	user := row.toUser()
+
+	return &user, nil
+}
+
+func (s *PostgresUserStore) UpdateRole(
+	ctx context.Context,
+	id int,
+	role content.Role,
+) (*content.User, error) {
+	query := `
+	UPDATE users
+	SET
+		role = $1
+	WHERE user_id = $2
+	RETURNING
+		user_id,
+		username,
+		password_hash,
+		role
+	`
+
+	pgxRows, err := s.db.Query(ctx, query,
+		role,
+		id,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	row, err := collectRow[userRow](pgxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	user := row.toUser()
+
+	return &user, nil
+}
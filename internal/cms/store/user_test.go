@@ -0,0 +1,65 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/adamkadda/arman/internal/content"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgresUserStore(t *testing.T) {
+	tx := testTx(t)
+	ctx := context.Background()
+
+	userStore := NewPostgresUserStore(tx)
+
+	created, err := userStore.Create(ctx, content.User{
+		Username:     "foo",
+		PasswordHash: "bar",
+		Role:         content.RoleEditor,
+	})
+	require.NoError(t, err)
+	require.NotZero(t, created.ID)
+
+	user, err := userStore.GetByUsername(ctx, "foo")
+	require.NoError(t, err)
+	require.Equal(t, created, user)
+
+	_, err = userStore.GetByUsername(ctx, "baz")
+	require.ErrorIs(t, err, content.ErrResourceNotFound)
+}
+
+func TestPostgresSessionStore(t *testing.T) {
+	tx := testTx(t)
+	ctx := context.Background()
+
+	user, err := NewPostgresUserStore(tx).Create(ctx, content.User{
+		Username:     "foo",
+		PasswordHash: "bar",
+		Role:         content.RoleOwner,
+	})
+	require.NoError(t, err)
+
+	sessionStore := NewPostgresSessionStore(tx)
+
+	expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+
+	_, err = sessionStore.Create(ctx, content.Session{
+		TokenHash: "baz",
+		UserID:    user.ID,
+		ExpiresAt: expiresAt,
+	})
+	require.NoError(t, err)
+
+	session, err := sessionStore.Get(ctx, "baz")
+	require.NoError(t, err)
+	require.Equal(t, user.ID, session.UserID)
+	require.True(t, expiresAt.Equal(session.ExpiresAt))
+
+	require.NoError(t, sessionStore.Delete(ctx, "baz"))
+
+	_, err = sessionStore.Get(ctx, "baz")
+	require.ErrorIs(t, err, content.ErrResourceNotFound)
+}
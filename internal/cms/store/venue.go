@@ -20,26 +20,27 @@ func NewPostgresVenueStore(db Executor) *PostgresVenueStore {
 
 // venueRow represents a row from the venues table.
 type venueRow struct {
-	venueID      int    `db:"venue_id"`
-	venueName    string `db:"venue_name"`
-	fullAddress  string `db:"full_address"`
-	shortAddress string `db:"short_address"`
-	event_count  int    `db:"event_count"`
+	VenueID      int    `db:"venue_id"`
+	VenueName    string `db:"venue_name"`
+	FullAddress  string `db:"full_address"`
+	ShortAddress string `db:"short_address"`
+	EventCount   int    `db:"event_count"`
+	TotalCount   int    `db:"total_count"`
 }
 
 func (r *venueRow) toVenue() content.Venue {
 	return content.Venue{
-		ID:           r.venueID,
-		Name:         r.venueName,
-		FullAddress:  r.fullAddress,
-		ShortAddress: r.shortAddress,
+		ID:           r.VenueID,
+		Name:         r.VenueName,
+		FullAddress:  r.FullAddress,
+		ShortAddress: r.ShortAddress,
 	}
 }
 
 func (r *venueRow) toVenueWithDetails() model.VenueWithDetails {
 	return model.VenueWithDetails{
 		Venue:      r.toVenue(),
-		EventCount: r.event_count,
+		EventCount: r.EventCount,
 	}
 }
 
@@ -72,6 +73,40 @@ func (s *PostgresVenueStore) Get(
 	return &venue, nil
 }
 
+// ListByIDs returns the Venues with the passed ids, in no particular order.
+// Ids without a Venue are left out.
+func (s *PostgresVenueStore) ListByIDs(
+	ctx context.Context,
+	ids []int,
+) ([]content.Venue, error) {
+	query := `
+	SELECT
+		venue_id,
+		venue_name,
+		full_address,
+		short_address
+	FROM venues
+	WHERE venue_id = ANY($1)
+	`
+
+	pgxRows, err := s.db.Query(ctx, query, ids)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	rows, err := collectRows[venueRow](pgxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	venues := make([]content.Venue, len(rows))
+	for i, row := range rows {
+		venues[i] = row.toVenue()
+	}
+
+	return venues, nil
+}
+
 func (s *PostgresVenueStore) GetWithDetails(
 	ctx context.Context,
 	id int,
@@ -108,16 +143,44 @@ func (s *PostgresVenueStore) GetWithDetails(
 	return &venue, nil
 }
 
+// venueSortColumns maps model.VenueSortFields to their columns.
+var venueSortColumns = map[string]string{
+	"id":          "v.venue_id",
+	"name":        "v.venue_name",
+	"event_count": "event_count",
+}
+
+// ListWithDetails returns a page of venues with their published event counts.
+// The filter is matched against the name and both addresses.
 func (s *PostgresVenueStore) ListWithDetails(
 	ctx context.Context,
-) ([]model.VenueWithDetails, error) {
+	q model.ListQuery,
+) (*model.Page[model.VenueWithDetails], error) {
+	clause, err := pageClause(q, venueSortColumns, "v.venue_id")
+	if err != nil {
+		return nil, err
+	}
+
+	filter := `
+	WHERE $1 = ''
+	OR v.venue_name ILIKE $1
+	OR v.full_address ILIKE $1
+	OR v.short_address ILIKE $1
+	`
+
+	countQuery := `
+	SELECT COUNT(*)
+	FROM venues v
+	` + filter
+
 	query := `
 	SELECT
 		v.venue_id,
 		v.venue_name,
 		v.full_address,
 		v.short_address,
-		COALESCE(e.event_count, 0) AS event_count
+		COALESCE(e.event_count, 0) AS event_count,
+		COUNT(*) OVER () AS total_count
 	FROM venues v
 	LEFT JOIN (
 		SELECT venue_id, COUNT(*) AS event_count
@@ -125,10 +188,11 @@ func (s *PostgresVenueStore) ListWithDetails(
 		WHERE status = 'published'
 		GROUP BY venue_id
 	) e ON e.venue_id = v.venue_id
-	ORDER BY v.venue_id
-	`
+	` + filter + clause
+
+	pattern := likePattern(q.Filter)
 
-	pgxRows, err := s.db.Query(ctx, query)
+	pgxRows, err := s.db.Query(ctx, query, pattern)
 	if err != nil {
 		return nil, fmt.Errorf("query failed: %w", err)
 	}
@@ -138,12 +202,21 @@ func (s *PostgresVenueStore) ListWithDetails(
 		return nil, err
 	}
 
+	total := 0
+	if len(rows) > 0 {
+		total = rows[0].TotalCount
+	} else if q.Offset > 0 {
+		if err := s.db.QueryRow(ctx, countQuery, pattern).Scan(&total); err != nil {
+			return nil, fmt.Errorf("count failed: %w", err)
+		}
+	}
+
 	venues := make([]model.VenueWithDetails, len(rows))
 	for i, row := range rows {
 		venues[i] = row.toVenueWithDetails()
 	}
 
-	return venues, nil
+	return model.NewPage(q, venues, total), nil
 }
 
 func (s *PostgresVenueStore) Create(
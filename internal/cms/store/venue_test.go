@@ -0,0 +1,70 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/adamkadda/arman/internal/cms/model"
+	"github.com/adamkadda/arman/internal/content"
+	"github.com/stretchr/testify/require"
+)
+
+// Every page of a listing carries the total, including pages past the last
+// item.
+func TestPostgresVenueStore_ListWithDetails(t *testing.T) {
+	tx := testTx(t)
+	ctx := context.Background()
+
+	venueStore := NewPostgresVenueStore(tx)
+
+	for i := range 3 {
+		_, err := venueStore.Create(ctx, content.Venue{
+			Name:         fmt.Sprintf("Foo Hall %d", i),
+			FullAddress:  "11 Foo St. Foo City",
+			ShortAddress: "11 Foo St.",
+		})
+		require.NoError(t, err)
+	}
+
+	tests := []struct {
+		name     string
+		offset   int
+		items    int
+		nextPage bool
+	}{
+		{
+			name:     "first page",
+			offset:   0,
+			items:    2,
+			nextPage: true,
+		},
+		{
+			name:     "last page",
+			offset:   2,
+			items:    1,
+			nextPage: false,
+		},
+		{
+			name:     "past the last page",
+			offset:   5,
+			items:    0,
+			nextPage: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := venueStore.ListWithDetails(ctx, model.ListQuery{
+				Filter: "foo hall",
+				Offset: tt.offset,
+				Limit:  2,
+			})
+			require.NoError(t, err)
+
+			require.Len(t, page.Items, tt.items)
+			require.Equal(t, 3, *page.Total)
+			require.Equal(t, tt.nextPage, page.NextCursor != nil)
+		})
+	}
+}
@@ -1,13 +1,33 @@
 package content
 
-import "errors"
+import (
+	"errors"
+	"time"
 
+	"golang.org/x/text/language"
+)
+
+// Biography is the text of one variant of the biography in one language.
 type Biography struct {
-	Content string
-	Variant BiographyVariant
+	Content  string
+	Variant  BiographyVariant
+	Language Language
 }
 
-// TODO: Consider implementing biography versioning.
+// BiographyRevision is a previous text of a Biography. A revision is recorded
+// every time a Biography is replaced, holding the text as it was along with
+// who wrote it and when.
+//
+// AuthorID is nil for text written by internal callers, such as imports, and
+// for text whose author has since been deleted.
+type BiographyRevision struct {
+	ID        int
+	Variant   BiographyVariant
+	Language  Language
+	Content   string
+	AuthorID  *int
+	CreatedAt time.Time
+}
 
 // TODO: Consider adding validation rules for biographies.
 
@@ -28,4 +48,27 @@ func (b BiographyVariant) Validate() error {
 	return ErrInvalidBiographyVariant
 }
 
-var ErrInvalidBiographyVariant = errors.New("invalid biography variant")
+// BiographyVariants lists every BiographyVariant.
+var BiographyVariants = []BiographyVariant{
+	BiographyFull,
+	BiographyShort,
+}
+
+// Language is a BCP 47 language tag in its canonical form, such as "en", "de"
+// or "pt-BR".
+type Language string
+
+func (l Language) Validate() error {
+	tag, err := language.Parse(string(l))
+	if err != nil || tag.String() != string(l) {
+		return ErrInvalidLanguage
+	}
+
+	return nil
+}
+
+var (
+	ErrInvalidBiographyVariant = errors.New("invalid biography variant")
+	ErrInvalidLanguage         = errors.New("invalid language")
+	ErrLanguageUnsupported     = errors.New("unsupported language")
+)
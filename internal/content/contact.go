@@ -0,0 +1,79 @@
+package content
+
+import (
+	"errors"
+	"net/mail"
+	"net/url"
+)
+
+// Contact holds the contact details of the artist. There is exactly one
+// Contact, so it has no id.
+//
+// Phone is the artist's personal phone number and is private: it must never
+// be shown to public users. Every other field is public.
+type Contact struct {
+	Email string
+	Phone string
+
+	ManagementAgency string
+	ManagementEmail  string
+	ManagementPhone  string
+
+	PressContact string
+	PressEmail   string
+	PressPhone   string
+
+	SocialLinks []SocialLink
+}
+
+// SocialLink is a link to one of the artist's profiles, such as Instagram or
+// YouTube.
+type SocialLink struct {
+	Platform string
+	URL      string
+}
+
+// Validate checks the format of every field that is set. Every field is
+// optional.
+func (contact *Contact) Validate() error {
+	for _, email := range []string{
+		contact.Email,
+		contact.ManagementEmail,
+		contact.PressEmail,
+	} {
+		if email == "" {
+			continue
+		}
+
+		if _, err := mail.ParseAddress(email); err != nil {
+			return ErrContactEmailInvalid
+		}
+	}
+
+	for _, link := range contact.SocialLinks {
+		if err := link.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (link *SocialLink) Validate() error {
+	if link.Platform == "" {
+		return ErrSocialPlatformEmpty
+	}
+
+	u, err := url.Parse(link.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrSocialURLInvalid
+	}
+
+	return nil
+}
+
+var (
+	ErrContactEmailInvalid = errors.New("contact email is invalid")
+	ErrSocialPlatformEmpty = errors.New("social link platform is empty")
+	ErrSocialURLInvalid    = errors.New("social link url is invalid")
+)
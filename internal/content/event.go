@@ -2,14 +2,18 @@ package content
 
 import (
 	"errors"
+	"slices"
 	"time"
 )
 
-// Status is a type that represents the three possible variants an Event's status
-// can hold. Drafted events are the only mutable events, but this restriction on
+// Status is a type that represents the possible variants an Event's status can
+// hold. Drafted events are the only mutable events, but this restriction on
 // mutability does not apply to the Notes field. Notes can be edited regardless of
 // Event status.
 //
+// Cancelled and postponed events were published before they were called off, and
+// remain listed alongside the reason they were called off.
+//
 // The mutability restriction extends to the Programmes they reference. That means
 // that a Programme referenced by at least one Published event becomes immutable.
 // This mutability restriction does not extend to Pieces, Composers, and Venues.
@@ -19,8 +23,52 @@ const (
 	StatusDraft     Status = "draft"
 	StatusPublished Status = "published"
 	StatusArchived  Status = "archived"
+	StatusCancelled Status = "cancelled"
+	StatusPostponed Status = "postponed"
 )
 
+// transitions lists the statuses an Event may move to from each status. Every
+// other move is forbidden: an archived Event must be drafted again, and so go
+// through the publishability checks, before it can be republished.
+var transitions = map[Status][]Status{
+	StatusDraft:     {StatusPublished},
+	StatusPublished: {StatusArchived, StatusCancelled, StatusPostponed},
+	StatusPostponed: {StatusDraft, StatusCancelled, StatusArchived},
+	StatusCancelled: {StatusArchived},
+	StatusArchived:  {StatusDraft},
+}
+
+// CanTransition determines whether an Event in status s may move to status to.
+// It returns ErrInvalidStatusTransition for every move missing from the
+// transition table.
+func (s Status) CanTransition(to Status) error {
+	if err := to.Validate(); err != nil {
+		return err
+	}
+
+	if !slices.Contains(transitions[s], to) {
+		return ErrInvalidStatusTransition
+	}
+
+	return nil
+}
+
+// CallsOff reports whether an Event moving to status s is being called off,
+// in which case the move must be explained, see StatusChange.
+func (s Status) CallsOff() bool {
+	return s == StatusCancelled || s == StatusPostponed
+}
+
+func (s Status) Validate() error {
+	switch s {
+	case StatusDraft, StatusPublished, StatusArchived,
+		StatusCancelled, StatusPostponed:
+		return nil
+	default:
+		return ErrInvalidEventStatus
+	}
+}
+
 // Timeframe is a type that represents when a Event's date occurs, relative to today.
 // It is used primarily as a filter, but it holds business meaning because public
 // users will often interact with Events filtered by their timeframe.
@@ -34,6 +82,14 @@ const (
 	TimeframeUpcoming Timeframe = "upcoming"
 )
 
+func (t Timeframe) Validate() error {
+	if t == TimeframePast || t == TimeframeUpcoming {
+		return nil
+	}
+
+	return ErrInvalidTimeframe
+}
+
 type Event struct {
 	ID          int
 	Title       string
@@ -43,6 +99,16 @@ type Event struct {
 	ProgrammeID *int
 	Status      Status
 	Notes       *string
+
+	// PublishAt schedules a draft Event to be published once the time has
+	// passed. It is cleared once the Event leaves the draft status.
+	PublishAt *time.Time
+
+	// StatusReason explains why a cancelled or postponed Event was called off.
+	// ReplacementID optionally links the Event taking its place, such as the
+	// rescheduled date of a postponed Event.
+	StatusReason  *string
+	ReplacementID *int
 }
 
 func (event *Event) Validate() error {
@@ -50,13 +116,7 @@ func (event *Event) Validate() error {
 		return ErrEventTitleEmpty
 	}
 
-	switch event.Status {
-	case StatusDraft, StatusPublished, StatusArchived:
-	default:
-		return ErrInvalidEventStatus
-	}
-
-	return nil
+	return event.Status.Validate()
 }
 
 // Mutable determines whether an Event is mutable by checking its status.
@@ -65,7 +125,7 @@ func (event *Event) Mutable() error {
 	switch event.Status {
 	case StatusDraft:
 		return nil
-	case StatusPublished, StatusArchived:
+	case StatusPublished, StatusArchived, StatusCancelled, StatusPostponed:
 		return ErrEventImmutable
 	default:
 		return ErrInvalidEventStatus
@@ -92,6 +152,43 @@ func (event *Event) Publishable() error {
 	}
 }
 
+// StatusChange describes why an Event moves to another status. Events being
+// called off must be given a reason, and may link the Event replacing them.
+type StatusChange struct {
+	Reason        string
+	ReplacementID *int
+}
+
+// Validate checks a StatusChange against the status an Event moves to.
+func (c *StatusChange) Validate(to Status) error {
+	if !to.CallsOff() {
+		if c.ReplacementID != nil {
+			return ErrEventReplacementInvalid
+		}
+
+		return nil
+	}
+
+	if c.Reason == "" {
+		return ErrEventStatusReasonEmpty
+	}
+
+	return nil
+}
+
+// StatusTransition records an Event moving from one status to another.
+// ChangedBy is nil for moves made by internal callers, such as the scheduler,
+// and for users who have since been deleted.
+type StatusTransition struct {
+	ID        int
+	EventID   int
+	From      Status
+	To        Status
+	Reason    *string
+	ChangedBy *int
+	ChangedAt time.Time
+}
+
 var (
 	ErrEventTitleEmpty      = errors.New("event title is empty")
 	ErrInvalidEventStatus   = errors.New("invalid event status")
@@ -102,4 +199,9 @@ var (
 	ErrEventImmutable       = errors.New("event is immutable")
 	ErrEventProtected       = errors.New("event protected; deletion forbidden")
 	ErrEventNotPublishable  = errors.New("event not publishable")
+	ErrInvalidTimeframe     = errors.New("invalid timeframe")
+
+	ErrInvalidStatusTransition = errors.New("invalid event status transition")
+	ErrEventStatusReasonEmpty  = errors.New("event status reason is empty")
+	ErrEventReplacementInvalid = errors.New("invalid replacement event")
 )
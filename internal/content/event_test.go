@@ -0,0 +1,124 @@
+package content
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatus_CanTransition(t *testing.T) {
+	statuses := []Status{
+		StatusDraft,
+		StatusPublished,
+		StatusArchived,
+		StatusCancelled,
+		StatusPostponed,
+	}
+
+	allowed := map[[2]Status]bool{
+		{StatusDraft, StatusPublished}:     true,
+		{StatusPublished, StatusArchived}:  true,
+		{StatusPublished, StatusCancelled}: true,
+		{StatusPublished, StatusPostponed}: true,
+		{StatusPostponed, StatusDraft}:     true,
+		{StatusPostponed, StatusCancelled}: true,
+		{StatusPostponed, StatusArchived}:  true,
+		{StatusCancelled, StatusArchived}:  true,
+		{StatusArchived, StatusDraft}:      true,
+	}
+
+	for _, from := range statuses {
+		for _, to := range statuses {
+			t.Run(string(from)+" to "+string(to), func(t *testing.T) {
+				t.Parallel()
+
+				err := from.CanTransition(to)
+
+				if allowed[[2]Status{from, to}] {
+					require.NoError(t, err)
+				} else {
+					require.ErrorIs(t, err, ErrInvalidStatusTransition)
+				}
+			})
+		}
+	}
+}
+
+func TestStatus_CanTransitionInvalid(t *testing.T) {
+	require.ErrorIs(t, StatusDraft.CanTransition("foo"), ErrInvalidEventStatus)
+	require.ErrorIs(t, Status("foo").CanTransition(StatusDraft), ErrInvalidStatusTransition)
+}
+
+func TestStatusChange_Validate(t *testing.T) {
+	replacementID := 1
+
+	tests := []struct {
+		name        string
+		to          Status
+		change      StatusChange
+		expectedErr error
+	}{
+		{
+			name:        "cancelled with reason",
+			to:          StatusCancelled,
+			change:      StatusChange{Reason: "foo"},
+			expectedErr: nil,
+		},
+		{
+			name:        "cancelled without reason",
+			to:          StatusCancelled,
+			change:      StatusChange{},
+			expectedErr: ErrEventStatusReasonEmpty,
+		},
+		{
+			name:        "postponed with replacement",
+			to:          StatusPostponed,
+			change:      StatusChange{Reason: "foo", ReplacementID: &replacementID},
+			expectedErr: nil,
+		},
+		{
+			name:        "postponed without reason",
+			to:          StatusPostponed,
+			change:      StatusChange{ReplacementID: &replacementID},
+			expectedErr: ErrEventStatusReasonEmpty,
+		},
+		{
+			name:        "published without reason",
+			to:          StatusPublished,
+			change:      StatusChange{},
+			expectedErr: nil,
+		},
+		{
+			name:        "archived with reason",
+			to:          StatusArchived,
+			change:      StatusChange{Reason: "foo"},
+			expectedErr: nil,
+		},
+		{
+			name:        "draft with replacement",
+			to:          StatusDraft,
+			change:      StatusChange{ReplacementID: &replacementID},
+			expectedErr: ErrEventReplacementInvalid,
+		},
+		{
+			name:        "archived with replacement",
+			to:          StatusArchived,
+			change:      StatusChange{Reason: "foo", ReplacementID: &replacementID},
+			expectedErr: ErrEventReplacementInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.change.Validate(tt.to)
+
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package content
+
+import (
+	"errors"
+	"time"
+)
+
+// Media is an uploaded image or document. The file itself lives in a blob
+// store under StorageKey; Media only describes it.
+type Media struct {
+	ID         int
+	Filename   string
+	MIMEType   string
+	Size       int64
+	Checksum   string
+	StorageKey string
+	AltText    string
+	Credits    string
+	CreatedAt  time.Time
+}
+
+// MediaTypes lists the MIME types that may be uploaded.
+var MediaTypes = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/gif":       ".gif",
+	"image/webp":      ".webp",
+	"application/pdf": ".pdf",
+}
+
+func (media *Media) Validate() error {
+	if media.Filename == "" {
+		return ErrMediaFilenameEmpty
+	}
+
+	if _, ok := MediaTypes[media.MIMEType]; !ok {
+		return ErrMediaTypeUnsupported
+	}
+
+	if media.Size <= 0 {
+		return ErrMediaEmpty
+	}
+
+	return nil
+}
+
+// Extension returns the file extension matching the MIME type of the Media.
+func (media *Media) Extension() string {
+	return MediaTypes[media.MIMEType]
+}
+
+var (
+	ErrMediaFilenameEmpty   = errors.New("media filename is empty")
+	ErrMediaTypeUnsupported = errors.New("media type unsupported")
+	ErrMediaEmpty           = errors.New("media is empty")
+	ErrMediaTooLarge        = errors.New("media too large")
+	ErrMediaProtected       = errors.New("media protected; deletion forbidden")
+)
@@ -21,4 +21,5 @@ func (piece *Piece) Validate() error {
 var (
 	ErrPieceTitleEmpty = errors.New("piece title is empty")
 	ErrPieceProtected  = errors.New("piece protected; deletion forbidden")
+	ErrPieceExists     = errors.New("piece already exists")
 )
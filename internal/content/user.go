@@ -0,0 +1,108 @@
+package content
+
+import (
+	"errors"
+	"time"
+)
+
+// User is an admin account able to sign in to the dashboard. The password is
+// never held in plain text outside of the request that sets it; only its hash
+// is persisted.
+type User struct {
+	ID           int
+	Username     string
+	PasswordHash string
+	Role         Role
+}
+
+// MinPasswordLength is the minimum number of bytes accepted for a password.
+const MinPasswordLength = 12
+
+func (user *User) Validate() error {
+	if user.Username == "" {
+		return ErrUsernameEmpty
+	}
+
+	if err := user.Role.Validate(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Role determines which operations a User may perform. Roles are ordered, each
+// role is granted everything the roles below it are granted:
+//
+//	viewer < editor < owner
+//
+// Viewers are read-only. Editors may create and edit content, but may not put
+// it in front of the public. Owners may do anything, including managing users.
+type Role string
+
+const (
+	RoleViewer Role = "viewer"
+	RoleEditor Role = "editor"
+	RoleOwner  Role = "owner"
+)
+
+func (r Role) Validate() error {
+	switch r {
+	case RoleViewer, RoleEditor, RoleOwner:
+		return nil
+	default:
+		return ErrInvalidRole
+	}
+}
+
+// Includes reports whether r is granted everything other is granted.
+func (r Role) Includes(other Role) bool {
+	return r.rank() >= other.rank() && other.rank() > 0
+}
+
+func (r Role) rank() int {
+	switch r {
+	case RoleViewer:
+		return 1
+	case RoleEditor:
+		return 2
+	case RoleOwner:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// ValidatePassword checks a plain text password against the password rules.
+// It is kept separate from Validate because a User only ever carries a hash.
+func ValidatePassword(password string) error {
+	if len(password) < MinPasswordLength {
+		return ErrPasswordTooShort
+	}
+
+	return nil
+}
+
+// Session is a server-side login session. The token handed to the client is
+// never persisted; only its hash is stored, so a leaked sessions table cannot
+// be replayed.
+type Session struct {
+	TokenHash string
+	UserID    int
+	ExpiresAt time.Time
+}
+
+// Expired reports whether the session has expired at the given instant.
+func (session *Session) Expired(now time.Time) bool {
+	return !now.Before(session.ExpiresAt)
+}
+
+var (
+	ErrUsernameEmpty      = errors.New("username is empty")
+	ErrPasswordTooShort   = errors.New("password is too short")
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrSessionExpired     = errors.New("session expired")
+	ErrUnauthenticated    = errors.New("unauthenticated")
+	ErrInvalidRole        = errors.New("invalid role")
+	ErrPermissionDenied   = errors.New("permission denied")
+	ErrOwnRoleImmutable   = errors.New("users cannot change their own role")
+)
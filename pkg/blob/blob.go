@@ -0,0 +1,37 @@
+// Package blob stores opaque files by key. Backends are interchangeable: the
+// local filesystem is the only one for now, but anything able to put, get and
+// delete objects by key (such as S3-compatible storage) can implement Store.
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+)
+
+// Store is a flat key-value store for files.
+//
+// Keys are slash-free strings chosen by the caller. Put overwrites existing
+// objects, Get and Delete return ErrNotFound for missing ones.
+type Store interface {
+	Put(ctx context.Context, key string, r io.Reader) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+var (
+	ErrNotFound   = errors.New("blob not found")
+	ErrInvalidKey = errors.New("invalid blob key")
+)
+
+// validateKey rejects keys that could escape a flat namespace, such as keys
+// containing path separators.
+func validateKey(key string) error {
+	if key == "" || key == "." || key == ".." ||
+		strings.ContainsAny(key, `/\`) || strings.ContainsRune(key, 0) {
+		return ErrInvalidKey
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+// Package diff computes line-based differences between two texts.
+package diff
+
+import "strings"
+
+// Op is the kind of change a Line represents.
+type Op string
+
+const (
+	Equal  Op = "equal"
+	Insert Op = "insert"
+	Delete Op = "delete"
+)
+
+// Line is a single line of a diff. Deleted lines come from the old text,
+// inserted lines from the new one and equal lines from both.
+type Line struct {
+	Op   Op
+	Text string
+}
+
+// Lines returns the line-based difference between a and b, turning a into b
+// with as few inserted and deleted lines as possible. Within a changed region,
+// deletions are listed before insertions.
+//
+// Lines uses the classic longest common subsequence table, which takes
+// quadratic time and memory in the number of lines. That is plenty for texts
+// such as biographies, but not for large files.
+func Lines(a, b string) []Line {
+	x := split(a)
+	y := split(b)
+
+	// lcs[i][j] is the length of the longest common subsequence of x[i:] and
+	// y[j:].
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	lines := make([]Line, 0, max(len(x), len(y)))
+
+	i, j := 0, 0
+	for i < len(x) && j < len(y) {
+		switch {
+		case x[i] == y[j]:
+			lines = append(lines, Line{Op: Equal, Text: x[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, Line{Op: Delete, Text: x[i]})
+			i++
+		default:
+			lines = append(lines, Line{Op: Insert, Text: y[j]})
+			j++
+		}
+	}
+
+	for ; i < len(x); i++ {
+		lines = append(lines, Line{Op: Delete, Text: x[i]})
+	}
+
+	for ; j < len(y); j++ {
+		lines = append(lines, Line{Op: Insert, Text: y[j]})
+	}
+
+	return lines
+}
+
+// split breaks a text into lines. An empty text has no lines, and a trailing
+// newline does not start an extra empty line.
+func split(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
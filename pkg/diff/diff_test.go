@@ -0,0 +1,111 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        string
+		b        string
+		expected []Line
+	}{
+		{
+			name:     "both empty",
+			a:        "",
+			b:        "",
+			expected: []Line{},
+		},
+		{
+			name: "equal",
+			a:    "foo\nbar",
+			b:    "foo\nbar",
+			expected: []Line{
+				{Op: Equal, Text: "foo"},
+				{Op: Equal, Text: "bar"},
+			},
+		},
+		{
+			name: "insert only",
+			a:    "",
+			b:    "foo\nbar",
+			expected: []Line{
+				{Op: Insert, Text: "foo"},
+				{Op: Insert, Text: "bar"},
+			},
+		},
+		{
+			name: "delete only",
+			a:    "foo\nbar",
+			b:    "",
+			expected: []Line{
+				{Op: Delete, Text: "foo"},
+				{Op: Delete, Text: "bar"},
+			},
+		},
+		{
+			name: "insert in the middle",
+			a:    "foo\nbaz",
+			b:    "foo\nbar\nbaz",
+			expected: []Line{
+				{Op: Equal, Text: "foo"},
+				{Op: Insert, Text: "bar"},
+				{Op: Equal, Text: "baz"},
+			},
+		},
+		{
+			name: "interleaved",
+			a:    "foo\nbar\nbaz\nqux",
+			b:    "foo\nquux\nbaz\ncorge\nqux",
+			expected: []Line{
+				{Op: Equal, Text: "foo"},
+				{Op: Delete, Text: "bar"},
+				{Op: Insert, Text: "quux"},
+				{Op: Equal, Text: "baz"},
+				{Op: Insert, Text: "corge"},
+				{Op: Equal, Text: "qux"},
+			},
+		},
+		{
+			name: "replaced",
+			a:    "foo\nbar",
+			b:    "baz\nqux",
+			expected: []Line{
+				{Op: Delete, Text: "foo"},
+				{Op: Delete, Text: "bar"},
+				{Op: Insert, Text: "baz"},
+				{Op: Insert, Text: "qux"},
+			},
+		},
+		{
+			name: "trailing newline ignored",
+			a:    "foo\nbar\n",
+			b:    "foo\nbar",
+			expected: []Line{
+				{Op: Equal, Text: "foo"},
+				{Op: Equal, Text: "bar"},
+			},
+		},
+		{
+			name: "empty line",
+			a:    "foo\n\nbar",
+			b:    "foo\nbar",
+			expected: []Line{
+				{Op: Equal, Text: "foo"},
+				{Op: Delete, Text: ""},
+				{Op: Equal, Text: "bar"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.expected, Lines(tt.a, tt.b))
+		})
+	}
+}
@@ -0,0 +1,184 @@
+// The feed package encodes syndication feeds, both as Atom (RFC 4287) and as
+// RSS 2.0, from a single description of the feed.
+package feed
+
+import (
+	"encoding/xml"
+	"io"
+	"time"
+)
+
+const (
+	AtomContentType = "application/atom+xml; charset=utf-8"
+	RSSContentType  = "application/rss+xml; charset=utf-8"
+)
+
+// Feed describes a feed. Link is the address of the website the feed belongs
+// to, Self the address the feed itself is served from. Updated defaults to the
+// latest update of an entry.
+type Feed struct {
+	Title       string
+	Description string
+	Link        string
+	Self        string
+	Updated     time.Time
+	Entries     []Entry
+}
+
+// Entry is an item of a feed. ID identifies the entry and must never change.
+// Content is plain text.
+type Entry struct {
+	ID        string
+	Title     string
+	Link      string
+	Content   string
+	Published time.Time
+	Updated   time.Time
+}
+
+func (f *Feed) updated() time.Time {
+	updated := f.Updated
+	if updated.IsZero() {
+		for _, e := range f.Entries {
+			if e.Updated.After(updated) {
+				updated = e.Updated
+			}
+		}
+	}
+
+	return updated.UTC()
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+type atomEntry struct {
+	ID        string   `xml:"id"`
+	Title     string   `xml:"title"`
+	Link      atomLink `xml:"link"`
+	Published string   `xml:"published"`
+	Updated   string   `xml:"updated"`
+	Content   atomText `xml:"content"`
+}
+
+type atomFeed struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID       string      `xml:"id"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	Links    []atomLink  `xml:"link"`
+	Updated  string      `xml:"updated"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+// EncodeAtom writes the feed to w as an Atom feed. The address of the feed
+// doubles as its id.
+func (f *Feed) EncodeAtom(w io.Writer) error {
+	feed := atomFeed{
+		ID:       f.Self,
+		Title:    f.Title,
+		Subtitle: f.Description,
+		Links: []atomLink{
+			{Href: f.Link, Rel: "alternate", Type: "text/html"},
+			{Href: f.Self, Rel: "self", Type: "application/atom+xml"},
+		},
+		Updated: f.updated().Format(time.RFC3339),
+		Entries: make([]atomEntry, len(f.Entries)),
+	}
+
+	for i, e := range f.Entries {
+		feed.Entries[i] = atomEntry{
+			ID:        e.ID,
+			Title:     e.Title,
+			Link:      atomLink{Href: e.Link, Rel: "alternate"},
+			Published: e.Published.UTC().Format(time.RFC3339),
+			Updated:   e.Updated.UTC().Format(time.RFC3339),
+			Content:   atomText{Type: "text", Body: e.Content},
+		}
+	}
+
+	return encode(w, feed)
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	Description string  `xml:"description"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	AtomLink      atomLink  `xml:"http://www.w3.org/2005/Atom link"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+// EncodeRSS writes the feed to w as an RSS 2.0 feed. RSS has no notion of
+// updates, so items are dated by their publication.
+func (f *Feed) EncodeRSS(w io.Writer) error {
+	feed := rssFeed{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:       f.Title,
+			Link:        f.Link,
+			Description: f.Description,
+			AtomLink: atomLink{
+				Href: f.Self,
+				Rel:  "self",
+				Type: "application/rss+xml",
+			},
+			LastBuildDate: f.updated().Format(time.RFC1123Z),
+			Items:         make([]rssItem, len(f.Entries)),
+		},
+	}
+
+	for i, e := range f.Entries {
+		feed.Channel.Items[i] = rssItem{
+			Title:       e.Title,
+			Link:        e.Link,
+			Description: e.Content,
+			GUID:        rssGUID{IsPermaLink: false, Value: e.ID},
+			PubDate:     e.Published.UTC().Format(time.RFC1123Z),
+		}
+	}
+
+	return encode(w, feed)
+}
+
+func encode(w io.Writer, v any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+
+	return enc.Close()
+}
@@ -0,0 +1,93 @@
+package feed
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func testFeed() *Feed {
+	cet := time.FixedZone("CET", 3600)
+
+	return &Feed{
+		Title:       "Foo – Concerts",
+		Description: "Upcoming concerts of Foo",
+		Link:        "https://example.com/",
+		Self:        "https://example.com/public/feed.atom",
+		Entries: []Entry{
+			{
+				ID:        "https://example.com/concerts/2",
+				Title:     "Bar & Baz",
+				Link:      "https://example.com/concerts/2",
+				Content:   "Chopin <Ballade No. 1>\nLiszt – Sonata",
+				Published: time.Date(2026, 3, 1, 12, 0, 0, 0, cet),
+				Updated:   time.Date(2026, 3, 2, 12, 0, 0, 0, cet),
+			},
+			{
+				ID:        "https://example.com/concerts/1",
+				Title:     "Qux",
+				Link:      "https://example.com/concerts/1",
+				Content:   "",
+				Published: time.Date(2026, 2, 1, 12, 0, 0, 0, time.UTC),
+				Updated:   time.Date(2026, 2, 1, 12, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+}
+
+func TestFeed_EncodeAtom(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, testFeed().EncodeAtom(&buf))
+
+	requireGolden(t, "testdata/feed.atom", buf.Bytes())
+}
+
+func TestFeed_EncodeRSS(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, testFeed().EncodeRSS(&buf))
+
+	requireGolden(t, "testdata/feed.rss", buf.Bytes())
+}
+
+func TestFeed_Updated(t *testing.T) {
+	f := testFeed()
+	require.Equal(t, time.Date(2026, 3, 2, 11, 0, 0, 0, time.UTC), f.updated())
+
+	f.Updated = time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	require.Equal(t, f.Updated, f.updated())
+
+	empty := &Feed{}
+	require.True(t, empty.updated().IsZero())
+}
+
+// An empty feed is still a valid document.
+func TestFeed_EncodeEmpty(t *testing.T) {
+	for _, encode := range []func(*Feed, io.Writer) error{
+		(*Feed).EncodeAtom,
+		(*Feed).EncodeRSS,
+	} {
+		var buf bytes.Buffer
+		require.NoError(t, encode(&Feed{Title: "Foo"}, &buf))
+		require.NotContains(t, buf.String(), "<entry>")
+		require.NotContains(t, buf.String(), "<item>")
+	}
+}
+
+func requireGolden(t *testing.T, golden string, actual []byte) {
+	t.Helper()
+
+	if *update {
+		require.NoError(t, os.WriteFile(golden, actual, 0o644))
+	}
+
+	expected, err := os.ReadFile(golden)
+	require.NoError(t, err)
+	require.Equal(t, string(expected), string(actual))
+}
@@ -0,0 +1,177 @@
+// The ical package encodes calendars in the iCalendar format of RFC 5545.
+//
+// Only the subset needed to publish a calendar of events is supported: a
+// VCALENDAR holding VEVENTs. Text values are escaped, and lines longer than
+// 75 octets are folded, as the RFC requires.
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ContentType is the media type of an iCalendar object.
+const ContentType = "text/calendar; charset=utf-8"
+
+// Status is the overall status of an event.
+type Status string
+
+const (
+	StatusTentative Status = "TENTATIVE"
+	StatusConfirmed Status = "CONFIRMED"
+	StatusCancelled Status = "CANCELLED"
+)
+
+// Calendar is a VCALENDAR. ProdID identifies the product that created the
+// calendar, Name is the name calendar clients display for it.
+type Calendar struct {
+	ProdID string
+	Name   string
+	Events []Event
+}
+
+// Event is a VEVENT. UID identifies the event globally and must never change,
+// while Sequence must grow every time the event is revised, so that clients
+// replace their copy.
+//
+// Start is written in UTC, or as a floating time when Floating is set. A
+// floating time is the same wall clock time in every time zone, which suits
+// events whose time is only known locally. Stamp and
+// LastModified are always written in UTC. Empty optional values are omitted.
+type Event struct {
+	UID          string
+	Sequence     int
+	Stamp        time.Time
+	LastModified time.Time
+	Start        time.Time
+	Floating     bool
+	Summary      string
+	Location     string
+	Description  string
+	URL          string
+	Status       Status
+}
+
+const (
+	utcLayout      = "20060102T150405Z"
+	floatingLayout = "20060102T150405"
+)
+
+// Encode writes the calendar to w.
+func (c *Calendar) Encode(w io.Writer) error {
+	e := &encoder{w: bufio.NewWriter(w)}
+
+	e.line("BEGIN", "VCALENDAR")
+	e.line("VERSION", "2.0")
+	e.line("PRODID", c.ProdID)
+	e.line("CALSCALE", "GREGORIAN")
+	e.line("METHOD", "PUBLISH")
+
+	if c.Name != "" {
+		e.line("X-WR-CALNAME", escape(c.Name))
+	}
+
+	for i := range c.Events {
+		e.event(&c.Events[i])
+	}
+
+	e.line("END", "VCALENDAR")
+
+	if e.err != nil {
+		return e.err
+	}
+
+	return e.w.Flush()
+}
+
+// encoder writes content lines, keeping the first error it runs into.
+type encoder struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (e *encoder) event(ev *Event) {
+	e.line("BEGIN", "VEVENT")
+	e.line("UID", escape(ev.UID))
+	e.line("SEQUENCE", strconv.Itoa(ev.Sequence))
+	e.line("DTSTAMP", ev.Stamp.UTC().Format(utcLayout))
+
+	if !ev.LastModified.IsZero() {
+		e.line("LAST-MODIFIED", ev.LastModified.UTC().Format(utcLayout))
+	}
+
+	if ev.Floating {
+		e.line("DTSTART", ev.Start.Format(floatingLayout))
+	} else {
+		e.line("DTSTART", ev.Start.UTC().Format(utcLayout))
+	}
+
+	e.optional("SUMMARY", escape(ev.Summary))
+	e.optional("LOCATION", escape(ev.Location))
+	e.optional("DESCRIPTION", escape(ev.Description))
+	// A URL is not text: it is written as is, less any line breaks.
+	e.optional("URL", strings.NewReplacer("\r", "", "\n", "").Replace(ev.URL))
+	e.optional("STATUS", string(ev.Status))
+	e.line("END", "VEVENT")
+}
+
+func (e *encoder) optional(name, value string) {
+	if value != "" {
+		e.line(name, value)
+	}
+}
+
+// line writes a content line, folding it into lines of at most 75 octets.
+// Continuation lines start with a space, and multi-octet characters are never
+// split across lines.
+func (e *encoder) line(name, value string) {
+	if e.err != nil {
+		return
+	}
+
+	s := name + ":" + value
+
+	limit := 75
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+
+		e.write(s[:cut])
+		e.write("\r\n ")
+
+		s = s[cut:]
+		// The leading space counts towards the length of the line.
+		limit = 74
+	}
+
+	e.write(s)
+	e.write("\r\n")
+}
+
+func (e *encoder) write(s string) {
+	if e.err != nil {
+		return
+	}
+
+	_, e.err = e.w.WriteString(s)
+}
+
+var textEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+	"\r", "",
+)
+
+// escape escapes a TEXT value.
+func escape(s string) string {
+	return textEscaper.Replace(s)
+}
@@ -0,0 +1,187 @@
+package ical
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"os"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestCalendar_Encode(t *testing.T) {
+	stamp := time.Date(2026, 3, 1, 12, 30, 0, 0, time.FixedZone("CET", 3600))
+
+	cal := Calendar{
+		ProdID: "-//Foo//Bar//EN",
+		Name:   "Concerts, 2026",
+		Events: []Event{
+			{
+				UID:          "event-1@example.com",
+				Sequence:     3,
+				Stamp:        stamp,
+				LastModified: stamp,
+				Start:        time.Date(2026, 4, 1, 19, 30, 0, 0, time.UTC),
+				Floating:     true,
+				Summary:      "Recital; Chopin, Liszt",
+				Location:     "Foo Hall, 11 Foo St.",
+				Description:  "This concert has been cancelled. Illness\n\nChopin – Ballade No. 1 in G minor\nLiszt – Sonata in B minor\\S.178",
+				URL:          "https://example.com/tickets?a=1,b=2",
+				Status:       StatusCancelled,
+			},
+			{
+				UID:     "event-2@example.com",
+				Stamp:   stamp,
+				Start:   time.Date(2026, 5, 1, 18, 0, 0, 0, time.FixedZone("CEST", 7200)),
+				Summary: "Foo",
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, cal.Encode(&buf))
+
+	golden := "testdata/calendar.ics"
+
+	if *update {
+		require.NoError(t, os.WriteFile(golden, buf.Bytes(), 0o644))
+	}
+
+	expected, err := os.ReadFile(golden)
+	require.NoError(t, err)
+	require.Equal(t, string(expected), buf.String())
+}
+
+func TestEscape(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+	}{
+		{
+			name:     "plain",
+			value:    "foo bar",
+			expected: "foo bar",
+		},
+		{
+			name:     "backslash",
+			value:    `foo\bar`,
+			expected: `foo\\bar`,
+		},
+		{
+			name:     "semicolon and comma",
+			value:    "foo;bar,baz",
+			expected: `foo\;bar\,baz`,
+		},
+		{
+			name:     "line feed",
+			value:    "foo\nbar",
+			expected: `foo\nbar`,
+		},
+		{
+			name:     "carriage return and line feed",
+			value:    "foo\r\nbar",
+			expected: `foo\nbar`,
+		},
+		{
+			name:     "lone carriage return",
+			value:    "foo\rbar",
+			expected: "foobar",
+		},
+		{
+			name:     "colon left as is",
+			value:    "foo: bar",
+			expected: "foo: bar",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.expected, escape(tt.value))
+		})
+	}
+}
+
+func TestEncoder_Line(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		lines int
+	}{
+		{
+			name:  "short",
+			value: "foo",
+			lines: 1,
+		},
+		{
+			name:  "exactly 75 octets",
+			value: strings.Repeat("a", 75-len("SUMMARY:")),
+			lines: 1,
+		},
+		{
+			name:  "76 octets",
+			value: strings.Repeat("a", 76-len("SUMMARY:")),
+			lines: 2,
+		},
+		{
+			name:  "several folds",
+			value: strings.Repeat("a", 200),
+			lines: 3,
+		},
+		{
+			name:  "two octet runes",
+			value: strings.Repeat("é", 100),
+			lines: 3,
+		},
+		{
+			name:  "three octet runes",
+			value: strings.Repeat("–", 60),
+			lines: 3,
+		},
+		{
+			name:  "four octet runes",
+			value: "a" + strings.Repeat("𝄞", 40),
+			lines: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+
+			e := &encoder{w: bufio.NewWriter(&buf)}
+			e.line("SUMMARY", tt.value)
+			require.NoError(t, e.err)
+			require.NoError(t, e.w.Flush())
+
+			out := buf.String()
+			require.True(t, strings.HasSuffix(out, "\r\n"))
+
+			lines := strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n")
+			require.Len(t, lines, tt.lines)
+
+			for i, line := range lines {
+				require.LessOrEqual(t, len(line), 75)
+				require.True(t, utf8.ValidString(line), "line %d splits a rune", i)
+
+				if i > 0 {
+					require.True(t, strings.HasPrefix(line, " "))
+				}
+			}
+
+			// Unfolding gives back the content line.
+			unfolded := strings.ReplaceAll(strings.TrimSuffix(out, "\r\n"), "\r\n ", "")
+			require.Equal(t, "SUMMARY:"+tt.value, unfolded)
+		})
+	}
+}
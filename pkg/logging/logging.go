@@ -19,6 +19,10 @@ type contextKey string
 // loggerKey points to the value in the context where the logger is stored.
 const loggerKey = contextKey("logger")
 
+// requestIDKey points to the value in the context where the request ID is
+// stored.
+const requestIDKey = contextKey("request_id")
+
 var (
 	// defaultLogger is the default logger. It is initialized once per package
 	// include upon calling DefaultLogger.
@@ -75,6 +79,18 @@ func FromContext(ctx context.Context) *slog.Logger {
 	return DefaultLogger()
 }
 
+// WithRequestID creates a new context with the provided request ID attached.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestIDFromContext returns the ID Middleware assigned to the request the
+// context belongs to, if any.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	requestID, ok := ctx.Value(requestIDKey).(string)
+	return requestID, ok
+}
+
 const (
 	levelDebug = "DEBUG"
 	levelInfo  = "INFO"
@@ -146,6 +162,7 @@ func (w *loggingWriter) Write(b []byte) (int, error) {
 // The logging package Middleware uses the default logger initialized once by the
 // DefaultLogger function. Middleware assigns a requestID to each request it
 // intercepts, and logs a Debug-level log for the request's start and completion.
+// The requestID is attached to the request's context, see RequestIDFromContext.
 //
 // Additionally, it wraps the http.ResponseWriter around the loggingWriter to keep
 // track of how many bytes were written and the status code returned.
@@ -167,6 +184,7 @@ func Middleware() func(http.Handler) http.Handler {
 			)
 
 			ctx := WithLogger(r.Context(), logger)
+			ctx = WithRequestID(ctx, requestID)
 			r = r.WithContext(ctx)
 
 			logger.Debug("request start")
@@ -0,0 +1,496 @@
+// The migrate package applies numbered SQL migrations to a Postgres database
+// and records them in the schema_migrations table.
+//
+// Migrations are read from a file system, typically embedded in the binary,
+// holding a pair of files per version:
+//
+//	0001_initial.up.sql
+//	0001_initial.down.sql
+//
+// Every change is made in a single transaction holding an advisory lock, so
+// replicas migrating the same database on startup never race: the first one
+// applies the pending migrations while the others wait, then find nothing left
+// to do. A failing migration leaves the database as it was.
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"log/slog"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/adamkadda/arman/pkg/logging"
+	"github.com/jackc/pgx/v5"
+)
+
+// DB begins the transactions migrations are applied in.
+type DB interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// Migration is a numbered change to the schema, along with the SQL reverting
+// it.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// State tells whether a migration is applied. Unknown is set for versions
+// recorded in the database that the Migrator has no migration for, such as
+// those applied by a newer release.
+type State struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time
+	Unknown   bool
+}
+
+var (
+	ErrNoMigrations  = errors.New("no migrations found")
+	ErrNothingToUndo = errors.New("no migration applied")
+)
+
+// filePattern matches the name of a migration file.
+var filePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Load reads the migrations in the root of fsys, ordered by version. Every
+// version needs both an up and a down file; other files are ignored.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("read migrations: %w", err)
+	}
+
+	byVersion := make(map[int]*Migration)
+
+	for _, entry := range entries {
+		m := filePattern.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || m == nil {
+			continue
+		}
+
+		version, err := strconv.Atoi(m[1])
+		if err != nil || version < 1 {
+			return nil, fmt.Errorf("migration %s: invalid version", entry.Name())
+		}
+
+		body, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("read migration %s: %w", entry.Name(), err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = migration
+		}
+
+		if migration.Name != m[2] {
+			return nil, fmt.Errorf("migration %d: conflicting names %q and %q", version, migration.Name, m[2])
+		}
+
+		if m[3] == "up" {
+			migration.Up = string(body)
+		} else {
+			migration.Down = string(body)
+		}
+	}
+
+	if len(byVersion) == 0 {
+		return nil, ErrNoMigrations
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d: missing up or down file", migration.Version)
+		}
+
+		migrations = append(migrations, *migration)
+	}
+
+	slices.SortFunc(migrations, func(a, b Migration) int {
+		return a.Version - b.Version
+	})
+
+	return migrations, nil
+}
+
+// Migrator applies and reverts a set of migrations.
+type Migrator struct {
+	db         DB
+	migrations []Migration
+}
+
+// New returns a Migrator for migrations, which must be ordered by version, as
+// returned by Load.
+func New(db DB, migrations []Migration) *Migrator {
+	return &Migrator{
+		db:         db,
+		migrations: migrations,
+	}
+}
+
+// lockKey is the advisory lock held while migrating.
+var lockKey = func() int64 {
+	h := fnv.New64a()
+	h.Write([]byte("schema_migrations"))
+
+	return int64(h.Sum64())
+}()
+
+const createTable = `
+CREATE TABLE IF NOT EXISTS schema_migrations (
+    version INT PRIMARY KEY,
+    name TEXT NOT NULL,
+    applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+)`
+
+// Up applies every pending migration, in order, and returns them.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	logger := logging.FromContext(ctx).With(
+		slog.String("operation", "migrate.up"),
+	)
+
+	var applied []Migration
+
+	err := m.locked(ctx, logger, func(tx pgx.Tx, done map[int]time.Time) error {
+		for _, migration := range m.migrations {
+			if _, ok := done[migration.Version]; ok {
+				continue
+			}
+
+			logger.Info(
+				"apply migration",
+				slog.Int("version", migration.Version),
+				slog.String("name", migration.Name),
+			)
+
+			if _, err := tx.Exec(ctx, migration.Up); err != nil {
+				return fmt.Errorf("apply migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+
+			if err := record(ctx, tx, migration); err != nil {
+				return err
+			}
+
+			applied = append(applied, migration)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return applied, nil
+}
+
+// Down reverts the latest applied migration and returns it. It returns
+// ErrNothingToUndo when no migration is applied.
+func (m *Migrator) Down(ctx context.Context) (*Migration, error) {
+	logger := logging.FromContext(ctx).With(
+		slog.String("operation", "migrate.down"),
+	)
+
+	var reverted *Migration
+
+	err := m.locked(ctx, logger, func(tx pgx.Tx, done map[int]time.Time) error {
+		if len(done) == 0 {
+			return ErrNothingToUndo
+		}
+
+		latest := slices.Max(slices.Collect(maps.Keys(done)))
+
+		i := slices.IndexFunc(m.migrations, func(migration Migration) bool {
+			return migration.Version == latest
+		})
+		if i < 0 {
+			return fmt.Errorf("revert migration %d: unknown version", latest)
+		}
+
+		migration := m.migrations[i]
+
+		logger.Info(
+			"revert migration",
+			slog.Int("version", migration.Version),
+			slog.String("name", migration.Name),
+		)
+
+		if _, err := tx.Exec(ctx, migration.Down); err != nil {
+			return fmt.Errorf("revert migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+
+		_, err := tx.Exec(ctx,
+			"DELETE FROM schema_migrations WHERE version = $1",
+			migration.Version,
+		)
+		if err != nil {
+			return fmt.Errorf("revert migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+
+		reverted = &migration
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reverted, nil
+}
+
+// Baseline records every migration up to version as applied, without running
+// them. It adopts a database whose schema was created by other means.
+func (m *Migrator) Baseline(ctx context.Context, version int) ([]Migration, error) {
+	logger := logging.FromContext(ctx).With(
+		slog.String("operation", "migrate.baseline"),
+		slog.Int("version", version),
+	)
+
+	if !slices.ContainsFunc(m.migrations, func(migration Migration) bool {
+		return migration.Version == version
+	}) {
+		return nil, fmt.Errorf("baseline migration %d: unknown version", version)
+	}
+
+	var recorded []Migration
+
+	err := m.locked(ctx, logger, func(tx pgx.Tx, done map[int]time.Time) error {
+		for _, migration := range m.migrations {
+			if migration.Version > version {
+				break
+			}
+
+			if _, ok := done[migration.Version]; ok {
+				continue
+			}
+
+			if err := record(ctx, tx, migration); err != nil {
+				return err
+			}
+
+			recorded = append(recorded, migration)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return recorded, nil
+}
+
+// Status returns the state of every migration, ordered by version. It only
+// reads the database: no lock is taken, and a database without the
+// schema_migrations table reports every migration as pending.
+func (m *Migrator) Status(ctx context.Context) ([]State, error) {
+	logger := logging.FromContext(ctx).With(
+		slog.String("operation", "migrate.status"),
+	)
+
+	tx, err := m.db.Begin(ctx)
+	if err != nil {
+		logger.Error(
+			"begin transaction failed",
+			slog.String("step", "tx.begin"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+	defer tx.Rollback(context.WithoutCancel(ctx))
+
+	if _, err := tx.Exec(ctx, "SET TRANSACTION READ ONLY"); err != nil {
+		logger.Error(
+			"set transaction read only failed",
+			slog.String("step", "tx.read_only"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
+	var exists bool
+
+	err = tx.QueryRow(ctx,
+		"SELECT to_regclass('schema_migrations') IS NOT NULL",
+	).Scan(&exists)
+	if err != nil {
+		logger.Error(
+			"find migrations table failed",
+			slog.String("step", "table.find"),
+			slog.Any("error", err),
+		)
+
+		return nil, err
+	}
+
+	done := make(map[int]time.Time)
+
+	if exists {
+		done, err = appliedVersions(ctx, tx)
+		if err != nil {
+			logger.Error(
+				"list applied migrations failed",
+				slog.String("step", "migrations.list"),
+				slog.Any("error", err),
+			)
+
+			return nil, err
+		}
+	}
+
+	states := make([]State, 0, len(m.migrations))
+
+	for _, migration := range m.migrations {
+		state := State{
+			Version: migration.Version,
+			Name:    migration.Name,
+		}
+
+		if at, ok := done[migration.Version]; ok {
+			state.AppliedAt = &at
+			delete(done, migration.Version)
+		}
+
+		states = append(states, state)
+	}
+
+	for version, at := range done {
+		states = append(states, State{
+			Version:   version,
+			AppliedAt: &at,
+			Unknown:   true,
+		})
+	}
+
+	slices.SortFunc(states, func(a, b State) int {
+		return a.Version - b.Version
+	})
+
+	return states, nil
+}
+
+// locked runs fn in a transaction holding the migration lock, along with the
+// versions applied so far. The transaction is committed if fn succeeds.
+func (m *Migrator) locked(
+	ctx context.Context,
+	logger *slog.Logger,
+	fn func(tx pgx.Tx, done map[int]time.Time) error,
+) error {
+	tx, err := m.db.Begin(ctx)
+	if err != nil {
+		logger.Error(
+			"begin transaction failed",
+			slog.String("step", "tx.begin"),
+			slog.Any("error", err),
+		)
+
+		return err
+	}
+	defer tx.Rollback(context.WithoutCancel(ctx))
+
+	// Other replicas wait here until the lock is released on commit.
+	if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", lockKey); err != nil {
+		logger.Error(
+			"acquire migration lock failed",
+			slog.String("step", "lock.acquire"),
+			slog.Any("error", err),
+		)
+
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, createTable); err != nil {
+		logger.Error(
+			"create migrations table failed",
+			slog.String("step", "table.create"),
+			slog.Any("error", err),
+		)
+
+		return err
+	}
+
+	done, err := appliedVersions(ctx, tx)
+	if err != nil {
+		logger.Error(
+			"list applied migrations failed",
+			slog.String("step", "migrations.list"),
+			slog.Any("error", err),
+		)
+
+		return err
+	}
+
+	if err := fn(tx, done); err != nil {
+		logger.Error(
+			"migrate failed",
+			slog.String("step", "migrate"),
+			slog.Any("error", err),
+		)
+
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		logger.Error(
+			"commit transaction failed",
+			slog.String("step", "tx.commit"),
+			slog.Any("error", err),
+		)
+
+		return err
+	}
+
+	return nil
+}
+
+// appliedVersions returns when each applied version was applied.
+func appliedVersions(ctx context.Context, tx pgx.Tx) (map[int]time.Time, error) {
+	rows, err := tx.Query(ctx, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	done := make(map[int]time.Time)
+
+	for rows.Next() {
+		var (
+			version int
+			at      time.Time
+		)
+
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, err
+		}
+
+		done[version] = at
+	}
+
+	return done, rows.Err()
+}
+
+func record(ctx context.Context, tx pgx.Tx, migration Migration) error {
+	_, err := tx.Exec(ctx,
+		"INSERT INTO schema_migrations (version, name) VALUES ($1, $2)",
+		migration.Version,
+		migration.Name,
+	)
+	if err != nil {
+		return fmt.Errorf("record migration %d_%s: %w", migration.Version, migration.Name, err)
+	}
+
+	return nil
+}
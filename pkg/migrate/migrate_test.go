@@ -0,0 +1,95 @@
+package migrate
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func file(body string) *fstest.MapFile {
+	return &fstest.MapFile{Data: []byte(body)}
+}
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0010_baz.up.sql":       file("baz up"),
+		"0002_bar.down.sql":     file("bar down"),
+		"0001_foo.up.sql":       file("foo up"),
+		"0010_baz.down.sql":     file("baz down"),
+		"0001_foo.down.sql":     file("foo down"),
+		"0002_bar.up.sql":       file("bar up"),
+		"README.md":             file("foo"),
+		"0003_qux.sql":          file("qux"),
+		"0004_Quux.up.sql":      file("quux"),
+		"nested/0005.up.sql":    file("corge"),
+		"0006_dir.up.sql/foo":   file("grault"),
+		"0007_dir.down.sql/foo": file("garply"),
+	}
+
+	migrations, err := Load(fsys)
+	require.NoError(t, err)
+
+	require.Equal(t, []Migration{
+		{Version: 1, Name: "foo", Up: "foo up", Down: "foo down"},
+		{Version: 2, Name: "bar", Up: "bar up", Down: "bar down"},
+		{Version: 10, Name: "baz", Up: "baz up", Down: "baz down"},
+	}, migrations)
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := []struct {
+		name        string
+		fsys        fstest.MapFS
+		expectedErr string
+	}{
+		{
+			name: "missing down file",
+			fsys: fstest.MapFS{
+				"0001_foo.up.sql":   file("foo up"),
+				"0001_foo.down.sql": file("foo down"),
+				"0002_bar.up.sql":   file("bar up"),
+			},
+			expectedErr: "migration 2: missing up or down file",
+		},
+		{
+			name: "missing up file",
+			fsys: fstest.MapFS{
+				"0001_foo.down.sql": file("foo down"),
+			},
+			expectedErr: "migration 1: missing up or down file",
+		},
+		{
+			name: "conflicting names",
+			fsys: fstest.MapFS{
+				"0001_foo.up.sql":   file("foo up"),
+				"0001_bar.down.sql": file("bar down"),
+			},
+			expectedErr: `migration 1: conflicting names "bar" and "foo"`,
+		},
+		{
+			name: "version zero",
+			fsys: fstest.MapFS{
+				"0000_foo.up.sql":   file("foo up"),
+				"0000_foo.down.sql": file("foo down"),
+			},
+			expectedErr: "migration 0000_foo.down.sql: invalid version",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Load(tt.fsys)
+			require.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestLoad_Empty(t *testing.T) {
+	_, err := Load(fstest.MapFS{
+		"README.md": file("foo"),
+	})
+	require.ErrorIs(t, err, ErrNoMigrations)
+}
@@ -0,0 +1,263 @@
+// The queue package provides a job queue backed by a Postgres table. Jobs are
+// claimed with SELECT ... FOR UPDATE SKIP LOCKED, so any number of workers, in
+// any number of processes, may share a queue.
+//
+// A failing job is retried with an exponential backoff until it runs out of
+// attempts, at which point it is dead-lettered: it stays in the table with its
+// last error until it is retried by hand, see Retry.
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Executor runs the queries of the queue. Pass a transaction to Enqueue to
+// enqueue a job only if the transaction commits.
+type Executor interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+// Status is the state of a Job in the queue.
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusRunning   Status = "running"
+	StatusSucceeded Status = "succeeded"
+	StatusDead      Status = "dead"
+)
+
+func (s Status) Validate() error {
+	switch s {
+	case StatusPending, StatusRunning, StatusSucceeded, StatusDead:
+		return nil
+	default:
+		return ErrInvalidStatus
+	}
+}
+
+// DefaultMaxAttempts is how many times a job is run before it is dead-lettered,
+// unless enqueued with MaxAttempts.
+const DefaultMaxAttempts = 5
+
+type Job struct {
+	ID          int64
+	Kind        string
+	Payload     json.RawMessage
+	Status      Status
+	Attempts    int
+	MaxAttempts int
+	RunAt       time.Time
+	LastError   *string
+	CreatedAt   time.Time
+}
+
+var (
+	ErrNotFound      = errors.New("job not found")
+	ErrNotDead       = errors.New("job is not dead")
+	ErrInvalidStatus = errors.New("invalid job status")
+	ErrKindEmpty     = errors.New("job kind is empty")
+	ErrLeaseExpired  = errors.New("job lease expired on its last attempt")
+	ErrLeaseLost     = errors.New("job lease lost to another worker")
+)
+
+type enqueueOptions struct {
+	runAt       *time.Time
+	maxAttempts int
+}
+
+// EnqueueOption configures a job passed to Enqueue.
+type EnqueueOption func(*enqueueOptions)
+
+// RunAt delays a job until the passed time.
+func RunAt(t time.Time) EnqueueOption {
+	return func(o *enqueueOptions) {
+		o.runAt = &t
+	}
+}
+
+// MaxAttempts sets how many times a job is run before it is dead-lettered.
+func MaxAttempts(n int) EnqueueOption {
+	return func(o *enqueueOptions) {
+		o.maxAttempts = n
+	}
+}
+
+// Enqueue adds a job of the passed kind to the queue. The payload is encoded as
+// JSON and handed to the Handler registered for the kind. Enqueue returns the
+// id of the new job.
+func Enqueue(
+	ctx context.Context,
+	db Executor,
+	kind string,
+	payload any,
+	opts ...EnqueueOption,
+) (int64, error) {
+	if kind == "" {
+		return 0, ErrKindEmpty
+	}
+
+	o := enqueueOptions{
+		maxAttempts: DefaultMaxAttempts,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return 0, fmt.Errorf("encode payload failed: %w", err)
+	}
+
+	query := `
+	INSERT INTO jobs (
+		kind,
+		payload,
+		max_attempts,
+		run_at
+	)
+	VALUES ($1, $2, $3, COALESCE($4, CURRENT_TIMESTAMP))
+	RETURNING job_id
+	`
+
+	rows, err := db.Query(ctx, query, kind, data, o.maxAttempts, o.runAt)
+	if err != nil {
+		return 0, fmt.Errorf("query failed: %w", err)
+	}
+
+	id, err := pgx.CollectExactlyOneRow(rows, pgx.RowTo[int64])
+	if err != nil {
+		return 0, fmt.Errorf("collect row failed: %w", err)
+	}
+
+	return id, nil
+}
+
+const jobColumns = `
+		job_id,
+		kind,
+		payload,
+		status,
+		attempts,
+		max_attempts,
+		run_at,
+		last_error,
+		created_at`
+
+func scanJob(row pgx.CollectableRow) (Job, error) {
+	var j Job
+
+	err := row.Scan(
+		&j.ID,
+		&j.Kind,
+		&j.Payload,
+		&j.Status,
+		&j.Attempts,
+		&j.MaxAttempts,
+		&j.RunAt,
+		&j.LastError,
+		&j.CreatedAt,
+	)
+
+	return j, err
+}
+
+// Get returns a job by id.
+func Get(
+	ctx context.Context,
+	db Executor,
+	id int64,
+) (*Job, error) {
+	query := `
+	SELECT` + jobColumns + `
+	FROM jobs
+	WHERE job_id = $1
+	`
+
+	rows, err := db.Query(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	job, err := pgx.CollectExactlyOneRow(rows, scanJob)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("collect row failed: %w", err)
+	}
+
+	return &job, nil
+}
+
+// List returns the jobs in the passed status, or every job if status is nil,
+// most recent first.
+func List(
+	ctx context.Context,
+	db Executor,
+	status *Status,
+	offset int,
+	limit int,
+) ([]Job, error) {
+	query := `
+	SELECT` + jobColumns + `
+	FROM jobs
+	WHERE ($1::text IS NULL OR status = $1::text::job_status)
+	ORDER BY created_at DESC, job_id DESC
+	OFFSET $2
+	LIMIT $3
+	`
+
+	rows, err := db.Query(ctx, query, status, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	jobs, err := pgx.CollectRows(rows, scanJob)
+	if err != nil {
+		return nil, fmt.Errorf("collect rows failed: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// Retry moves a dead job back into the queue, with all of its attempts
+// restored. Jobs in any other status are reported as ErrNotDead.
+func Retry(
+	ctx context.Context,
+	db Executor,
+	id int64,
+) error {
+	query := `
+	UPDATE jobs
+	SET
+		status = 'pending',
+		attempts = 0,
+		run_at = CURRENT_TIMESTAMP
+	WHERE job_id = $1
+	AND status = 'dead'
+	`
+
+	cmdTag, err := db.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("query failed: %w", err)
+	}
+
+	if cmdTag.RowsAffected() == 0 {
+		if _, err := Get(ctx, db, id); err != nil {
+			return err
+		}
+
+		return ErrNotDead
+	}
+
+	return nil
+}
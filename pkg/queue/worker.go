@@ -0,0 +1,344 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/adamkadda/arman/pkg/logging"
+	"github.com/jackc/pgx/v5"
+)
+
+// Handler runs a job of one kind. A returned error fails the attempt; wrap it
+// with Permanent to dead-letter the job without retrying it.
+type Handler func(ctx context.Context, job *Job) error
+
+// Handlers maps job kinds to the Handler running them. Jobs of a kind without
+// a Handler are dead-lettered.
+type Handlers map[string]Handler
+
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent marks an error as not worth retrying.
+func Permanent(err error) error {
+	return permanentError{err: err}
+}
+
+// Backoff returns how long to wait before the next attempt of a job that has
+// failed attempts times. It doubles from 10 seconds up to an hour, with up to
+// 10% of jitter so that jobs failing together are not retried together.
+func Backoff(attempts int) time.Duration {
+	const (
+		base    = 10 * time.Second
+		ceiling = time.Hour
+	)
+
+	attempts = max(attempts, 1)
+
+	d := ceiling
+	if attempts < 20 {
+		d = min(base<<(attempts-1), ceiling)
+	}
+
+	return d + rand.N(d/10+1)
+}
+
+// Workers runs jobs from the queue with a fixed number of concurrent workers.
+type Workers struct {
+	db       Executor
+	handlers Handlers
+	count    int
+
+	// pollInterval is how long an idle worker waits before looking for a job.
+	pollInterval time.Duration
+	// lease is how long a job may run before it is assumed abandoned, such as
+	// by a crashed process, and handed to another worker.
+	lease time.Duration
+}
+
+func NewWorkers(
+	db Executor,
+	handlers Handlers,
+	count int,
+	pollInterval time.Duration,
+	lease time.Duration,
+) *Workers {
+	return &Workers{
+		db:           db,
+		handlers:     handlers,
+		count:        count,
+		pollInterval: pollInterval,
+		lease:        lease,
+	}
+}
+
+// Run starts the workers and blocks until the provided context is closed. Jobs
+// running when the context is closed are given the closed context, and Run
+// waits for them to return.
+func (w *Workers) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+
+	for i := range w.count {
+		wg.Go(func() {
+			w.work(ctx, i)
+		})
+	}
+
+	wg.Wait()
+
+	return nil
+}
+
+func (w *Workers) work(ctx context.Context, worker int) {
+	logger := logging.FromContext(ctx).With(
+		slog.Int("worker", worker),
+	)
+	ctx = logging.WithLogger(ctx, logger)
+
+	for {
+		// Keep claiming jobs until the queue is drained, then wait.
+		ran, err := w.runNext(ctx)
+		if err != nil {
+			logger.Error(
+				"claim job failed",
+				slog.String("operation", "job.claim"),
+				slog.String("step", "job.claim"),
+				slog.Any("error", err),
+			)
+		}
+
+		if ran && err == nil {
+			if ctx.Err() != nil {
+				return
+			}
+
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(w.pollInterval):
+		}
+	}
+}
+
+// runNext claims the next due job and runs it. It reports whether a job was
+// claimed.
+func (w *Workers) runNext(ctx context.Context) (bool, error) {
+	job, err := w.claim(ctx)
+	if err != nil || job == nil {
+		return false, err
+	}
+
+	logger := logging.FromContext(ctx).With(
+		slog.String("operation", "job."+job.Kind),
+		slog.Int64("job_id", job.ID),
+		slog.Int("attempt", job.Attempts),
+	)
+
+	logger.Info(
+		"run job",
+	)
+
+	start := time.Now()
+	runErr := w.run(logging.WithLogger(ctx, logger), job)
+
+	// Record the outcome even if the context was closed while the job ran.
+	ctx = context.WithoutCancel(ctx)
+
+	if runErr == nil {
+		if err := w.succeed(ctx, job); err != nil {
+			logger.Error(
+				"complete job failed",
+				slog.String("step", "job.succeed"),
+				slog.Any("error", err),
+			)
+
+			return true, nil
+		}
+
+		logger.Debug(
+			"job succeeded",
+			slog.Duration("duration", time.Since(start)),
+		)
+
+		return true, nil
+	}
+
+	var permanent permanentError
+	dead := errors.As(runErr, &permanent) || job.Attempts >= job.MaxAttempts
+
+	if dead {
+		logger.Error(
+			"job dead-lettered",
+			slog.String("step", "job.run"),
+			slog.Any("error", runErr),
+		)
+	} else {
+		logger.Warn(
+			"job failed",
+			slog.String("reason", runErr.Error()),
+		)
+	}
+
+	if err := w.fail(ctx, job, runErr, dead); err != nil {
+		logger.Error(
+			"fail job failed",
+			slog.String("step", "job.fail"),
+			slog.Any("error", err),
+		)
+	}
+
+	return true, nil
+}
+
+// run hands a job to its Handler, turning panics into errors.
+func (w *Workers) run(ctx context.Context, job *Job) (err error) {
+	handler, ok := w.handlers[job.Kind]
+	if !ok {
+		return Permanent(fmt.Errorf("no handler for job kind %q", job.Kind))
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panic: %v", r)
+		}
+	}()
+
+	return handler(ctx, job)
+}
+
+// claim marks the next due job as running, and returns it with its attempt
+// counted. Running jobs whose lease expired are claimed again, unless they ran
+// out of attempts, in which case they are dead-lettered by the same query. It
+// returns a nil Job if no job is due.
+func (w *Workers) claim(ctx context.Context) (*Job, error) {
+	query := `
+	WITH expired AS (
+		UPDATE jobs
+		SET
+			status = 'dead',
+			locked_until = NULL,
+			last_error = $2
+		WHERE status = 'running'
+		AND locked_until < CURRENT_TIMESTAMP
+		AND attempts >= max_attempts
+	)
+	UPDATE jobs
+	SET
+		status = 'running',
+		attempts = attempts + 1,
+		locked_until = CURRENT_TIMESTAMP + $1::interval
+	WHERE job_id = (
+		SELECT job_id
+		FROM jobs
+		WHERE (status = 'pending' AND run_at <= CURRENT_TIMESTAMP)
+		OR (
+			status = 'running'
+			AND locked_until < CURRENT_TIMESTAMP
+			AND attempts < max_attempts
+		)
+		ORDER BY run_at, job_id
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED
+	)
+	RETURNING` + jobColumns + `
+	`
+
+	rows, err := w.db.Query(ctx, query, w.lease, ErrLeaseExpired.Error())
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	job, err := pgx.CollectExactlyOneRow(rows, scanJob)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("collect row failed: %w", err)
+	}
+
+	return &job, nil
+}
+
+// succeed records a successful attempt. Attempts count claims, so a job that
+// was claimed again after its lease expired is left to the worker holding it,
+// and ErrLeaseLost is returned.
+func (w *Workers) succeed(ctx context.Context, job *Job) error {
+	query := `
+	UPDATE jobs
+	SET
+		status = 'succeeded',
+		locked_until = NULL,
+		last_error = NULL,
+		completed_at = CURRENT_TIMESTAMP
+	WHERE job_id = $1
+	AND status = 'running'
+	AND attempts = $2
+	`
+
+	cmdTag, err := w.db.Exec(ctx, query, job.ID, job.Attempts)
+	if err != nil {
+		return fmt.Errorf("query failed: %w", err)
+	}
+
+	if cmdTag.RowsAffected() == 0 {
+		return ErrLeaseLost
+	}
+
+	return nil
+}
+
+// fail records a failed attempt. The job is dead-lettered if dead is set,
+// otherwise it is rescheduled after its backoff. Like succeed, it returns
+// ErrLeaseLost if the job was claimed again.
+func (w *Workers) fail(ctx context.Context, job *Job, runErr error, dead bool) error {
+	status := StatusPending
+	if dead {
+		status = StatusDead
+	}
+
+	query := `
+	UPDATE jobs
+	SET
+		status = $1,
+		run_at = CURRENT_TIMESTAMP + $2::interval,
+		locked_until = NULL,
+		last_error = $3
+	WHERE job_id = $4
+	AND status = 'running'
+	AND attempts = $5
+	`
+
+	cmdTag, err := w.db.Exec(ctx, query,
+		status,
+		Backoff(job.Attempts),
+		runErr.Error(),
+		job.ID,
+		job.Attempts,
+	)
+	if err != nil {
+		return fmt.Errorf("query failed: %w", err)
+	}
+
+	if cmdTag.RowsAffected() == 0 {
+		return ErrLeaseLost
+	}
+
+	return nil
+}
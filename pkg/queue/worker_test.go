@@ -0,0 +1,346 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/adamkadda/arman/pkg/migrate"
+	"github.com/adamkadda/arman/schema"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/require"
+)
+
+var ErrFoo = errors.New("foo")
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		name     string
+		attempts int
+		expected time.Duration
+	}{
+		{
+			name:     "no attempt",
+			attempts: 0,
+			expected: 10 * time.Second,
+		},
+		{
+			name:     "first attempt",
+			attempts: 1,
+			expected: 10 * time.Second,
+		},
+		{
+			name:     "second attempt",
+			attempts: 2,
+			expected: 20 * time.Second,
+		},
+		{
+			name:     "fifth attempt",
+			attempts: 5,
+			expected: 160 * time.Second,
+		},
+		{
+			name:     "capped",
+			attempts: 12,
+			expected: time.Hour,
+		},
+		{
+			name:     "too many to shift",
+			attempts: 100,
+			expected: time.Hour,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			for range 100 {
+				d := Backoff(tt.attempts)
+
+				require.GreaterOrEqual(t, d, tt.expected)
+				require.LessOrEqual(t, d, tt.expected+tt.expected/10)
+			}
+		})
+	}
+}
+
+func TestWorkers_RunNext(t *testing.T) {
+	tests := []struct {
+		name           string
+		attempts       int
+		handler        Handler
+		leaseLost      bool
+		expectedStatus Status
+	}{
+		{
+			name:           "success",
+			attempts:       1,
+			handler:        func(ctx context.Context, job *Job) error { return nil },
+			expectedStatus: StatusSucceeded,
+		},
+		{
+			name:           "failure retried",
+			attempts:       1,
+			handler:        func(ctx context.Context, job *Job) error { return ErrFoo },
+			expectedStatus: StatusPending,
+		},
+		{
+			name:           "last attempt failed",
+			attempts:       3,
+			handler:        func(ctx context.Context, job *Job) error { return ErrFoo },
+			expectedStatus: StatusDead,
+		},
+		{
+			name:           "permanent failure",
+			attempts:       1,
+			handler:        func(ctx context.Context, job *Job) error { return Permanent(ErrFoo) },
+			expectedStatus: StatusDead,
+		},
+		{
+			name:           "no handler",
+			attempts:       1,
+			handler:        nil,
+			expectedStatus: StatusDead,
+		},
+		{
+			name:           "panic retried",
+			attempts:       1,
+			handler:        func(ctx context.Context, job *Job) error { panic("foo") },
+			expectedStatus: StatusPending,
+		},
+		{
+			name:           "lease lost",
+			attempts:       1,
+			handler:        func(ctx context.Context, job *Job) error { return nil },
+			leaseLost:      true,
+			expectedStatus: StatusSucceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			db := &mockExecutor{
+				job: &Job{
+					ID:          1,
+					Kind:        "foo",
+					Status:      StatusRunning,
+					Attempts:    tt.attempts,
+					MaxAttempts: 3,
+				},
+				leaseLost: tt.leaseLost,
+			}
+
+			handlers := Handlers{}
+			if tt.handler != nil {
+				handlers["foo"] = tt.handler
+			}
+
+			w := NewWorkers(db, handlers, 1, time.Second, time.Minute)
+
+			ran, err := w.runNext(context.Background())
+			require.NoError(t, err)
+			require.True(t, ran)
+
+			require.Len(t, db.execs, 1)
+
+			args := db.execs[0]
+			if tt.expectedStatus == StatusSucceeded {
+				require.Equal(t, []any{int64(1), tt.attempts}, args)
+			} else {
+				require.Equal(t, tt.expectedStatus, args[0])
+				require.Equal(t, int64(1), args[3])
+				// The attempt is fenced by the claim it was made under.
+				require.Equal(t, tt.attempts, args[4])
+			}
+		})
+	}
+}
+
+func TestWorkers_RunNextEmpty(t *testing.T) {
+	db := &mockExecutor{}
+
+	ran, err := NewWorkers(db, Handlers{}, 1, time.Second, time.Minute).runNext(context.Background())
+	require.NoError(t, err)
+	require.False(t, ran)
+	require.Empty(t, db.execs)
+}
+
+// mockExecutor claims job, if any, and records the arguments of every Exec.
+// Exec affects no row if leaseLost is set.
+type mockExecutor struct {
+	job       *Job
+	leaseLost bool
+	execs     [][]any
+}
+
+func (db *mockExecutor) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	db.execs = append(db.execs, args)
+
+	if db.leaseLost {
+		return pgconn.NewCommandTag("UPDATE 0"), nil
+	}
+
+	return pgconn.NewCommandTag("UPDATE 1"), nil
+}
+
+func (db *mockExecutor) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	return &mockRows{job: db.job}, nil
+}
+
+// mockRows returns job as the only row, if set.
+type mockRows struct {
+	pgx.Rows
+	job  *Job
+	read bool
+}
+
+func (r *mockRows) Next() bool {
+	if r.job == nil || r.read {
+		return false
+	}
+
+	r.read = true
+
+	return true
+}
+
+func (r *mockRows) Scan(dest ...any) error {
+	*dest[0].(*int64) = r.job.ID
+	*dest[1].(*string) = r.job.Kind
+	*dest[3].(*Status) = r.job.Status
+	*dest[4].(*int) = r.job.Attempts
+	*dest[5].(*int) = r.job.MaxAttempts
+
+	return nil
+}
+
+func (r *mockRows) Close() {}
+
+func (r *mockRows) Err() error {
+	return nil
+}
+
+func (r *mockRows) CommandTag() pgconn.CommandTag {
+	return pgconn.NewCommandTag("UPDATE 1")
+}
+
+func TestWorkers_Claim(t *testing.T) {
+	db := testTx(t)
+	ctx := context.Background()
+
+	w := NewWorkers(db, Handlers{}, 1, time.Second, time.Minute)
+
+	id, err := Enqueue(ctx, db, "foo", nil, MaxAttempts(2))
+	require.NoError(t, err)
+
+	job, err := w.claim(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, job)
+	require.Equal(t, id, job.ID)
+	require.Equal(t, StatusRunning, job.Status)
+	require.Equal(t, 1, job.Attempts)
+
+	// The job is leased.
+	job, err = w.claim(ctx)
+	require.NoError(t, err)
+	require.Nil(t, job)
+
+	// A worker whose lease expired no longer owns the job.
+	expireLease(t, db, id)
+
+	job, err = w.claim(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, job)
+	require.Equal(t, 2, job.Attempts)
+
+	require.ErrorIs(t, w.succeed(ctx, &Job{ID: id, Attempts: 1}), ErrLeaseLost)
+	require.NoError(t, w.succeed(ctx, job))
+
+	got, err := Get(ctx, db, id)
+	require.NoError(t, err)
+	require.Equal(t, StatusSucceeded, got.Status)
+}
+
+// A job whose lease expires on its last attempt is dead-lettered rather than
+// run again.
+func TestWorkers_ClaimDeadLetters(t *testing.T) {
+	db := testTx(t)
+	ctx := context.Background()
+
+	w := NewWorkers(db, Handlers{}, 1, time.Second, time.Minute)
+
+	id, err := Enqueue(ctx, db, "foo", nil, MaxAttempts(1))
+	require.NoError(t, err)
+
+	job, err := w.claim(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, job)
+
+	expireLease(t, db, id)
+
+	job, err = w.claim(ctx)
+	require.NoError(t, err)
+	require.Nil(t, job)
+
+	got, err := Get(ctx, db, id)
+	require.NoError(t, err)
+	require.Equal(t, StatusDead, got.Status)
+	require.Equal(t, ErrLeaseExpired.Error(), *got.LastError)
+
+	require.NoError(t, Retry(ctx, db, id))
+
+	job, err = w.claim(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, job)
+	require.Equal(t, 1, job.Attempts)
+}
+
+func expireLease(t *testing.T, db Executor, id int64) {
+	t.Helper()
+
+	_, err := db.Exec(context.Background(), `
+	UPDATE jobs
+	SET locked_until = CURRENT_TIMESTAMP - INTERVAL '1 second'
+	WHERE job_id = $1
+	`, id)
+	require.NoError(t, err)
+}
+
+// testTx returns a transaction on the database named by TEST_DATABASE_URL,
+// migrated to the latest version. The transaction is rolled back when the test
+// ends. Tests calling testTx are skipped when TEST_DATABASE_URL is unset.
+func testTx(t *testing.T) pgx.Tx {
+	t.Helper()
+
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	ctx := context.Background()
+
+	pool, err := pgxpool.New(ctx, url)
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+
+	migrations, err := schema.Migrations()
+	require.NoError(t, err)
+
+	_, err = migrate.New(pool, migrations).Up(ctx)
+	require.NoError(t, err)
+
+	tx, err := pool.Begin(ctx)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		tx.Rollback(ctx)
+	})
+
+	return tx
+}
@@ -0,0 +1,144 @@
+// The scheduler package runs periodic tasks in the background. It is safe to
+// run a Scheduler in several replicas of the same application: every run of a
+// task holds a Postgres advisory lock, so each task runs in one replica at a
+// time.
+package scheduler
+
+import (
+	"context"
+	"hash/fnv"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/adamkadda/arman/pkg/logging"
+	"github.com/jackc/pgx/v5"
+)
+
+// DB begins the transactions holding the advisory lock of a task.
+type DB interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// Task is a unit of work run every Interval. Its Name identifies the task in
+// logs and derives its advisory lock, so it must be unique.
+type Task struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+type Scheduler struct {
+	db    DB
+	tasks []Task
+}
+
+func New(db DB, tasks ...Task) *Scheduler {
+	return &Scheduler{
+		db:    db,
+		tasks: tasks,
+	}
+}
+
+// Run runs every task once, then again after each of its intervals, and blocks
+// until the provided context is closed. A task that is running when the context
+// is closed is given the closed context, and Run waits for it to return.
+//
+// A failing run of a task is logged and does not stop the task.
+func (s *Scheduler) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+
+	for _, task := range s.tasks {
+		wg.Go(func() {
+			s.loop(ctx, task)
+		})
+	}
+
+	wg.Wait()
+
+	return nil
+}
+
+func (s *Scheduler) loop(ctx context.Context, task Task) {
+	ticker := time.NewTicker(task.Interval)
+	defer ticker.Stop()
+
+	for {
+		s.runOnce(ctx, task)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runOnce runs a task if no other replica is running it. The advisory lock is
+// held by a transaction of its own, and is released when the transaction ends.
+func (s *Scheduler) runOnce(ctx context.Context, task Task) {
+	logger := logging.FromContext(ctx).With(
+		slog.String("operation", task.Name),
+	)
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		logger.Error(
+			"begin transaction failed",
+			slog.String("step", "tx.begin"),
+			slog.Any("error", err),
+		)
+
+		return
+	}
+	defer tx.Rollback(context.WithoutCancel(ctx))
+
+	var locked bool
+
+	err = tx.QueryRow(ctx,
+		"SELECT pg_try_advisory_xact_lock($1)",
+		lockKey(task.Name),
+	).Scan(&locked)
+	if err != nil {
+		logger.Error(
+			"acquire task lock failed",
+			slog.String("step", "lock.acquire"),
+			slog.Any("error", err),
+		)
+
+		return
+	}
+
+	if !locked {
+		logger.Debug(
+			"task running elsewhere",
+		)
+
+		return
+	}
+
+	start := time.Now()
+
+	if err := task.Run(logging.WithLogger(ctx, logger)); err != nil {
+		logger.Error(
+			"run task failed",
+			slog.String("step", "task.run"),
+			slog.Any("error", err),
+		)
+
+		return
+	}
+
+	logger.Debug(
+		"run task",
+		slog.Duration("duration", time.Since(start)),
+	)
+}
+
+// lockKey derives the advisory lock key of a task from its name.
+func lockKey(name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+
+	return int64(h.Sum64())
+}
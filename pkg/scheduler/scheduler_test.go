@@ -0,0 +1,126 @@
+package scheduler
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+)
+
+// mockDB begins transactions in which the advisory lock is acquired when
+// locked is set.
+type mockDB struct {
+	locked bool
+}
+
+func (db mockDB) Begin(ctx context.Context) (pgx.Tx, error) {
+	return mockTx{locked: db.locked}, nil
+}
+
+// mockTx only implements the methods used by the Scheduler.
+type mockTx struct {
+	pgx.Tx
+	locked bool
+}
+
+func (tx mockTx) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return mockRow{locked: tx.locked}
+}
+
+func (tx mockTx) Rollback(ctx context.Context) error {
+	return nil
+}
+
+type mockRow struct {
+	locked bool
+}
+
+func (r mockRow) Scan(dest ...any) error {
+	*dest[0].(*bool) = r.locked
+	return nil
+}
+
+func TestScheduler_RunOnce(t *testing.T) {
+	tests := []struct {
+		name     string
+		locked   bool
+		expected int32
+	}{
+		{
+			name:     "lock acquired",
+			locked:   true,
+			expected: 1,
+		},
+		{
+			name:     "running elsewhere",
+			locked:   false,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var runs atomic.Int32
+
+			task := Task{
+				Name:     "foo",
+				Interval: time.Hour,
+				Run: func(ctx context.Context) error {
+					runs.Add(1)
+					return nil
+				},
+			}
+
+			s := New(mockDB{locked: tt.locked}, task)
+			s.runOnce(context.Background(), task)
+
+			require.Equal(t, tt.expected, runs.Load())
+		})
+	}
+}
+
+// Run returns once the context is closed, after the running task returned.
+func TestScheduler_RunShutdown(t *testing.T) {
+	started := make(chan struct{})
+
+	var stopped atomic.Bool
+
+	task := Task{
+		Name:     "foo",
+		Interval: time.Hour,
+		Run: func(ctx context.Context) error {
+			close(started)
+			<-ctx.Done()
+			stopped.Store(true)
+			return ctx.Err()
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan error)
+	go func() {
+		done <- New(mockDB{locked: true}, task).Run(ctx)
+	}()
+
+	<-started
+	cancel()
+
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+		require.True(t, stopped.Load())
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after the context was closed")
+	}
+}
+
+func TestLockKey(t *testing.T) {
+	require.Equal(t, lockKey("foo"), lockKey("foo"))
+	require.NotEqual(t, lockKey("foo"), lockKey("bar"))
+}
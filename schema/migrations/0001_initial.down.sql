@@ -0,0 +1,11 @@
+DROP TRIGGER IF EXISTS update_events_updated_at ON events;
+DROP FUNCTION IF EXISTS update_updated_at();
+
+DROP TABLE IF EXISTS biographies;
+DROP TABLE IF EXISTS events;
+DROP TYPE IF EXISTS event_status;
+DROP TABLE IF EXISTS programme_pieces;
+DROP TABLE IF EXISTS programmes;
+DROP TABLE IF EXISTS pieces;
+DROP TABLE IF EXISTS composers;
+DROP TABLE IF EXISTS venues;
@@ -0,0 +1,9 @@
+-- Dropping the columns drops their indexes along with them.
+ALTER TABLE events DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE programmes DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE pieces DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE composers DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE venues DROP COLUMN IF EXISTS search_vector;
+
+-- The unaccent extension is left installed, other schemas may rely on it.
+DROP FUNCTION IF EXISTS f_unaccent(text);
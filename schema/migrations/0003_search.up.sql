@@ -0,0 +1,34 @@
+-- Search matches text regardless of accents, so that "Dvorak" finds "Dvořák".
+-- unaccent is only STABLE, the wrapper below lets generated columns use it.
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+CREATE OR REPLACE FUNCTION f_unaccent(text)
+RETURNS text AS $$
+    SELECT public.unaccent('public.unaccent'::regdictionary, $1)
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT;
+
+ALTER TABLE venues ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+    to_tsvector('simple', f_unaccent(venue_name))
+) STORED;
+
+ALTER TABLE composers ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+    to_tsvector('simple', f_unaccent(full_name || ' ' || short_name))
+) STORED;
+
+ALTER TABLE pieces ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+    to_tsvector('simple', f_unaccent(piece_title))
+) STORED;
+
+ALTER TABLE programmes ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+    to_tsvector('simple', f_unaccent(programme_title))
+) STORED;
+
+ALTER TABLE events ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+    to_tsvector('simple', f_unaccent(event_title))
+) STORED;
+
+CREATE INDEX venues_search_idx ON venues USING GIN (search_vector);
+CREATE INDEX composers_search_idx ON composers USING GIN (search_vector);
+CREATE INDEX pieces_search_idx ON pieces USING GIN (search_vector);
+CREATE INDEX programmes_search_idx ON programmes USING GIN (search_vector);
+CREATE INDEX events_search_idx ON events USING GIN (search_vector);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS biography_media;
+DROP TABLE IF EXISTS composer_media;
+DROP TABLE IF EXISTS venue_media;
+DROP TABLE IF EXISTS event_media;
+DROP TABLE IF EXISTS media;
@@ -0,0 +1,44 @@
+-- Media files live in a blob store under storage_key; only their metadata is
+-- kept here.
+CREATE TABLE media (
+    media_id INT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    filename VARCHAR(255) NOT NULL,
+    mime_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL CHECK (size > 0),
+    checksum CHAR(64) NOT NULL,
+    storage_key VARCHAR(100) NOT NULL UNIQUE,
+    alt_text TEXT NOT NULL DEFAULT '',
+    credits TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Media attachments, ordered by position. Deleting either side removes the
+-- attachment; deleting media still shown on a published event is blocked by
+-- the application.
+CREATE TABLE event_media (
+    event_id INT NOT NULL REFERENCES events(event_id) ON DELETE CASCADE,
+    media_id INT NOT NULL REFERENCES media(media_id) ON DELETE CASCADE,
+    position INT NOT NULL CHECK (position > 0),
+    PRIMARY KEY (event_id, media_id)
+);
+
+CREATE TABLE venue_media (
+    venue_id INT NOT NULL REFERENCES venues(venue_id) ON DELETE CASCADE,
+    media_id INT NOT NULL REFERENCES media(media_id) ON DELETE CASCADE,
+    position INT NOT NULL CHECK (position > 0),
+    PRIMARY KEY (venue_id, media_id)
+);
+
+CREATE TABLE composer_media (
+    composer_id INT NOT NULL REFERENCES composers(composer_id) ON DELETE CASCADE,
+    media_id INT NOT NULL REFERENCES media(media_id) ON DELETE CASCADE,
+    position INT NOT NULL CHECK (position > 0),
+    PRIMARY KEY (composer_id, media_id)
+);
+
+CREATE TABLE biography_media (
+    variant TEXT NOT NULL REFERENCES biographies(variant) ON DELETE CASCADE,
+    media_id INT NOT NULL REFERENCES media(media_id) ON DELETE CASCADE,
+    position INT NOT NULL CHECK (position > 0),
+    PRIMARY KEY (variant, media_id)
+);
@@ -0,0 +1,15 @@
+-- The single row of contact details. The id column only exists to keep a
+-- second row from ever being inserted.
+CREATE TABLE contact (
+    contact_id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (contact_id),
+    email TEXT NOT NULL DEFAULT '',
+    phone TEXT NOT NULL DEFAULT '',
+    management_agency TEXT NOT NULL DEFAULT '',
+    management_email TEXT NOT NULL DEFAULT '',
+    management_phone TEXT NOT NULL DEFAULT '',
+    press_contact TEXT NOT NULL DEFAULT '',
+    press_email TEXT NOT NULL DEFAULT '',
+    press_phone TEXT NOT NULL DEFAULT '',
+    social_links JSONB NOT NULL DEFAULT '[]',
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS biography_revisions;
+
+ALTER TABLE biographies DROP COLUMN IF EXISTS updated_by;
@@ -0,0 +1,14 @@
+ALTER TABLE biographies
+    ADD COLUMN updated_by INT REFERENCES users(user_id) ON DELETE SET NULL;
+
+-- Every replaced biography text is kept, along with who wrote it and when.
+CREATE TABLE biography_revisions (
+    revision_id INT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    variant TEXT NOT NULL REFERENCES biographies(variant) ON DELETE CASCADE,
+    content TEXT NOT NULL,
+    author_id INT REFERENCES users(user_id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX biography_revisions_variant_idx
+    ON biography_revisions (variant, created_at DESC);
@@ -0,0 +1,20 @@
+-- Only the English texts are kept, translations and their revisions are lost.
+DELETE FROM biographies WHERE language <> 'en';
+
+DROP INDEX IF EXISTS biography_revisions_variant_idx;
+ALTER TABLE biography_revisions DROP CONSTRAINT biography_revisions_variant_language_fkey;
+ALTER TABLE biography_revisions DROP COLUMN language;
+
+ALTER TABLE biography_media DROP CONSTRAINT biography_media_variant_check;
+
+ALTER TABLE biographies DROP CONSTRAINT biographies_pkey;
+ALTER TABLE biographies DROP COLUMN language;
+ALTER TABLE biographies ADD PRIMARY KEY (variant);
+
+ALTER TABLE biography_media
+    ADD FOREIGN KEY (variant) REFERENCES biographies(variant) ON DELETE CASCADE;
+ALTER TABLE biography_revisions
+    ADD FOREIGN KEY (variant) REFERENCES biographies(variant) ON DELETE CASCADE;
+
+CREATE INDEX biography_revisions_variant_idx
+    ON biography_revisions (variant, created_at DESC);
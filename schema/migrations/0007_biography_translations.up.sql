@@ -0,0 +1,24 @@
+-- Every variant of the biography may be translated; a row holds the text of
+-- one variant in one language. Existing texts are taken to be written in
+-- English, the default of LANGUAGES.
+ALTER TABLE biography_revisions DROP CONSTRAINT biography_revisions_variant_fkey;
+
+ALTER TABLE biographies ADD COLUMN language TEXT NOT NULL DEFAULT 'en';
+ALTER TABLE biographies ALTER COLUMN language DROP DEFAULT;
+ALTER TABLE biographies DROP CONSTRAINT biographies_pkey;
+ALTER TABLE biographies ADD PRIMARY KEY (variant, language);
+
+-- Media is attached to a biography variant and shared by all of its languages.
+ALTER TABLE biography_media DROP CONSTRAINT biography_media_variant_fkey;
+ALTER TABLE biography_media
+    ADD CONSTRAINT biography_media_variant_check CHECK (variant IN ('full', 'short'));
+
+ALTER TABLE biography_revisions ADD COLUMN language TEXT NOT NULL DEFAULT 'en';
+ALTER TABLE biography_revisions ALTER COLUMN language DROP DEFAULT;
+ALTER TABLE biography_revisions
+    ADD FOREIGN KEY (variant, language)
+        REFERENCES biographies(variant, language) ON DELETE CASCADE;
+
+DROP INDEX biography_revisions_variant_idx;
+CREATE INDEX biography_revisions_variant_idx
+    ON biography_revisions (variant, language, created_at DESC);
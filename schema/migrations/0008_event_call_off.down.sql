@@ -0,0 +1,17 @@
+ALTER TABLE events
+    DROP COLUMN IF EXISTS replacement_event_id,
+    DROP COLUMN IF EXISTS status_reason;
+
+-- Values cannot be removed from an enum, so the type is replaced. Events
+-- that were called off are archived.
+UPDATE events SET status = 'archived' WHERE status IN ('cancelled', 'postponed');
+
+ALTER TYPE event_status RENAME TO event_status_old;
+CREATE TYPE event_status AS ENUM ('draft', 'published', 'archived');
+
+ALTER TABLE events ALTER COLUMN status DROP DEFAULT;
+ALTER TABLE events
+    ALTER COLUMN status TYPE event_status USING status::text::event_status;
+ALTER TABLE events ALTER COLUMN status SET DEFAULT 'draft';
+
+DROP TYPE event_status_old;
@@ -0,0 +1,9 @@
+-- Consider extending variants to include 'deleted'.
+ALTER TYPE event_status ADD VALUE 'cancelled';
+ALTER TYPE event_status ADD VALUE 'postponed';
+
+-- Why a cancelled or postponed event was called off, and which event
+-- replaces it, if any.
+ALTER TABLE events
+    ADD COLUMN status_reason TEXT,
+    ADD COLUMN replacement_event_id INT REFERENCES events(event_id) ON DELETE SET NULL;
@@ -0,0 +1,13 @@
+-- Every change of an event's status, along with who made it, when and why.
+CREATE TABLE event_status_history (
+    history_id INT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    event_id INT NOT NULL REFERENCES events(event_id) ON DELETE CASCADE,
+    from_status event_status NOT NULL,
+    to_status event_status NOT NULL,
+    reason TEXT,
+    changed_by INT REFERENCES users(user_id) ON DELETE SET NULL,
+    changed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX event_status_history_event_idx
+    ON event_status_history (event_id, changed_at DESC);
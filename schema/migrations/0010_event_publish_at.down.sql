@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS events_publish_at_idx;
+
+ALTER TABLE events DROP COLUMN IF EXISTS publish_at;
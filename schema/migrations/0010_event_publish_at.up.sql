@@ -0,0 +1,4 @@
+ALTER TABLE events ADD COLUMN publish_at TIMESTAMP;
+
+CREATE INDEX events_publish_at_idx ON events (publish_at)
+    WHERE status = 'draft' AND publish_at IS NOT NULL;
@@ -0,0 +1,20 @@
+CREATE TYPE job_status AS ENUM ('pending', 'running', 'succeeded', 'dead');
+
+-- The background job queue, see pkg/queue. Running jobs are leased until
+-- locked_until; a job whose lease expires is handed to another worker.
+CREATE TABLE jobs (
+    job_id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    kind TEXT NOT NULL,
+    payload JSONB NOT NULL DEFAULT '{}',
+    status job_status NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    max_attempts INT NOT NULL DEFAULT 5 CHECK (max_attempts > 0),
+    run_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    locked_until TIMESTAMP,
+    last_error TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMP
+);
+
+CREATE INDEX jobs_due_idx ON jobs (run_at, job_id)
+    WHERE status IN ('pending', 'running');
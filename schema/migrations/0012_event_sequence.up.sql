@@ -0,0 +1,3 @@
+-- Counts the revisions of an event, so that calendar subscribers can tell a
+-- newer copy of an event from an older one.
+ALTER TABLE events ADD COLUMN sequence INT NOT NULL DEFAULT 0;
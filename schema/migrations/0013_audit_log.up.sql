@@ -0,0 +1,23 @@
+-- Every change made through the services, along with who made it and in which
+-- request. Snapshots hold the changed resource as it was before and after the
+-- change: before is NULL for creations, after is NULL for deletions.
+--
+-- The actor's username is copied, so that entries stay readable once the user
+-- is gone. Changes made by internal callers, such as the scheduler or the
+-- command line, have no actor.
+CREATE TABLE audit_log (
+    audit_id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    actor_id INT REFERENCES users(user_id) ON DELETE SET NULL,
+    actor_username VARCHAR(100),
+    operation TEXT NOT NULL,
+    entity_type TEXT NOT NULL,
+    entity_id TEXT NOT NULL,
+    before JSONB,
+    after JSONB,
+    request_id TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX audit_log_created_at_idx ON audit_log (created_at DESC, audit_id DESC);
+CREATE INDEX audit_log_entity_idx ON audit_log (entity_type, entity_id, created_at DESC);
+CREATE INDEX audit_log_actor_idx ON audit_log (actor_id, created_at DESC);
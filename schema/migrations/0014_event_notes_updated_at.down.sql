@@ -0,0 +1,8 @@
+DROP TRIGGER IF EXISTS update_events_updated_at ON events;
+
+CREATE TRIGGER update_events_updated_at
+BEFORE UPDATE ON events
+FOR EACH ROW
+EXECUTE FUNCTION update_updated_at();
+
+DROP FUNCTION IF EXISTS update_event_updated_at();
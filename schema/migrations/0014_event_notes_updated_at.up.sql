@@ -0,0 +1,22 @@
+-- Notes are private to the editors: an update changing nothing else leaves
+-- updated_at, which feeds and calendar subscribers see, as it was. Generated
+-- columns are not computed yet in a BEFORE trigger, so search_vector is left
+-- out of the comparison.
+CREATE OR REPLACE FUNCTION update_event_updated_at()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF to_jsonb(NEW) - 'notes' - 'updated_at' - 'search_vector'
+        IS DISTINCT FROM to_jsonb(OLD) - 'notes' - 'updated_at' - 'search_vector'
+    THEN
+        NEW.updated_at = NOW();
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER update_events_updated_at ON events;
+
+CREATE TRIGGER update_events_updated_at
+BEFORE UPDATE ON events
+FOR EACH ROW
+EXECUTE FUNCTION update_event_updated_at();
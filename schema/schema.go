@@ -0,0 +1,24 @@
+// The schema package embeds the migrations building the database schema of the
+// CMS, see pkg/migrate. Migrations are numbered, and a released migration is
+// never edited: changes to the schema are made by adding a new one.
+package schema
+
+import (
+	"embed"
+	"io/fs"
+
+	"github.com/adamkadda/arman/pkg/migrate"
+)
+
+//go:embed migrations/*.sql
+var files embed.FS
+
+// Migrations returns the migrations of the CMS, ordered by version.
+func Migrations() ([]migrate.Migration, error) {
+	migrations, err := fs.Sub(files, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	return migrate.Load(migrations)
+}